@@ -21,6 +21,15 @@ type FakePushActor struct {
 	uploadAppReturns struct {
 		result1 error
 	}
+	UploadDropletStub        func(appGUID string, dropletPath string) error
+	uploadDropletMutex       sync.RWMutex
+	uploadDropletArgsForCall []struct {
+		appGUID     string
+		dropletPath string
+	}
+	uploadDropletReturns struct {
+		result1 error
+	}
 	ProcessPathStub        func(dirOrZipFile string, f func(string)) error
 	processPathMutex       sync.RWMutex
 	processPathArgsForCall []struct {
@@ -45,12 +54,17 @@ type FakePushActor struct {
 }
 
 func (fake *FakePushActor) UploadApp(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error {
+	var presentFilesCopy []resources.AppFileResource
+	if presentFiles != nil {
+		presentFilesCopy = make([]resources.AppFileResource, len(presentFiles))
+		copy(presentFilesCopy, presentFiles)
+	}
 	fake.uploadAppMutex.Lock()
 	fake.uploadAppArgsForCall = append(fake.uploadAppArgsForCall, struct {
 		appGUID      string
 		zipFile      *os.File
 		presentFiles []resources.AppFileResource
-	}{appGUID, zipFile, presentFiles})
+	}{appGUID, zipFile, presentFilesCopy})
 	fake.uploadAppMutex.Unlock()
 	if fake.UploadAppStub != nil {
 		return fake.UploadAppStub(appGUID, zipFile, presentFiles)
@@ -78,6 +92,39 @@ func (fake *FakePushActor) UploadAppReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakePushActor) UploadDroplet(appGUID string, dropletPath string) error {
+	fake.uploadDropletMutex.Lock()
+	fake.uploadDropletArgsForCall = append(fake.uploadDropletArgsForCall, struct {
+		appGUID     string
+		dropletPath string
+	}{appGUID, dropletPath})
+	fake.uploadDropletMutex.Unlock()
+	if fake.UploadDropletStub != nil {
+		return fake.UploadDropletStub(appGUID, dropletPath)
+	} else {
+		return fake.uploadDropletReturns.result1
+	}
+}
+
+func (fake *FakePushActor) UploadDropletCallCount() int {
+	fake.uploadDropletMutex.RLock()
+	defer fake.uploadDropletMutex.RUnlock()
+	return len(fake.uploadDropletArgsForCall)
+}
+
+func (fake *FakePushActor) UploadDropletArgsForCall(i int) (string, string) {
+	fake.uploadDropletMutex.RLock()
+	defer fake.uploadDropletMutex.RUnlock()
+	return fake.uploadDropletArgsForCall[i].appGUID, fake.uploadDropletArgsForCall[i].dropletPath
+}
+
+func (fake *FakePushActor) UploadDropletReturns(result1 error) {
+	fake.UploadDropletStub = nil
+	fake.uploadDropletReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePushActor) ProcessPath(dirOrZipFile string, f func(string)) error {
 	fake.processPathMutex.Lock()
 	fake.processPathArgsForCall = append(fake.processPathArgsForCall, struct {
@@ -112,12 +159,17 @@ func (fake *FakePushActor) ProcessPathReturns(result1 error) {
 }
 
 func (fake *FakePushActor) GatherFiles(localFiles []models.AppFileFields, appDir string, uploadDir string) ([]resources.AppFileResource, bool, error) {
+	var localFilesCopy []models.AppFileFields
+	if localFiles != nil {
+		localFilesCopy = make([]models.AppFileFields, len(localFiles))
+		copy(localFilesCopy, localFiles)
+	}
 	fake.gatherFilesMutex.Lock()
 	fake.gatherFilesArgsForCall = append(fake.gatherFilesArgsForCall, struct {
 		localFiles []models.AppFileFields
 		appDir     string
 		uploadDir  string
-	}{localFiles, appDir, uploadDir})
+	}{localFilesCopy, appDir, uploadDir})
 	fake.gatherFilesMutex.Unlock()
 	if fake.GatherFilesStub != nil {
 		return fake.GatherFilesStub(localFiles, appDir, uploadDir)
@@ -20,6 +20,7 @@ const windowsPathPrefix = `\\?\`
 
 type PushActor interface {
 	UploadApp(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error
+	UploadDroplet(appGUID string, dropletPath string) error
 	ProcessPath(dirOrZipFile string, f func(string)) error
 	GatherFiles(localFiles []models.AppFileFields, appDir string, uploadDir string) ([]resources.AppFileResource, bool, error)
 }
@@ -156,3 +157,13 @@ func (actor PushActorImpl) GatherFiles(localFiles []models.AppFileFields, appDir
 func (actor PushActorImpl) UploadApp(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error {
 	return actor.appBitsRepo.UploadBits(appGUID, zipFile, presentFiles)
 }
+
+func (actor PushActorImpl) UploadDroplet(appGUID string, dropletPath string) error {
+	dropletFile, err := os.Open(dropletPath)
+	if err != nil {
+		return err
+	}
+	defer dropletFile.Close()
+
+	return actor.appBitsRepo.UploadDroplet(appGUID, dropletFile)
+}
@@ -265,6 +265,25 @@ var _ = Describe("Push Actor", func() {
 		It("Simply delegates to the UploadApp function on the app bits repo, which is not worth testing", func() {})
 	})
 
+	Describe(".UploadDroplet", func() {
+		It("uploads the droplet at the given path through the app bits repo", func() {
+			dropletPath := filepath.Join(fixturesDir, "example-app.zip")
+			err := actor.UploadDroplet("app-guid", dropletPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(appBitsRepo.UploadDropletCallCount()).To(Equal(1))
+			appGUID, dropletFile := appBitsRepo.UploadDropletArgsForCall(0)
+			Expect(appGUID).To(Equal("app-guid"))
+			Expect(dropletFile.Name()).To(Equal(dropletPath))
+		})
+
+		It("returns an error when the droplet cannot be opened", func() {
+			err := actor.UploadDroplet("app-guid", filepath.Join(fixturesDir, "does-not-exist.tgz"))
+			Expect(err).To(HaveOccurred())
+			Expect(appBitsRepo.UploadDropletCallCount()).To(Equal(0))
+		})
+	})
+
 	Describe("ProcessPath", func() {
 		var (
 			wasCalled     bool
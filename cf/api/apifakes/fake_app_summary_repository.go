@@ -16,6 +16,25 @@ type FakeAppSummaryRepository struct {
 		result1 []models.Application
 		result2 error
 	}
+	GetSummariesInSpaceStub        func(spaceGUID string) (apps []models.Application, apiErr error)
+	getSummariesInSpaceMutex       sync.RWMutex
+	getSummariesInSpaceArgsForCall []struct {
+		spaceGUID string
+	}
+	getSummariesInSpaceReturns struct {
+		result1 []models.Application
+		result2 error
+	}
+	GetSpaceSummaryStub        func(spaceGUID string) (apps []models.Application, serviceInstances []models.ServiceInstance, apiErr error)
+	getSpaceSummaryMutex       sync.RWMutex
+	getSpaceSummaryArgsForCall []struct {
+		spaceGUID string
+	}
+	getSpaceSummaryReturns struct {
+		result1 []models.Application
+		result2 []models.ServiceInstance
+		result3 error
+	}
 	GetSummaryStub        func(appGUID string) (summary models.Application, apiErr error)
 	getSummaryMutex       sync.RWMutex
 	getSummaryArgsForCall []struct {
@@ -52,6 +71,73 @@ func (fake *FakeAppSummaryRepository) GetSummariesInCurrentSpaceReturns(result1
 	}{result1, result2}
 }
 
+func (fake *FakeAppSummaryRepository) GetSummariesInSpace(spaceGUID string) (apps []models.Application, apiErr error) {
+	fake.getSummariesInSpaceMutex.Lock()
+	fake.getSummariesInSpaceArgsForCall = append(fake.getSummariesInSpaceArgsForCall, struct {
+		spaceGUID string
+	}{spaceGUID})
+	fake.getSummariesInSpaceMutex.Unlock()
+	if fake.GetSummariesInSpaceStub != nil {
+		return fake.GetSummariesInSpaceStub(spaceGUID)
+	} else {
+		return fake.getSummariesInSpaceReturns.result1, fake.getSummariesInSpaceReturns.result2
+	}
+}
+
+func (fake *FakeAppSummaryRepository) GetSummariesInSpaceCallCount() int {
+	fake.getSummariesInSpaceMutex.RLock()
+	defer fake.getSummariesInSpaceMutex.RUnlock()
+	return len(fake.getSummariesInSpaceArgsForCall)
+}
+
+func (fake *FakeAppSummaryRepository) GetSummariesInSpaceArgsForCall(i int) string {
+	fake.getSummariesInSpaceMutex.RLock()
+	defer fake.getSummariesInSpaceMutex.RUnlock()
+	return fake.getSummariesInSpaceArgsForCall[i].spaceGUID
+}
+
+func (fake *FakeAppSummaryRepository) GetSummariesInSpaceReturns(result1 []models.Application, result2 error) {
+	fake.GetSummariesInSpaceStub = nil
+	fake.getSummariesInSpaceReturns = struct {
+		result1 []models.Application
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppSummaryRepository) GetSpaceSummary(spaceGUID string) (apps []models.Application, serviceInstances []models.ServiceInstance, apiErr error) {
+	fake.getSpaceSummaryMutex.Lock()
+	fake.getSpaceSummaryArgsForCall = append(fake.getSpaceSummaryArgsForCall, struct {
+		spaceGUID string
+	}{spaceGUID})
+	fake.getSpaceSummaryMutex.Unlock()
+	if fake.GetSpaceSummaryStub != nil {
+		return fake.GetSpaceSummaryStub(spaceGUID)
+	} else {
+		return fake.getSpaceSummaryReturns.result1, fake.getSpaceSummaryReturns.result2, fake.getSpaceSummaryReturns.result3
+	}
+}
+
+func (fake *FakeAppSummaryRepository) GetSpaceSummaryCallCount() int {
+	fake.getSpaceSummaryMutex.RLock()
+	defer fake.getSpaceSummaryMutex.RUnlock()
+	return len(fake.getSpaceSummaryArgsForCall)
+}
+
+func (fake *FakeAppSummaryRepository) GetSpaceSummaryArgsForCall(i int) string {
+	fake.getSpaceSummaryMutex.RLock()
+	defer fake.getSpaceSummaryMutex.RUnlock()
+	return fake.getSpaceSummaryArgsForCall[i].spaceGUID
+}
+
+func (fake *FakeAppSummaryRepository) GetSpaceSummaryReturns(result1 []models.Application, result2 []models.ServiceInstance, result3 error) {
+	fake.GetSpaceSummaryStub = nil
+	fake.getSpaceSummaryReturns = struct {
+		result1 []models.Application
+		result2 []models.ServiceInstance
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeAppSummaryRepository) GetSummary(appGUID string) (summary models.Application, apiErr error) {
 	fake.getSummaryMutex.Lock()
 	fake.getSummaryArgsForCall = append(fake.getSummaryArgsForCall, struct {
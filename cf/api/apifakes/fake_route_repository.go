@@ -17,6 +17,15 @@ type FakeRouteRepository struct {
 	listRoutesReturns struct {
 		result1 error
 	}
+	ListRoutesInSpaceStub        func(spaceGUID string, cb func(models.Route) bool) (apiErr error)
+	listRoutesInSpaceMutex       sync.RWMutex
+	listRoutesInSpaceArgsForCall []struct {
+		spaceGUID string
+		cb        func(models.Route) bool
+	}
+	listRoutesInSpaceReturns struct {
+		result1 error
+	}
 	ListAllRoutesStub        func(cb func(models.Route) bool) (apiErr error)
 	listAllRoutesMutex       sync.RWMutex
 	listAllRoutesArgsForCall []struct {
@@ -134,6 +143,39 @@ func (fake *FakeRouteRepository) ListRoutesReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRouteRepository) ListRoutesInSpace(spaceGUID string, cb func(models.Route) bool) (apiErr error) {
+	fake.listRoutesInSpaceMutex.Lock()
+	fake.listRoutesInSpaceArgsForCall = append(fake.listRoutesInSpaceArgsForCall, struct {
+		spaceGUID string
+		cb        func(models.Route) bool
+	}{spaceGUID, cb})
+	fake.listRoutesInSpaceMutex.Unlock()
+	if fake.ListRoutesInSpaceStub != nil {
+		return fake.ListRoutesInSpaceStub(spaceGUID, cb)
+	} else {
+		return fake.listRoutesInSpaceReturns.result1
+	}
+}
+
+func (fake *FakeRouteRepository) ListRoutesInSpaceCallCount() int {
+	fake.listRoutesInSpaceMutex.RLock()
+	defer fake.listRoutesInSpaceMutex.RUnlock()
+	return len(fake.listRoutesInSpaceArgsForCall)
+}
+
+func (fake *FakeRouteRepository) ListRoutesInSpaceArgsForCall(i int) (string, func(models.Route) bool) {
+	fake.listRoutesInSpaceMutex.RLock()
+	defer fake.listRoutesInSpaceMutex.RUnlock()
+	return fake.listRoutesInSpaceArgsForCall[i].spaceGUID, fake.listRoutesInSpaceArgsForCall[i].cb
+}
+
+func (fake *FakeRouteRepository) ListRoutesInSpaceReturns(result1 error) {
+	fake.ListRoutesInSpaceStub = nil
+	fake.listRoutesInSpaceReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRouteRepository) ListAllRoutes(cb func(models.Route) bool) (apiErr error) {
 	fake.listAllRoutesMutex.Lock()
 	fake.listAllRoutesArgsForCall = append(fake.listAllRoutesArgsForCall, struct {
@@ -9,16 +9,25 @@ import (
 )
 
 type FakeServiceSummaryRepository struct {
-	GetSummariesInCurrentSpaceStub        func() (instances []models.ServiceInstance, apiErr error)
+	GetSummariesInCurrentSpaceStub        func() ([]models.ServiceInstance, error)
 	getSummariesInCurrentSpaceMutex       sync.RWMutex
 	getSummariesInCurrentSpaceArgsForCall []struct{}
 	getSummariesInCurrentSpaceReturns     struct {
 		result1 []models.ServiceInstance
 		result2 error
 	}
+	GetSummariesInSpaceStub        func(spaceGUID string) ([]models.ServiceInstance, error)
+	getSummariesInSpaceMutex       sync.RWMutex
+	getSummariesInSpaceArgsForCall []struct {
+		spaceGUID string
+	}
+	getSummariesInSpaceReturns struct {
+		result1 []models.ServiceInstance
+		result2 error
+	}
 }
 
-func (fake *FakeServiceSummaryRepository) GetSummariesInCurrentSpace() (instances []models.ServiceInstance, apiErr error) {
+func (fake *FakeServiceSummaryRepository) GetSummariesInCurrentSpace() ([]models.ServiceInstance, error) {
 	fake.getSummariesInCurrentSpaceMutex.Lock()
 	fake.getSummariesInCurrentSpaceArgsForCall = append(fake.getSummariesInCurrentSpaceArgsForCall, struct{}{})
 	fake.getSummariesInCurrentSpaceMutex.Unlock()
@@ -43,4 +52,37 @@ func (fake *FakeServiceSummaryRepository) GetSummariesInCurrentSpaceReturns(resu
 	}{result1, result2}
 }
 
+func (fake *FakeServiceSummaryRepository) GetSummariesInSpace(spaceGUID string) ([]models.ServiceInstance, error) {
+	fake.getSummariesInSpaceMutex.Lock()
+	fake.getSummariesInSpaceArgsForCall = append(fake.getSummariesInSpaceArgsForCall, struct {
+		spaceGUID string
+	}{spaceGUID})
+	fake.getSummariesInSpaceMutex.Unlock()
+	if fake.GetSummariesInSpaceStub != nil {
+		return fake.GetSummariesInSpaceStub(spaceGUID)
+	} else {
+		return fake.getSummariesInSpaceReturns.result1, fake.getSummariesInSpaceReturns.result2
+	}
+}
+
+func (fake *FakeServiceSummaryRepository) GetSummariesInSpaceCallCount() int {
+	fake.getSummariesInSpaceMutex.RLock()
+	defer fake.getSummariesInSpaceMutex.RUnlock()
+	return len(fake.getSummariesInSpaceArgsForCall)
+}
+
+func (fake *FakeServiceSummaryRepository) GetSummariesInSpaceArgsForCall(i int) string {
+	fake.getSummariesInSpaceMutex.RLock()
+	defer fake.getSummariesInSpaceMutex.RUnlock()
+	return fake.getSummariesInSpaceArgsForCall[i].spaceGUID
+}
+
+func (fake *FakeServiceSummaryRepository) GetSummariesInSpaceReturns(result1 []models.ServiceInstance, result2 error) {
+	fake.GetSummariesInSpaceStub = nil
+	fake.getSummariesInSpaceReturns = struct {
+		result1 []models.ServiceInstance
+		result2 error
+	}{result1, result2}
+}
+
 var _ api.ServiceSummaryRepository = new(FakeServiceSummaryRepository)
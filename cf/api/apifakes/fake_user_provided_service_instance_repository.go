@@ -35,6 +35,15 @@ type FakeUserProvidedServiceInstanceRepository struct {
 		result1 models.UserProvidedServiceSummary
 		result2 error
 	}
+	ListUserProvidedServicesInSpaceStub        func(spaceGUID string, cb func(models.ServiceInstanceFields) bool) error
+	listUserProvidedServicesInSpaceMutex       sync.RWMutex
+	listUserProvidedServicesInSpaceArgsForCall []struct {
+		spaceGUID string
+		cb        func(models.ServiceInstanceFields) bool
+	}
+	listUserProvidedServicesInSpaceReturns struct {
+		result1 error
+	}
 }
 
 func (fake *FakeUserProvidedServiceInstanceRepository) Create(name string, drainURL string, routeServiceURL string, params map[string]interface{}) (apiErr error) {
@@ -129,4 +138,37 @@ func (fake *FakeUserProvidedServiceInstanceRepository) GetSummariesReturns(resul
 	}{result1, result2}
 }
 
+func (fake *FakeUserProvidedServiceInstanceRepository) ListUserProvidedServicesInSpace(spaceGUID string, cb func(models.ServiceInstanceFields) bool) error {
+	fake.listUserProvidedServicesInSpaceMutex.Lock()
+	fake.listUserProvidedServicesInSpaceArgsForCall = append(fake.listUserProvidedServicesInSpaceArgsForCall, struct {
+		spaceGUID string
+		cb        func(models.ServiceInstanceFields) bool
+	}{spaceGUID, cb})
+	fake.listUserProvidedServicesInSpaceMutex.Unlock()
+	if fake.ListUserProvidedServicesInSpaceStub != nil {
+		return fake.ListUserProvidedServicesInSpaceStub(spaceGUID, cb)
+	} else {
+		return fake.listUserProvidedServicesInSpaceReturns.result1
+	}
+}
+
+func (fake *FakeUserProvidedServiceInstanceRepository) ListUserProvidedServicesInSpaceCallCount() int {
+	fake.listUserProvidedServicesInSpaceMutex.RLock()
+	defer fake.listUserProvidedServicesInSpaceMutex.RUnlock()
+	return len(fake.listUserProvidedServicesInSpaceArgsForCall)
+}
+
+func (fake *FakeUserProvidedServiceInstanceRepository) ListUserProvidedServicesInSpaceArgsForCall(i int) (string, func(models.ServiceInstanceFields) bool) {
+	fake.listUserProvidedServicesInSpaceMutex.RLock()
+	defer fake.listUserProvidedServicesInSpaceMutex.RUnlock()
+	return fake.listUserProvidedServicesInSpaceArgsForCall[i].spaceGUID, fake.listUserProvidedServicesInSpaceArgsForCall[i].cb
+}
+
+func (fake *FakeUserProvidedServiceInstanceRepository) ListUserProvidedServicesInSpaceReturns(result1 error) {
+	fake.ListUserProvidedServicesInSpaceStub = nil
+	fake.listUserProvidedServicesInSpaceReturns = struct {
+		result1 error
+	}{result1}
+}
+
 var _ api.UserProvidedServiceInstanceRepository = new(FakeUserProvidedServiceInstanceRepository)
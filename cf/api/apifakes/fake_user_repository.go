@@ -18,6 +18,25 @@ type FakeUserRepository struct {
 		result1 models.UserFields
 		result2 error
 	}
+	FindByUsernameAndOriginStub        func(username, origin string) (user models.UserFields, apiErr error)
+	findByUsernameAndOriginMutex       sync.RWMutex
+	findByUsernameAndOriginArgsForCall []struct {
+		username string
+		origin   string
+	}
+	findByUsernameAndOriginReturns struct {
+		result1 models.UserFields
+		result2 error
+	}
+	FindAllByUsernameStub        func(username string) (users []models.UserFields, apiErr error)
+	findAllByUsernameMutex       sync.RWMutex
+	findAllByUsernameArgsForCall []struct {
+		username string
+	}
+	findAllByUsernameReturns struct {
+		result1 []models.UserFields
+		result2 error
+	}
 	ListUsersInOrgForRoleStub        func(orgGUID string, role models.Role) ([]models.UserFields, error)
 	listUsersInOrgForRoleMutex       sync.RWMutex
 	listUsersInOrgForRoleArgsForCall []struct {
@@ -58,6 +77,26 @@ type FakeUserRepository struct {
 		result1 []models.UserFields
 		result2 error
 	}
+	ListOrgsForUserWithRoleStub        func(userGUID string, role models.Role) ([]models.OrganizationFields, error)
+	listOrgsForUserWithRoleMutex       sync.RWMutex
+	listOrgsForUserWithRoleArgsForCall []struct {
+		userGUID string
+		role     models.Role
+	}
+	listOrgsForUserWithRoleReturns struct {
+		result1 []models.OrganizationFields
+		result2 error
+	}
+	ListSpacesForUserWithRoleStub        func(userGUID string, role models.Role) ([]models.Space, error)
+	listSpacesForUserWithRoleMutex       sync.RWMutex
+	listSpacesForUserWithRoleArgsForCall []struct {
+		userGUID string
+		role     models.Role
+	}
+	listSpacesForUserWithRoleReturns struct {
+		result1 []models.Space
+		result2 error
+	}
 	CreateStub        func(username, password string) (apiErr error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
@@ -67,6 +106,15 @@ type FakeUserRepository struct {
 	createReturns struct {
 		result1 error
 	}
+	CreateWithOriginStub        func(username, origin string) (apiErr error)
+	createWithOriginMutex       sync.RWMutex
+	createWithOriginArgsForCall []struct {
+		username string
+		origin   string
+	}
+	createWithOriginReturns struct {
+		result1 error
+	}
 	DeleteStub        func(userGUID string) (apiErr error)
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
@@ -192,6 +240,73 @@ func (fake *FakeUserRepository) FindByUsernameReturns(result1 models.UserFields,
 	}{result1, result2}
 }
 
+func (fake *FakeUserRepository) FindByUsernameAndOrigin(username string, origin string) (user models.UserFields, apiErr error) {
+	fake.findByUsernameAndOriginMutex.Lock()
+	fake.findByUsernameAndOriginArgsForCall = append(fake.findByUsernameAndOriginArgsForCall, struct {
+		username string
+		origin   string
+	}{username, origin})
+	fake.findByUsernameAndOriginMutex.Unlock()
+	if fake.FindByUsernameAndOriginStub != nil {
+		return fake.FindByUsernameAndOriginStub(username, origin)
+	} else {
+		return fake.findByUsernameAndOriginReturns.result1, fake.findByUsernameAndOriginReturns.result2
+	}
+}
+
+func (fake *FakeUserRepository) FindByUsernameAndOriginCallCount() int {
+	fake.findByUsernameAndOriginMutex.RLock()
+	defer fake.findByUsernameAndOriginMutex.RUnlock()
+	return len(fake.findByUsernameAndOriginArgsForCall)
+}
+
+func (fake *FakeUserRepository) FindByUsernameAndOriginArgsForCall(i int) (string, string) {
+	fake.findByUsernameAndOriginMutex.RLock()
+	defer fake.findByUsernameAndOriginMutex.RUnlock()
+	return fake.findByUsernameAndOriginArgsForCall[i].username, fake.findByUsernameAndOriginArgsForCall[i].origin
+}
+
+func (fake *FakeUserRepository) FindByUsernameAndOriginReturns(result1 models.UserFields, result2 error) {
+	fake.FindByUsernameAndOriginStub = nil
+	fake.findByUsernameAndOriginReturns = struct {
+		result1 models.UserFields
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUserRepository) FindAllByUsername(username string) (users []models.UserFields, apiErr error) {
+	fake.findAllByUsernameMutex.Lock()
+	fake.findAllByUsernameArgsForCall = append(fake.findAllByUsernameArgsForCall, struct {
+		username string
+	}{username})
+	fake.findAllByUsernameMutex.Unlock()
+	if fake.FindAllByUsernameStub != nil {
+		return fake.FindAllByUsernameStub(username)
+	} else {
+		return fake.findAllByUsernameReturns.result1, fake.findAllByUsernameReturns.result2
+	}
+}
+
+func (fake *FakeUserRepository) FindAllByUsernameCallCount() int {
+	fake.findAllByUsernameMutex.RLock()
+	defer fake.findAllByUsernameMutex.RUnlock()
+	return len(fake.findAllByUsernameArgsForCall)
+}
+
+func (fake *FakeUserRepository) FindAllByUsernameArgsForCall(i int) string {
+	fake.findAllByUsernameMutex.RLock()
+	defer fake.findAllByUsernameMutex.RUnlock()
+	return fake.findAllByUsernameArgsForCall[i].username
+}
+
+func (fake *FakeUserRepository) FindAllByUsernameReturns(result1 []models.UserFields, result2 error) {
+	fake.FindAllByUsernameStub = nil
+	fake.findAllByUsernameReturns = struct {
+		result1 []models.UserFields
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeUserRepository) ListUsersInOrgForRole(orgGUID string, role models.Role) ([]models.UserFields, error) {
 	fake.listUsersInOrgForRoleMutex.Lock()
 	fake.listUsersInOrgForRoleArgsForCall = append(fake.listUsersInOrgForRoleArgsForCall, struct {
@@ -328,6 +443,74 @@ func (fake *FakeUserRepository) ListUsersInSpaceForRoleWithNoUAAReturns(result1
 	}{result1, result2}
 }
 
+func (fake *FakeUserRepository) ListOrgsForUserWithRole(userGUID string, role models.Role) ([]models.OrganizationFields, error) {
+	fake.listOrgsForUserWithRoleMutex.Lock()
+	fake.listOrgsForUserWithRoleArgsForCall = append(fake.listOrgsForUserWithRoleArgsForCall, struct {
+		userGUID string
+		role     models.Role
+	}{userGUID, role})
+	fake.listOrgsForUserWithRoleMutex.Unlock()
+	if fake.ListOrgsForUserWithRoleStub != nil {
+		return fake.ListOrgsForUserWithRoleStub(userGUID, role)
+	} else {
+		return fake.listOrgsForUserWithRoleReturns.result1, fake.listOrgsForUserWithRoleReturns.result2
+	}
+}
+
+func (fake *FakeUserRepository) ListOrgsForUserWithRoleCallCount() int {
+	fake.listOrgsForUserWithRoleMutex.RLock()
+	defer fake.listOrgsForUserWithRoleMutex.RUnlock()
+	return len(fake.listOrgsForUserWithRoleArgsForCall)
+}
+
+func (fake *FakeUserRepository) ListOrgsForUserWithRoleArgsForCall(i int) (string, models.Role) {
+	fake.listOrgsForUserWithRoleMutex.RLock()
+	defer fake.listOrgsForUserWithRoleMutex.RUnlock()
+	return fake.listOrgsForUserWithRoleArgsForCall[i].userGUID, fake.listOrgsForUserWithRoleArgsForCall[i].role
+}
+
+func (fake *FakeUserRepository) ListOrgsForUserWithRoleReturns(result1 []models.OrganizationFields, result2 error) {
+	fake.ListOrgsForUserWithRoleStub = nil
+	fake.listOrgsForUserWithRoleReturns = struct {
+		result1 []models.OrganizationFields
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUserRepository) ListSpacesForUserWithRole(userGUID string, role models.Role) ([]models.Space, error) {
+	fake.listSpacesForUserWithRoleMutex.Lock()
+	fake.listSpacesForUserWithRoleArgsForCall = append(fake.listSpacesForUserWithRoleArgsForCall, struct {
+		userGUID string
+		role     models.Role
+	}{userGUID, role})
+	fake.listSpacesForUserWithRoleMutex.Unlock()
+	if fake.ListSpacesForUserWithRoleStub != nil {
+		return fake.ListSpacesForUserWithRoleStub(userGUID, role)
+	} else {
+		return fake.listSpacesForUserWithRoleReturns.result1, fake.listSpacesForUserWithRoleReturns.result2
+	}
+}
+
+func (fake *FakeUserRepository) ListSpacesForUserWithRoleCallCount() int {
+	fake.listSpacesForUserWithRoleMutex.RLock()
+	defer fake.listSpacesForUserWithRoleMutex.RUnlock()
+	return len(fake.listSpacesForUserWithRoleArgsForCall)
+}
+
+func (fake *FakeUserRepository) ListSpacesForUserWithRoleArgsForCall(i int) (string, models.Role) {
+	fake.listSpacesForUserWithRoleMutex.RLock()
+	defer fake.listSpacesForUserWithRoleMutex.RUnlock()
+	return fake.listSpacesForUserWithRoleArgsForCall[i].userGUID, fake.listSpacesForUserWithRoleArgsForCall[i].role
+}
+
+func (fake *FakeUserRepository) ListSpacesForUserWithRoleReturns(result1 []models.Space, result2 error) {
+	fake.ListSpacesForUserWithRoleStub = nil
+	fake.listSpacesForUserWithRoleReturns = struct {
+		result1 []models.Space
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeUserRepository) Create(username string, password string) (apiErr error) {
 	fake.createMutex.Lock()
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
@@ -361,6 +544,39 @@ func (fake *FakeUserRepository) CreateReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeUserRepository) CreateWithOrigin(username string, origin string) (apiErr error) {
+	fake.createWithOriginMutex.Lock()
+	fake.createWithOriginArgsForCall = append(fake.createWithOriginArgsForCall, struct {
+		username string
+		origin   string
+	}{username, origin})
+	fake.createWithOriginMutex.Unlock()
+	if fake.CreateWithOriginStub != nil {
+		return fake.CreateWithOriginStub(username, origin)
+	} else {
+		return fake.createWithOriginReturns.result1
+	}
+}
+
+func (fake *FakeUserRepository) CreateWithOriginCallCount() int {
+	fake.createWithOriginMutex.RLock()
+	defer fake.createWithOriginMutex.RUnlock()
+	return len(fake.createWithOriginArgsForCall)
+}
+
+func (fake *FakeUserRepository) CreateWithOriginArgsForCall(i int) (string, string) {
+	fake.createWithOriginMutex.RLock()
+	defer fake.createWithOriginMutex.RUnlock()
+	return fake.createWithOriginArgsForCall[i].username, fake.createWithOriginArgsForCall[i].origin
+}
+
+func (fake *FakeUserRepository) CreateWithOriginReturns(result1 error) {
+	fake.CreateWithOriginStub = nil
+	fake.createWithOriginReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeUserRepository) Delete(userGUID string) (apiErr error) {
 	fake.deleteMutex.Lock()
 	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
@@ -18,6 +18,16 @@ func (repo *OldFakeAppSummaryRepo) GetSummariesInCurrentSpace() (apps []models.A
 	return
 }
 
+func (repo *OldFakeAppSummaryRepo) GetSummariesInSpace(spaceGUID string) (apps []models.Application, apiErr error) {
+	apps = repo.GetSummariesInCurrentSpaceApps
+	return
+}
+
+func (repo *OldFakeAppSummaryRepo) GetSpaceSummary(spaceGUID string) (apps []models.Application, serviceInstances []models.ServiceInstance, apiErr error) {
+	apps = repo.GetSummariesInCurrentSpaceApps
+	return
+}
+
 func (repo *OldFakeAppSummaryRepo) GetSummary(appGUID string) (summary models.Application, apiErr error) {
 	repo.GetSummaryAppGUID = appGUID
 	summary = repo.GetSummarySummary
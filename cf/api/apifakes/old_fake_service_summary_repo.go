@@ -4,9 +4,15 @@ import "github.com/cloudfoundry/cli/cf/models"
 
 type OldFakeServiceSummaryRepo struct {
 	GetSummariesInCurrentSpaceInstances []models.ServiceInstance
+	GetSummariesInSpaceInstances        []models.ServiceInstance
 }
 
 func (repo *OldFakeServiceSummaryRepo) GetSummariesInCurrentSpace() (instances []models.ServiceInstance, apiErr error) {
 	instances = repo.GetSummariesInCurrentSpaceInstances
 	return
 }
+
+func (repo *OldFakeServiceSummaryRepo) GetSummariesInSpace(spaceGUID string) (instances []models.ServiceInstance, apiErr error) {
+	instances = repo.GetSummariesInSpaceInstances
+	return
+}
@@ -21,6 +21,13 @@ func (resource ApplicationSummaries) ToModels() (apps []models.ApplicationFields
 	return
 }
 
+// SpaceSummary is the summary of a space, with its apps and its service
+// instances.
+type SpaceSummary struct {
+	Apps             []ApplicationFromSummary
+	ServiceInstances []ServiceInstanceSummary `json:"services"`
+}
+
 type ApplicationFromSummary struct {
 	GUID                 string
 	Name                 string
@@ -43,6 +50,7 @@ type ApplicationFromSummary struct {
 	PackageState         string     `json:"package_state"`
 	PackageUpdatedAt     *time.Time `json:"package_updated_at"`
 	Buildpack            string
+	ServiceNames         []string `json:"service_names"`
 }
 
 func (resource ApplicationFromSummary) ToFields() (app models.ApplicationFields) {
@@ -129,6 +137,8 @@ type DomainSummary struct {
 
 type AppSummaryRepository interface {
 	GetSummariesInCurrentSpace() (apps []models.Application, apiErr error)
+	GetSummariesInSpace(spaceGUID string) (apps []models.Application, apiErr error)
+	GetSpaceSummary(spaceGUID string) (apps []models.Application, serviceInstances []models.ServiceInstance, apiErr error)
 	GetSummary(appGUID string) (summary models.Application, apiErr error)
 }
 
@@ -144,9 +154,13 @@ func NewCloudControllerAppSummaryRepository(config coreconfig.Reader, gateway ne
 }
 
 func (repo CloudControllerAppSummaryRepository) GetSummariesInCurrentSpace() ([]models.Application, error) {
+	return repo.GetSummariesInSpace(repo.config.SpaceFields().GUID)
+}
+
+func (repo CloudControllerAppSummaryRepository) GetSummariesInSpace(spaceGUID string) ([]models.Application, error) {
 	resources := new(ApplicationSummaries)
 
-	path := fmt.Sprintf("%s/v2/spaces/%s/summary", repo.config.APIEndpoint(), repo.config.SpaceFields().GUID)
+	path := fmt.Sprintf("%s/v2/spaces/%s/summary", repo.config.APIEndpoint(), spaceGUID)
 	err := repo.gateway.GetResource(path, resources)
 	if err != nil {
 		return []models.Application{}, err
@@ -160,6 +174,27 @@ func (repo CloudControllerAppSummaryRepository) GetSummariesInCurrentSpace() ([]
 	return apps, nil
 }
 
+// GetSpaceSummary returns both the apps and the service instances of the
+// space from a single request.
+func (repo CloudControllerAppSummaryRepository) GetSpaceSummary(spaceGUID string) ([]models.Application, []models.ServiceInstance, error) {
+	resource := new(SpaceSummary)
+
+	path := fmt.Sprintf("%s/v2/spaces/%s/summary", repo.config.APIEndpoint(), spaceGUID)
+	err := repo.gateway.GetResource(path, resource)
+	if err != nil {
+		return []models.Application{}, nil, err
+	}
+
+	apps := make([]models.Application, len(resource.Apps))
+	serviceSummaries := ServiceInstancesSummaries{ServiceInstances: resource.ServiceInstances}
+	for i, app := range resource.Apps {
+		apps[i] = app.ToModel()
+		serviceSummaries.Apps = append(serviceSummaries.Apps, ServiceInstanceSummaryApp{Name: app.Name, ServiceNames: app.ServiceNames})
+	}
+
+	return apps, serviceSummaries.ToModels(), nil
+}
+
 func (repo CloudControllerAppSummaryRepository) GetSummary(appGUID string) (summary models.Application, apiErr error) {
 	path := fmt.Sprintf("%s/v2/apps/%s/summary", repo.config.APIEndpoint(), appGUID)
 	summaryResponse := new(ApplicationFromSummary)
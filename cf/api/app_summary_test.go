@@ -85,6 +85,87 @@ var _ = Describe("AppSummaryRepository", func() {
 		})
 	})
 
+	Describe("GetSummariesInSpace()", func() {
+		BeforeEach(func() {
+			getAppSummariesRequest := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/spaces/other-space-guid/summary",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body:   getAppSummariesResponseBody,
+				},
+			})
+
+			testServer, handler = testnet.NewServer([]testnet.TestRequest{getAppSummariesRequest})
+			configRepo := testconfig.NewRepositoryWithDefaults()
+			configRepo.SetAPIEndpoint(testServer.URL)
+			gateway := cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
+			repo = NewCloudControllerAppSummaryRepository(configRepo, gateway)
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+		})
+
+		It("returns the app summaries of the given space", func() {
+			apps, apiErr := repo.GetSummariesInSpace("other-space-guid")
+			Expect(handler).To(HaveAllRequestsCalled())
+
+			Expect(apiErr).NotTo(HaveOccurred())
+			Expect(apps).To(HaveLen(3))
+			Expect(apps[1].Name).To(Equal("app2"))
+			Expect(apps[1].InstanceCount).To(Equal(3))
+			Expect(apps[1].Memory).To(Equal(int64(512)))
+		})
+	})
+
+	Describe("GetSpaceSummary()", func() {
+		BeforeEach(func() {
+			getSpaceSummaryRequest := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/spaces/other-space-guid/summary",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body: `{
+  "apps": [
+    {"name": "app1", "state": "STARTED", "instances": 2, "memory": 256, "service_names": ["my-db"]}
+  ],
+  "services": [
+    {"name": "my-db", "service_plan": {"name": "small", "guid": "small-guid", "service": {"label": "mysql"}}},
+    {"name": "my-ups"}
+  ]
+}`,
+				},
+			})
+
+			testServer, handler = testnet.NewServer([]testnet.TestRequest{getSpaceSummaryRequest})
+			configRepo := testconfig.NewRepositoryWithDefaults()
+			configRepo.SetAPIEndpoint(testServer.URL)
+			gateway := cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
+			repo = NewCloudControllerAppSummaryRepository(configRepo, gateway)
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+		})
+
+		It("returns the apps and the service instances of the space from one request", func() {
+			apps, serviceInstances, apiErr := repo.GetSpaceSummary("other-space-guid")
+			Expect(handler).To(HaveAllRequestsCalled())
+
+			Expect(apiErr).NotTo(HaveOccurred())
+			Expect(apps).To(HaveLen(1))
+			Expect(apps[0].State).To(Equal("started"))
+			Expect(apps[0].InstanceCount).To(Equal(2))
+
+			Expect(serviceInstances).To(HaveLen(2))
+			Expect(serviceInstances[0].Name).To(Equal("my-db"))
+			Expect(serviceInstances[0].ServicePlan.GUID).To(Equal("small-guid"))
+			Expect(serviceInstances[0].ApplicationNames).To(Equal([]string{"app1"}))
+			Expect(serviceInstances[1].IsUserProvided()).To(BeTrue())
+		})
+	})
+
 	Describe("GetSummary()", func() {
 		BeforeEach(func() {
 			getAppSummaryRequest := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
@@ -1,6 +1,11 @@
 package appevents
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
 	"github.com/cloudfoundry/cli/cf/api/resources"
 	"github.com/cloudfoundry/cli/cf/api/strategy"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
@@ -12,6 +17,25 @@ import (
 
 type AppEventsRepository interface {
 	RecentEvents(appGUID string, limit int64) ([]models.EventFields, error)
+	ListEvents(query EventQuery) ([]models.EventFields, error)
+}
+
+// EventQuery selects events with the filters of /v2/events. Only one of
+// ActeeGUID, SpaceGUID and OrganizationGUID is used, in that order.
+type EventQuery struct {
+	ActeeGUID        string
+	SpaceGUID        string
+	OrganizationGUID string
+	Types            []string
+	// Actor matches the GUID or the name of the actor. The CC cannot filter
+	// by actor, so the events are filtered after they are fetched.
+	Actor string
+	// Since selects the events at or after the time.
+	Since time.Time
+	// Ascending lists the oldest events first instead of the newest.
+	Ascending bool
+	// Limit is the maximum number of events, or 0 for all of them.
+	Limit int64
 }
 
 type CloudControllerAppEventsRepository struct {
@@ -50,3 +74,64 @@ func (repo CloudControllerAppEventsRepository) listEvents(appGUID string, limit
 			return cb(resource.(resources.EventResource).ToFields())
 		})
 }
+
+func (repo CloudControllerAppEventsRepository) ListEvents(query EventQuery) ([]models.EventFields, error) {
+	events := []models.EventFields{}
+	err := repo.gateway.ListPaginatedResources(
+		repo.config.APIEndpoint(),
+		eventsURL(query),
+		resources.EventResourceNewV2{},
+
+		func(resource interface{}) bool {
+			event := resource.(resources.EventResourceNewV2).ToFields()
+			if query.Actor != "" && query.Actor != event.Actor && query.Actor != event.ActorName {
+				return true
+			}
+
+			events = append(events, event)
+			return query.Limit == 0 || int64(len(events)) < query.Limit
+		})
+
+	return events, err
+}
+
+func eventsURL(query EventQuery) string {
+	filters := []string{}
+	switch {
+	case query.ActeeGUID != "":
+		filters = append(filters, "actee:"+query.ActeeGUID)
+	case query.SpaceGUID != "":
+		filters = append(filters, "space_guid:"+query.SpaceGUID)
+	case query.OrganizationGUID != "":
+		filters = append(filters, "organization_guid:"+query.OrganizationGUID)
+	}
+
+	if len(query.Types) == 1 {
+		filters = append(filters, "type:"+query.Types[0])
+	} else if len(query.Types) > 1 {
+		filters = append(filters, "type IN "+strings.Join(query.Types, ","))
+	}
+
+	if !query.Since.IsZero() {
+		filters = append(filters, "timestamp>="+query.Since.UTC().Format(time.RFC3339))
+	}
+
+	params := []string{}
+	for _, filter := range filters {
+		params = append(params, "q="+url.QueryEscape(filter))
+	}
+
+	direction := "desc"
+	if query.Ascending {
+		direction = "asc"
+	}
+	params = append(params, "order-direction="+direction)
+
+	perPage := int64(100)
+	if query.Limit > 0 && query.Limit < perPage && query.Actor == "" {
+		perPage = query.Limit
+	}
+	params = append(params, fmt.Sprintf("results-per-page=%d", perPage))
+
+	return "/v2/events?" + strings.Join(params, "&")
+}
@@ -3,6 +3,7 @@ package appevents_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 
 	. "github.com/cloudfoundry/cli/cf/api/appevents"
 	"github.com/cloudfoundry/cli/cf/api/strategy"
@@ -12,6 +13,8 @@ import (
 	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
 	testnet "github.com/cloudfoundry/cli/testhelpers/net"
 	testtime "github.com/cloudfoundry/cli/testhelpers/time"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -72,6 +75,51 @@ var _ = Describe("App Events Repo", func() {
 			}))
 		})
 	})
+
+	Describe("list events", func() {
+		It("filters the events of a space by type and time", func() {
+			setupTestServer(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/events?q=space_guid%3Amy-space-guid&q=type+IN+audit.app.crash%2Capp.crash&q=timestamp%3E%3D2014-01-21T00%3A00%3A00Z&order-direction=asc&results-per-page=100",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body:   strings.Replace(eventsRequest.Response.Body, `"/v2/events?q=actee%3Amy-app-guid&page=2"`, "null", 1),
+				},
+			})
+
+			list, err := repo.ListEvents(EventQuery{
+				SpaceGUID: "my-space-guid",
+				Types:     []string{"audit.app.crash", "app.crash"},
+				Since:     testtime.MustParse(eventTimestampFormat, "2014-01-21T00:00:00+00:00"),
+				Ascending: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(handler).To(HaveAllRequestsCalled())
+
+			Expect(list).To(HaveLen(2))
+			Expect(list[0].GUID).To(Equal("event-1-guid"))
+		})
+
+		It("filters the events by actor and stops at the limit", func() {
+			setupTestServer(testnet.TestRequest{
+				Method:   "GET",
+				Path:     "/v2/events?q=organization_guid%3Amy-org-guid&q=type%3Aaudit.app.update&order-direction=desc&results-per-page=100",
+				Response: eventsRequest.Response,
+			})
+
+			list, err := repo.ListEvents(EventQuery{
+				OrganizationGUID: "my-org-guid",
+				Types:            []string{"audit.app.update"},
+				Actor:            "nobody@pivotallabs.com",
+				Limit:            1,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(handler).To(HaveAllRequestsCalled())
+
+			Expect(list).To(HaveLen(1))
+			Expect(list[0].GUID).To(Equal("event-2-guid"))
+		})
+	})
 })
 
 const eventTimestampFormat = "2006-01-02T15:04:05-07:00"
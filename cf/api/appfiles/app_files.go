@@ -2,8 +2,11 @@ package appfiles
 
 import (
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/cf/net"
 )
 
@@ -11,6 +14,7 @@ import (
 
 type AppFilesRepository interface {
 	ListFiles(appGUID string, instance int, path string) (files string, apiErr error)
+	DownloadFile(appGUID string, instance int, path string, destination io.Writer) error
 }
 
 type CloudControllerAppFilesRepository struct {
@@ -34,3 +38,54 @@ func (repo CloudControllerAppFilesRepository) ListFiles(appGUID string, instance
 	files, _, apiErr = repo.gateway.PerformRequestForTextResponse(request)
 	return
 }
+
+func (repo CloudControllerAppFilesRepository) DownloadFile(appGUID string, instance int, path string, destination io.Writer) error {
+	url := fmt.Sprintf("%s/v2/apps/%s/instances/%d/files/%s", repo.config.APIEndpoint(), appGUID, instance, path)
+	request, err := repo.gateway.NewRequest("GET", url, repo.config.AccessToken(), nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := repo.gateway.PerformRequest(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	_, err = io.Copy(destination, response.Body)
+	if err != nil {
+		return fmt.Errorf("%s: %s", T("Error downloading file"), err.Error())
+	}
+
+	return nil
+}
+
+type FileEntry struct {
+	Name  string
+	IsDir bool
+}
+
+// ParseFileListing parses the directory listing returned by ListFiles. Each
+// line holds an entry name followed by its size; directories have a trailing
+// slash and a size of "-".
+func ParseFileListing(listing string) []FileEntry {
+	entries := []FileEntry{}
+	for _, line := range strings.Split(listing, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name := line
+		if i := strings.LastIndexAny(line, " \t"); i != -1 {
+			name = strings.TrimSpace(line[:i])
+		}
+
+		if strings.HasSuffix(name, "/") {
+			entries = append(entries, FileEntry{Name: strings.TrimSuffix(name, "/"), IsDir: true})
+		} else {
+			entries = append(entries, FileEntry{Name: name})
+		}
+	}
+	return entries
+}
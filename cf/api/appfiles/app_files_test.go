@@ -1,6 +1,7 @@
 package appfiles_test
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -64,4 +65,52 @@ var _ = Describe("AppFilesRepository", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(list).To(Equal(expectedResponse))
 	})
+
+	It("downloads a file", func() {
+		req := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+			Method: "GET",
+			Path:   "/v2/apps/my-app-guid/instances/2/files/app/logs/heap.hprof",
+			Response: testnet.TestResponse{
+				Status: http.StatusOK,
+				Body:   "heap-dump",
+			},
+		})
+
+		server, handler := testnet.NewServer([]testnet.TestRequest{req})
+		defer server.Close()
+
+		configRepo := testconfig.NewRepositoryWithDefaults()
+		configRepo.SetAPIEndpoint(server.URL)
+
+		gateway := cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
+		repo := NewCloudControllerAppFilesRepository(configRepo, gateway)
+
+		destination := &bytes.Buffer{}
+		err := repo.DownloadFile("my-app-guid", 2, "app/logs/heap.hprof", destination)
+
+		Expect(handler).To(HaveAllRequestsCalled())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(destination.String()).To(ContainSubstring("heap-dump"))
+	})
+})
+
+var _ = Describe("ParseFileListing", func() {
+	It("parses files and directories", func() {
+		listing := `
+.bash_logout                              220B
+app/                                         -
+my file.txt                               1.2K
+logs/                                        -
+`
+		Expect(ParseFileListing(listing)).To(Equal([]FileEntry{
+			{Name: ".bash_logout"},
+			{Name: "app", IsDir: true},
+			{Name: "my file.txt"},
+			{Name: "logs", IsDir: true},
+		}))
+	})
+
+	It("returns no entries for an empty listing", func() {
+		Expect(ParseFileListing("")).To(BeEmpty())
+	})
 })
@@ -2,6 +2,7 @@
 package appfilesfakes
 
 import (
+	"io"
 	"sync"
 
 	"github.com/cloudfoundry/cli/cf/api/appfiles"
@@ -19,6 +20,17 @@ type FakeAppFilesRepository struct {
 		result1 string
 		result2 error
 	}
+	DownloadFileStub        func(appGUID string, instance int, path string, destination io.Writer) error
+	downloadFileMutex       sync.RWMutex
+	downloadFileArgsForCall []struct {
+		appGUID     string
+		instance    int
+		path        string
+		destination io.Writer
+	}
+	downloadFileReturns struct {
+		result1 error
+	}
 }
 
 func (fake *FakeAppFilesRepository) ListFiles(appGUID string, instance int, path string) (files string, apiErr error) {
@@ -56,4 +68,39 @@ func (fake *FakeAppFilesRepository) ListFilesReturns(result1 string, result2 err
 	}{result1, result2}
 }
 
+func (fake *FakeAppFilesRepository) DownloadFile(appGUID string, instance int, path string, destination io.Writer) error {
+	fake.downloadFileMutex.Lock()
+	fake.downloadFileArgsForCall = append(fake.downloadFileArgsForCall, struct {
+		appGUID     string
+		instance    int
+		path        string
+		destination io.Writer
+	}{appGUID, instance, path, destination})
+	fake.downloadFileMutex.Unlock()
+	if fake.DownloadFileStub != nil {
+		return fake.DownloadFileStub(appGUID, instance, path, destination)
+	} else {
+		return fake.downloadFileReturns.result1
+	}
+}
+
+func (fake *FakeAppFilesRepository) DownloadFileCallCount() int {
+	fake.downloadFileMutex.RLock()
+	defer fake.downloadFileMutex.RUnlock()
+	return len(fake.downloadFileArgsForCall)
+}
+
+func (fake *FakeAppFilesRepository) DownloadFileArgsForCall(i int) (string, int, string, io.Writer) {
+	fake.downloadFileMutex.RLock()
+	defer fake.downloadFileMutex.RUnlock()
+	return fake.downloadFileArgsForCall[i].appGUID, fake.downloadFileArgsForCall[i].instance, fake.downloadFileArgsForCall[i].path, fake.downloadFileArgsForCall[i].destination
+}
+
+func (fake *FakeAppFilesRepository) DownloadFileReturns(result1 error) {
+	fake.DownloadFileStub = nil
+	fake.downloadFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
 var _ appfiles.AppFilesRepository = new(FakeAppFilesRepository)
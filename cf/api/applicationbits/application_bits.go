@@ -26,6 +26,8 @@ const (
 type ApplicationBitsRepository interface {
 	GetApplicationFiles(appFilesRequest []resources.AppFileResource) ([]resources.AppFileResource, error)
 	UploadBits(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) (apiErr error)
+	UploadDroplet(appGUID string, dropletFile *os.File) error
+	DownloadDroplet(appGUID string, destination io.Writer) error
 }
 
 type CloudControllerApplicationBitsRepository struct {
@@ -39,26 +41,62 @@ func NewCloudControllerApplicationBitsRepository(config coreconfig.Reader, gatew
 	return
 }
 
-func (repo CloudControllerApplicationBitsRepository) UploadBits(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) (apiErr error) {
+func (repo CloudControllerApplicationBitsRepository) UploadBits(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error {
 	apiURL := fmt.Sprintf("/v2/apps/%s/bits", appGUID)
-	fileutils.TempFile("requests", func(requestFile *os.File, err error) {
-		if err != nil {
-			apiErr = fmt.Errorf("%s: %s", T("Error creating tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err.Error())
-			return
-		}
 
-		// json.Marshal represents a nil value as "null" instead of an empty slice "[]"
-		if presentFiles == nil {
-			presentFiles = []resources.AppFileResource{}
-		}
+	// json.Marshal represents a nil value as "null" instead of an empty slice "[]"
+	if presentFiles == nil {
+		presentFiles = []resources.AppFileResource{}
+	}
+
+	presentFilesJSON, err := json.Marshal(presentFiles)
+	if err != nil {
+		return fmt.Errorf("%s: %s", T("Error marshaling JSON"), err.Error())
+	}
+
+	return repo.uploadMultipart(apiURL, func(requestFile *os.File) (string, error) {
+		return repo.writeUploadBody(zipFile, requestFile, presentFilesJSON)
+	})
+}
+
+func (repo CloudControllerApplicationBitsRepository) UploadDroplet(appGUID string, dropletFile *os.File) error {
+	apiURL := fmt.Sprintf("/v2/apps/%s/droplet/upload", appGUID)
+
+	return repo.uploadMultipart(apiURL, func(requestFile *os.File) (string, error) {
+		return repo.writeDropletUploadBody(dropletFile, requestFile)
+	})
+}
+
+func (repo CloudControllerApplicationBitsRepository) DownloadDroplet(appGUID string, destination io.Writer) error {
+	apiURL := fmt.Sprintf("%s/v2/apps/%s/droplet/download", repo.config.APIEndpoint(), appGUID)
+
+	request, err := repo.gateway.NewRequest("GET", apiURL, repo.config.AccessToken(), nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := repo.gateway.PerformRequest(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	_, err = io.Copy(destination, response.Body)
+	if err != nil {
+		return fmt.Errorf("%s: %s", T("Error downloading droplet"), err.Error())
+	}
+
+	return nil
+}
 
-		presentFilesJSON, err := json.Marshal(presentFiles)
+func (repo CloudControllerApplicationBitsRepository) uploadMultipart(apiURL string, writeBody func(requestFile *os.File) (string, error)) (apiErr error) {
+	fileutils.TempFile("requests", func(requestFile *os.File, err error) {
 		if err != nil {
-			apiErr = fmt.Errorf("%s: %s", T("Error marshaling JSON"), err.Error())
+			apiErr = fmt.Errorf("%s: %s", T("Error creating tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err.Error())
 			return
 		}
 
-		boundary, err := repo.writeUploadBody(zipFile, requestFile, presentFilesJSON)
+		boundary, err := writeBody(requestFile)
 		if err != nil {
 			apiErr = fmt.Errorf("%s: %s", T("Error writing to tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err.Error())
 			return
@@ -173,6 +211,32 @@ func (repo CloudControllerApplicationBitsRepository) writeUploadBody(zipFile *os
 	return
 }
 
+func (repo CloudControllerApplicationBitsRepository) writeDropletUploadBody(dropletFile *os.File, body *os.File) (boundary string, err error) {
+	writer := multipart.NewWriter(body)
+	defer writer.Close()
+
+	boundary = writer.Boundary()
+
+	dropletStats, err := dropletFile.Stat()
+	if err != nil {
+		return
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="droplet"; filename="droplet.tgz"`)
+	h.Set("Content-Type", "application/gzip")
+	h.Set("Content-Length", fmt.Sprintf("%d", dropletStats.Size()))
+	h.Set("Content-Transfer-Encoding", "binary")
+
+	part, err := writer.CreatePart(h)
+	if err != nil {
+		return
+	}
+
+	_, err = io.Copy(part, dropletFile)
+	return
+}
+
 func createZipPartWriter(zipStats os.FileInfo, writer *multipart.Writer) (io.Writer, error) {
 	h := make(textproto.MIMEHeader)
 	h.Set("Content-Disposition", `form-data; name="application"; filename="application.zip"`)
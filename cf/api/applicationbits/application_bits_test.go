@@ -2,7 +2,9 @@ package applicationbits_test
 
 import (
 	"archive/zip"
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -198,6 +200,122 @@ var _ = Describe("CloudControllerApplicationBitsRepository", func() {
 		})
 	})
 
+	Describe(".UploadDroplet", func() {
+		var dropletFile *os.File
+
+		BeforeEach(func() {
+			var err error
+			dropletFile, err = ioutil.TempFile("", "droplet")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = dropletFile.WriteString("droplet-contents")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = dropletFile.Seek(0, 0)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+			dropletFile.Close()
+			os.Remove(dropletFile.Name())
+		})
+
+		It("uploads the droplet as multipart form data and waits for the job to finish", func() {
+			setupTestServer(testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "PUT",
+				Path:   "/v2/apps/my-cool-app-guid/droplet/upload",
+				Matcher: func(request *http.Request) {
+					err := request.ParseMultipartForm(maxMultipartResponseSizeInBytes)
+					Expect(err).NotTo(HaveOccurred())
+					defer request.MultipartForm.RemoveAll()
+
+					fileHeaders, ok := request.MultipartForm.File["droplet"]
+					Expect(ok).To(BeTrue(), "Droplet file part not present")
+					Expect(fileHeaders).To(HaveLen(1))
+
+					file, err := fileHeaders[0].Open()
+					Expect(err).NotTo(HaveOccurred())
+					contents, err := ioutil.ReadAll(file)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(Equal("droplet-contents"))
+				},
+				Response: testnet.TestResponse{
+					Status: http.StatusCreated,
+					Body: `
+					{
+						"metadata":{
+							"guid": "my-job-guid",
+							"url": "/v2/jobs/my-job-guid"
+						}
+					}`,
+				},
+			}),
+				createProgressEndpoint("running"),
+				createProgressEndpoint("finished"),
+			)
+
+			err := repo.UploadDroplet("my-cool-app-guid", dropletFile)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns a failure when the upload job fails", func() {
+			setupTestServer(testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "PUT",
+				Path:   "/v2/apps/my-cool-app-guid/droplet/upload",
+				Response: testnet.TestResponse{
+					Status: http.StatusCreated,
+					Body: `
+					{
+						"metadata":{
+							"guid": "my-job-guid",
+							"url": "/v2/jobs/my-job-guid"
+						}
+					}`,
+				},
+			}),
+				createProgressEndpoint("failed"),
+			)
+
+			err := repo.UploadDroplet("my-cool-app-guid", dropletFile)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe(".DownloadDroplet", func() {
+		AfterEach(func() {
+			testServer.Close()
+		})
+
+		It("writes the droplet to the given destination", func() {
+			setupTestServer(testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/apps/my-cool-app-guid/droplet/download",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body:   "droplet-contents",
+				},
+			}))
+
+			destination := &bytes.Buffer{}
+			err := repo.DownloadDroplet("my-cool-app-guid", destination)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(destination.String()).To(ContainSubstring("droplet-contents"))
+		})
+
+		It("returns an error when the app has no droplet", func() {
+			setupTestServer(testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/apps/my-cool-app-guid/droplet/download",
+				Response: testnet.TestResponse{
+					Status: http.StatusNotFound,
+					Body:   `{"code": 10010, "description": "Droplet not found"}`,
+				},
+			}))
+
+			err := repo.DownloadDroplet("my-cool-app-guid", &bytes.Buffer{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe(".GetApplicationFiles", func() {
 		It("accepts a slice of files and returns a slice of the files that it already has", func() {
 			setupTestServer(matchResourceRequest)
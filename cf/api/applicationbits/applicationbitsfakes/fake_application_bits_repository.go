@@ -2,6 +2,7 @@
 package applicationbitsfakes
 
 import (
+	"io"
 	"os"
 	"sync"
 
@@ -29,6 +30,24 @@ type FakeApplicationBitsRepository struct {
 	uploadBitsReturns struct {
 		result1 error
 	}
+	UploadDropletStub        func(appGUID string, dropletFile *os.File) error
+	uploadDropletMutex       sync.RWMutex
+	uploadDropletArgsForCall []struct {
+		appGUID     string
+		dropletFile *os.File
+	}
+	uploadDropletReturns struct {
+		result1 error
+	}
+	DownloadDropletStub        func(appGUID string, destination io.Writer) error
+	downloadDropletMutex       sync.RWMutex
+	downloadDropletArgsForCall []struct {
+		appGUID     string
+		destination io.Writer
+	}
+	downloadDropletReturns struct {
+		result1 error
+	}
 }
 
 func (fake *FakeApplicationBitsRepository) GetApplicationFiles(appFilesRequest []resources.AppFileResource) ([]resources.AppFileResource, error) {
@@ -108,4 +127,70 @@ func (fake *FakeApplicationBitsRepository) UploadBitsReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeApplicationBitsRepository) UploadDroplet(appGUID string, dropletFile *os.File) error {
+	fake.uploadDropletMutex.Lock()
+	fake.uploadDropletArgsForCall = append(fake.uploadDropletArgsForCall, struct {
+		appGUID     string
+		dropletFile *os.File
+	}{appGUID, dropletFile})
+	fake.uploadDropletMutex.Unlock()
+	if fake.UploadDropletStub != nil {
+		return fake.UploadDropletStub(appGUID, dropletFile)
+	} else {
+		return fake.uploadDropletReturns.result1
+	}
+}
+
+func (fake *FakeApplicationBitsRepository) UploadDropletCallCount() int {
+	fake.uploadDropletMutex.RLock()
+	defer fake.uploadDropletMutex.RUnlock()
+	return len(fake.uploadDropletArgsForCall)
+}
+
+func (fake *FakeApplicationBitsRepository) UploadDropletArgsForCall(i int) (string, *os.File) {
+	fake.uploadDropletMutex.RLock()
+	defer fake.uploadDropletMutex.RUnlock()
+	return fake.uploadDropletArgsForCall[i].appGUID, fake.uploadDropletArgsForCall[i].dropletFile
+}
+
+func (fake *FakeApplicationBitsRepository) UploadDropletReturns(result1 error) {
+	fake.UploadDropletStub = nil
+	fake.uploadDropletReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeApplicationBitsRepository) DownloadDroplet(appGUID string, destination io.Writer) error {
+	fake.downloadDropletMutex.Lock()
+	fake.downloadDropletArgsForCall = append(fake.downloadDropletArgsForCall, struct {
+		appGUID     string
+		destination io.Writer
+	}{appGUID, destination})
+	fake.downloadDropletMutex.Unlock()
+	if fake.DownloadDropletStub != nil {
+		return fake.DownloadDropletStub(appGUID, destination)
+	} else {
+		return fake.downloadDropletReturns.result1
+	}
+}
+
+func (fake *FakeApplicationBitsRepository) DownloadDropletCallCount() int {
+	fake.downloadDropletMutex.RLock()
+	defer fake.downloadDropletMutex.RUnlock()
+	return len(fake.downloadDropletArgsForCall)
+}
+
+func (fake *FakeApplicationBitsRepository) DownloadDropletArgsForCall(i int) (string, io.Writer) {
+	fake.downloadDropletMutex.RLock()
+	defer fake.downloadDropletMutex.RUnlock()
+	return fake.downloadDropletArgsForCall[i].appGUID, fake.downloadDropletArgsForCall[i].destination
+}
+
+func (fake *FakeApplicationBitsRepository) DownloadDropletReturns(result1 error) {
+	fake.DownloadDropletStub = nil
+	fake.downloadDropletReturns = struct {
+		result1 error
+	}{result1}
+}
+
 var _ applicationbits.ApplicationBitsRepository = new(FakeApplicationBitsRepository)
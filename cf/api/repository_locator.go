@@ -27,6 +27,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/api/spaces"
 	"github.com/cloudfoundry/cli/cf/api/stacks"
 	"github.com/cloudfoundry/cli/cf/api/strategy"
+	"github.com/cloudfoundry/cli/cf/api/usageevents"
 	"github.com/cloudfoundry/cli/cf/appfiles"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/net"
@@ -46,7 +47,7 @@ type RepositoryLocator struct {
 	quotaRepo                       quotas.QuotaRepository
 	spaceRepo                       spaces.SpaceRepository
 	appRepo                         applications.ApplicationRepository
-	appBitsRepo                     applicationbits.CloudControllerApplicationBitsRepository
+	appBitsRepo                     applicationbits.ApplicationBitsRepository
 	appSummaryRepo                  AppSummaryRepository
 	appInstancesRepo                appinstances.AppInstancesRepository
 	appEventsRepo                   appevents.AppEventsRepository
@@ -78,6 +79,7 @@ type RepositoryLocator struct {
 	featureFlagRepo                 featureflags.FeatureFlagRepository
 	environmentVariableGroupRepo    environmentvariablegroups.EnvironmentVariableGroupsRepository
 	copyAppSourceRepo               copyapplicationsource.CopyApplicationSourceRepository
+	usageEventsRepo                 usageevents.UsageEventsRepository
 
 	v3Repository repository.Repository
 }
@@ -146,9 +148,10 @@ func NewRepositoryLocator(config coreconfig.ReadWriter, gatewaysByName map[strin
 	loc.featureFlagRepo = featureflags.NewCloudControllerFeatureFlagRepository(config, cloudControllerGateway)
 	loc.environmentVariableGroupRepo = environmentvariablegroups.NewCloudControllerEnvironmentVariableGroupsRepository(config, cloudControllerGateway)
 	loc.copyAppSourceRepo = copyapplicationsource.NewCloudControllerCopyApplicationSourceRepository(config, cloudControllerGateway)
+	loc.usageEventsRepo = usageevents.NewCloudControllerUsageEventsRepository(config, cloudControllerGateway)
 
 	client := v3client.NewClient(config.APIEndpoint(), config.AuthenticationEndpoint(), config.AccessToken(), config.RefreshToken())
-	loc.v3Repository = repository.NewRepository(config, client)
+	loc.v3Repository = repository.NewRepository(config, client, cloudControllerGateway)
 
 	return
 }
@@ -216,6 +219,11 @@ func (locator RepositoryLocator) GetApplicationRepository() applications.Applica
 	return locator.appRepo
 }
 
+func (locator RepositoryLocator) SetApplicationBitsRepository(repo applicationbits.ApplicationBitsRepository) RepositoryLocator {
+	locator.appBitsRepo = repo
+	return locator
+}
+
 func (locator RepositoryLocator) GetApplicationBitsRepository() applicationbits.ApplicationBitsRepository {
 	return locator.appBitsRepo
 }
@@ -488,6 +496,15 @@ func (locator RepositoryLocator) GetCopyApplicationSourceRepository() copyapplic
 	return locator.copyAppSourceRepo
 }
 
+func (locator RepositoryLocator) SetUsageEventsRepository(repo usageevents.UsageEventsRepository) RepositoryLocator {
+	locator.usageEventsRepo = repo
+	return locator
+}
+
+func (locator RepositoryLocator) GetUsageEventsRepository() usageevents.UsageEventsRepository {
+	return locator.usageEventsRepo
+}
+
 func (locator RepositoryLocator) GetV3Repository() repository.Repository {
 	return locator.v3Repository
 }
@@ -22,6 +22,9 @@ type EventResourceNewV2 struct {
 		Type      string
 		Actor     string `json:"actor"`
 		ActorName string `json:"actor_name"`
+		Actee     string `json:"actee"`
+		ActeeType string `json:"actee_type"`
+		ActeeName string `json:"actee_name"`
 		Metadata  map[string]interface{}
 	}
 }
@@ -49,6 +52,9 @@ func (resource EventResourceNewV2) ToFields() models.EventFields {
 		Description: formatDescription(metadata, knownMetadataKeys),
 		Actor:       resource.Entity.Actor,
 		ActorName:   resource.Entity.ActorName,
+		Actee:       resource.Entity.Actee,
+		ActeeType:   resource.Entity.ActeeType,
+		ActeeName:   resource.Entity.ActeeName,
 	}
 }
 
@@ -54,6 +54,9 @@ var _ = Describe("Event resources", func() {
 			  "entity": {
 				"type": "audit.app.update",
 				"timestamp": "2014-01-21T00:20:11+00:00",
+				"actee": "app-guid",
+				"actee_type": "app",
+				"actee_name": "dora",
 				"metadata": {
 				  "request": {
 				  	"state": "STOPPED",
@@ -73,6 +76,9 @@ var _ = Describe("Event resources", func() {
 			Expect(eventFields.Name).To(Equal("audit.app.update"))
 			Expect(eventFields.Timestamp).To(Equal(testtime.MustParse(eventTimestampFormat, "2014-01-21T00:20:11+00:00")))
 			Expect(eventFields.Description).To(Equal("instances: 1, memory: 256, state: STOPPED, command: PRIVATE DATA HIDDEN, environment_json: PRIVATE DATA HIDDEN"))
+			Expect(eventFields.Actee).To(Equal("app-guid"))
+			Expect(eventFields.ActeeType).To(Equal("app"))
+			Expect(eventFields.ActeeName).To(Equal("dora"))
 		})
 
 		It("unmarshals app delete events", func() {
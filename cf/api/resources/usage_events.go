@@ -0,0 +1,68 @@
+package resources
+
+import (
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/models"
+)
+
+type UsageEventMetadata struct {
+	GUID      string    `json:"guid"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AppUsageEventResource struct {
+	Metadata UsageEventMetadata
+	Entity   struct {
+		State                 string `json:"state"`
+		AppGUID               string `json:"app_guid"`
+		AppName               string `json:"app_name"`
+		SpaceGUID             string `json:"space_guid"`
+		SpaceName             string `json:"space_name"`
+		OrgGUID               string `json:"org_guid"`
+		InstanceCount         int    `json:"instance_count"`
+		MemoryInMBPerInstance int64  `json:"memory_in_mb_per_instance"`
+	}
+}
+
+type ServiceUsageEventResource struct {
+	Metadata UsageEventMetadata
+	Entity   struct {
+		State               string `json:"state"`
+		ServiceInstanceGUID string `json:"service_instance_guid"`
+		ServiceInstanceName string `json:"service_instance_name"`
+		ServiceInstanceType string `json:"service_instance_type"`
+		SpaceGUID           string `json:"space_guid"`
+		SpaceName           string `json:"space_name"`
+		OrgGUID             string `json:"org_guid"`
+	}
+}
+
+func (resource AppUsageEventResource) ToFields() models.AppUsageEvent {
+	return models.AppUsageEvent{
+		GUID:                  resource.Metadata.GUID,
+		CreatedAt:             resource.Metadata.CreatedAt,
+		State:                 resource.Entity.State,
+		AppGUID:               resource.Entity.AppGUID,
+		AppName:               resource.Entity.AppName,
+		SpaceGUID:             resource.Entity.SpaceGUID,
+		SpaceName:             resource.Entity.SpaceName,
+		OrgGUID:               resource.Entity.OrgGUID,
+		InstanceCount:         resource.Entity.InstanceCount,
+		MemoryInMBPerInstance: resource.Entity.MemoryInMBPerInstance,
+	}
+}
+
+func (resource ServiceUsageEventResource) ToFields() models.ServiceUsageEvent {
+	return models.ServiceUsageEvent{
+		GUID:                resource.Metadata.GUID,
+		CreatedAt:           resource.Metadata.CreatedAt,
+		State:               resource.Entity.State,
+		ServiceInstanceGUID: resource.Entity.ServiceInstanceGUID,
+		ServiceInstanceName: resource.Entity.ServiceInstanceName,
+		ServiceInstanceType: resource.Entity.ServiceInstanceType,
+		SpaceGUID:           resource.Entity.SpaceGUID,
+		SpaceName:           resource.Entity.SpaceName,
+		OrgGUID:             resource.Entity.OrgGUID,
+	}
+}
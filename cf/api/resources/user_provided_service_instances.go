@@ -0,0 +1,27 @@
+package resources
+
+import "github.com/cloudfoundry/cli/cf/models"
+
+type UserProvidedServiceInstanceResource struct {
+	Resource
+	Entity UserProvidedServiceInstanceEntity
+}
+
+type UserProvidedServiceInstanceEntity struct {
+	Name            string                 `json:"name"`
+	Credentials     map[string]interface{} `json:"credentials"`
+	SysLogDrainURL  string                 `json:"syslog_drain_url"`
+	RouteServiceURL string                 `json:"route_service_url"`
+	Tags            []string               `json:"tags"`
+}
+
+func (resource UserProvidedServiceInstanceResource) ToFields() models.ServiceInstanceFields {
+	return models.ServiceInstanceFields{
+		GUID:            resource.Metadata.GUID,
+		Name:            resource.Entity.Name,
+		Params:          resource.Entity.Credentials,
+		SysLogDrainURL:  resource.Entity.SysLogDrainURL,
+		RouteServiceURL: resource.Entity.RouteServiceURL,
+		Tags:            resource.Entity.Tags,
+	}
+}
@@ -39,8 +39,9 @@ type UAAUserResourceName struct {
 type UAAUserResource struct {
 	Username string                 `json:"userName"`
 	Emails   []UAAUserResourceEmail `json:"emails"`
-	Password string                 `json:"password"`
+	Password string                 `json:"password,omitempty"`
 	Name     UAAUserResourceName    `json:"name"`
+	Origin   string                 `json:"origin,omitempty"`
 }
 
 func NewUAAUserResource(username, password string) UAAUserResource {
@@ -19,6 +19,7 @@ import (
 
 type RouteRepository interface {
 	ListRoutes(cb func(models.Route) bool) (apiErr error)
+	ListRoutesInSpace(spaceGUID string, cb func(models.Route) bool) (apiErr error)
 	ListAllRoutes(cb func(models.Route) bool) (apiErr error)
 	Find(host string, domain models.DomainFields, path string, port int) (route models.Route, apiErr error)
 	Create(host string, domain models.DomainFields, path string, useRandomPort bool) (createdRoute models.Route, apiErr error)
@@ -41,9 +42,13 @@ func NewCloudControllerRouteRepository(config coreconfig.Reader, gateway net.Gat
 }
 
 func (repo CloudControllerRouteRepository) ListRoutes(cb func(models.Route) bool) (apiErr error) {
+	return repo.ListRoutesInSpace(repo.config.SpaceFields().GUID, cb)
+}
+
+func (repo CloudControllerRouteRepository) ListRoutesInSpace(spaceGUID string, cb func(models.Route) bool) (apiErr error) {
 	return repo.gateway.ListPaginatedResources(
 		repo.config.APIEndpoint(),
-		fmt.Sprintf("/v2/spaces/%s/routes?inline-relations-depth=1", repo.config.SpaceFields().GUID),
+		fmt.Sprintf("/v2/spaces/%s/routes?inline-relations-depth=1", spaceGUID),
 		resources.RouteResource{},
 		func(resource interface{}) bool {
 			return cb(resource.(resources.RouteResource).ToModel())
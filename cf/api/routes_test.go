@@ -77,6 +77,28 @@ var _ = Describe("route repository", func() {
 			Expect(apiErr).NotTo(HaveOccurred())
 		})
 
+		It("lists routes in another space", func() {
+			ts, handler = testnet.NewServer([]testnet.TestRequest{
+				apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+					Method:   "GET",
+					Path:     "/v2/spaces/other-space-guid/routes?inline-relations-depth=1",
+					Response: secondPageRoutesResponse,
+				}),
+			})
+			configRepo.SetAPIEndpoint(ts.URL)
+
+			routes := []models.Route{}
+			apiErr := repo.ListRoutesInSpace("other-space-guid", func(route models.Route) bool {
+				routes = append(routes, route)
+				return true
+			})
+
+			Expect(len(routes)).To(Equal(1))
+			Expect(routes[0].GUID).To(Equal("route-2-guid"))
+			Expect(handler).To(HaveAllRequestsCalled())
+			Expect(apiErr).NotTo(HaveOccurred())
+		})
+
 		It("lists routes from all the spaces of current org", func() {
 			ts, handler = testnet.NewServer([]testnet.TestRequest{
 				apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
@@ -90,6 +90,7 @@ type ServiceOfferingSummary struct {
 
 type ServiceSummaryRepository interface {
 	GetSummariesInCurrentSpace() ([]models.ServiceInstance, error)
+	GetSummariesInSpace(spaceGUID string) ([]models.ServiceInstance, error)
 }
 
 type CloudControllerServiceSummaryRepository struct {
@@ -105,8 +106,12 @@ func NewCloudControllerServiceSummaryRepository(config coreconfig.Reader, gatewa
 }
 
 func (repo CloudControllerServiceSummaryRepository) GetSummariesInCurrentSpace() ([]models.ServiceInstance, error) {
+	return repo.GetSummariesInSpace(repo.config.SpaceFields().GUID)
+}
+
+func (repo CloudControllerServiceSummaryRepository) GetSummariesInSpace(spaceGUID string) ([]models.ServiceInstance, error) {
 	var instances []models.ServiceInstance
-	path := fmt.Sprintf("%s/v2/spaces/%s/summary", repo.config.APIEndpoint(), repo.config.SpaceFields().GUID)
+	path := fmt.Sprintf("%s/v2/spaces/%s/summary", repo.config.APIEndpoint(), spaceGUID)
 	resource := new(ServiceInstancesSummaries)
 
 	err := repo.gateway.GetResource(path, resource)
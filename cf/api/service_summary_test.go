@@ -90,6 +90,25 @@ var _ = Describe("ServiceSummaryRepository", func() {
 		Expect(instance1.ApplicationNames[0]).To(Equal("app1"))
 		Expect(instance1.ApplicationNames[1]).To(Equal("app2"))
 	})
+
+	It("gets a summary of services in another space", func() {
+		req := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+			Method:   "GET",
+			Path:     "/v2/spaces/other-space-guid/summary",
+			Response: serviceInstanceSummariesResponse,
+		})
+
+		ts, handler, repo := createServiceSummaryRepo(req)
+		defer ts.Close()
+
+		serviceInstances, apiErr := repo.GetSummariesInSpace("other-space-guid")
+		Expect(handler).To(HaveAllRequestsCalled())
+
+		Expect(apiErr).NotTo(HaveOccurred())
+		Expect(serviceInstances).To(HaveLen(1))
+		Expect(serviceInstances[0].Name).To(Equal("my-service-instance"))
+		Expect(serviceInstances[0].ApplicationNames).To(Equal([]string{"app1", "app2"}))
+	})
 })
 
 func createServiceSummaryRepo(req testnet.TestRequest) (ts *httptest.Server, handler *testnet.TestHandler, repo ServiceSummaryRepository) {
@@ -17,6 +17,7 @@ import (
 
 type SpaceRepository interface {
 	ListSpaces(func(models.Space) bool) error
+	ListSpacesFromOrg(orgGUID string, spaceFunc func(models.Space) bool) error
 	FindByName(name string) (space models.Space, apiErr error)
 	FindByNameInOrg(name, orgGUID string) (space models.Space, apiErr error)
 	Create(name string, orgGUID string, spaceQuotaGUID string) (space models.Space, apiErr error)
@@ -37,9 +38,13 @@ func NewCloudControllerSpaceRepository(config coreconfig.Reader, gateway net.Gat
 }
 
 func (repo CloudControllerSpaceRepository) ListSpaces(callback func(models.Space) bool) error {
+	return repo.ListSpacesFromOrg(repo.config.OrganizationFields().GUID, callback)
+}
+
+func (repo CloudControllerSpaceRepository) ListSpacesFromOrg(orgGUID string, callback func(models.Space) bool) error {
 	return repo.gateway.ListPaginatedResources(
 		repo.config.APIEndpoint(),
-		fmt.Sprintf("/v2/organizations/%s/spaces?order-by=name&inline-relations-depth=1", repo.config.OrganizationFields().GUID),
+		fmt.Sprintf("/v2/organizations/%s/spaces?order-by=name&inline-relations-depth=1", orgGUID),
 		resources.SpaceResource{},
 		func(resource interface{}) bool {
 			return callback(resource.(resources.SpaceResource).ToModel())
@@ -114,6 +114,56 @@ var _ = Describe("Space Repository", func() {
 		})
 	})
 
+	Describe("ListSpacesFromOrg", func() {
+		var (
+			ccServer *ghttp.Server
+			repo     CloudControllerSpaceRepository
+		)
+
+		BeforeEach(func() {
+			ccServer = ghttp.NewServer()
+			configRepo := testconfig.NewRepositoryWithDefaults()
+			configRepo.SetAPIEndpoint(ccServer.URL())
+			gateway := cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
+			repo = NewCloudControllerSpaceRepository(configRepo, gateway)
+			ccServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/organizations/other-org-guid/spaces", "order-by=name&inline-relations-depth=1"),
+					ghttp.RespondWith(http.StatusOK, `{
+						"total_results": 1,
+						"total_pages": 1,
+						"prev_url": null,
+						"next_url": null,
+						"resources": [
+							{
+								"metadata": { "guid": "space1-guid" },
+								"entity": { "name": "Alpha", "space_quota_definition_guid": "quota-guid" }
+							}
+						]
+					}`),
+				),
+			)
+		})
+
+		AfterEach(func() {
+			ccServer.Close()
+		})
+
+		It("lists the spaces of the given org", func() {
+			spaces := []models.Space{}
+			apiErr := repo.ListSpacesFromOrg("other-org-guid", func(space models.Space) bool {
+				spaces = append(spaces, space)
+				return true
+			})
+
+			Expect(apiErr).NotTo(HaveOccurred())
+			Expect(ccServer.ReceivedRequests()).To(HaveLen(1))
+			Expect(len(spaces)).To(Equal(1))
+			Expect(spaces[0].GUID).To(Equal("space1-guid"))
+			Expect(spaces[0].SpaceQuotaGUID).To(Equal("quota-guid"))
+		})
+	})
+
 	Describe("finding spaces by name", func() {
 		It("returns the space", func() {
 			testSpacesFindByNameWithOrg("my-org-guid",
@@ -0,0 +1,50 @@
+package usageevents
+
+import (
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/net"
+)
+
+//go:generate counterfeiter . UsageEventsRepository
+
+// UsageEventsRepository lists the app and service usage events, oldest
+// first, until the callback returns false. The CC cannot filter these
+// events by time.
+type UsageEventsRepository interface {
+	ListAppUsageEvents(cb func(models.AppUsageEvent) bool) error
+	ListServiceUsageEvents(cb func(models.ServiceUsageEvent) bool) error
+}
+
+type CloudControllerUsageEventsRepository struct {
+	config  coreconfig.Reader
+	gateway net.Gateway
+}
+
+func NewCloudControllerUsageEventsRepository(config coreconfig.Reader, gateway net.Gateway) CloudControllerUsageEventsRepository {
+	return CloudControllerUsageEventsRepository{
+		config:  config,
+		gateway: gateway,
+	}
+}
+
+func (repo CloudControllerUsageEventsRepository) ListAppUsageEvents(cb func(models.AppUsageEvent) bool) error {
+	return repo.gateway.ListPaginatedResources(
+		repo.config.APIEndpoint(),
+		"/v2/app_usage_events?results-per-page=100",
+		resources.AppUsageEventResource{},
+		func(resource interface{}) bool {
+			return cb(resource.(resources.AppUsageEventResource).ToFields())
+		})
+}
+
+func (repo CloudControllerUsageEventsRepository) ListServiceUsageEvents(cb func(models.ServiceUsageEvent) bool) error {
+	return repo.gateway.ListPaginatedResources(
+		repo.config.APIEndpoint(),
+		"/v2/service_usage_events?results-per-page=100",
+		resources.ServiceUsageEventResource{},
+		func(resource interface{}) bool {
+			return cb(resource.(resources.ServiceUsageEventResource).ToFields())
+		})
+}
@@ -0,0 +1,172 @@
+package usageevents_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/cloudfoundry/cli/cf/api/usageevents"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/testhelpers/cloudcontrollergateway"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testnet "github.com/cloudfoundry/cli/testhelpers/net"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Usage Events Repo", func() {
+	var (
+		server  *httptest.Server
+		handler *testnet.TestHandler
+		config  coreconfig.ReadWriter
+		repo    UsageEventsRepository
+	)
+
+	BeforeEach(func() {
+		config = testconfig.NewRepositoryWithDefaults()
+		gateway := cloudcontrollergateway.NewTestCloudControllerGateway(config)
+		repo = NewCloudControllerUsageEventsRepository(config, gateway)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	setupTestServer := func(requests ...testnet.TestRequest) {
+		server, handler = testnet.NewServer(requests)
+		config.SetAPIEndpoint(server.URL)
+	}
+
+	Describe("ListAppUsageEvents", func() {
+		It("pages through the app usage events", func() {
+			setupTestServer(
+				testnet.TestRequest{
+					Method: "GET",
+					Path:   "/v2/app_usage_events?results-per-page=100",
+					Response: testnet.TestResponse{
+						Status: http.StatusOK,
+						Body: `{
+							"next_url": "/v2/app_usage_events?results-per-page=100&page=2",
+							"resources": [{
+								"metadata": {"guid": "event-1-guid", "created_at": "2016-05-01T10:00:00Z"},
+								"entity": {
+									"state": "STARTED",
+									"app_guid": "app-guid",
+									"app_name": "my-app",
+									"space_guid": "space-guid",
+									"space_name": "my-space",
+									"org_guid": "org-guid",
+									"instance_count": 2,
+									"memory_in_mb_per_instance": 512
+								}
+							}]
+						}`,
+					},
+				},
+				testnet.TestRequest{
+					Method: "GET",
+					Path:   "/v2/app_usage_events?results-per-page=100&page=2",
+					Response: testnet.TestResponse{
+						Status: http.StatusOK,
+						Body: `{
+							"resources": [{
+								"metadata": {"guid": "event-2-guid", "created_at": "2016-05-01T12:00:00Z"},
+								"entity": {"state": "STOPPED", "app_guid": "app-guid"}
+							}]
+						}`,
+					},
+				},
+			)
+
+			events := []models.AppUsageEvent{}
+			err := repo.ListAppUsageEvents(func(event models.AppUsageEvent) bool {
+				events = append(events, event)
+				return true
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handler).To(HaveAllRequestsCalled())
+
+			Expect(events).To(HaveLen(2))
+			Expect(events[0]).To(Equal(models.AppUsageEvent{
+				GUID:                  "event-1-guid",
+				CreatedAt:             time.Date(2016, 5, 1, 10, 0, 0, 0, time.UTC),
+				State:                 "STARTED",
+				AppGUID:               "app-guid",
+				AppName:               "my-app",
+				SpaceGUID:             "space-guid",
+				SpaceName:             "my-space",
+				OrgGUID:               "org-guid",
+				InstanceCount:         2,
+				MemoryInMBPerInstance: 512,
+			}))
+			Expect(events[1].State).To(Equal("STOPPED"))
+		})
+
+		It("stops when the callback returns false", func() {
+			setupTestServer(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/app_usage_events?results-per-page=100",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body: `{
+						"next_url": "/v2/app_usage_events?results-per-page=100&page=2",
+						"resources": [{"metadata": {"guid": "event-1-guid"}, "entity": {}}]
+					}`,
+				},
+			})
+
+			err := repo.ListAppUsageEvents(func(models.AppUsageEvent) bool { return false })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handler).To(HaveAllRequestsCalled())
+		})
+	})
+
+	Describe("ListServiceUsageEvents", func() {
+		It("lists the service usage events", func() {
+			setupTestServer(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/service_usage_events?results-per-page=100",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body: `{
+						"resources": [{
+							"metadata": {"guid": "event-1-guid", "created_at": "2016-05-01T10:00:00Z"},
+							"entity": {
+								"state": "CREATED",
+								"service_instance_guid": "instance-guid",
+								"service_instance_name": "my-db",
+								"service_instance_type": "managed_service_instance",
+								"space_guid": "space-guid",
+								"space_name": "my-space",
+								"org_guid": "org-guid"
+							}
+						}]
+					}`,
+				},
+			})
+
+			events := []models.ServiceUsageEvent{}
+			err := repo.ListServiceUsageEvents(func(event models.ServiceUsageEvent) bool {
+				events = append(events, event)
+				return true
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handler).To(HaveAllRequestsCalled())
+
+			Expect(events).To(Equal([]models.ServiceUsageEvent{{
+				GUID:                "event-1-guid",
+				CreatedAt:           time.Date(2016, 5, 1, 10, 0, 0, 0, time.UTC),
+				State:               "CREATED",
+				ServiceInstanceGUID: "instance-guid",
+				ServiceInstanceName: "my-db",
+				ServiceInstanceType: "managed_service_instance",
+				SpaceGUID:           "space-guid",
+				SpaceName:           "my-space",
+				OrgGUID:             "org-guid",
+			}}))
+		})
+	})
+})
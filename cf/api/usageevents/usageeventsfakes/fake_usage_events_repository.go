@@ -0,0 +1,94 @@
+// This file was generated by counterfeiter
+package usageeventsfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/cli/cf/api/usageevents"
+	"github.com/cloudfoundry/cli/cf/models"
+)
+
+type FakeUsageEventsRepository struct {
+	ListAppUsageEventsStub        func(cb func(models.AppUsageEvent) bool) error
+	listAppUsageEventsMutex       sync.RWMutex
+	listAppUsageEventsArgsForCall []struct {
+		cb func(models.AppUsageEvent) bool
+	}
+	listAppUsageEventsReturns struct {
+		result1 error
+	}
+	ListServiceUsageEventsStub        func(cb func(models.ServiceUsageEvent) bool) error
+	listServiceUsageEventsMutex       sync.RWMutex
+	listServiceUsageEventsArgsForCall []struct {
+		cb func(models.ServiceUsageEvent) bool
+	}
+	listServiceUsageEventsReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeUsageEventsRepository) ListAppUsageEvents(cb func(models.AppUsageEvent) bool) error {
+	fake.listAppUsageEventsMutex.Lock()
+	fake.listAppUsageEventsArgsForCall = append(fake.listAppUsageEventsArgsForCall, struct {
+		cb func(models.AppUsageEvent) bool
+	}{cb})
+	fake.listAppUsageEventsMutex.Unlock()
+	if fake.ListAppUsageEventsStub != nil {
+		return fake.ListAppUsageEventsStub(cb)
+	} else {
+		return fake.listAppUsageEventsReturns.result1
+	}
+}
+
+func (fake *FakeUsageEventsRepository) ListAppUsageEventsCallCount() int {
+	fake.listAppUsageEventsMutex.RLock()
+	defer fake.listAppUsageEventsMutex.RUnlock()
+	return len(fake.listAppUsageEventsArgsForCall)
+}
+
+func (fake *FakeUsageEventsRepository) ListAppUsageEventsArgsForCall(i int) func(models.AppUsageEvent) bool {
+	fake.listAppUsageEventsMutex.RLock()
+	defer fake.listAppUsageEventsMutex.RUnlock()
+	return fake.listAppUsageEventsArgsForCall[i].cb
+}
+
+func (fake *FakeUsageEventsRepository) ListAppUsageEventsReturns(result1 error) {
+	fake.ListAppUsageEventsStub = nil
+	fake.listAppUsageEventsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUsageEventsRepository) ListServiceUsageEvents(cb func(models.ServiceUsageEvent) bool) error {
+	fake.listServiceUsageEventsMutex.Lock()
+	fake.listServiceUsageEventsArgsForCall = append(fake.listServiceUsageEventsArgsForCall, struct {
+		cb func(models.ServiceUsageEvent) bool
+	}{cb})
+	fake.listServiceUsageEventsMutex.Unlock()
+	if fake.ListServiceUsageEventsStub != nil {
+		return fake.ListServiceUsageEventsStub(cb)
+	} else {
+		return fake.listServiceUsageEventsReturns.result1
+	}
+}
+
+func (fake *FakeUsageEventsRepository) ListServiceUsageEventsCallCount() int {
+	fake.listServiceUsageEventsMutex.RLock()
+	defer fake.listServiceUsageEventsMutex.RUnlock()
+	return len(fake.listServiceUsageEventsArgsForCall)
+}
+
+func (fake *FakeUsageEventsRepository) ListServiceUsageEventsArgsForCall(i int) func(models.ServiceUsageEvent) bool {
+	fake.listServiceUsageEventsMutex.RLock()
+	defer fake.listServiceUsageEventsMutex.RUnlock()
+	return fake.listServiceUsageEventsArgsForCall[i].cb
+}
+
+func (fake *FakeUsageEventsRepository) ListServiceUsageEventsReturns(result1 error) {
+	fake.ListServiceUsageEventsStub = nil
+	fake.listServiceUsageEventsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ usageevents.UsageEventsRepository = new(FakeUsageEventsRepository)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/cloudfoundry/cli/cf/api/resources"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/net"
@@ -16,6 +17,7 @@ type UserProvidedServiceInstanceRepository interface {
 	Create(name, drainURL string, routeServiceURL string, params map[string]interface{}) (apiErr error)
 	Update(serviceInstanceFields models.ServiceInstanceFields) (apiErr error)
 	GetSummaries() (models.UserProvidedServiceSummary, error)
+	ListUserProvidedServicesInSpace(spaceGUID string, cb func(models.ServiceInstanceFields) bool) error
 }
 
 type CCUserProvidedServiceInstanceRepository struct {
@@ -55,6 +57,7 @@ func (repo CCUserProvidedServiceInstanceRepository) Update(serviceInstanceFields
 		Credentials:     serviceInstanceFields.Params,
 		SysLogDrainURL:  serviceInstanceFields.SysLogDrainURL,
 		RouteServiceURL: serviceInstanceFields.RouteServiceURL,
+		Tags:            serviceInstanceFields.Tags,
 	}
 	jsonBytes, err := json.Marshal(reqBody)
 	if err != nil {
@@ -77,3 +80,13 @@ func (repo CCUserProvidedServiceInstanceRepository) GetSummaries() (models.UserP
 
 	return model, nil
 }
+
+func (repo CCUserProvidedServiceInstanceRepository) ListUserProvidedServicesInSpace(spaceGUID string, cb func(models.ServiceInstanceFields) bool) error {
+	return repo.gateway.ListPaginatedResources(
+		repo.config.APIEndpoint(),
+		fmt.Sprintf("/v2/spaces/%s/user_provided_service_instances", spaceGUID),
+		resources.UserProvidedServiceInstanceResource{},
+		func(resource interface{}) bool {
+			return cb(resource.(resources.UserProvidedServiceInstanceResource).ToFields())
+		})
+}
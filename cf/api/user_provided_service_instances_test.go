@@ -107,6 +107,84 @@ var _ = Describe("UserProvidedServiceRepository", func() {
 			Expect(handler).To(HaveAllRequestsCalled())
 			Expect(apiErr).NotTo(HaveOccurred())
 		})
+
+		It("updates the tags of a user provided service when it has them", func() {
+			req := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method:   "PUT",
+				Path:     "/v2/user_provided_service_instances/my-instance-guid",
+				Matcher:  testnet.RequestBodyMatcher(`{"credentials":{"user":"me"},"syslog_drain_url":"","route_service_url":"","tags":["db"]}`),
+				Response: testnet.TestResponse{Status: http.StatusCreated},
+			})
+
+			ts, handler, repo := createUserProvidedServiceInstanceRepo([]testnet.TestRequest{req})
+			defer ts.Close()
+
+			serviceInstance := models.ServiceInstanceFields{}
+			serviceInstance.GUID = "my-instance-guid"
+			serviceInstance.Params = map[string]interface{}{"user": "me"}
+			serviceInstance.Tags = []string{"db"}
+
+			apiErr := repo.Update(serviceInstance)
+			Expect(handler).To(HaveAllRequestsCalled())
+			Expect(apiErr).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("ListUserProvidedServicesInSpace()", func() {
+		It("lists the user provided services of the space, page by page", func() {
+			firstPage := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/spaces/my-space-guid/user_provided_service_instances",
+				Response: testnet.TestResponse{Status: http.StatusOK, Body: `{
+					"next_url": "/v2/spaces/my-space-guid/user_provided_service_instances?page=2",
+					"resources": [
+						{
+							"metadata": {"guid": "my-ups-guid"},
+							"entity": {
+								"name": "my-ups",
+								"credentials": {"username": "admin"},
+								"syslog_drain_url": "syslog://example.com",
+								"route_service_url": "",
+								"tags": ["db", "shared"]
+							}
+						}
+					]
+				}`},
+			})
+			secondPage := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/spaces/my-space-guid/user_provided_service_instances?page=2",
+				Response: testnet.TestResponse{Status: http.StatusOK, Body: `{
+					"resources": [
+						{
+							"metadata": {"guid": "other-ups-guid"},
+							"entity": {"name": "other-ups", "credentials": {}}
+						}
+					]
+				}`},
+			})
+
+			ts, handler, repo := createUserProvidedServiceInstanceRepo([]testnet.TestRequest{firstPage, secondPage})
+			defer ts.Close()
+
+			instances := []models.ServiceInstanceFields{}
+			apiErr := repo.ListUserProvidedServicesInSpace("my-space-guid", func(instance models.ServiceInstanceFields) bool {
+				instances = append(instances, instance)
+				return true
+			})
+			Expect(apiErr).NotTo(HaveOccurred())
+			Expect(handler).To(HaveAllRequestsCalled())
+
+			Expect(instances).To(HaveLen(2))
+			Expect(instances[0]).To(Equal(models.ServiceInstanceFields{
+				GUID:           "my-ups-guid",
+				Name:           "my-ups",
+				Params:         map[string]interface{}{"username": "admin"},
+				SysLogDrainURL: "syslog://example.com",
+				Tags:           []string{"db", "shared"},
+			}))
+			Expect(instances[1].Name).To(Equal("other-ups"))
+		})
 	})
 
 	Context("GetSummaries()", func() {
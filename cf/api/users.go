@@ -30,6 +30,19 @@ var spaceRoleToPathMap = map[models.Role]string{
 	models.RoleSpaceAuditor:   "auditors",
 }
 
+var userOrgRoleToPathMap = map[models.Role]string{
+	models.RoleOrgUser:        "organizations",
+	models.RoleOrgManager:     "managed_organizations",
+	models.RoleBillingManager: "billing_managed_organizations",
+	models.RoleOrgAuditor:     "audited_organizations",
+}
+
+var userSpaceRoleToPathMap = map[models.Role]string{
+	models.RoleSpaceManager:   "managed_spaces",
+	models.RoleSpaceDeveloper: "spaces",
+	models.RoleSpaceAuditor:   "audited_spaces",
+}
+
 type apiErrResponse struct {
 	Code        int    `json:"code,omitempty"`
 	ErrorCode   string `json:"error_code,omitempty"`
@@ -40,11 +53,16 @@ type apiErrResponse struct {
 
 type UserRepository interface {
 	FindByUsername(username string) (user models.UserFields, apiErr error)
+	FindByUsernameAndOrigin(username, origin string) (user models.UserFields, apiErr error)
+	FindAllByUsername(username string) (users []models.UserFields, apiErr error)
 	ListUsersInOrgForRole(orgGUID string, role models.Role) ([]models.UserFields, error)
 	ListUsersInOrgForRoleWithNoUAA(orgGUID string, role models.Role) ([]models.UserFields, error)
 	ListUsersInSpaceForRole(spaceGUID string, role models.Role) ([]models.UserFields, error)
 	ListUsersInSpaceForRoleWithNoUAA(spaceGUID string, role models.Role) ([]models.UserFields, error)
+	ListOrgsForUserWithRole(userGUID string, role models.Role) ([]models.OrganizationFields, error)
+	ListSpacesForUserWithRole(userGUID string, role models.Role) ([]models.Space, error)
 	Create(username, password string) (apiErr error)
+	CreateWithOrigin(username, origin string) (apiErr error)
 	Delete(userGUID string) (apiErr error)
 	SetOrgRoleByGUID(userGUID, orgGUID string, role models.Role) (apiErr error)
 	SetOrgRoleByUsername(username, orgGUID string, role models.Role) (apiErr error)
@@ -70,13 +88,36 @@ func NewCloudControllerUserRepository(config coreconfig.Reader, uaaGateway net.G
 }
 
 func (repo CloudControllerUserRepository) FindByUsername(username string) (models.UserFields, error) {
+	return repo.findByFilter(username, fmt.Sprintf(`userName Eq "%s"`, username))
+}
+
+func (repo CloudControllerUserRepository) FindByUsernameAndOrigin(username, origin string) (models.UserFields, error) {
+	return repo.findByFilter(username, fmt.Sprintf(`userName Eq "%s" and origin Eq "%s"`, username, origin))
+}
+
+// FindAllByUsername returns the users with the username in every origin.
+func (repo CloudControllerUserRepository) FindAllByUsername(username string) ([]models.UserFields, error) {
+	return repo.findAllByFilter(fmt.Sprintf(`userName Eq "%s"`, username))
+}
+
+func (repo CloudControllerUserRepository) findByFilter(username string, filter string) (models.UserFields, error) {
+	users, apiErr := repo.findAllByFilter(filter)
+	if apiErr != nil {
+		return models.UserFields{}, apiErr
+	} else if len(users) == 0 {
+		return models.UserFields{}, errors.NewModelNotFoundError("User", username)
+	}
+
+	return users[0], nil
+}
+
+func (repo CloudControllerUserRepository) findAllByFilter(filter string) ([]models.UserFields, error) {
 	uaaEndpoint, apiErr := repo.getAuthEndpoint()
-	var user models.UserFields
 	if apiErr != nil {
-		return user, apiErr
+		return nil, apiErr
 	}
 
-	usernameFilter := neturl.QueryEscape(fmt.Sprintf(`userName Eq "%s"`, username))
+	usernameFilter := neturl.QueryEscape(filter)
 	path := fmt.Sprintf("%s/Users?attributes=id,userName&filter=%s", uaaEndpoint, usernameFilter)
 	users, apiErr := repo.updateOrFindUsersWithUAAPath([]models.UserFields{}, path)
 
@@ -84,15 +125,13 @@ func (repo CloudControllerUserRepository) FindByUsername(username string) (model
 		errType, ok := apiErr.(errors.HTTPError)
 		if ok {
 			if errType.StatusCode() == 403 {
-				return user, errors.NewAccessDeniedError()
+				return nil, errors.NewAccessDeniedError()
 			}
 		}
-		return user, apiErr
-	} else if len(users) == 0 {
-		return user, errors.NewModelNotFoundError("User", username)
+		return nil, apiErr
 	}
 
-	return users[0], apiErr
+	return users, nil
 }
 
 func (repo CloudControllerUserRepository) ListUsersInOrgForRole(orgGUID string, roleName models.Role) (users []models.UserFields, apiErr error) {
@@ -111,6 +150,36 @@ func (repo CloudControllerUserRepository) ListUsersInSpaceForRoleWithNoUAA(space
 	return repo.listUsersWithPathWithNoUAA(fmt.Sprintf("/v2/spaces/%s/%s", spaceGUID, spaceRoleToPathMap[roleName]))
 }
 
+func (repo CloudControllerUserRepository) ListOrgsForUserWithRole(userGUID string, role models.Role) ([]models.OrganizationFields, error) {
+	orgs := []models.OrganizationFields{}
+	err := repo.ccGateway.ListPaginatedResources(
+		repo.config.APIEndpoint(),
+		fmt.Sprintf("/v2/users/%s/%s", userGUID, userOrgRoleToPathMap[role]),
+		resources.OrganizationResource{},
+		func(resource interface{}) bool {
+			if orgResource, ok := resource.(resources.OrganizationResource); ok {
+				orgs = append(orgs, orgResource.ToFields())
+			}
+			return true
+		})
+	return orgs, err
+}
+
+func (repo CloudControllerUserRepository) ListSpacesForUserWithRole(userGUID string, role models.Role) ([]models.Space, error) {
+	spaces := []models.Space{}
+	err := repo.ccGateway.ListPaginatedResources(
+		repo.config.APIEndpoint(),
+		fmt.Sprintf("/v2/users/%s/%s?inline-relations-depth=1&include-relations=organization", userGUID, userSpaceRoleToPathMap[role]),
+		resources.SpaceResource{},
+		func(resource interface{}) bool {
+			if spaceResource, ok := resource.(resources.SpaceResource); ok {
+				spaces = append(spaces, spaceResource.ToModel())
+			}
+			return true
+		})
+	return spaces, err
+}
+
 func (repo CloudControllerUserRepository) listUsersWithPathWithNoUAA(path string) (users []models.UserFields, apiErr error) {
 	apiErr = repo.ccGateway.ListPaginatedResources(
 		repo.config.APIEndpoint(),
@@ -187,13 +256,26 @@ func (repo CloudControllerUserRepository) updateOrFindUsersWithUAAPath(ccUsers [
 }
 
 func (repo CloudControllerUserRepository) Create(username, password string) (err error) {
+	return repo.create(resources.NewUAAUserResource(username, password))
+}
+
+// CreateWithOrigin creates a user without a password that authenticates with
+// the identity provider of the given origin, such as ldap.
+func (repo CloudControllerUserRepository) CreateWithOrigin(username, origin string) (err error) {
+	user := resources.NewUAAUserResource(username, "")
+	user.Origin = origin
+	return repo.create(user)
+}
+
+func (repo CloudControllerUserRepository) create(user resources.UAAUserResource) (err error) {
 	uaaEndpoint, err := repo.getAuthEndpoint()
 	if err != nil {
 		return
 	}
 
+	username := user.Username
 	path := "/Users"
-	body, err := json.Marshal(resources.NewUAAUserResource(username, password))
+	body, err := json.Marshal(user)
 
 	if err != nil {
 		return
@@ -637,6 +637,60 @@ var _ = Describe("UserRepository", func() {
 		})
 	})
 
+	Describe("ListOrgsForUserWithRole", func() {
+		BeforeEach(func() {
+			ccServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/users/user-guid/managed_organizations"),
+					ghttp.RespondWith(http.StatusOK, `{
+						"resources": [
+							{"metadata": {"guid": "org-guid"}, "entity": {"name": "my-org"}}
+						]
+					}`),
+				),
+			)
+		})
+
+		It("returns the orgs in which the user has the role", func() {
+			orgs, err := client.ListOrgsForUserWithRole("user-guid", models.RoleOrgManager)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ccServer.ReceivedRequests()).To(HaveLen(1))
+			Expect(orgs).To(HaveLen(1))
+			Expect(orgs[0].GUID).To(Equal("org-guid"))
+			Expect(orgs[0].Name).To(Equal("my-org"))
+		})
+	})
+
+	Describe("ListSpacesForUserWithRole", func() {
+		BeforeEach(func() {
+			ccServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/users/user-guid/audited_spaces", "inline-relations-depth=1&include-relations=organization"),
+					ghttp.RespondWith(http.StatusOK, `{
+						"resources": [
+							{
+								"metadata": {"guid": "space-guid"},
+								"entity": {
+									"name": "my-space",
+									"organization": {"metadata": {"guid": "org-guid"}, "entity": {"name": "my-org"}}
+								}
+							}
+						]
+					}`),
+				),
+			)
+		})
+
+		It("returns the spaces in which the user has the role with their orgs", func() {
+			spaces, err := client.ListSpacesForUserWithRole("user-guid", models.RoleSpaceAuditor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spaces).To(HaveLen(1))
+			Expect(spaces[0].GUID).To(Equal("space-guid"))
+			Expect(spaces[0].Name).To(Equal("my-space"))
+			Expect(spaces[0].Organization.Name).To(Equal("my-org"))
+		})
+	})
+
 	Describe("FindByUsername", func() {
 		Context("when the user exists", func() {
 			BeforeEach(func() {
@@ -695,6 +749,27 @@ var _ = Describe("UserRepository", func() {
 			})
 		})
 
+		Context("when the username exists in more than one origin", func() {
+			BeforeEach(func() {
+				uaaServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/Users", fmt.Sprintf("attributes=id,userName&filter=%s", url.QueryEscape(`userName Eq "damien+user1@pivotallabs.com"`))),
+						ghttp.RespondWith(http.StatusOK, `{
+								"resources": [
+								{ "id": "uaa-guid", "userName": "damien+user1@pivotallabs.com" },
+								{ "id": "ldap-guid", "userName": "damien+user1@pivotallabs.com" }
+								]}`),
+					),
+				)
+			})
+
+			It("returns the first one", func() {
+				user, err := client.FindByUsername("damien+user1@pivotallabs.com")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(user.GUID).To(Equal("uaa-guid"))
+			})
+		})
+
 		Context("when the cli user is not authorized", func() {
 			BeforeEach(func() {
 				uaaServer.AppendHandlers(
@@ -744,6 +819,86 @@ var _ = Describe("UserRepository", func() {
 		})
 	})
 
+	Describe("FindByUsernameAndOrigin", func() {
+		BeforeEach(func() {
+			uaaServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/Users", fmt.Sprintf("attributes=id,userName&filter=%s", url.QueryEscape(`userName Eq "my-user" and origin Eq "ldap"`))),
+					ghttp.RespondWith(http.StatusOK, `{
+							"resources": [
+							{ "id": "my-guid", "userName": "my-user" }
+							]}`),
+				),
+			)
+		})
+
+		It("returns the user of the given origin", func() {
+			user, err := client.FindByUsernameAndOrigin("my-user", "ldap")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+			Expect(user).To(Equal(models.UserFields{
+				Username: "my-user",
+				GUID:     "my-guid",
+			}))
+		})
+	})
+
+	Describe("FindAllByUsername", func() {
+		BeforeEach(func() {
+			uaaServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/Users", fmt.Sprintf("attributes=id,userName&filter=%s", url.QueryEscape(`userName Eq "my-user"`))),
+					ghttp.RespondWith(http.StatusOK, `{
+							"resources": [
+							{ "id": "uaa-guid", "userName": "my-user" },
+							{ "id": "ldap-guid", "userName": "my-user" }
+							]}`),
+				),
+			)
+		})
+
+		It("returns the users of every origin", func() {
+			users, err := client.FindAllByUsername("my-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(Equal([]models.UserFields{
+				{Username: "my-user", GUID: "uaa-guid"},
+				{Username: "my-user", GUID: "ldap-guid"},
+			}))
+		})
+	})
+
+	Describe("CreateWithOrigin", func() {
+		BeforeEach(func() {
+			ccServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/v2/users"),
+					ghttp.VerifyJSON(`{"guid":"my-user-guid"}`),
+				),
+			)
+			uaaServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/Users"),
+					ghttp.VerifyJSON(`{
+						"userName":"my-user",
+						"emails":[{"value":"my-user"}],
+						"origin":"ldap",
+						"name":{
+							"givenName":"my-user",
+							"familyName":"my-user"}
+						}`),
+					ghttp.RespondWith(http.StatusOK, `{"id":"my-user-guid"}`),
+				),
+			)
+		})
+
+		It("creates the user in UAA without a password and in CC", func() {
+			err := client.CreateWithOrigin("my-user", "ldap")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+			Expect(ccServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
 	Describe("Create", func() {
 		Context("when the user does not exist", func() {
 			BeforeEach(func() {
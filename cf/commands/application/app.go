@@ -3,6 +3,7 @@ package application
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/flags"
@@ -18,6 +19,8 @@ import (
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/cf/uihelpers"
+	v3models "github.com/cloudfoundry/cli/cf/v3/models"
+	"github.com/cloudfoundry/cli/cf/v3/repository"
 )
 
 //go:generate counterfeiter . ApplicationDisplayer
@@ -31,6 +34,7 @@ type ShowApp struct {
 	config           coreconfig.Reader
 	appSummaryRepo   api.AppSummaryRepository
 	appInstancesRepo appinstances.AppInstancesRepository
+	v3Repo           repository.Repository
 	appReq           requirements.ApplicationRequirement
 	pluginAppModel   *plugin_models.GetAppModel
 	pluginCall       bool
@@ -75,6 +79,7 @@ func (cmd *ShowApp) SetDependency(deps commandregistry.Dependency, pluginCall bo
 	cmd.config = deps.Config
 	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
 	cmd.appInstancesRepo = deps.RepoLocator.GetAppInstancesRepository()
+	cmd.v3Repo = deps.RepoLocator.GetV3Repository()
 
 	cmd.pluginAppModel = deps.PluginModels.Application
 	cmd.pluginCall = pluginCall
@@ -183,6 +188,63 @@ func (cmd *ShowApp) ShowApp(app models.Application, orgName, spaceName string) e
 		return nil
 	}
 
+	cmd.printInstances(instances)
+	return cmd.showAdditionalProcesses(app)
+}
+
+func (cmd *ShowApp) showAdditionalProcesses(app models.Application) error {
+	// a Cloud Controller without the v3 API reports no processes
+	processes, err := cmd.v3Repo.GetProcesses(fmt.Sprintf("/v3/apps/%s/processes", app.GUID))
+	if err != nil {
+		return err
+	}
+
+	for _, process := range processes {
+		if process.Type == models.WebProcessType || process.Instances == 0 {
+			continue
+		}
+
+		stats, err := cmd.v3Repo.GetProcessStats(processPath(process, "stats"))
+		if err != nil {
+			return err
+		}
+
+		cmd.ui.Say("")
+		cmd.ui.Say("%s %s", terminal.HeaderColor(T("process:")), process.Type)
+		cmd.ui.Say(T("{{.Usage}} {{.FormattedMemory}} x {{.InstanceCount}} instances",
+			map[string]interface{}{
+				"Usage":           terminal.HeaderColor(T("usage:")),
+				"FormattedMemory": formatters.ByteSize(process.MemoryInMB * formatters.MEGABYTE),
+				"InstanceCount":   process.Instances}))
+		cmd.printInstances(processInstances(stats))
+	}
+	return nil
+}
+
+// processInstances orders the stats of a process by instance index.
+func processInstances(stats []v3models.V3ProcessStats) []models.AppInstanceFields {
+	instances := make([]models.AppInstanceFields, len(stats))
+	now := time.Now()
+	for _, stat := range stats {
+		if stat.Index < 0 || stat.Index >= len(instances) {
+			continue
+		}
+
+		instances[stat.Index] = models.AppInstanceFields{
+			State:     models.InstanceState(strings.ToLower(stat.State)),
+			Details:   stat.Details,
+			Since:     now.Add(-time.Duration(stat.Uptime) * time.Second),
+			CPUUsage:  stat.Usage.CPU,
+			DiskQuota: stat.DiskQuota,
+			DiskUsage: stat.Usage.Disk,
+			MemQuota:  stat.MemQuota,
+			MemUsage:  stat.Usage.Mem,
+		}
+	}
+	return instances
+}
+
+func (cmd *ShowApp) printInstances(instances []models.AppInstanceFields) {
 	table := cmd.ui.Table([]string{"", T("state"), T("since"), T("cpu"), T("memory"), T("disk"), T("details")})
 
 	for index, instance := range instances {
@@ -204,7 +266,6 @@ func (cmd *ShowApp) ShowApp(app models.Application, orgName, spaceName string) e
 	}
 
 	table.Print()
-	return nil
 }
 
 func (cmd *ShowApp) populatePluginModel(
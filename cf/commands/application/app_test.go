@@ -13,6 +13,8 @@ import (
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/requirements/requirementsfakes"
+	v3models "github.com/cloudfoundry/cli/cf/v3/models"
+	"github.com/cloudfoundry/cli/cf/v3/repository/repositoryfakes"
 	"github.com/cloudfoundry/cli/flags"
 	"github.com/cloudfoundry/cli/plugin/models"
 
@@ -33,6 +35,7 @@ var _ = Describe("App", func() {
 		ui               *testterm.FakeUI
 		appSummaryRepo   *apifakes.FakeAppSummaryRepository
 		appInstancesRepo *appinstancesfakes.FakeAppInstancesRepository
+		v3Repo           *repositoryfakes.FakeRepository
 		getAppModel      *plugin_models.GetAppModel
 
 		cmd         commandregistry.Command
@@ -58,6 +61,8 @@ var _ = Describe("App", func() {
 		repoLocator = repoLocator.SetAppSummaryRepository(appSummaryRepo)
 		appInstancesRepo = new(appinstancesfakes.FakeAppInstancesRepository)
 		repoLocator = repoLocator.SetAppInstancesRepository(appInstancesRepo)
+		v3Repo = new(repositoryfakes.FakeRepository)
+		repoLocator = repoLocator.SetV3Repository(v3Repo)
 
 		deps = commandregistry.Dependency{
 			UI:     ui,
@@ -388,6 +393,87 @@ var _ = Describe("App", func() {
 			})
 		})
 
+		Context("when the app has processes other than web", func() {
+			BeforeEach(func() {
+				v3Repo.GetProcessesReturns([]v3models.V3Process{
+					{GUID: "web-guid", Type: "web", Instances: 1, MemoryInMB: 1024},
+					{GUID: "worker-guid", Type: "worker", Instances: 2, MemoryInMB: 512},
+					{GUID: "clock-guid", Type: "clock", Instances: 0, MemoryInMB: 128},
+				}, nil)
+				v3Repo.GetProcessStatsReturns([]v3models.V3ProcessStats{
+					{
+						Index: 1,
+						State: "CRASHED",
+					},
+					{
+						Index: 0,
+						State: "RUNNING",
+						Usage: v3models.V3StatsUsage{
+							Mem:  int64(12 * formatters.MEGABYTE),
+							Disk: int64(1 * formatters.MEGABYTE),
+						},
+						MemQuota:  int64(512 * formatters.MEGABYTE),
+						DiskQuota: int64(1 * formatters.GIGABYTE),
+					},
+				}, nil)
+			})
+
+			It("lists the instances of each scaled process type", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(v3Repo.GetProcessesArgsForCall(0)).To(Equal("/v3/apps/fake-app-guid/processes"))
+				Expect(v3Repo.GetProcessStatsCallCount()).To(Equal(1))
+				Expect(v3Repo.GetProcessStatsArgsForCall(0)).To(Equal("/v3/processes/worker-guid/stats"))
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"#0", "running", "25.0%", "24M of 32M", "1G of 2G"},
+					[]string{"process:", "worker"},
+					[]string{"usage: 512M x 2 instances"},
+					[]string{"#0", "running", "12M of 512M", "1M of 1G"},
+					[]string{"#1", "crashed"},
+				))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"process:", "clock"}))
+			})
+		})
+
+		Context("when the Cloud Controller reports no v3 processes", func() {
+			BeforeEach(func() {
+				v3Repo.GetProcessesReturns([]v3models.V3Process{}, nil)
+			})
+
+			It("only shows the web instances", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"#0", "running", "25.0%", "24M of 32M", "1G of 2G"},
+				))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"process:"}))
+			})
+		})
+
+		Context("when the processes of the app cannot be retrieved", func() {
+			BeforeEach(func() {
+				v3Repo.GetProcessesReturns(nil, errors.New("processes-error"))
+			})
+
+			It("returns the error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("processes-error"))
+			})
+		})
+
+		Context("when the instances of a process cannot be retrieved", func() {
+			BeforeEach(func() {
+				v3Repo.GetProcessesReturns([]v3models.V3Process{
+					{GUID: "worker-guid", Type: "worker", Instances: 2, MemoryInMB: 512},
+				}, nil)
+				v3Repo.GetProcessStatsReturns(nil, errors.New("stats-error"))
+			})
+
+			It("returns the error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("stats-error"))
+			})
+		})
+
 		Context("when running instances is -1", func() {
 			BeforeEach(func() {
 				getAppSummaryModel.RunningInstances = -1
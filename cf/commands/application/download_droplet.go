@@ -0,0 +1,127 @@
+package application
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api/applicationbits"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+const defaultDropletPath = "droplet.tgz"
+
+type DownloadDroplet struct {
+	ui          terminal.UI
+	config      coreconfig.Reader
+	appReq      requirements.ApplicationRequirement
+	appBitsRepo applicationbits.ApplicationBitsRepository
+}
+
+func init() {
+	commandregistry.Register(&DownloadDroplet{})
+}
+
+func (cmd *DownloadDroplet) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["p"] = &flags.StringFlag{ShortName: "p", Usage: T("Path the droplet is written to (Default: droplet.tgz)")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "download-droplet",
+		Description: T("Download the staged droplet of an app"),
+		Usage: []string{
+			T("CF_NAME download-droplet APP_NAME [-p PATH]"),
+		},
+		Examples: []string{
+			"CF_NAME download-droplet my-app -p my-app.tgz",
+			"CF_NAME push my-app --droplet my-app.tgz",
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *DownloadDroplet) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires APP_NAME as argument\n\n") + commandregistry.Commands.CommandUsage("download-droplet"))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[0])
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+
+	return reqs
+}
+
+func (cmd *DownloadDroplet) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.appBitsRepo = deps.RepoLocator.GetApplicationBitsRepository()
+	return cmd
+}
+
+func (cmd *DownloadDroplet) Execute(c flags.FlagContext) error {
+	app := cmd.appReq.GetApplication()
+
+	path := c.String("p")
+	if path == "" {
+		path = defaultDropletPath
+	}
+
+	cmd.ui.Say(T("Downloading droplet of app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		map[string]interface{}{
+			"AppName":   terminal.EntityNameColor(app.Name),
+			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	// write to a temporary file next to the destination so that a failed
+	// download never leaves a truncated droplet behind; the temporary file
+	// is removed unless it has been renamed
+	dropletFile, err := ioutil.TempFile(filepath.Dir(path), ".droplet")
+	if err != nil {
+		return errors.New(T("Error creating droplet file {{.Path}}: {{.Err}}",
+			map[string]interface{}{"Path": path, "Err": err.Error()}))
+	}
+	defer os.Remove(dropletFile.Name())
+
+	err = cmd.appBitsRepo.DownloadDroplet(app.GUID, dropletFile)
+	closeErr := dropletFile.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return errors.New(T("Error creating droplet file {{.Path}}: {{.Err}}",
+			map[string]interface{}{"Path": path, "Err": closeErr.Error()}))
+	}
+
+	fileInfo, err := os.Stat(dropletFile.Name())
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(dropletFile.Name(), path)
+	if err != nil {
+		return errors.New(T("Error creating droplet file {{.Path}}: {{.Err}}",
+			map[string]interface{}{"Path": path, "Err": err.Error()}))
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("Droplet of {{.Size}} written to {{.Path}}",
+		map[string]interface{}{
+			"Size": formatters.ByteSize(fileInfo.Size()),
+			"Path": terminal.EntityNameColor(path),
+		}))
+	return nil
+}
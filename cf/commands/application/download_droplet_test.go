@@ -0,0 +1,162 @@
+package application_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api/applicationbits/applicationbitsfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("download-droplet command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		requirementsFactory *testreq.FakeReqFactory
+		appBitsRepo         *applicationbitsfakes.FakeApplicationBitsRepository
+		configRepo          coreconfig.Repository
+		deps                commandregistry.Dependency
+		tmpDir              string
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{}
+		appBitsRepo = new(applicationbitsfakes.FakeApplicationBitsRepository)
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "download-droplet")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetApplicationBitsRepository(appBitsRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("download-droplet").SetDependency(deps, pluginCall))
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("download-droplet", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails with usage when called without an app name", func() {
+			requirementsFactory.LoginSuccess = true
+
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires", "argument"},
+			))
+		})
+
+		It("fails requirements when not logged in", func() {
+			Expect(runCommand("my-app")).To(BeFalse())
+		})
+
+		It("fails if a space is not targeted", func() {
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand("my-app")).To(BeFalse())
+		})
+
+		It("fails when the app does not exist", func() {
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+			requirementsFactory.ApplicationFails = true
+			Expect(runCommand("my-app")).To(BeFalse())
+		})
+	})
+
+	Describe("downloading the droplet", func() {
+		var dropletPath string
+
+		BeforeEach(func() {
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+			requirementsFactory.Application = models.Application{}
+			requirementsFactory.Application.Name = "my-app"
+			requirementsFactory.Application.GUID = "my-app-guid"
+
+			dropletPath = filepath.Join(tmpDir, "my-app.tgz")
+		})
+
+		It("writes the droplet to the given path", func() {
+			appBitsRepo.DownloadDropletStub = func(appGUID string, destination io.Writer) error {
+				_, err := destination.Write([]byte("droplet-contents"))
+				return err
+			}
+
+			runCommand("my-app", "-p", dropletPath)
+
+			Expect(appBitsRepo.DownloadDropletCallCount()).To(Equal(1))
+			appGUID, _ := appBitsRepo.DownloadDropletArgsForCall(0)
+			Expect(appGUID).To(Equal("my-app-guid"))
+
+			contents, err := ioutil.ReadFile(dropletPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("droplet-contents"))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Downloading droplet of app", "my-app", "my-org", "my-space", "my-user"},
+				[]string{"OK"},
+				[]string{"Droplet of 16B written to", dropletPath},
+			))
+		})
+
+		It("does not leave a partial droplet behind when the download fails", func() {
+			appBitsRepo.DownloadDropletStub = func(appGUID string, destination io.Writer) error {
+				destination.Write([]byte("drop"))
+				return errors.New("download-error")
+			}
+
+			runCommand("my-app", "-p", dropletPath)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"download-error"},
+			))
+			Expect(dropletPath).NotTo(BeAnExistingFile())
+
+			files, err := ioutil.ReadDir(tmpDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(BeEmpty())
+		})
+
+		It("does not leave a droplet behind when the file cannot be closed", func() {
+			appBitsRepo.DownloadDropletStub = func(appGUID string, destination io.Writer) error {
+				destination.Write([]byte("droplet-contents"))
+				return destination.(*os.File).Close()
+			}
+
+			runCommand("my-app", "-p", dropletPath)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Error creating droplet file", dropletPath},
+			))
+			Expect(dropletPath).NotTo(BeAnExistingFile())
+
+			files, err := ioutil.ReadDir(tmpDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(BeEmpty())
+		})
+	})
+})
@@ -3,6 +3,7 @@ package application
 import (
 	"encoding/json"
 	"sort"
+	"strings"
 
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	. "github.com/cloudfoundry/cli/cf/i18n"
@@ -10,9 +11,12 @@ import (
 
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/envfile"
 	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/trace"
 )
 
 type Env struct {
@@ -26,13 +30,21 @@ func init() {
 }
 
 func (cmd *Env) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["export"] = &flags.StringFlag{Name: "export", Usage: T("Print only the user-provided env variables, in the dotenv or json format. Credentials are not hidden, so that the output can be read back by 'CF_NAME set-env --from-file'")}
+	fs["show-secrets"] = &flags.BoolFlag{Name: "show-secrets", Usage: T("Show passwords, keys and other credentials instead of hiding them")}
+
 	return commandregistry.CommandMetadata{
 		Name:        "env",
 		ShortName:   "e",
 		Description: T("Show all env variables for an app"),
 		Usage: []string{
-			T("CF_NAME env APP_NAME"),
+			T("CF_NAME env APP_NAME [--export dotenv|json] [--show-secrets]"),
+		},
+		Examples: []string{
+			"CF_NAME env my-app --export dotenv > .env",
 		},
+		Flags: fs,
 	}
 }
 
@@ -41,6 +53,10 @@ func (cmd *Env) Requirements(requirementsFactory requirements.Factory, fc flags.
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("env"))
 	}
 
+	if format := fc.String("export"); fc.IsSet("export") && format != envfile.FormatDotenv && format != envfile.FormatJSON {
+		cmd.ui.Failed(T("Incorrect Usage. --export must be dotenv or json\n\n") + commandregistry.Commands.CommandUsage("env"))
+	}
+
 	reqs := []requirements.Requirement{
 		requirementsFactory.NewLoginRequirement(),
 		requirementsFactory.NewTargetedSpaceRequirement(),
@@ -62,6 +78,10 @@ func (cmd *Env) Execute(c flags.FlagContext) error {
 		return notFound
 	}
 
+	if c.IsSet("export") {
+		return cmd.exportUserProvidedEnvironment(app.GUID, c.String("export"))
+	}
+
 	cmd.ui.Say(T("Getting env variables for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
 		map[string]interface{}{
 			"AppName":   terminal.EntityNameColor(app.Name),
@@ -74,6 +94,13 @@ func (cmd *Env) Execute(c flags.FlagContext) error {
 		return err
 	}
 
+	if !c.Bool("show-secrets") {
+		env, err = cmd.maskSecrets(env)
+		if err != nil {
+			return err
+		}
+	}
+
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
@@ -91,6 +118,41 @@ func (cmd *Env) Execute(c flags.FlagContext) error {
 	return nil
 }
 
+// exportUserProvidedEnvironment prints only the user-provided variables, so
+// that the output can be saved and read back with 'set-env --from-file'.
+// Secrets are not masked, as the placeholders would be written back.
+func (cmd *Env) exportUserProvidedEnvironment(appGUID string, format string) error {
+	env, err := cmd.appRepo.ReadEnv(appGUID)
+	if err != nil {
+		return err
+	}
+
+	exported, err := envfile.Marshal(env.Environment, format)
+	if err != nil {
+		return err
+	}
+
+	if len(exported) > 0 {
+		cmd.ui.Say("%s", strings.TrimSuffix(string(exported), "\n"))
+	}
+	return nil
+}
+
+func (cmd *Env) maskSecrets(env *models.Environment) (*models.Environment, error) {
+	masker, err := trace.NewSecretMasker(cmd.config.SecretPatterns())
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Environment{
+		Environment: masker.MaskVariables(env.Environment),
+		Running:     masker.MaskVariables(env.Running),
+		Staging:     masker.MaskVariables(env.Staging),
+		System:      masker.MaskVariables(env.System),
+		Application: masker.MaskVariables(env.Application),
+	}, nil
+}
+
 func (cmd *Env) displaySystemiAndAppProvidedEnvironment(env map[string]interface{}, app map[string]interface{}) error {
 	var vcapServices string
 	var vcapApplication string
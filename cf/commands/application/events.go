@@ -1,22 +1,50 @@
 package application
 
 import (
+	"encoding/json"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/cloudfoundry/cli/cf/api/appevents"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/flags"
 )
 
+const (
+	DefaultEventsLimit    = 50
+	DefaultFollowInterval = 5 * time.Second
+
+	eventTimestampFormat = "2006-01-02T15:04:05.00-0700"
+)
+
 type Events struct {
 	ui         terminal.UI
 	config     coreconfig.Reader
 	appReq     requirements.ApplicationRequirement
 	eventsRepo appevents.AppEventsRepository
+
+	since time.Time
+	limit int64
+
+	FollowInterval time.Duration
+}
+
+type eventJSON struct {
+	GUID        string    `json:"guid"`
+	Type        string    `json:"type"`
+	Timestamp   time.Time `json:"timestamp"`
+	Actor       string    `json:"actor"`
+	ActorName   string    `json:"actor_name,omitempty"`
+	Actee       string    `json:"actee,omitempty"`
+	ActeeType   string    `json:"actee_type,omitempty"`
+	ActeeName   string    `json:"actee_name,omitempty"`
+	Description string    `json:"description"`
 }
 
 func init() {
@@ -24,27 +52,77 @@ func init() {
 }
 
 func (cmd *Events) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["space"] = &flags.BoolFlag{Name: "space", Usage: T("Show the events of every app and service in the targeted space")}
+	fs["org"] = &flags.BoolFlag{Name: "org", Usage: T("Show the events in every space of the targeted org")}
+	fs["since"] = &flags.StringFlag{Name: "since", Usage: T("Only show the events since a time such as 2016-10-01T15:04:05Z, or for a duration such as 2h")}
+	fs["type"] = &flags.StringSliceFlag{Name: "type", Usage: T("Only show the events of a type such as audit.app.crash, flag can be specified multiple times")}
+	fs["actor"] = &flags.StringFlag{Name: "actor", Usage: T("Only show the events caused by the user or client with the given name or GUID")}
+	fs["limit"] = &flags.IntFlag{Name: "limit", Usage: T("Maximum number of events to show, or 0 for all of them (Default: 50, or all of them when given --since)")}
+	fs["follow"] = &flags.BoolFlag{Name: "follow", Usage: T("Keep checking for new events and show them as they happen")}
+	fs["json"] = &flags.BoolFlag{Name: "json", Usage: T("Show the events as JSON")}
+
 	return commandregistry.CommandMetadata{
 		Name:        "events",
 		Description: T("Show recent app events"),
 		Usage: []string{
-			"CF_NAME events ",
-			T("APP_NAME"),
+			T("CF_NAME events (APP_NAME | --space | --org) [--since TIME] [--type TYPE] [--actor ACTOR] [--limit NUMBER] [--follow] [--json]"),
+		},
+		Examples: []string{
+			"CF_NAME events my-app --type audit.app.crash --since 24h",
+			"CF_NAME events --space --actor admin --follow",
 		},
+		Flags: fs,
 	}
 }
 
 func (cmd *Events) Requirements(requirementsFactory requirements.Factory, c flags.FlagContext) []requirements.Requirement {
-	if len(c.Args()) != 1 {
-		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("events"))
+	scopes := 0
+	for _, scope := range []bool{len(c.Args()) == 1, c.Bool("space"), c.Bool("org")} {
+		if scope {
+			scopes++
+		}
+	}
+
+	if len(c.Args()) > 1 || scopes != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument, or one of --space and --org\n\n") + commandregistry.Commands.CommandUsage("events"))
 	}
 
-	cmd.appReq = requirementsFactory.NewApplicationRequirement(c.Args()[0])
+	cmd.since = time.Time{}
+	if c.IsSet("since") {
+		since, err := parseSince(c.String("since"), time.Now())
+		if err != nil {
+			cmd.ui.Failed(T("Incorrect Usage:") + " " + T("--since must be a time such as 2016-10-01T15:04:05Z, or a duration such as 2h") + "\n\n" + commandregistry.Commands.CommandUsage("events"))
+		}
+		cmd.since = since
+	}
+
+	// the events since a given time are all shown, unless limited explicitly
+	cmd.limit = DefaultEventsLimit
+	if !cmd.since.IsZero() {
+		cmd.limit = 0
+	}
+	if c.IsSet("limit") {
+		if c.Int("limit") < 0 {
+			cmd.ui.Failed(T("Incorrect Usage:") + " " + T("--limit cannot be negative") + "\n\n" + commandregistry.Commands.CommandUsage("events"))
+		}
+		cmd.limit = int64(c.Int("limit"))
+	}
 
 	reqs := []requirements.Requirement{
 		requirementsFactory.NewLoginRequirement(),
-		requirementsFactory.NewTargetedSpaceRequirement(),
-		cmd.appReq,
+	}
+
+	if c.Bool("org") {
+		reqs = append(reqs, requirementsFactory.NewTargetedOrgRequirement())
+		return reqs
+	}
+
+	reqs = append(reqs, requirementsFactory.NewTargetedSpaceRequirement())
+
+	if len(c.Args()) == 1 {
+		cmd.appReq = requirementsFactory.NewApplicationRequirement(c.Args()[0])
+		reqs = append(reqs, cmd.appReq)
 	}
 
 	return reqs
@@ -54,47 +132,243 @@ func (cmd *Events) SetDependency(deps commandregistry.Dependency, pluginCall boo
 	cmd.ui = deps.UI
 	cmd.config = deps.Config
 	cmd.eventsRepo = deps.RepoLocator.GetAppEventsRepository()
+	cmd.FollowInterval = DefaultFollowInterval
 	return cmd
 }
 
 func (cmd *Events) Execute(c flags.FlagContext) error {
-	app := cmd.appReq.GetApplication()
+	query := appevents.EventQuery{
+		Actor: c.String("actor"),
+		Since: cmd.since,
+		Limit: cmd.limit,
+	}
+	for _, eventType := range c.StringSlice("type") {
+		query.Types = append(query.Types, strings.Split(eventType, ",")...)
+	}
 
-	cmd.ui.Say(T("Getting events for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
-		map[string]interface{}{
-			"AppName":   terminal.EntityNameColor(app.Name),
-			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
-			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
-			"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	var app models.Application
+	var noEventsMessage string
 
-	table := cmd.ui.Table([]string{T("time"), T("event"), T("actor"), T("description")})
+	switch {
+	case c.Bool("org"):
+		query.OrganizationGUID = cmd.config.OrganizationFields().GUID
+		noEventsMessage = T("No events for org {{.OrgName}}",
+			map[string]interface{}{"OrgName": terminal.EntityNameColor(cmd.config.OrganizationFields().Name)})
+		cmd.sayUnlessJSON(c, T("Getting events for org {{.OrgName}} as {{.Username}}...\n",
+			map[string]interface{}{
+				"OrgName":  terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"Username": terminal.EntityNameColor(cmd.config.Username())}))
+	case c.Bool("space"):
+		query.SpaceGUID = cmd.config.SpaceFields().GUID
+		noEventsMessage = T("No events for space {{.SpaceName}}",
+			map[string]interface{}{"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name)})
+		cmd.sayUnlessJSON(c, T("Getting events for space {{.SpaceName}} in org {{.OrgName}} as {{.Username}}...\n",
+			map[string]interface{}{
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	default:
+		app = cmd.appReq.GetApplication()
+		query.ActeeGUID = app.GUID
+		noEventsMessage = T("No events for app {{.AppName}}",
+			map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)})
+		cmd.sayUnlessJSON(c, T("Getting events for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(app.Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	}
+
+	var events []models.EventFields
+	var err error
 
-	events, err := cmd.eventsRepo.RecentEvents(app.GUID, 50)
+	// without filters, the recent events of an app are listed in the way
+	// that works with every API version
+	if query.ActeeGUID != "" && len(query.Types) == 0 && query.Actor == "" && query.Since.IsZero() && query.Limit > 0 {
+		events, err = cmd.eventsRepo.RecentEvents(app.GUID, query.Limit)
+	} else {
+		events, err = cmd.eventsRepo.ListEvents(query)
+	}
 	if err != nil {
 		return errors.New(T("Failed fetching events.\n{{.APIErr}}",
 			map[string]interface{}{"APIErr": err.Error()}))
 	}
 
+	if !c.Bool("follow") {
+		if c.Bool("json") {
+			return cmd.printEventsJSON(events)
+		}
+
+		cmd.printEvents(events, query.ActeeGUID == "")
+		if len(events) == 0 {
+			cmd.ui.Say(noEventsMessage)
+		}
+		return nil
+	}
+
+	// the recent events are listed newest first, but followed events are
+	// shown in the order in which they happened
+	oldestFirst := make([]models.EventFields, len(events))
+	for i, event := range events {
+		oldestFirst[len(events)-1-i] = event
+	}
+
+	return cmd.follow(c, query, oldestFirst)
+}
+
+func (cmd *Events) follow(c flags.FlagContext, query appevents.EventQuery, events []models.EventFields) error {
+	// events are queried from the time of the latest one, so the ones at that
+	// time which have already been shown are skipped
+	seen := map[string]bool{}
+
+	// with no events to start from, the new ones are those after the latest
+	// event on the server, whose clock may differ from the local one
+	if query.Since.IsZero() && len(events) == 0 {
+		latestQuery := query
+		latestQuery.Actor = ""
+		latestQuery.Limit = 1
+		latest, err := cmd.eventsRepo.ListEvents(latestQuery)
+		if err != nil {
+			return errors.New(T("Failed fetching events.\n{{.APIErr}}",
+				map[string]interface{}{"APIErr": err.Error()}))
+		}
+		if len(latest) > 0 {
+			query.Since = latest[0].Timestamp
+			seen[latest[0].GUID] = true
+		}
+	}
+
+	query.Ascending = true
+	query.Limit = 0
+
+	cmd.sayUnlessJSON(c, T("Following new events, press Ctrl-C to stop...\n"))
+
+	for {
+		if c.Bool("json") {
+			for _, event := range events {
+				err := cmd.printEventJSON(event)
+				if err != nil {
+					return err
+				}
+			}
+		} else if len(events) > 0 {
+			cmd.printEvents(events, query.ActeeGUID == "")
+		}
+
+		for _, event := range events {
+			if event.Timestamp.After(query.Since) {
+				query.Since = event.Timestamp
+				seen = map[string]bool{}
+			}
+			seen[event.GUID] = true
+		}
+
+		time.Sleep(cmd.FollowInterval)
+
+		newEvents, err := cmd.eventsRepo.ListEvents(query)
+		if err != nil {
+			return errors.New(T("Failed fetching events.\n{{.APIErr}}",
+				map[string]interface{}{"APIErr": err.Error()}))
+		}
+
+		events = []models.EventFields{}
+		for _, event := range newEvents {
+			if !seen[event.GUID] {
+				events = append(events, event)
+			}
+		}
+	}
+}
+
+func (cmd *Events) sayUnlessJSON(c flags.FlagContext, message string) {
+	if !c.Bool("json") {
+		cmd.ui.Say(message)
+	}
+}
+
+func (cmd *Events) printEvents(events []models.EventFields, showActee bool) {
+	headers := []string{T("time"), T("event"), T("actor"), T("description")}
+	if showActee {
+		headers = []string{T("time"), T("event"), T("target"), T("actor"), T("description")}
+	}
+	table := cmd.ui.Table(headers)
+
 	for _, event := range events {
 		actor := event.ActorName
 		if actor == "" {
 			actor = event.Actor
 		}
 
-		table.Add(
-			event.Timestamp.Local().Format("2006-01-02T15:04:05.00-0700"),
-			event.Name,
-			actor,
-			event.Description,
-		)
+		row := []string{event.Timestamp.Local().Format(eventTimestampFormat), event.Name}
+		if showActee {
+			actee := event.ActeeName
+			if actee == "" {
+				actee = event.Actee
+			}
+			row = append(row, actee)
+		}
+		row = append(row, actor, event.Description)
+
+		table.Add(row...)
 	}
 
 	table.Print()
+}
 
-	if len(events) == 0 {
-		cmd.ui.Say(T("No events for app {{.AppName}}",
-			map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
-		return nil
+func (cmd *Events) printEventsJSON(events []models.EventFields) error {
+	eventsJSON := []eventJSON{}
+	for _, event := range events {
+		eventsJSON = append(eventsJSON, newEventJSON(event))
+	}
+
+	jsonBytes, err := json.MarshalIndent(eventsJSON, "", "  ")
+	if err != nil {
+		return err
 	}
+
+	cmd.ui.Say("%s", string(jsonBytes))
+	return nil
+}
+
+// printEventJSON prints one event per line, so that followed events can be
+// read as they happen.
+func (cmd *Events) printEventJSON(event models.EventFields) error {
+	jsonBytes, err := json.Marshal(newEventJSON(event))
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Say("%s", string(jsonBytes))
 	return nil
 }
+
+func newEventJSON(event models.EventFields) eventJSON {
+	return eventJSON{
+		GUID:        event.GUID,
+		Type:        event.Name,
+		Timestamp:   event.Timestamp,
+		Actor:       event.Actor,
+		ActorName:   event.ActorName,
+		Actee:       event.Actee,
+		ActeeType:   event.ActeeType,
+		ActeeName:   event.ActeeName,
+		Description: event.Description,
+	}
+}
+
+func parseSince(value string, now time.Time) (time.Time, error) {
+	duration, err := time.ParseDuration(value)
+	if err == nil {
+		return now.Add(-duration), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		since, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return since, nil
+		}
+	}
+
+	return time.Time{}, errors.New(T("Invalid time: {{.Time}}", map[string]interface{}{"Time": value}))
+}
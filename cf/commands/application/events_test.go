@@ -2,6 +2,7 @@ package application_test
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/cloudfoundry/cli/cf/api"
@@ -11,6 +12,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/flags"
 
+	"github.com/cloudfoundry/cli/cf/api/appevents"
 	"github.com/cloudfoundry/cli/cf/api/appevents/appeventsfakes"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig/coreconfigfakes"
 	"github.com/cloudfoundry/cli/cf/requirements/requirementsfakes"
@@ -46,8 +48,8 @@ var _ = Describe("events command", func() {
 		eventsRepo = new(appeventsfakes.FakeAppEventsRepository)
 		config = new(coreconfigfakes.FakeRepository)
 
-		config.OrganizationFieldsReturns(models.OrganizationFields{Name: "my-org"})
-		config.SpaceFieldsReturns(models.SpaceFields{Name: "my-space"})
+		config.OrganizationFieldsReturns(models.OrganizationFields{Name: "my-org", GUID: "my-org-guid"})
+		config.SpaceFieldsReturns(models.SpaceFields{Name: "my-space", GUID: "my-space-guid"})
 		config.UsernameReturns("my-user")
 
 		deps = commandregistry.Dependency{
@@ -84,6 +86,54 @@ var _ = Describe("events command", func() {
 			})
 		})
 
+		Context("when provided an app name and --space", func() {
+			It("fails", func() {
+				err := flagContext.Parse("my-app", "--space")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(func() { cmd.Requirements(reqFactory, flagContext) }).To(Panic())
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Incorrect Usage", "Requires an argument, or one of --space and --org"},
+				))
+			})
+		})
+
+		Context("when provided an invalid --since", func() {
+			It("fails", func() {
+				err := flagContext.Parse("my-app", "--since", "yesterday")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(func() { cmd.Requirements(reqFactory, flagContext) }).To(Panic())
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Incorrect Usage", "--since must be a time"},
+				))
+			})
+		})
+
+		Context("when provided a negative --limit", func() {
+			It("fails", func() {
+				err := flagContext.Parse("my-app", "--limit", "-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(func() { cmd.Requirements(reqFactory, flagContext) }).To(Panic())
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Incorrect Usage", "--limit cannot be negative"},
+				))
+			})
+		})
+
+		Context("when provided --org", func() {
+			It("returns a TargetedOrgRequirement instead of a TargetedSpaceRequirement", func() {
+				targetedOrgRequirement := new(requirementsfakes.FakeTargetedOrgRequirement)
+				reqFactory.NewTargetedOrgRequirementReturns(targetedOrgRequirement)
+
+				err := flagContext.Parse("--org")
+				Expect(err).NotTo(HaveOccurred())
+				actualRequirements := cmd.Requirements(reqFactory, flagContext)
+
+				Expect(actualRequirements).To(ContainElement(targetedOrgRequirement))
+				Expect(reqFactory.NewTargetedSpaceRequirementCallCount()).To(Equal(0))
+				Expect(reqFactory.NewApplicationRequirementCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when provided exactly one arg", func() {
 			var actualRequirements []requirements.Requirement
 
@@ -217,4 +267,260 @@ var _ = Describe("events command", func() {
 			})
 		})
 	})
+
+	Describe("Execute with filters", func() {
+		var (
+			earlierTimestamp time.Time
+			timestamp        time.Time
+			laterTimestamp   time.Time
+			events           []models.EventFields
+		)
+
+		BeforeEach(func() {
+			applicationRequirement.GetApplicationReturns(models.Application{
+				ApplicationFields: models.ApplicationFields{
+					Name: "my-app",
+					GUID: "my-app-guid",
+				},
+			})
+
+			var err error
+			earlierTimestamp, err = time.Parse(TIMESTAMP_FORMAT, "1999-12-31T23:59:11.00-0000")
+			Expect(err).NotTo(HaveOccurred())
+			timestamp, err = time.Parse(TIMESTAMP_FORMAT, "2000-01-01T00:01:11.00-0000")
+			Expect(err).NotTo(HaveOccurred())
+			laterTimestamp, err = time.Parse(TIMESTAMP_FORMAT, "2000-01-01T00:02:11.00-0000")
+			Expect(err).NotTo(HaveOccurred())
+
+			events = []models.EventFields{
+				{
+					GUID:        "event-guid-2",
+					Name:        "audit.app.crash",
+					Timestamp:   timestamp,
+					Description: "exit_status: 77",
+					Actor:       "my-app-guid",
+					Actee:       "my-app-guid",
+					ActeeName:   "my-app",
+				},
+				{
+					GUID:        "event-guid-1",
+					Name:        "audit.app.update",
+					Timestamp:   earlierTimestamp,
+					Description: "instances: 2",
+					Actor:       "user-guid",
+					ActorName:   "admin",
+					Actee:       "other-app-guid",
+					ActeeName:   "other-app",
+				},
+			}
+
+			cmd.SetDependency(deps, false)
+			cmd.FollowInterval = 0
+		})
+
+		runCommand := func(args ...string) error {
+			err := flagContext.Parse(args...)
+			Expect(err).NotTo(HaveOccurred())
+			cmd.Requirements(reqFactory, flagContext)
+			return cmd.Execute(flagContext)
+		}
+
+		It("lists the events of the targeted space matching the filters", func() {
+			eventsRepo.ListEventsReturns(events, nil)
+
+			err := runCommand("--space", "--type", "audit.app.crash,audit.app.update", "--actor", "admin", "--since", "2h")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(eventsRepo.RecentEventsCallCount()).To(Equal(0))
+			Expect(eventsRepo.ListEventsCallCount()).To(Equal(1))
+			query := eventsRepo.ListEventsArgsForCall(0)
+			Expect(query.SpaceGUID).To(Equal("my-space-guid"))
+			Expect(query.ActeeGUID).To(BeEmpty())
+			Expect(query.Types).To(Equal([]string{"audit.app.crash", "audit.app.update"}))
+			Expect(query.Actor).To(Equal("admin"))
+			Expect(query.Since).To(BeTemporally("~", time.Now().Add(-2*time.Hour), time.Minute))
+			Expect(query.Limit).To(BeZero())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Getting events for space", "my-space", "my-org", "my-user"},
+				[]string{"time", "event", "target", "actor", "description"},
+				[]string{timestamp.Local().Format(TIMESTAMP_FORMAT), "audit.app.crash", "my-app", "my-app-guid", "exit_status: 77"},
+				[]string{earlierTimestamp.Local().Format(TIMESTAMP_FORMAT), "audit.app.update", "other-app", "admin", "instances: 2"},
+			))
+		})
+
+		It("shows at most the default number of events without --since", func() {
+			eventsRepo.ListEventsReturns(events, nil)
+
+			err := runCommand("--space", "--type", "audit.app.crash")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(eventsRepo.ListEventsArgsForCall(0).Limit).To(Equal(int64(50)))
+		})
+
+		It("shows at most the number of events given with --limit", func() {
+			eventsRepo.ListEventsReturns(events, nil)
+
+			err := runCommand("--space", "--since", "2h", "--limit", "10")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(eventsRepo.ListEventsArgsForCall(0).Limit).To(Equal(int64(10)))
+		})
+
+		It("lists the recent events of an app up to the number given with --limit", func() {
+			eventsRepo.RecentEventsReturns(events, nil)
+
+			err := runCommand("my-app", "--limit", "10")
+			Expect(err).NotTo(HaveOccurred())
+
+			appGUID, limit := eventsRepo.RecentEventsArgsForCall(0)
+			Expect(appGUID).To(Equal("my-app-guid"))
+			Expect(limit).To(Equal(int64(10)))
+		})
+
+		It("lists all of the events of an app when given a --limit of 0", func() {
+			eventsRepo.ListEventsReturns(events, nil)
+
+			err := runCommand("my-app", "--limit", "0")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(eventsRepo.RecentEventsCallCount()).To(Equal(0))
+			query := eventsRepo.ListEventsArgsForCall(0)
+			Expect(query.ActeeGUID).To(Equal("my-app-guid"))
+			Expect(query.Limit).To(BeZero())
+		})
+
+		It("lists the events of the targeted org", func() {
+			eventsRepo.ListEventsReturns([]models.EventFields{}, nil)
+
+			err := runCommand("--org")
+			Expect(err).NotTo(HaveOccurred())
+
+			query := eventsRepo.ListEventsArgsForCall(0)
+			Expect(query.OrganizationGUID).To(Equal("my-org-guid"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Getting events for org", "my-org", "my-user"},
+				[]string{"No events for org", "my-org"},
+			))
+		})
+
+		It("prints the events as JSON", func() {
+			eventsRepo.RecentEventsReturns(events, nil)
+
+			err := runCommand("my-app", "--json")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ui.Outputs[0]).To(Equal("["))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{`"guid": "event-guid-2"`},
+				[]string{`"type": "audit.app.crash"`},
+				[]string{`"timestamp": "2000-01-01T00:01:11Z"`},
+				[]string{`"actor_name": "admin"`},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Getting events"}))
+		})
+
+		Context("when following events", func() {
+			BeforeEach(func() {
+				eventsRepo.RecentEventsReturns(events, nil)
+				eventsRepo.ListEventsStub = func(query appevents.EventQuery) ([]models.EventFields, error) {
+					if eventsRepo.ListEventsCallCount() == 1 {
+						return []models.EventFields{
+							events[0],
+							{
+								GUID:      "event-guid-3",
+								Name:      "audit.app.start",
+								Timestamp: laterTimestamp,
+								Actor:     "user-guid",
+								ActorName: "admin",
+							},
+						}, nil
+					}
+					return nil, errors.New("connection lost")
+				}
+			})
+
+			It("shows the new events in the order in which they happened", func() {
+				err := runCommand("my-app", "--follow")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("connection lost"))
+
+				query := eventsRepo.ListEventsArgsForCall(0)
+				Expect(query.ActeeGUID).To(Equal("my-app-guid"))
+				Expect(query.Since).To(Equal(timestamp))
+				Expect(query.Ascending).To(BeTrue())
+				Expect(query.Limit).To(BeZero())
+
+				Expect(eventsRepo.ListEventsArgsForCall(1).Since).To(Equal(laterTimestamp))
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Following new events", "Ctrl-C"},
+					[]string{"audit.app.update"},
+					[]string{"audit.app.crash"},
+					[]string{"audit.app.start"},
+				))
+
+				crashes := 0
+				for _, line := range ui.Outputs {
+					if strings.Contains(line, "audit.app.crash") {
+						crashes++
+					}
+				}
+				Expect(crashes).To(Equal(1))
+			})
+
+			It("prints one JSON object per line when given --json", func() {
+				err := runCommand("my-app", "--follow", "--json")
+				Expect(err).To(HaveOccurred())
+
+				Expect(ui.Outputs).To(HaveLen(3))
+				Expect(ui.Outputs[0]).To(HavePrefix(`{"guid":"event-guid-1"`))
+				Expect(ui.Outputs[2]).To(HavePrefix(`{"guid":"event-guid-3"`))
+			})
+		})
+
+		Context("when following events and there are none to start from", func() {
+			BeforeEach(func() {
+				eventsRepo.RecentEventsReturns([]models.EventFields{}, nil)
+				eventsRepo.ListEventsStub = func(query appevents.EventQuery) ([]models.EventFields, error) {
+					switch eventsRepo.ListEventsCallCount() {
+					case 1:
+						return []models.EventFields{events[1]}, nil
+					case 2:
+						return []models.EventFields{
+							events[1],
+							{
+								GUID:      "event-guid-3",
+								Name:      "audit.app.start",
+								Timestamp: laterTimestamp,
+								Actor:     "user-guid",
+								ActorName: "admin",
+							},
+						}, nil
+					}
+					return nil, errors.New("connection lost")
+				}
+			})
+
+			It("only shows the events after the latest one on the server", func() {
+				err := runCommand("my-app", "--follow")
+				Expect(err).To(HaveOccurred())
+
+				query := eventsRepo.ListEventsArgsForCall(0)
+				Expect(query.ActeeGUID).To(Equal("my-app-guid"))
+				Expect(query.Limit).To(Equal(int64(1)))
+				Expect(query.Ascending).To(BeFalse())
+
+				query = eventsRepo.ListEventsArgsForCall(1)
+				Expect(query.Since).To(Equal(earlierTimestamp))
+				Expect(query.Ascending).To(BeTrue())
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Following new events", "Ctrl-C"},
+					[]string{"audit.app.start"},
+				))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"audit.app.update"}))
+			})
+		})
+	})
 })
@@ -2,11 +2,17 @@ package application
 
 import (
 	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/cloudfoundry/cli/cf/api/appfiles"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/flags"
@@ -26,6 +32,10 @@ func init() {
 func (cmd *Files) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["i"] = &flags.IntFlag{ShortName: "i", Usage: T("Instance")}
+	fs["download"] = &flags.StringFlag{Name: "download", Usage: T("Download the file, or the directory recursively, into the given local directory")}
+	fs["include"] = &flags.StringSliceFlag{Name: "include", Usage: T("Only download files matching the glob pattern. This flag can be defined more than once.")}
+	fs["exclude"] = &flags.StringSliceFlag{Name: "exclude", Usage: T("Skip files and directories matching the glob pattern. This flag can be defined more than once.")}
+	fs["all-instances"] = &flags.BoolFlag{Name: "all-instances", Usage: T("Download from every instance into a subdirectory per instance index")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "files",
@@ -33,10 +43,16 @@ func (cmd *Files) MetaData() commandregistry.CommandMetadata {
 		Description: T("Print out a list of files in a directory or the contents of a specific file of an app running on the DEA backend"),
 		Usage: []string{
 			T(`CF_NAME files APP_NAME [PATH] [-i INSTANCE]
-			
+
+   CF_NAME files APP_NAME PATH --download LOCAL_DIR [-i INSTANCE | --all-instances] [--include GLOB] [--exclude GLOB]
+
 TIP:
   To list and inspect files of an app running on the Diego backend, use 'CF_NAME ssh'`),
 		},
+		Examples: []string{
+			"CF_NAME files my-app /app/logs --download ./logs --include '*.log'",
+			"CF_NAME files my-app /app/dumps --download ./dumps --all-instances",
+		},
 		Flags: fs,
 	}
 }
@@ -67,6 +83,21 @@ func (cmd *Files) SetDependency(deps commandregistry.Dependency, pluginCall bool
 func (cmd *Files) Execute(c flags.FlagContext) error {
 	app := cmd.appReq.GetApplication()
 
+	if c.String("download") == "" && (c.Bool("all-instances") || len(c.StringSlice("include")) > 0 || len(c.StringSlice("exclude")) > 0) {
+		return errors.New(T("Incorrect Usage. The --all-instances, --include and --exclude flags require --download."))
+	}
+
+	if c.Bool("all-instances") && c.IsSet("i") {
+		return errors.New(T("Incorrect Usage. The -i and --all-instances flags cannot be used together."))
+	}
+
+	for _, pattern := range append(c.StringSlice("include"), c.StringSlice("exclude")...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.New(T("Incorrect Usage. Invalid glob pattern {{.Pattern}}: {{.Err}}",
+				map[string]interface{}{"Pattern": pattern, "Err": err.Error()}))
+		}
+	}
+
 	var instance int
 	if c.IsSet("i") {
 		instance = c.Int("i")
@@ -97,6 +128,10 @@ func (cmd *Files) Execute(c flags.FlagContext) error {
 		path = c.Args()[1]
 	}
 
+	if c.String("download") != "" {
+		return cmd.download(app, instance, path, c)
+	}
+
 	list, err := cmd.appFilesRepo.ListFiles(app.GUID, instance, path)
 	if err != nil {
 		return err
@@ -112,3 +147,186 @@ func (cmd *Files) Execute(c flags.FlagContext) error {
 	}
 	return nil
 }
+
+func (cmd *Files) download(app models.Application, instance int, remotePath string, c flags.FlagContext) error {
+	downloader := fileDownloader{
+		appFilesRepo: cmd.appFilesRepo,
+		appGUID:      app.GUID,
+		includes:     c.StringSlice("include"),
+		excludes:     c.StringSlice("exclude"),
+	}
+
+	localDir := c.String("download")
+
+	if c.Bool("all-instances") {
+		failedCount := 0
+		for i := 0; i < app.InstanceCount; i++ {
+			downloader.instance = i
+			err := downloader.download(remotePath, filepath.Join(localDir, strconv.Itoa(i)))
+			if err != nil {
+				// a crashed or starting instance should not prevent collecting
+				// files from the others
+				cmd.ui.Warn(T("Could not download files from instance {{.Instance}}: {{.Err}}",
+					map[string]interface{}{"Instance": i, "Err": err.Error()}))
+				failedCount++
+			}
+		}
+
+		if failedCount > 0 {
+			return errors.New(T("Could not download files from {{.FailedCount}} of {{.InstanceCount}} instances; downloaded {{.FileCount}} files to {{.Path}}",
+				map[string]interface{}{
+					"FailedCount":   failedCount,
+					"InstanceCount": app.InstanceCount,
+					"FileCount":     downloader.fileCount,
+					"Path":          localDir,
+				}))
+		}
+	} else {
+		downloader.instance = instance
+		err := downloader.download(remotePath, localDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("Downloaded {{.FileCount}} files to {{.Path}}",
+		map[string]interface{}{"FileCount": downloader.fileCount, "Path": terminal.EntityNameColor(localDir)}))
+	return nil
+}
+
+type fileDownloader struct {
+	appFilesRepo appfiles.AppFilesRepository
+	appGUID      string
+	instance     int
+	includes     []string
+	excludes     []string
+	fileCount    int
+}
+
+func (d *fileDownloader) download(remotePath string, localDir string) error {
+	isDir, err := d.isDir(remotePath)
+	if err != nil {
+		return err
+	}
+
+	if isDir {
+		return d.downloadDir(remotePath, "", localDir)
+	}
+
+	return d.downloadFile(remotePath, filepath.Join(localDir, path.Base(remotePath)))
+}
+
+// isDir looks the path up in its parent's listing, since the files endpoint
+// returns the contents of a file and the listing of a directory alike.
+func (d *fileDownloader) isDir(remotePath string) (bool, error) {
+	trimmed := strings.TrimSuffix(remotePath, "/")
+	if trimmed == "" || trimmed != remotePath {
+		return true, nil
+	}
+
+	listing, err := d.appFilesRepo.ListFiles(d.appGUID, d.instance, path.Dir(trimmed)+"/")
+	if err != nil {
+		return false, err
+	}
+
+	base := path.Base(trimmed)
+	for _, entry := range appfiles.ParseFileListing(listing) {
+		if entry.Name == base {
+			return entry.IsDir, nil
+		}
+	}
+
+	return false, errors.New(T("File or directory {{.Path}} not found", map[string]interface{}{"Path": remotePath}))
+}
+
+func (d *fileDownloader) downloadDir(remoteDir string, relativeDir string, localDir string) error {
+	listing, err := d.appFilesRepo.ListFiles(d.appGUID, d.instance, strings.TrimSuffix(remoteDir, "/")+"/")
+	if err != nil {
+		return err
+	}
+
+	// with --include, directories are only created for the files they hold
+	if len(d.includes) == 0 {
+		err = os.MkdirAll(localDir, 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range appfiles.ParseFileListing(listing) {
+		if !isPlainFileName(entry.Name) {
+			return errors.New(T("Invalid file name {{.Name}} in the listing of {{.Path}}",
+				map[string]interface{}{"Name": entry.Name, "Path": remoteDir}))
+		}
+
+		relativePath := path.Join(relativeDir, entry.Name)
+		if matchesAny(d.excludes, entry.Name, relativePath) {
+			continue
+		}
+
+		remotePath := path.Join(remoteDir, entry.Name)
+		localPath := filepath.Join(localDir, entry.Name)
+
+		if entry.IsDir {
+			err = d.downloadDir(remotePath, relativePath, localPath)
+		} else if len(d.includes) == 0 || matchesAny(d.includes, entry.Name, relativePath) {
+			err = d.downloadFile(remotePath, localPath)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *fileDownloader) downloadFile(remotePath string, localPath string) error {
+	err := os.MkdirAll(filepath.Dir(localPath), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(localPath)
+	if err != nil {
+		return err
+	}
+
+	// a failed download never leaves a truncated file behind
+	err = d.appFilesRepo.DownloadFile(d.appGUID, d.instance, remotePath, file)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(localPath)
+		return err
+	}
+
+	d.fileCount++
+	return nil
+}
+
+// isPlainFileName reports whether a name from a remote listing names an
+// entry of the listed directory, and nothing outside of it.
+func isPlainFileName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	return !strings.ContainsAny(name, `/\`)
+}
+
+// matchesAny reports whether a glob pattern matches either the base name or
+// the path relative to the downloaded directory. The patterns have been
+// checked to be well formed before the download starts.
+func matchesAny(patterns []string, name string, relativePath string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, relativePath); matched {
+			return true
+		}
+	}
+	return false
+}
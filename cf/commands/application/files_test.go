@@ -2,6 +2,11 @@ package application_test
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/commands/application"
@@ -253,5 +258,230 @@ var _ = Describe("Files", func() {
 				Expect(path).To(Equal("the-path"))
 			})
 		})
+
+		Context("when downloading", func() {
+			var localDir string
+
+			BeforeEach(func() {
+				var tmpErr error
+				localDir, tmpErr = ioutil.TempDir("", "files-download")
+				Expect(tmpErr).NotTo(HaveOccurred())
+
+				listings := map[string]string{
+					"/app/":          "logs/                                        -\nheap.hprof                                 2M\n",
+					"/app/logs/":     "app.log                                   1.2K\nold/                                         -\ndebug.txt                                  12B\n",
+					"/app/logs/old/": "app.log.1                                 1.1K\n",
+				}
+				appFilesRepo.ListFilesStub = func(appGUID string, instance int, path string) (string, error) {
+					listing, ok := listings[path]
+					if !ok {
+						return "", errors.New("unexpected path " + path)
+					}
+					return listing, nil
+				}
+				appFilesRepo.DownloadFileStub = func(appGUID string, instance int, path string, destination io.Writer) error {
+					_, err := fmt.Fprintf(destination, "%d:%s", instance, path)
+					return err
+				}
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(localDir)
+			})
+
+			readLocalFile := func(path ...string) string {
+				contents, err := ioutil.ReadFile(filepath.Join(append([]string{localDir}, path...)...))
+				Expect(err).NotTo(HaveOccurred())
+				return string(contents)
+			}
+
+			Context("when given a directory", func() {
+				BeforeEach(func() {
+					args = []string{"app-name", "/app/logs", "--download", localDir}
+				})
+
+				It("reproduces the directory tree locally", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(readLocalFile("app.log")).To(Equal("0:/app/logs/app.log"))
+					Expect(readLocalFile("debug.txt")).To(Equal("0:/app/logs/debug.txt"))
+					Expect(readLocalFile("old", "app.log.1")).To(Equal("0:/app/logs/old/app.log.1"))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"OK"},
+						[]string{"Downloaded 3 files to", localDir},
+					))
+				})
+			})
+
+			Context("when given a file", func() {
+				BeforeEach(func() {
+					args = []string{"app-name", "/app/heap.hprof", "--download", localDir}
+				})
+
+				It("downloads the single file", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(readLocalFile("heap.hprof")).To(Equal("0:/app/heap.hprof"))
+					Expect(appFilesRepo.DownloadFileCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the path does not exist", func() {
+				BeforeEach(func() {
+					args = []string{"app-name", "/app/missing", "--download", localDir}
+				})
+
+				It("fails with error", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("/app/missing not found"))
+				})
+			})
+
+			Context("when given glob filters", func() {
+				BeforeEach(func() {
+					args = []string{"app-name", "/app/logs/", "--download", localDir, "--include", "*.log*", "--exclude", "old"}
+				})
+
+				It("only downloads matching files", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(readLocalFile("app.log")).To(Equal("0:/app/logs/app.log"))
+					Expect(filepath.Join(localDir, "debug.txt")).NotTo(BeAnExistingFile())
+					Expect(filepath.Join(localDir, "old")).NotTo(BeAnExistingFile())
+					Expect(appFilesRepo.DownloadFileCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when an include filter only matches files in some directories", func() {
+				BeforeEach(func() {
+					args = []string{"app-name", "/app/", "--download", localDir, "--include", "debug.txt"}
+				})
+
+				It("does not create directories without matching files", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(readLocalFile("logs", "debug.txt")).To(Equal("0:/app/logs/debug.txt"))
+					Expect(filepath.Join(localDir, "logs", "old")).NotTo(BeAnExistingFile())
+				})
+			})
+
+			Context("when the listing holds a name outside of the directory", func() {
+				BeforeEach(func() {
+					appFilesRepo.ListFilesReturns("../escape.txt                               1K\n", nil)
+					appFilesRepo.ListFilesStub = nil
+					args = []string{"app-name", "/app/", "--download", localDir}
+				})
+
+				It("fails without writing the file", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("Invalid file name ../escape.txt"))
+					Expect(appFilesRepo.DownloadFileCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when a download fails", func() {
+				BeforeEach(func() {
+					appFilesRepo.DownloadFileStub = func(appGUID string, instance int, path string, destination io.Writer) error {
+						fmt.Fprint(destination, "partial")
+						return errors.New("connection reset")
+					}
+					args = []string{"app-name", "/app/heap.hprof", "--download", localDir}
+				})
+
+				It("fails and removes the partial file", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("connection reset"))
+					Expect(filepath.Join(localDir, "heap.hprof")).NotTo(BeAnExistingFile())
+				})
+			})
+
+			Context("when a downloaded file cannot be closed", func() {
+				BeforeEach(func() {
+					appFilesRepo.DownloadFileStub = func(appGUID string, instance int, path string, destination io.Writer) error {
+						fmt.Fprint(destination, "contents")
+						return destination.(*os.File).Close()
+					}
+					args = []string{"app-name", "/app/heap.hprof", "--download", localDir}
+				})
+
+				It("fails and removes the file", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(filepath.Join(localDir, "heap.hprof")).NotTo(BeAnExistingFile())
+					Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"OK"}))
+				})
+			})
+
+			Context("when downloading from all instances", func() {
+				BeforeEach(func() {
+					app := models.Application{}
+					app.InstanceCount = 2
+					app.GUID = "app-guid"
+					app.Name = "app-name"
+					deaApplicationRequirement.GetApplicationReturns(app)
+
+					args = []string{"app-name", "/app/heap.hprof", "--download", localDir, "--all-instances"}
+				})
+
+				It("downloads into a subdirectory per instance", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(readLocalFile("0", "heap.hprof")).To(Equal("0:/app/heap.hprof"))
+					Expect(readLocalFile("1", "heap.hprof")).To(Equal("1:/app/heap.hprof"))
+				})
+
+				Context("when an instance is unavailable", func() {
+					BeforeEach(func() {
+						appFilesRepo.DownloadFileStub = func(appGUID string, instance int, path string, destination io.Writer) error {
+							if instance == 0 {
+								return errors.New("instance-down")
+							}
+							_, err := fmt.Fprintf(destination, "%d:%s", instance, path)
+							return err
+						}
+					})
+
+					It("warns, downloads from the remaining instances and fails", func() {
+						Expect(ui.WarnOutputs).To(ContainSubstrings(
+							[]string{"Could not download files from instance 0", "instance-down"},
+						))
+						Expect(readLocalFile("1", "heap.hprof")).To(Equal("1:/app/heap.hprof"))
+
+						Expect(err).To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring("Could not download files from 1 of 2 instances"))
+						Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"OK"}))
+					})
+				})
+			})
+
+			Context("when --all-instances is combined with -i", func() {
+				BeforeEach(func() {
+					args = []string{"app-name", "/app", "--download", localDir, "--all-instances", "-i", "0"}
+				})
+
+				It("fails with usage", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("Incorrect Usage"))
+				})
+			})
+		})
+
+		Context("when given a glob filter without --download", func() {
+			BeforeEach(func() {
+				args = []string{"app-name", "/app", "--include", "*.log"}
+			})
+
+			It("fails with usage", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Incorrect Usage"))
+				Expect(appFilesRepo.ListFilesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when given a malformed glob pattern", func() {
+			BeforeEach(func() {
+				args = []string{"app-name", "/app", "--download", "some-dir", "--include", "*.log", "--exclude", "[old"}
+			})
+
+			It("fails with usage", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Incorrect Usage. Invalid glob pattern [old"))
+				Expect(appFilesRepo.ListFilesCallCount()).To(Equal(0))
+			})
+		})
 	})
 })
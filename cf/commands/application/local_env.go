@@ -0,0 +1,334 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/applications"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/envfile"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type LocalEnv struct {
+	ui             terminal.UI
+	config         coreconfig.Reader
+	appRepo        applications.ApplicationRepository
+	serviceRepo    api.ServiceRepository
+	serviceKeyRepo api.ServiceKeyRepository
+	appReq         requirements.ApplicationRequirement
+
+	localFlags flags.FlagContext
+	command    []string
+
+	Exit func(status int)
+}
+
+type localServiceKey struct {
+	instance models.ServiceInstance
+	key      models.ServiceKey
+}
+
+func init() {
+	commandregistry.Register(&LocalEnv{})
+}
+
+func (cmd *LocalEnv) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:        "local-env",
+		Description: T("Run a command locally with the env variables of an app, including its bound services"),
+		Usage: []string{
+			T("CF_NAME local-env APP_NAME [--service-keys] -- COMMAND [ARGS...]"),
+			T("CF_NAME local-env APP_NAME [--service-keys] --write FILE [--format dotenv|json]"),
+		},
+		Examples: []string{
+			"CF_NAME local-env my-app -- npm start",
+			"CF_NAME local-env my-app --service-keys --write .env",
+		},
+		Flags: localEnvFlags(),
+		// the command after '--' can have flags of its own, so the flags are
+		// parsed by Requirements
+		SkipFlagParsing: true,
+	}
+}
+
+func localEnvFlags() map[string]flags.FlagSet {
+	fs := make(map[string]flags.FlagSet)
+	fs["service-keys"] = &flags.BoolFlag{Name: "service-keys", Usage: T("Create a service key for each bound service and use its credentials instead of the app's")}
+	fs["write"] = &flags.StringFlag{Name: "write", Usage: T("Write the env variables to FILE instead of running a command")}
+	fs["format"] = &flags.StringFlag{Name: "format", Value: envfile.FormatDotenv, Usage: T("Format of the file written with --write: dotenv or json")}
+	return fs
+}
+
+func (cmd *LocalEnv) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	args := fc.Args()
+	cmd.command = nil
+
+	for i, arg := range args {
+		if arg == "--" {
+			cmd.command = args[i+1:]
+			args = args[:i]
+			break
+		}
+	}
+
+	cmd.localFlags = flags.NewFlagContext(localEnvFlags())
+	err := cmd.localFlags.Parse(args...)
+	if err != nil {
+		cmd.ui.Failed(T("Incorrect Usage. ") + err.Error() + "\n\n" + commandregistry.Commands.CommandUsage("local-env"))
+	}
+
+	if len(cmd.localFlags.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires APP_NAME as argument") + "\n\n" + commandregistry.Commands.CommandUsage("local-env"))
+	}
+
+	writing := cmd.localFlags.IsSet("write")
+	if writing == (len(cmd.command) > 0) {
+		cmd.ui.Failed(T("Incorrect Usage:") + " " + T("Requires either a command after '--' or --write") + "\n\n" + commandregistry.Commands.CommandUsage("local-env"))
+	}
+
+	if format := cmd.localFlags.String("format"); format != envfile.FormatDotenv && format != envfile.FormatJSON {
+		cmd.ui.Failed(T("Incorrect Usage:") + " " + T("--format must be dotenv or json") + "\n\n" + commandregistry.Commands.CommandUsage("local-env"))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(cmd.localFlags.Args()[0])
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+
+	return reqs
+}
+
+func (cmd *LocalEnv) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
+	cmd.serviceKeyRepo = deps.RepoLocator.GetServiceKeyRepository()
+	cmd.Exit = os.Exit
+	return cmd
+}
+
+func (cmd *LocalEnv) Execute(fc flags.FlagContext) error {
+	app := cmd.appReq.GetApplication()
+
+	cmd.ui.Say(T("Getting env variables for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		map[string]interface{}{
+			"AppName":   terminal.EntityNameColor(app.Name),
+			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+
+	env, err := cmd.appRepo.ReadEnv(app.GUID)
+	if err != nil {
+		return err
+	}
+
+	vars := localEnvVars(env)
+
+	var serviceKeys []localServiceKey
+	if cmd.localFlags.Bool("service-keys") {
+		// an interrupt must not leave the keys behind; the local command gets
+		// it from the terminal as well, and the keys are deleted once it exits
+		interrupted := make(chan os.Signal, 1)
+		signal.Notify(interrupted, os.Interrupt)
+		defer signal.Stop(interrupted)
+
+		serviceKeys, err = cmd.useServiceKeys(app, vars, interrupted)
+		if err != nil {
+			cmd.deleteServiceKeys(serviceKeys)
+			return err
+		}
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	if cmd.localFlags.IsSet("write") {
+		return cmd.writeEnvFile(vars, serviceKeys)
+	}
+
+	exitStatus, err := cmd.runLocally(app, vars)
+	cmd.deleteServiceKeys(serviceKeys)
+	if err != nil {
+		return err
+	}
+
+	if exitStatus != 0 {
+		cmd.Exit(exitStatus)
+	}
+	return nil
+}
+
+// localEnvVars returns the variables that the app sees when it is running:
+// the running environment variable group, the user-provided variables, and
+// the VCAP_APPLICATION and VCAP_SERVICES provided by the platform.
+func localEnvVars(env *models.Environment) map[string]interface{} {
+	vars := map[string]interface{}{}
+
+	for name, value := range env.Running {
+		vars[name] = value
+	}
+	for name, value := range env.Environment {
+		vars[name] = value
+	}
+	if vcapApplication, ok := env.Application["VCAP_APPLICATION"]; ok {
+		vars["VCAP_APPLICATION"] = vcapApplication
+	}
+	if vcapServices, ok := env.System["VCAP_SERVICES"]; ok {
+		vars["VCAP_SERVICES"] = vcapServices
+	}
+
+	return vars
+}
+
+// useServiceKeys creates a service key for each service in VCAP_SERVICES and
+// replaces the credentials of the app's binding with the key's, so that the
+// app's own credentials are not used outside of the platform. User-provided
+// services cannot have keys, so their credentials are kept.
+func (cmd *LocalEnv) useServiceKeys(app models.Application, vars map[string]interface{}, interrupted <-chan os.Signal) ([]localServiceKey, error) {
+	serviceKeys := []localServiceKey{}
+
+	services, _ := vars["VCAP_SERVICES"].(map[string]interface{})
+	keyName := fmt.Sprintf("local-env-%s-%s", app.Name, time.Now().Format("20060102T150405"))
+
+	for label, bindings := range services {
+		if label == "user-provided" {
+			continue
+		}
+
+		bindings, _ := bindings.([]interface{})
+		for _, binding := range bindings {
+			binding, ok := binding.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			select {
+			case <-interrupted:
+				return serviceKeys, errors.New(T("Interrupted while creating service keys"))
+			default:
+			}
+
+			instanceName, _ := binding["name"].(string)
+			instance, err := cmd.serviceRepo.FindInstanceByName(instanceName)
+			if err != nil {
+				return serviceKeys, err
+			}
+
+			cmd.ui.Say(T("Creating service key {{.ServiceKeyName}} for service instance {{.ServiceInstanceName}}...",
+				map[string]interface{}{
+					"ServiceKeyName":      terminal.EntityNameColor(keyName),
+					"ServiceInstanceName": terminal.EntityNameColor(instance.Name)}))
+
+			err = cmd.serviceKeyRepo.CreateServiceKey(instance.GUID, keyName, nil)
+			if err != nil {
+				return serviceKeys, err
+			}
+
+			serviceKey, err := cmd.serviceKeyRepo.GetServiceKey(instance.GUID, keyName)
+			if err != nil {
+				return serviceKeys, err
+			}
+			serviceKeys = append(serviceKeys, localServiceKey{instance: instance, key: serviceKey})
+
+			binding["credentials"] = serviceKey.Credentials
+		}
+	}
+
+	return serviceKeys, nil
+}
+
+func (cmd *LocalEnv) deleteServiceKeys(serviceKeys []localServiceKey) {
+	for _, serviceKey := range serviceKeys {
+		cmd.ui.Say(T("Deleting service key {{.ServiceKeyName}} for service instance {{.ServiceInstanceName}}...",
+			map[string]interface{}{
+				"ServiceKeyName":      terminal.EntityNameColor(serviceKey.key.Fields.Name),
+				"ServiceInstanceName": terminal.EntityNameColor(serviceKey.instance.Name)}))
+
+		err := cmd.serviceKeyRepo.DeleteServiceKey(serviceKey.key.Fields.GUID)
+		if err != nil {
+			cmd.ui.Warn("%s", err.Error())
+		}
+	}
+}
+
+func (cmd *LocalEnv) writeEnvFile(vars map[string]interface{}, serviceKeys []localServiceKey) error {
+	path := cmd.localFlags.String("write")
+
+	contents, err := envfile.Marshal(vars, cmd.localFlags.String("format"))
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(path, contents, 0600)
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Say(T("Wrote {{.Count}} env variables to {{.Path}}",
+		map[string]interface{}{
+			"Count": len(vars),
+			"Path":  terminal.EntityNameColor(path)}))
+
+	for _, serviceKey := range serviceKeys {
+		cmd.ui.Say(T("TIP: Use '{{.Command}}' when you no longer need the credentials in the file",
+			map[string]interface{}{
+				"Command": terminal.CommandColor(fmt.Sprintf("%s delete-service-key %s %s", cf.Name, serviceKey.instance.Name, serviceKey.key.Fields.Name))}))
+	}
+
+	return nil
+}
+
+// runLocally runs the command and returns its exit status, which is
+// non-zero when the command fails.
+func (cmd *LocalEnv) runLocally(app models.Application, vars map[string]interface{}) (int, error) {
+	environ, err := envfile.Environ(vars)
+	if err != nil {
+		return 0, err
+	}
+
+	cmd.ui.Say(T("Running {{.Command}} with the env variables of app {{.AppName}}",
+		map[string]interface{}{
+			"Command": terminal.CommandColor(strings.Join(cmd.command, " ")),
+			"AppName": terminal.EntityNameColor(app.Name)}))
+	cmd.ui.Say("")
+
+	localCommand := exec.Command(cmd.command[0], cmd.command[1:]...)
+	localCommand.Env = append(os.Environ(), environ...)
+	localCommand.Stdin = os.Stdin
+	localCommand.Stdout = cmd.ui.Writer()
+	localCommand.Stderr = os.Stderr
+
+	err = localCommand.Run()
+	if exitError, ok := err.(*exec.ExitError); ok {
+		status := exitError.Sys().(syscall.WaitStatus)
+		if status.Signaled() {
+			return 128 + int(status.Signal()), nil
+		}
+		return status.ExitStatus(), nil
+	}
+	if err != nil {
+		return 0, errors.New(T("Error running {{.Command}}: {{.Err}}",
+			map[string]interface{}{"Command": cmd.command[0], "Err": err.Error()}))
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,284 @@
+package application_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/applications/applicationsfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands/application"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("local-env command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          coreconfig.Repository
+		appRepo             *applicationsfakes.FakeApplicationRepository
+		serviceRepo         *apifakes.FakeServiceRepository
+		serviceKeyRepo      *apifakes.FakeServiceKeyRepository
+		requirementsFactory *testreq.FakeReqFactory
+		deps                commandregistry.Dependency
+
+		tmpDir     string
+		exitStatus int
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceKeyRepository(serviceKeyRepo)
+		localEnv := commandregistry.Commands.FindCommand("local-env").SetDependency(deps, pluginCall).(*application.LocalEnv)
+		localEnv.Exit = func(status int) { exitStatus = status }
+		commandregistry.Commands.SetCommand(localEnv)
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		appRepo = new(applicationsfakes.FakeApplicationRepository)
+		serviceRepo = new(apifakes.FakeServiceRepository)
+		serviceKeyRepo = new(apifakes.FakeServiceKeyRepository)
+		exitStatus = 0
+
+		app := models.Application{}
+		app.Name = "my-app"
+		app.GUID = "my-app-guid"
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: app}
+
+		appRepo.ReadEnvReturns(&models.Environment{
+			Environment: map[string]interface{}{"GREETING": "hello"},
+			Running:     map[string]interface{}{"GREETING": "hi", "RUNNING_GROUP": "yes"},
+			Staging:     map[string]interface{}{"STAGING_GROUP": "yes"},
+			System: map[string]interface{}{
+				"VCAP_SERVICES": map[string]interface{}{
+					"p-mysql": []interface{}{
+						map[string]interface{}{
+							"name":        "my-db",
+							"credentials": map[string]interface{}{"username": "app-user"},
+						},
+					},
+				},
+			},
+			Application: map[string]interface{}{
+				"VCAP_APPLICATION": map[string]interface{}{"application_name": "my-app"},
+			},
+		}, nil)
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "local-env")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("local-env", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-app", "--", "true")).To(BeFalse())
+		})
+
+		It("fails with usage when not given an app name", func() {
+			runCommand("--", "true")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires APP_NAME as argument"},
+			))
+		})
+
+		It("fails with usage when given neither a command nor --write", func() {
+			Expect(runCommand("my-app")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires either a command after '--' or --write"},
+			))
+		})
+
+		It("fails with usage when given both a command and --write", func() {
+			Expect(runCommand("my-app", "--write", ".env", "--", "true")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires either a command after '--' or --write"},
+			))
+		})
+
+		It("fails with usage when given an unknown format", func() {
+			Expect(runCommand("my-app", "--write", ".env", "--format", "yaml")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--format must be dotenv or json"},
+			))
+		})
+	})
+
+	Context("when given --write", func() {
+		var envPath string
+
+		BeforeEach(func() {
+			envPath = filepath.Join(tmpDir, ".env")
+		})
+
+		It("writes the env variables that the app runs with", func() {
+			Expect(runCommand("my-app", "--write", envPath)).To(BeTrue())
+
+			Expect(appRepo.ReadEnvArgsForCall(0)).To(Equal("my-app-guid"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Getting env variables for app", "my-app", "my-org", "my-space", "my-user"},
+				[]string{"OK"},
+				[]string{"Wrote 4 env variables to", envPath},
+			))
+
+			contents, err := ioutil.ReadFile(envPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal(`GREETING=hello
+RUNNING_GROUP=yes
+VCAP_APPLICATION="{\"application_name\":\"my-app\"}"
+VCAP_SERVICES="{\"p-mysql\":[{\"credentials\":{\"username\":\"app-user\"},\"name\":\"my-db\"}]}"
+`))
+		})
+
+		It("writes the env variables in the json format", func() {
+			Expect(runCommand("my-app", "--write", envPath, "--format", "json")).To(BeTrue())
+
+			contents, err := ioutil.ReadFile(envPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring(`"RUNNING_GROUP": "yes"`))
+		})
+
+		Context("and --service-keys", func() {
+			BeforeEach(func() {
+				instance := models.ServiceInstance{}
+				instance.Name = "my-db"
+				instance.GUID = "my-db-guid"
+				serviceRepo.FindInstanceByNameReturns(instance, nil)
+
+				serviceKeyRepo.GetServiceKeyReturns(models.ServiceKey{
+					Fields:      models.ServiceKeyFields{Name: "local-env-my-app-key", GUID: "key-guid"},
+					Credentials: map[string]interface{}{"username": "key-user"},
+				}, nil)
+			})
+
+			It("uses the credentials of a new service key and keeps it", func() {
+				Expect(runCommand("my-app", "--service-keys", "--write", envPath)).To(BeTrue())
+
+				Expect(serviceRepo.FindInstanceByNameArgsForCall(0)).To(Equal("my-db"))
+				instanceGUID, keyName, _ := serviceKeyRepo.CreateServiceKeyArgsForCall(0)
+				Expect(instanceGUID).To(Equal("my-db-guid"))
+				Expect(keyName).To(HavePrefix("local-env-my-app-"))
+
+				contents, err := ioutil.ReadFile(envPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(ContainSubstring(`{\"username\":\"key-user\"}`))
+				Expect(string(contents)).NotTo(ContainSubstring("app-user"))
+
+				Expect(serviceKeyRepo.DeleteServiceKeyCallCount()).To(Equal(0))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Creating service key", "for service instance", "my-db"},
+					[]string{"TIP: Use 'cf delete-service-key my-db local-env-my-app-key'"},
+				))
+			})
+
+			It("keeps the credentials of user-provided services", func() {
+				appRepo.ReadEnvReturns(&models.Environment{
+					System: map[string]interface{}{
+						"VCAP_SERVICES": map[string]interface{}{
+							"user-provided": []interface{}{
+								map[string]interface{}{
+									"name":        "my-ups",
+									"credentials": map[string]interface{}{"username": "ups-user"},
+								},
+							},
+						},
+					},
+				}, nil)
+
+				Expect(runCommand("my-app", "--service-keys", "--write", envPath)).To(BeTrue())
+
+				Expect(serviceRepo.FindInstanceByNameCallCount()).To(Equal(0))
+				Expect(serviceKeyRepo.CreateServiceKeyCallCount()).To(Equal(0))
+				contents, err := ioutil.ReadFile(envPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(ContainSubstring(`{\"username\":\"ups-user\"}`))
+			})
+
+			It("does not write the file when creating a service key fails", func() {
+				serviceKeyRepo.CreateServiceKeyReturns(errors.New("not bindable"))
+
+				Expect(runCommand("my-app", "--service-keys", "--write", envPath)).To(BeFalse())
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"not bindable"},
+				))
+				Expect(serviceKeyRepo.DeleteServiceKeyCallCount()).To(Equal(0))
+				_, err := os.Stat(envPath)
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("when given a command", func() {
+		BeforeEach(func() {
+			if runtime.GOOS == "windows" {
+				Skip("uses sh to run the command")
+			}
+		})
+
+		It("runs it with the env variables of the app", func() {
+			outputPath := filepath.Join(tmpDir, "output")
+
+			Expect(runCommand("my-app", "--", "sh", "-c", `echo "$GREETING $VCAP_APPLICATION" > "$0"`, outputPath)).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Running", "sh -c", "with the env variables of app", "my-app"},
+			))
+
+			contents, err := ioutil.ReadFile(outputPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal(`hello {"application_name":"my-app"}` + "\n"))
+		})
+
+		It("deletes the service keys and exits with the status of the command", func() {
+			serviceKeyRepo.GetServiceKeyReturns(models.ServiceKey{
+				Fields: models.ServiceKeyFields{Name: "local-env-my-app-key", GUID: "key-guid"},
+			}, nil)
+
+			Expect(runCommand("my-app", "--service-keys", "--", "sh", "-c", "exit 3")).To(BeTrue())
+
+			Expect(serviceKeyRepo.DeleteServiceKeyCallCount()).To(Equal(1))
+			Expect(serviceKeyRepo.DeleteServiceKeyArgsForCall(0)).To(Equal("key-guid"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Deleting service key", "local-env-my-app-key"},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"FAILED"}))
+			Expect(exitStatus).To(Equal(3))
+		})
+
+		It("fails when the command cannot be run", func() {
+			Expect(runCommand("my-app", "--", filepath.Join(tmpDir, "missing"))).To(BeFalse())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Error running", "missing"},
+			))
+			Expect(exitStatus).To(Equal(0))
+		})
+	})
+})
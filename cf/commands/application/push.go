@@ -27,6 +27,8 @@ import (
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
+	v3models "github.com/cloudfoundry/cli/cf/v3/models"
+	"github.com/cloudfoundry/cli/cf/v3/repository"
 	"github.com/cloudfoundry/cli/words/generator"
 )
 
@@ -38,6 +40,7 @@ type Push struct {
 	appStopper    ApplicationStopper
 	serviceBinder service.ServiceBinder
 	appRepo       applications.ApplicationRepository
+	v3Repo        repository.Repository
 	domainRepo    api.DomainRepository
 	routeRepo     api.RouteRepository
 	serviceRepo   api.ServiceRepository
@@ -67,6 +70,7 @@ func (cmd *Push) MetaData() commandregistry.CommandMetadata {
 	fs["s"] = &flags.StringFlag{ShortName: "s", Usage: T("Stack to use (a stack is a pre-built file system, including an operating system, that can run apps)")}
 	fs["t"] = &flags.StringFlag{ShortName: "t", Usage: T("Maximum time (in seconds) for CLI to wait for application start, other server side timeouts may apply")}
 	fs["docker-image"] = &flags.StringFlag{Name: "docker-image", ShortName: "o", Usage: T("Docker-image to be used (e.g. user/docker-image-name)")}
+	fs["droplet"] = &flags.StringFlag{Name: "droplet", Usage: T("Path to a droplet downloaded with download-droplet, used instead of staging the app files")}
 	fs["health-check-type"] = &flags.StringFlag{Name: "health-check-type", ShortName: "u", Usage: T("Application health check type (e.g. 'port' or 'none')")}
 	fs["no-hostname"] = &flags.BoolFlag{Name: "no-hostname", Usage: T("Map the root domain to this app")}
 	fs["no-manifest"] = &flags.BoolFlag{Name: "no-manifest", Usage: T("Ignore manifest file")}
@@ -89,7 +93,8 @@ func (cmd *Push) MetaData() commandregistry.CommandMetadata {
 			fmt.Sprintf("[-c %s] ", T("COMMAND")),
 			fmt.Sprintf("[-d %s] ", T("DOMAIN")),
 			fmt.Sprintf("[-f %s] ", T("MANIFEST_PATH")),
-			fmt.Sprintf("[--docker-image %s]", T("DOCKER_IMAGE")),
+			fmt.Sprintf("[--docker-image %s] ", T("DOCKER_IMAGE")),
+			fmt.Sprintf("[--droplet %s]", T("DROPLET_PATH")),
 			"\n   ",
 			fmt.Sprintf("[-i %s] ", T("NUM_INSTANCES")),
 			fmt.Sprintf("[-k %s] ", T("DISK")),
@@ -163,6 +168,7 @@ func (cmd *Push) SetDependency(deps commandregistry.Dependency, pluginCall bool)
 	cmd.serviceBinder = appCommand.(service.ServiceBinder)
 
 	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.v3Repo = deps.RepoLocator.GetV3Repository()
 	cmd.domainRepo = deps.RepoLocator.GetDomainRepository()
 	cmd.routeRepo = deps.RepoLocator.GetRouteRepository()
 	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
@@ -177,6 +183,10 @@ func (cmd *Push) SetDependency(deps commandregistry.Dependency, pluginCall bool)
 }
 
 func (cmd *Push) Execute(c flags.FlagContext) error {
+	if c.String("droplet") != "" && (c.String("p") != "" || c.String("b") != "" || c.String("docker-image") != "") {
+		return errors.New(T("Incorrect Usage. The --droplet flag cannot be used with -b, -p or --docker-image."))
+	}
+
 	appsFromManifest, err := cmd.getAppParamsFromManifest(c)
 	if err != nil {
 		return err
@@ -192,6 +202,10 @@ func (cmd *Push) Execute(c flags.FlagContext) error {
 		return err
 	}
 
+	if c.String("droplet") != "" && len(appSet) > 1 {
+		return errors.New(T("Incorrect Usage. The --droplet flag cannot be used when pushing multiple apps from a manifest file."))
+	}
+
 	_, err = cmd.authRepo.RefreshAuthToken()
 	if err != nil {
 		return err
@@ -264,7 +278,12 @@ func (cmd *Push) Execute(c flags.FlagContext) error {
 			return err
 		}
 
-		if c.String("docker-image") == "" {
+		if c.String("droplet") != "" {
+			err = cmd.uploadDroplet(app, c.String("droplet"))
+			if err != nil {
+				return err
+			}
+		} else if c.String("docker-image") == "" {
 			err = cmd.actor.ProcessPath(*appParams.Path, cmd.processPathCallback(*appParams.Path, app))
 			if err != nil {
 				return errors.New(
@@ -283,6 +302,21 @@ func (cmd *Push) Execute(c flags.FlagContext) error {
 			}
 		}
 
+		// the processes are configured before the app is started, so that
+		// their instances start with the new configuration
+		var unstagedProcesses []models.ProcessParams
+		if appParams.Processes != nil {
+			unstagedProcesses, err = cmd.updateProcesses(app, *appParams.Processes)
+			if err != nil {
+				return err
+			}
+
+			if len(unstagedProcesses) > 0 && c.Bool("no-start") {
+				return errors.New(T("Process type {{.ProcessType}} of app {{.AppName}} cannot be configured with --no-start, as it only exists once the app's Procfile has been read during staging. Push the app without --no-start to configure it.",
+					map[string]interface{}{"ProcessType": unstagedProcesses[0].Type, "AppName": app.Name}))
+			}
+		}
+
 		err = cmd.restart(app, appParams, c)
 		if err != nil {
 			return errors.New(
@@ -292,10 +326,94 @@ func (cmd *Push) Execute(c flags.FlagContext) error {
 					}),
 			)
 		}
+
+		if len(unstagedProcesses) > 0 {
+			err = cmd.updateStagedProcesses(app, appParams, unstagedProcesses, c)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+// updateStagedProcesses configures the process types that were only created
+// when the started app was staged, and restarts the app for the configuration
+// to take effect.
+func (cmd *Push) updateStagedProcesses(app models.Application, appParams models.AppParams, processes []models.ProcessParams, c flags.FlagContext) error {
+	missingProcesses, err := cmd.updateProcesses(app, processes)
+	if err != nil {
+		return err
+	}
+
+	if len(missingProcesses) > 0 {
+		return errors.New(T("Process type {{.ProcessType}} not found for app {{.AppName}}. Make sure it is declared in the app's Procfile.",
+			map[string]interface{}{"ProcessType": missingProcesses[0].Type, "AppName": app.Name}))
+	}
+
+	app.State = "started"
+	err = cmd.restart(app, appParams, c)
+	if err != nil {
+		return errors.New(
+			T("Error restarting application: {{.Error}}",
+				map[string]interface{}{
+					"Error": err.Error(),
+				}),
+		)
+	}
+
+	return nil
+}
+
+// updateProcesses configures each process type of the app and returns those
+// that do not exist yet. Process types other than web only exist once the
+// app's Procfile has been read during staging.
+func (cmd *Push) updateProcesses(app models.Application, processes []models.ProcessParams) ([]models.ProcessParams, error) {
+	missingProcesses := []models.ProcessParams{}
+	for _, processParams := range processes {
+		process, err := findProcess(cmd.v3Repo, app.GUID, processParams.Type)
+		switch err.(type) {
+		case nil:
+		case *errors.ModelNotFoundError:
+			missingProcesses = append(missingProcesses, processParams)
+			continue
+		default:
+			return nil, err
+		}
+
+		cmd.ui.Say(T("Updating process {{.ProcessType}} of app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+			map[string]interface{}{
+				"ProcessType": terminal.EntityNameColor(process.Type),
+				"AppName":     terminal.EntityNameColor(app.Name),
+				"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"Username":    terminal.EntityNameColor(cmd.config.Username())}))
+
+		if processParams.HasUpdate() {
+			update := v3models.V3ProcessUpdate{Command: processParams.Command}
+			if processParams.HealthCheckType != nil {
+				update.HealthCheck = &v3models.V3HealthCheck{Type: *processParams.HealthCheckType}
+			}
+
+			err = cmd.v3Repo.UpdateProcess(processPath(process, ""), update)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if processParams.HasScale() {
+			err = cmd.v3Repo.ScaleProcess(processPath(process, "scale"), processScale(processParams))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cmd.ui.Ok()
+		cmd.ui.Say("")
+	}
+	return missingProcesses, nil
+}
+
 func (cmd *Push) processPathCallback(path string, app models.Application) func(string) {
 	return func(appDir string) {
 		localFiles, err := cmd.appfiles.AppFilesInDir(appDir)
@@ -331,6 +449,20 @@ func (cmd *Push) processPathCallback(path string, app models.Application) func(s
 	}
 }
 
+func (cmd *Push) uploadDroplet(app models.Application, dropletPath string) error {
+	cmd.ui.Say(T("Uploading droplet for {{.AppName}}...",
+		map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+
+	err := cmd.actor.UploadDroplet(app.GUID, dropletPath)
+	if err != nil {
+		return errors.New(T("Error uploading droplet.\n{{.APIErr}}",
+			map[string]interface{}{"APIErr": err.Error()}))
+	}
+
+	cmd.ui.Ok()
+	return nil
+}
+
 func (cmd *Push) updateRoutes(routeActor actors.RouteActor, app models.Application, appParams models.AppParams) error {
 	defaultRouteAcceptable := len(app.Routes) == 0
 	routeDefined := appParams.Domains != nil || !appParams.IsHostEmpty() || appParams.NoHostname
@@ -21,6 +21,8 @@ import (
 	"github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/manifest"
 	"github.com/cloudfoundry/cli/cf/models"
+	v3models "github.com/cloudfoundry/cli/cf/v3/models"
+	"github.com/cloudfoundry/cli/cf/v3/repository/repositoryfakes"
 	"github.com/cloudfoundry/cli/generic"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
 	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
@@ -47,6 +49,7 @@ var _ = Describe("Push Command", func() {
 		stopper                    *applicationfakes.FakeApplicationStopper
 		serviceBinder              *servicefakes.OldFakeAppBinder
 		appRepo                    *applicationsfakes.FakeApplicationRepository
+		v3Repo                     *repositoryfakes.FakeRepository
 		domainRepo                 *apifakes.FakeDomainRepository
 		routeRepo                  *apifakes.FakeRouteRepository
 		stackRepo                  *stacksfakes.FakeStackRepository
@@ -68,6 +71,7 @@ var _ = Describe("Push Command", func() {
 		deps.Config = configRepo
 		deps.ManifestRepo = manifestRepo
 		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetV3Repository(v3Repo)
 		deps.RepoLocator = deps.RepoLocator.SetDomainRepository(domainRepo)
 		deps.RepoLocator = deps.RepoLocator.SetRouteRepository(routeRepo)
 		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
@@ -105,6 +109,7 @@ var _ = Describe("Push Command", func() {
 		stopper.MetaDataReturns(commandregistry.CommandMetadata{Name: "stop"})
 
 		appRepo = new(applicationsfakes.FakeApplicationRepository)
+		v3Repo = new(repositoryfakes.FakeRepository)
 
 		domainRepo = new(apifakes.FakeDomainRepository)
 		sharedDomain := maker.NewSharedDomainFields(maker.Overrides{"name": "foo.cf-app.com", "guid": "foo-domain-guid"})
@@ -1456,6 +1461,202 @@ var _ = Describe("Push Command", func() {
 
 	})
 
+	Describe("process types", func() {
+		BeforeEach(func() {
+			appRepo.ReadReturns(models.Application{}, errors.NewModelNotFoundError("App", "the-app"))
+			appRepo.CreateStub = func(params models.AppParams) (models.Application, error) {
+				a := models.Application{}
+				a.Name = *params.Name
+				a.GUID = *params.Name + "-guid"
+
+				return a, nil
+			}
+
+			manifestRepo.ReadManifestReturns.Manifest = manifestWithProcesses()
+		})
+
+		Context("when the process types exist", func() {
+			BeforeEach(func() {
+				v3Repo.GetProcessesReturns([]v3models.V3Process{
+					{GUID: "web-guid", Type: "web"},
+					{GUID: "worker-guid", Type: "worker"},
+				}, nil)
+			})
+
+			It("updates and scales each process type from the manifest", func() {
+				callPush()
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"FAILED"}))
+
+				Expect(v3Repo.GetProcessesCallCount()).To(Equal(2))
+				Expect(v3Repo.GetProcessesArgsForCall(0)).To(Equal("/v3/apps/app1-guid/processes"))
+
+				Expect(v3Repo.UpdateProcessCallCount()).To(Equal(1))
+				path, update := v3Repo.UpdateProcessArgsForCall(0)
+				Expect(path).To(Equal("/v3/processes/worker-guid"))
+				Expect(*update.Command).To(Equal("bundle exec rake work"))
+
+				Expect(v3Repo.ScaleProcessCallCount()).To(Equal(2))
+				path, scale := v3Repo.ScaleProcessArgsForCall(0)
+				Expect(path).To(Equal("/v3/processes/web-guid/scale"))
+				Expect(*scale.Instances).To(Equal(2))
+
+				path, scale = v3Repo.ScaleProcessArgsForCall(1)
+				Expect(path).To(Equal("/v3/processes/worker-guid/scale"))
+				Expect(*scale.Instances).To(Equal(3))
+				Expect(*scale.MemoryInMB).To(Equal(int64(512)))
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Updating process", "web", "app1", "my-org", "my-space", "my-user"},
+					[]string{"OK"},
+					[]string{"Updating process", "worker", "app1", "my-org", "my-space", "my-user"},
+					[]string{"OK"},
+				))
+			})
+
+			It("configures the processes before the app is started", func() {
+				startCallCounts := []int{}
+				v3Repo.UpdateProcessStub = func(string, v3models.V3ProcessUpdate) error {
+					startCallCounts = append(startCallCounts, starter.ApplicationStartCallCount())
+					return nil
+				}
+				v3Repo.ScaleProcessStub = func(string, v3models.V3ProcessScale) error {
+					startCallCounts = append(startCallCounts, starter.ApplicationStartCallCount())
+					return nil
+				}
+
+				callPush()
+
+				Expect(startCallCounts).To(Equal([]int{0, 0, 0}))
+				Expect(starter.ApplicationStartCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when a process type only exists once the app has been staged", func() {
+			BeforeEach(func() {
+				v3Repo.GetProcessesStub = func(string) ([]v3models.V3Process, error) {
+					if starter.ApplicationStartCallCount() == 0 {
+						return []v3models.V3Process{{GUID: "web-guid", Type: "web"}}, nil
+					}
+					return []v3models.V3Process{
+						{GUID: "web-guid", Type: "web"},
+						{GUID: "worker-guid", Type: "worker"},
+					}, nil
+				}
+			})
+
+			It("configures it after the app has been started and restarts the app", func() {
+				callPush()
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"FAILED"}))
+
+				Expect(v3Repo.ScaleProcessCallCount()).To(Equal(2))
+				path, _ := v3Repo.ScaleProcessArgsForCall(0)
+				Expect(path).To(Equal("/v3/processes/web-guid/scale"))
+				path, _ = v3Repo.ScaleProcessArgsForCall(1)
+				Expect(path).To(Equal("/v3/processes/worker-guid/scale"))
+
+				Expect(v3Repo.UpdateProcessCallCount()).To(Equal(1))
+				path, _ = v3Repo.UpdateProcessArgsForCall(0)
+				Expect(path).To(Equal("/v3/processes/worker-guid"))
+
+				Expect(starter.ApplicationStartCallCount()).To(Equal(2))
+				Expect(stopper.ApplicationStopCallCount()).To(Equal(2))
+				app, _, _ := stopper.ApplicationStopArgsForCall(1)
+				Expect(app.GUID).To(Equal("app1-guid"))
+			})
+
+			It("fails without starting the app when it is not to be started", func() {
+				callPush("--no-start")
+
+				Expect(starter.ApplicationStartCallCount()).To(Equal(0))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Process type worker of app app1 cannot be configured with --no-start"},
+				))
+			})
+		})
+
+		Context("when a process type does not exist after staging", func() {
+			BeforeEach(func() {
+				v3Repo.GetProcessesReturns([]v3models.V3Process{{GUID: "web-guid", Type: "web"}}, nil)
+			})
+
+			It("fails with an error", func() {
+				callPush()
+
+				Expect(starter.ApplicationStartCallCount()).To(Equal(1))
+				Expect(v3Repo.UpdateProcessCallCount()).To(Equal(0))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Process type worker not found for app app1", "Procfile"},
+				))
+			})
+		})
+	})
+
+	Describe("pushing a droplet", func() {
+		BeforeEach(func() {
+			appRepo.ReadReturns(models.Application{}, errors.NewModelNotFoundError("App", "the-app"))
+			appRepo.CreateStub = func(params models.AppParams) (models.Application, error) {
+				a := models.Application{}
+				a.Name = *params.Name
+				a.GUID = *params.Name + "-guid"
+				a.State = "stopped"
+
+				return a, nil
+			}
+		})
+
+		It("uploads the droplet instead of the app files", func() {
+			callPush("my-app", "--droplet", "droplet.tgz")
+
+			Expect(actor.ProcessPathCallCount()).To(Equal(0))
+			Expect(actor.UploadAppCallCount()).To(Equal(0))
+			Expect(actor.UploadDropletCallCount()).To(Equal(1))
+			appGUID, dropletPath := actor.UploadDropletArgsForCall(0)
+			Expect(appGUID).To(Equal("my-app-guid"))
+			Expect(dropletPath).To(Equal("droplet.tgz"))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Uploading droplet for", "my-app"},
+				[]string{"OK"},
+			))
+			Expect(starter.ApplicationStartCallCount()).To(Equal(1))
+		})
+
+		It("fails when the droplet cannot be uploaded", func() {
+			actor.UploadDropletReturns(errors.New("droplet-upload-error"))
+
+			callPush("my-app", "--droplet", "droplet.tgz")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Error uploading droplet"},
+				[]string{"droplet-upload-error"},
+			))
+			Expect(starter.ApplicationStartCallCount()).To(Equal(0))
+		})
+
+		It("fails with usage when combined with a path", func() {
+			callPush("my-app", "--droplet", "droplet.tgz", "-p", "/some/path")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Incorrect Usage", "--droplet"},
+			))
+			Expect(appRepo.CreateCallCount()).To(Equal(0))
+		})
+
+		It("fails with usage when combined with a docker image", func() {
+			callPush("my-app", "--droplet", "droplet.tgz", "--docker-image", "sample/image")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Incorrect Usage", "--droplet"},
+			))
+			Expect(appRepo.CreateCallCount()).To(Equal(0))
+		})
+	})
+
 	Describe("checking for bad flags", func() {
 		It("fails when a non-numeric start timeout is given", func() {
 			appRepo.ReadReturns(models.Application{}, errors.NewModelNotFoundError("App", "the-app"))
@@ -1666,3 +1867,27 @@ func manifestWithServicesAndEnv() *manifest.Manifest {
 		}),
 	}
 }
+
+func manifestWithProcesses() *manifest.Manifest {
+	return &manifest.Manifest{
+		Data: generic.NewMap(map[interface{}]interface{}{
+			"applications": []interface{}{
+				generic.NewMap(map[interface{}]interface{}{
+					"name": "app1",
+					"processes": []interface{}{
+						generic.NewMap(map[interface{}]interface{}{
+							"type":      "web",
+							"instances": 2,
+						}),
+						generic.NewMap(map[interface{}]interface{}{
+							"type":      "worker",
+							"command":   "bundle exec rake work",
+							"instances": 3,
+							"memory":    "512M",
+						}),
+					},
+				}),
+			},
+		}),
+	}
+}
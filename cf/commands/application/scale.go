@@ -1,16 +1,19 @@
 package application
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/formatters"
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
+	v3models "github.com/cloudfoundry/cli/cf/v3/models"
+	"github.com/cloudfoundry/cli/cf/v3/repository"
 	"github.com/cloudfoundry/cli/flags"
 )
 
@@ -20,6 +23,7 @@ type Scale struct {
 	restarter ApplicationRestarter
 	appReq    requirements.ApplicationRequirement
 	appRepo   applications.ApplicationRepository
+	v3Repo    repository.Repository
 }
 
 func init() {
@@ -32,12 +36,13 @@ func (cmd *Scale) MetaData() commandregistry.CommandMetadata {
 	fs["k"] = &flags.StringFlag{ShortName: "k", Usage: T("Disk limit (e.g. 256M, 1024M, 1G)")}
 	fs["m"] = &flags.StringFlag{ShortName: "m", Usage: T("Memory limit (e.g. 256M, 1024M, 1G)")}
 	fs["f"] = &flags.BoolFlag{ShortName: "f", Usage: T("Force restart of app without prompt")}
+	fs["process"] = &flags.StringFlag{Name: "process", Usage: T("App process type to scale (e.g. web, worker)")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "scale",
 		Description: T("Change or view the instance count, disk space limit, and memory limit for an app"),
 		Usage: []string{
-			T("CF_NAME scale APP_NAME [-i INSTANCES] [-k DISK] [-m MEMORY] [-f]"),
+			T("CF_NAME scale APP_NAME [--process PROCESS_TYPE] [-i INSTANCES] [-k DISK] [-m MEMORY] [-f]"),
 		},
 		Flags: fs,
 	}
@@ -63,6 +68,7 @@ func (cmd *Scale) SetDependency(deps commandregistry.Dependency, pluginCall bool
 	cmd.ui = deps.UI
 	cmd.config = deps.Config
 	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.v3Repo = deps.RepoLocator.GetV3Repository()
 
 	//get command from registry for dependency
 	commandDep := commandregistry.Commands.FindCommand("restart")
@@ -76,6 +82,10 @@ var bytesInAMegabyte int64 = 1024 * 1024
 
 func (cmd *Scale) Execute(c flags.FlagContext) error {
 	currentApp := cmd.appReq.GetApplication()
+	if c.String("process") != "" {
+		return cmd.scaleProcess(c, currentApp)
+	}
+
 	if !anyFlagsSet(c) {
 		cmd.ui.Say(T("Showing current scale of app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
 			map[string]interface{}{
@@ -156,6 +166,94 @@ func (cmd *Scale) Execute(c flags.FlagContext) error {
 	return nil
 }
 
+func (cmd *Scale) scaleProcess(c flags.FlagContext, currentApp models.Application) error {
+	process, err := findProcess(cmd.v3Repo, currentApp.GUID, c.String("process"))
+	if err != nil {
+		return err
+	}
+
+	if !anyFlagsSet(c) {
+		cmd.ui.Say(T("Showing current scale of process {{.ProcessType}} of app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+			map[string]interface{}{
+				"ProcessType": terminal.EntityNameColor(process.Type),
+				"AppName":     terminal.EntityNameColor(currentApp.Name),
+				"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"CurrentUser": terminal.EntityNameColor(cmd.config.Username()),
+			}))
+		cmd.ui.Ok()
+		cmd.ui.Say("")
+
+		cmd.ui.Say("%s %s", terminal.HeaderColor(T("memory:")), formatters.ByteSize(process.MemoryInMB*bytesInAMegabyte))
+		cmd.ui.Say("%s %s", terminal.HeaderColor(T("disk:")), formatters.ByteSize(process.DiskInMB*bytesInAMegabyte))
+		cmd.ui.Say("%s %d", terminal.HeaderColor(T("instances:")), process.Instances)
+
+		return nil
+	}
+
+	params := models.ProcessParams{Type: process.Type}
+	shouldRestart := false
+
+	if c.String("m") != "" {
+		memory, err := formatters.ToMegabytes(c.String("m"))
+		if err != nil {
+			return errors.New(T("Invalid memory limit: {{.Memory}}\n{{.ErrorDescription}}",
+				map[string]interface{}{
+					"Memory":           c.String("m"),
+					"ErrorDescription": err,
+				}))
+		}
+		params.Memory = &memory
+		shouldRestart = true
+	}
+
+	if c.String("k") != "" {
+		diskQuota, err := formatters.ToMegabytes(c.String("k"))
+		if err != nil {
+			return errors.New(T("Invalid disk quota: {{.DiskQuota}}\n{{.ErrorDescription}}",
+				map[string]interface{}{
+					"DiskQuota":        c.String("k"),
+					"ErrorDescription": err,
+				}))
+		}
+		params.DiskQuota = &diskQuota
+		shouldRestart = true
+	}
+
+	if c.IsSet("i") {
+		instances := c.Int("i")
+		params.InstanceCount = &instances
+	}
+
+	if shouldRestart && !cmd.confirmRestart(c, currentApp.Name) {
+		return nil
+	}
+
+	cmd.ui.Say(T("Scaling process {{.ProcessType}} of app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"ProcessType": terminal.EntityNameColor(process.Type),
+			"AppName":     terminal.EntityNameColor(currentApp.Name),
+			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"CurrentUser": terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	err = cmd.v3Repo.ScaleProcess(processPath(process, "scale"), processScale(params))
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Ok()
+
+	if shouldRestart {
+		err = cmd.restarter.ApplicationRestart(currentApp, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (cmd *Scale) confirmRestart(context flags.FlagContext, appName string) bool {
 	if context.Bool("f") {
 		return true
@@ -170,3 +268,34 @@ func (cmd *Scale) confirmRestart(context flags.FlagContext, appName string) bool
 func anyFlagsSet(context flags.FlagContext) bool {
 	return context.IsSet("m") || context.IsSet("k") || context.IsSet("i")
 }
+
+// findProcess looks the process type up among the v3 processes of the app.
+func findProcess(v3Repo repository.Repository, appGUID string, processType string) (v3models.V3Process, error) {
+	processes, err := v3Repo.GetProcesses(fmt.Sprintf("/v3/apps/%s/processes", appGUID))
+	if err != nil {
+		return v3models.V3Process{}, err
+	}
+
+	for _, process := range processes {
+		if process.Type == processType {
+			return process, nil
+		}
+	}
+	return v3models.V3Process{}, errors.NewModelNotFoundError("Process", processType)
+}
+
+func processPath(process v3models.V3Process, resource string) string {
+	path := "/v3/processes/" + process.GUID
+	if resource != "" {
+		path += "/" + resource
+	}
+	return path
+}
+
+func processScale(params models.ProcessParams) v3models.V3ProcessScale {
+	return v3models.V3ProcessScale{
+		Instances:  params.InstanceCount,
+		MemoryInMB: params.Memory,
+		DiskInMB:   params.DiskQuota,
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/cloudfoundry/cli/cf/commands/application/applicationfakes"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/models"
+	v3models "github.com/cloudfoundry/cli/cf/v3/models"
+	"github.com/cloudfoundry/cli/cf/v3/repository/repositoryfakes"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
 	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
 	"github.com/cloudfoundry/cli/testhelpers/maker"
@@ -22,6 +24,7 @@ var _ = Describe("scale command", func() {
 		requirementsFactory *testreq.FakeReqFactory
 		restarter           *applicationfakes.FakeApplicationRestarter
 		appRepo             *applicationsfakes.FakeApplicationRepository
+		v3Repo              *repositoryfakes.FakeRepository
 		ui                  *testterm.FakeUI
 		config              coreconfig.Repository
 		app                 models.Application
@@ -32,6 +35,7 @@ var _ = Describe("scale command", func() {
 	updateCommandDependency := func(pluginCall bool) {
 		deps.UI = ui
 		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetV3Repository(v3Repo)
 		deps.Config = config
 
 		//inject fake 'command dependency' into registry
@@ -54,6 +58,7 @@ var _ = Describe("scale command", func() {
 		restarter.MetaDataReturns(commandregistry.CommandMetadata{Name: "restart"})
 
 		appRepo = new(applicationsfakes.FakeApplicationRepository)
+		v3Repo = new(repositoryfakes.FakeRepository)
 		ui = new(testterm.FakeUI)
 		config = testconfig.NewRepositoryWithDefaults()
 	})
@@ -196,4 +201,69 @@ var _ = Describe("scale command", func() {
 			})
 		})
 	})
+
+	Describe("scaling a process of an app", func() {
+		BeforeEach(func() {
+			app = maker.NewApp(maker.Overrides{"name": "my-app", "guid": "my-app-guid"})
+			requirementsFactory.Application = app
+
+			v3Repo.GetProcessesReturns([]v3models.V3Process{
+				{GUID: "web-guid", Type: "web", Instances: 1, MemoryInMB: 256, DiskInMB: 1024},
+				{GUID: "worker-guid", Type: "worker", Instances: 3, MemoryInMB: 512, DiskInMB: 1024},
+			}, nil)
+		})
+
+		Context("when no flags are specified", func() {
+			It("prints a description of the process's limits", func() {
+				testcmd.RunCLICommand("scale", []string{"my-app", "--process", "worker"}, requirementsFactory, updateCommandDependency, false, ui)
+
+				Expect(v3Repo.GetProcessesArgsForCall(0)).To(Equal("/v3/apps/my-app-guid/processes"))
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Showing", "worker", "my-app", "my-org", "my-space", "my-user"},
+					[]string{"OK"},
+					[]string{"memory", "512M"},
+					[]string{"disk", "1G"},
+					[]string{"instances", "3"},
+				))
+				Expect(v3Repo.ScaleProcessCallCount()).To(Equal(0))
+			})
+		})
+
+		It("scales the instance count of the process without restarting the app", func() {
+			testcmd.RunCLICommand("scale", []string{"my-app", "--process", "worker", "-i", "5"}, requirementsFactory, updateCommandDependency, false, ui)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Scaling process", "worker", "my-app", "my-org", "my-space", "my-user"},
+				[]string{"OK"},
+			))
+
+			path, scale := v3Repo.ScaleProcessArgsForCall(0)
+			Expect(path).To(Equal("/v3/processes/worker-guid/scale"))
+			Expect(*scale.Instances).To(Equal(5))
+			Expect(scale.MemoryInMB).To(BeNil())
+			Expect(scale.DiskInMB).To(BeNil())
+
+			Expect(appRepo.UpdateCallCount()).To(Equal(0))
+			Expect(restarter.ApplicationRestartCallCount()).To(Equal(0))
+		})
+
+		It("restarts the app when the memory limit of the process changes", func() {
+			testcmd.RunCLICommand("scale", []string{"my-app", "--process", "worker", "-m", "1G", "-f"}, requirementsFactory, updateCommandDependency, false, ui)
+
+			_, scale := v3Repo.ScaleProcessArgsForCall(0)
+			Expect(*scale.MemoryInMB).To(Equal(int64(1024)))
+			Expect(restarter.ApplicationRestartCallCount()).To(Equal(1))
+		})
+
+		It("fails when the process type cannot be found", func() {
+			testcmd.RunCLICommand("scale", []string{"my-app", "--process", "clock", "-i", "1"}, requirementsFactory, updateCommandDependency, false, ui)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Process", "clock", "not found"},
+			))
+			Expect(v3Repo.ScaleProcessCallCount()).To(Equal(0))
+		})
+	})
 })
@@ -0,0 +1,198 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	sshCmd "github.com/cloudfoundry/cli/cf/ssh"
+	"github.com/cloudfoundry/cli/cf/ssh/options"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type SCP struct {
+	ui            terminal.UI
+	config        coreconfig.Reader
+	gateway       net.Gateway
+	appReq        requirements.ApplicationRequirement
+	sshCodeGetter commands.SSHCodeGetter
+	secureShell   sshCmd.SecureShell
+
+	sources    []string
+	target     string
+	remotePath string
+	upload     bool
+}
+
+func init() {
+	commandregistry.Register(&SCP{})
+}
+
+func (cmd *SCP) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["app-instance-index"] = &flags.IntFlag{Name: "app-instance-index", ShortName: "i", Usage: T("Application instance index")}
+	fs["r"] = &flags.BoolFlag{ShortName: "r", Usage: T("Recursively copy entire directories")}
+	fs["skip-host-validation"] = &flags.BoolFlag{Name: "skip-host-validation", ShortName: "k", Usage: T("Skip host key validation")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "scp",
+		Description: T("Copy files to or from an application container instance over SSH"),
+		Usage: []string{
+			T("CF_NAME scp [-i app-instance-index] [-r] [--skip-host-validation] SOURCE... TARGET\n\n   Either the sources or the target must be given as APP_NAME:PATH."),
+		},
+		Examples: []string{
+			"CF_NAME scp my-app:/home/vcap/logs/app.log ./app.log",
+			"CF_NAME scp -i 1 -r my-app:/home/vcap/app/dumps ./dumps",
+			"CF_NAME scp ./config.yml ./certs.pem my-app:/tmp",
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *SCP) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) < 2 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires SOURCE and TARGET as arguments") + "\n\n" + commandregistry.Commands.CommandUsage("scp"))
+	}
+
+	if fc.IsSet("i") && fc.Int("i") < 0 {
+		cmd.ui.Failed(fmt.Sprintf(T("Incorrect Usage:")+" %s\n\n%s", T("Value for flag 'app-instance-index' cannot be negative"), commandregistry.Commands.CommandUsage("scp")))
+	}
+
+	appName, err := cmd.parseArgs(fc.Args())
+	if err != nil {
+		cmd.ui.Failed(fmt.Sprintf(T("Incorrect Usage:")+" %s\n\n%s", err.Error(), commandregistry.Commands.CommandUsage("scp")))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(appName)
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+
+	return reqs
+}
+
+func (cmd *SCP) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.gateway = deps.Gateways["cloud-controller"]
+
+	if deps.WildcardDependency != nil {
+		cmd.secureShell = deps.WildcardDependency.(sshCmd.SecureShell)
+	}
+
+	//get ssh-code for dependency
+	sshCodeGetter := commandregistry.Commands.FindCommand("ssh-code")
+	sshCodeGetter = sshCodeGetter.SetDependency(deps, false)
+	cmd.sshCodeGetter = sshCodeGetter.(commands.SSHCodeGetter)
+
+	return cmd
+}
+
+func (cmd *SCP) Execute(fc flags.FlagContext) error {
+	err := checkRecordingNotRequired(cmd.config, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name, cf.Name+" scp")
+	if err != nil {
+		return err
+	}
+
+	app := cmd.appReq.GetApplication()
+	info, err := getSSHEndpointInfo(cmd.gateway, cmd.config)
+	if err != nil {
+		return errors.New(T("Error getting SSH info:") + err.Error())
+	}
+
+	knownHosts, err := newKnownHosts()
+	if err != nil {
+		return err
+	}
+
+	secureShell, err := newSecureShell(cmd.sshCodeGetter, cmd.secureShell, knownHosts, app, info)
+	if err != nil {
+		return err
+	}
+
+	opts := &options.SSHOptions{
+		AppName:            app.Name,
+		Index:              uint(fc.Int("i")),
+		SkipHostValidation: fc.Bool("k"),
+	}
+
+	err = secureShell.Connect(opts)
+	if err != nil {
+		return errors.New(T("Error opening SSH connection: ") + err.Error())
+	}
+	defer secureShell.Close()
+
+	if cmd.upload {
+		err = secureShell.CopyToRemote(cmd.sources, cmd.remotePath, fc.Bool("r"))
+	} else {
+		err = secureShell.CopyFromRemote(cmd.remotePath, cmd.target, fc.Bool("r"))
+	}
+	if err != nil {
+		return errors.New(T("Error copying files: ") + err.Error())
+	}
+
+	return nil
+}
+
+// parseArgs splits the arguments into local paths and the single remote
+// APP_NAME:PATH, which must either be the target or the only source.
+func (cmd *SCP) parseArgs(args []string) (string, error) {
+	cmd.sources = args[:len(args)-1]
+	cmd.target = args[len(args)-1]
+	cmd.upload = false
+
+	if appName, remotePath, ok := splitRemotePath(cmd.target); ok {
+		for _, source := range cmd.sources {
+			if _, _, isRemote := splitRemotePath(source); isRemote {
+				return "", errors.New(T("Copying between two application containers is not supported"))
+			}
+		}
+
+		cmd.upload = true
+		cmd.remotePath = remotePath
+		return appName, nil
+	}
+
+	if len(cmd.sources) != 1 {
+		return "", errors.New(T("Only a single remote source can be copied from an application container"))
+	}
+
+	appName, remotePath, ok := splitRemotePath(cmd.sources[0])
+	if !ok {
+		return "", errors.New(T("Either the sources or the target must be given as APP_NAME:PATH"))
+	}
+
+	cmd.remotePath = remotePath
+	return appName, nil
+}
+
+func splitRemotePath(arg string) (string, string, bool) {
+	parts := strings.SplitN(arg, ":", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+
+	// single letters are Windows drive letters and anything containing a
+	// path separator is a local path
+	if len(parts[0]) < 2 || strings.ContainsAny(parts[0], `/\`) {
+		return "", "", false
+	}
+
+	remotePath := parts[1]
+	if remotePath == "" {
+		remotePath = "."
+	}
+
+	return parts[0], remotePath, true
+}
@@ -0,0 +1,231 @@
+package application_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands/commandsfakes"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/ssh/sshfakes"
+	"github.com/cloudfoundry/cli/testhelpers/cloudcontrollergateway"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testnet "github.com/cloudfoundry/cli/testhelpers/net"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("scp command", func() {
+	var (
+		ui *testterm.FakeUI
+
+		sshCodeGetter         *commandsfakes.FakeSSHCodeGetter
+		originalSSHCodeGetter commandregistry.Command
+
+		requirementsFactory *testreq.FakeReqFactory
+		configRepo          coreconfig.Repository
+		deps                commandregistry.Dependency
+
+		fakeSecureShell *sshfakes.FakeSecureShell
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{}
+		deps.Gateways = make(map[string]net.Gateway)
+		deps.WildcardDependency = nil
+
+		//save original command and restore later
+		originalSSHCodeGetter = commandregistry.Commands.FindCommand("ssh-code")
+
+		sshCodeGetter = new(commandsfakes.FakeSSHCodeGetter)
+
+		//setup fakes to correctly interact with commandregistry
+		sshCodeGetter.SetDependencyStub = func(_ commandregistry.Dependency, _ bool) commandregistry.Command {
+			return sshCodeGetter
+		}
+		sshCodeGetter.MetaDataReturns(commandregistry.CommandMetadata{Name: "ssh-code"})
+	})
+
+	AfterEach(func() {
+		//restore original command
+		commandregistry.Register(originalSSHCodeGetter)
+	})
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = configRepo
+
+		//inject fake 'sshCodeGetter' into registry
+		commandregistry.Register(sshCodeGetter)
+
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("scp").SetDependency(deps, pluginCall))
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("scp", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("Requirements", func() {
+		BeforeEach(func() {
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+		})
+
+		It("fails with usage when not provided a source and a target", func() {
+			runCommand("my-app:/tmp/file")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires SOURCE and TARGET"},
+			))
+		})
+
+		It("fails with usage when neither side is remote", func() {
+			Expect(runCommand("./a", "./b")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "APP_NAME:PATH"},
+			))
+		})
+
+		It("fails with usage when both sides are remote", func() {
+			Expect(runCommand("my-app:/a", "other-app:/b")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "not supported"},
+			))
+		})
+
+		It("fails with usage when copying multiple remote sources", func() {
+			Expect(runCommand("my-app:/a", "my-app:/b", "./local")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "single remote source"},
+			))
+		})
+
+		It("fails with usage when given a negative instance index", func() {
+			Expect(runCommand("-i", "-1", "my-app:/a", "./local")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "cannot be negative"},
+			))
+		})
+
+		It("fails requirements when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-app:/a", "./local")).To(BeFalse())
+		})
+
+		It("fails if the application is not found", func() {
+			requirementsFactory.ApplicationFails = true
+			Expect(runCommand("my-app:/a", "./local")).To(BeFalse())
+		})
+	})
+
+	Describe("copying files", func() {
+		var testServer *httptest.Server
+
+		BeforeEach(func() {
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+
+			currentApp := models.Application{}
+			currentApp.Name = "my-app"
+			currentApp.State = "started"
+			currentApp.GUID = "my-app-guid"
+			currentApp.EnableSSH = true
+			currentApp.Diego = true
+			requirementsFactory.Application = currentApp
+
+			fakeSecureShell = new(sshfakes.FakeSecureShell)
+			deps.WildcardDependency = fakeSecureShell
+
+			getRequest := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/info",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body:   getInfoResponseBody,
+				},
+			})
+
+			testServer, _ = testnet.NewServer([]testnet.TestRequest{getRequest})
+			configRepo.SetAPIEndpoint(testServer.URL)
+			deps.Gateways["cloud-controller"] = cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+		})
+
+		It("connects to the requested instance", func() {
+			runCommand("-i", "2", "-k", "my-app:/tmp/file", "./file")
+
+			Expect(fakeSecureShell.ConnectCallCount()).To(Equal(1))
+			opts := fakeSecureShell.ConnectArgsForCall(0)
+			Expect(opts.AppName).To(Equal("my-app"))
+			Expect(opts.Index).To(Equal(uint(2)))
+			Expect(opts.SkipHostValidation).To(BeTrue())
+			Expect(fakeSecureShell.CloseCallCount()).To(Equal(1))
+		})
+
+		It("copies files from the container", func() {
+			runCommand("-r", "my-app:/home/vcap/logs", "./logs")
+
+			Expect(fakeSecureShell.CopyFromRemoteCallCount()).To(Equal(1))
+			remotePath, localPath, recursive := fakeSecureShell.CopyFromRemoteArgsForCall(0)
+			Expect(remotePath).To(Equal("/home/vcap/logs"))
+			Expect(localPath).To(Equal("./logs"))
+			Expect(recursive).To(BeTrue())
+		})
+
+		It("copies files to the container", func() {
+			runCommand("./config.yml", "./certs.pem", "my-app:/tmp")
+
+			Expect(fakeSecureShell.CopyToRemoteCallCount()).To(Equal(1))
+			localPaths, remotePath, recursive := fakeSecureShell.CopyToRemoteArgsForCall(0)
+			Expect(localPaths).To(Equal([]string{"./config.yml", "./certs.pem"}))
+			Expect(remotePath).To(Equal("/tmp"))
+			Expect(recursive).To(BeFalse())
+		})
+
+		It("notifies users when connecting fails", func() {
+			fakeSecureShell.ConnectReturns(errors.New("dial error"))
+
+			runCommand("my-app:/tmp/file", "./file")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Error opening SSH connection", "dial error"},
+			))
+			Expect(fakeSecureShell.CopyFromRemoteCallCount()).To(Equal(0))
+		})
+
+		It("refuses to copy files when sessions in the targeted space must be recorded", func() {
+			configRepo.SetSSHRecordSpaces([]string{"my-org/my-space"})
+
+			Expect(runCommand("my-app:/tmp/file", "./file")).To(BeFalse())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"SSH sessions in space my-org/my-space must be recorded", "cf scp"},
+			))
+			Expect(fakeSecureShell.ConnectCallCount()).To(Equal(0))
+		})
+
+		It("notifies users when copying fails", func() {
+			fakeSecureShell.CopyFromRemoteReturns(errors.New("No such file or directory"))
+
+			runCommand("my-app:/tmp/file", "./file")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Error copying files", "No such file or directory"},
+			))
+		})
+	})
+})
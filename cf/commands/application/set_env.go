@@ -1,22 +1,31 @@
 package application
 
 import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"sort"
+	"strings"
+
 	"github.com/cloudfoundry/cli/cf"
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/envfile"
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/trace"
 	"github.com/cloudfoundry/cli/flags"
 )
 
 type SetEnv struct {
-	ui      terminal.UI
-	config  coreconfig.Reader
-	appRepo applications.ApplicationRepository
-	appReq  requirements.ApplicationRequirement
+	ui        terminal.UI
+	config    coreconfig.Reader
+	appRepo   applications.ApplicationRepository
+	appReq    requirements.ApplicationRequirement
+	fileFlags flags.FlagContext
 }
 
 func init() {
@@ -30,13 +39,42 @@ func (cmd *SetEnv) MetaData() commandregistry.CommandMetadata {
 		Description: T("Set an env variable for an app"),
 		Usage: []string{
 			T("CF_NAME set-env APP_NAME ENV_VAR_NAME ENV_VAR_VALUE"),
+			T("CF_NAME set-env APP_NAME --from-file FILE [--replace] [--dry-run] [--show-secrets]\n\n   FILE is either a JSON object or a dotenv file with one NAME=VALUE per line. Credentials in the changes are hidden unless --show-secrets is given."),
+		},
+		Examples: []string{
+			"CF_NAME set-env my-app --from-file .env",
+			"CF_NAME set-env my-app --from-file env.json --replace --dry-run",
 		},
+		Flags: setEnvFileFlags(),
+		// flag parsing is skipped so that values can start with '-'; the
+		// flags only apply to --from-file and are parsed by Requirements
 		SkipFlagParsing: true,
 	}
 }
 
+func setEnvFileFlags() map[string]flags.FlagSet {
+	fs := make(map[string]flags.FlagSet)
+	fs["from-file"] = &flags.StringFlag{Name: "from-file", Usage: T("Set all of the variables in a dotenv or JSON file at once")}
+	fs["replace"] = &flags.BoolFlag{Name: "replace", Usage: T("Remove the variables that are not in the file")}
+	fs["dry-run"] = &flags.BoolFlag{Name: "dry-run", Usage: T("Show the changes to the variables without making them")}
+	fs["show-secrets"] = &flags.BoolFlag{Name: "show-secrets", Usage: T("Show passwords, keys and other credentials instead of hiding them")}
+	return fs
+}
+
 func (cmd *SetEnv) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
-	if len(fc.Args()) != 3 {
+	cmd.fileFlags = nil
+
+	if len(fc.Args()) > 1 && isSetEnvFileFlag(fc.Args()[1]) {
+		cmd.fileFlags = flags.NewFlagContext(setEnvFileFlags())
+		err := cmd.fileFlags.Parse(fc.Args()[1:]...)
+		if err != nil {
+			cmd.ui.Failed(T("Incorrect Usage. ") + err.Error() + "\n\n" + commandregistry.Commands.CommandUsage("set-env"))
+		}
+
+		if cmd.fileFlags.String("from-file") == "" || len(cmd.fileFlags.Args()) != 0 {
+			cmd.ui.Failed(T("Incorrect Usage. Requires 'app-name --from-file file' as arguments\n\n") + commandregistry.Commands.CommandUsage("set-env"))
+		}
+	} else if len(fc.Args()) != 3 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires 'app-name env-name env-value' as arguments\n\n") + commandregistry.Commands.CommandUsage("set-env"))
 	}
 
@@ -58,7 +96,17 @@ func (cmd *SetEnv) SetDependency(deps commandregistry.Dependency, pluginCall boo
 	return cmd
 }
 
+func isSetEnvFileFlag(arg string) bool {
+	name := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+	_, ok := setEnvFileFlags()[name]
+	return strings.HasPrefix(arg, "--") && ok
+}
+
 func (cmd *SetEnv) Execute(c flags.FlagContext) error {
+	if cmd.fileFlags != nil {
+		return cmd.setFromFile(cmd.fileFlags.String("from-file"), cmd.fileFlags.Bool("replace"), cmd.fileFlags.Bool("dry-run"), cmd.fileFlags.Bool("show-secrets"))
+	}
+
 	varName := c.Args()[1]
 	varValue := c.Args()[2]
 	app := cmd.appReq.GetApplication()
@@ -89,3 +137,143 @@ func (cmd *SetEnv) Execute(c flags.FlagContext) error {
 		map[string]interface{}{"Command": terminal.CommandColor(cf.Name + " restage " + app.Name)}))
 	return nil
 }
+
+// setFromFile sets all of the variables in the file with a single update,
+// so that the app only needs to be restaged once.
+func (cmd *SetEnv) setFromFile(path string, replace bool, dryRun bool, showSecrets bool) error {
+	app := cmd.appReq.GetApplication()
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	fileVars, err := envfile.Parse(contents)
+	if err != nil {
+		return errors.New(T("Error reading env variables from {{.File}}: ", map[string]interface{}{"File": path}) + err.Error())
+	}
+
+	hiddenNames := []string{}
+	for name, value := range fileVars {
+		if s, ok := value.(string); ok && strings.Contains(s, trace.PrivateDataPlaceholder()) {
+			hiddenNames = append(hiddenNames, name)
+		}
+	}
+	if len(hiddenNames) > 0 {
+		sort.Strings(hiddenNames)
+		return errors.New(T("The values of {{.Names}} in {{.File}} are hidden credentials, not the actual values. Use '{{.Command}}' to save the env variables with their values.",
+			map[string]interface{}{
+				"Names":   strings.Join(hiddenNames, ", "),
+				"File":    path,
+				"Command": cf.Name + " env APP_NAME --export dotenv",
+			}))
+	}
+
+	cmd.ui.Say(T("Setting env variables from {{.File}} for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"File":        terminal.EntityNameColor(path),
+			"AppName":     terminal.EntityNameColor(app.Name),
+			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"CurrentUser": terminal.EntityNameColor(cmd.config.Username())}))
+
+	envParams := map[string]interface{}{}
+	if !replace {
+		for name, value := range app.EnvironmentVars {
+			envParams[name] = value
+		}
+	}
+	for name, value := range fileVars {
+		envParams[name] = value
+	}
+
+	var masker *trace.SecretMasker
+	if !showSecrets {
+		masker, err = trace.NewSecretMasker(cmd.config.SecretPatterns())
+		if err != nil {
+			return err
+		}
+	}
+
+	changed := cmd.printEnvDiff(app.EnvironmentVars, envParams, masker)
+
+	if !changed {
+		cmd.ui.Ok()
+		cmd.ui.Say(T("No changes to env variables"))
+		return nil
+	}
+
+	if dryRun {
+		cmd.ui.Ok()
+		cmd.ui.Say(T("Dry run, the env variables have not been changed"))
+		return nil
+	}
+
+	_, err = cmd.appRepo.Update(app.GUID, models.AppParams{EnvironmentVars: &envParams})
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("TIP: Use '{{.Command}}' to ensure your env variable changes take effect",
+		map[string]interface{}{"Command": terminal.CommandColor(cf.Name + " restage " + app.Name)}))
+	return nil
+}
+
+// printEnvDiff prints the variables that are added, changed or removed, and
+// returns whether there are any. The values are masked unless masker is nil.
+func (cmd *SetEnv) printEnvDiff(current map[string]interface{}, updated map[string]interface{}, masker *trace.SecretMasker) bool {
+	shownCurrent, shownUpdated := current, updated
+	if masker != nil {
+		shownCurrent = masker.MaskVariables(current)
+		shownUpdated = masker.MaskVariables(updated)
+	}
+
+	names := []string{}
+	for name := range current {
+		names = append(names, name)
+	}
+	for name := range updated {
+		if _, ok := current[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	lines := []string{}
+	for _, name := range names {
+		currentValue, inCurrent := current[name]
+		updatedValue, inUpdated := updated[name]
+
+		switch {
+		case !inCurrent:
+			lines = append(lines, terminal.SuccessColor("+ "+name)+": "+formatEnvValue(shownUpdated[name]))
+		case !inUpdated:
+			lines = append(lines, terminal.FailureColor("- "+name)+": "+formatEnvValue(shownCurrent[name]))
+		case !envfile.Equal(currentValue, updatedValue):
+			lines = append(lines, terminal.WarningColor("~ "+name)+": "+formatEnvValue(shownCurrent[name])+" -> "+formatEnvValue(shownUpdated[name]))
+		}
+	}
+
+	if len(lines) > 0 {
+		cmd.ui.Say("")
+		for _, line := range lines {
+			cmd.ui.Say("%s", line)
+		}
+		cmd.ui.Say("")
+	}
+
+	return len(lines) > 0
+}
+
+func formatEnvValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	formatted, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(formatted)
+}
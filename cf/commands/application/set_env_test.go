@@ -2,6 +2,9 @@ package application_test
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/cloudfoundry/cli/cf/api/applications/applicationsfakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
@@ -73,6 +76,17 @@ var _ = Describe("set-env command", func() {
 				[]string{"Incorrect Usage", "Requires", "arguments"},
 			))
 		})
+
+		It("fails with usage when --from-file is not given a file", func() {
+			requirementsFactory.Application = app
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+
+			Expect(runCommand("my-app", "--replace")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires 'app-name --from-file file' as arguments"},
+			))
+		})
 	})
 
 	Context("when logged in, a space is targeted and given enough args", func() {
@@ -177,4 +191,163 @@ var _ = Describe("set-env command", func() {
 			))
 		})
 	})
+
+	Context("when setting variables from a file", func() {
+		var (
+			tmpDir  string
+			envPath string
+		)
+
+		BeforeEach(func() {
+			app.EnvironmentVars = map[string]interface{}{
+				"KEPT":    "same",
+				"CHANGED": "old",
+				"REMOVED": "gone",
+			}
+			requirementsFactory.Application = app
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+
+			var err error
+			tmpDir, err = ioutil.TempDir("", "set-env")
+			Expect(err).NotTo(HaveOccurred())
+
+			envPath = filepath.Join(tmpDir, ".env")
+			err = ioutil.WriteFile(envPath, []byte("# settings\nKEPT=same\nCHANGED=new\nADDED=\"hello world\"\n"), 0600)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+
+		It("merges the variables into the app's with a single update", func() {
+			Expect(runCommand("my-app", "--from-file", envPath)).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Setting env variables from", envPath, "my-app", "my-org", "my-space", "my-user"},
+				[]string{"+ ADDED", "hello world"},
+				[]string{"~ CHANGED", "old -> new"},
+				[]string{"OK"},
+				[]string{"TIP: Use 'cf restage my-app'"},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"KEPT"}))
+
+			Expect(appRepo.UpdateCallCount()).To(Equal(1))
+			appGUID, params := appRepo.UpdateArgsForCall(0)
+			Expect(appGUID).To(Equal("my-app-guid"))
+			Expect(*params.EnvironmentVars).To(Equal(map[string]interface{}{
+				"KEPT":    "same",
+				"CHANGED": "new",
+				"REMOVED": "gone",
+				"ADDED":   "hello world",
+			}))
+		})
+
+		It("removes the variables that are not in the file when given --replace", func() {
+			Expect(runCommand("my-app", "--from-file", envPath, "--replace")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"- REMOVED", "gone"},
+			))
+
+			_, params := appRepo.UpdateArgsForCall(0)
+			Expect(*params.EnvironmentVars).To(Equal(map[string]interface{}{
+				"KEPT":    "same",
+				"CHANGED": "new",
+				"ADDED":   "hello world",
+			}))
+		})
+
+		It("reads JSON files", func() {
+			err := ioutil.WriteFile(envPath, []byte(`{"ADDED": {"nested": true}, "PORT": 8080}`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(runCommand("my-app", "--from-file="+envPath)).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"+ ADDED", `{"nested":true}`},
+				[]string{"+ PORT", "8080"},
+			))
+			Expect(appRepo.UpdateCallCount()).To(Equal(1))
+		})
+
+		It("only shows the changes when given --dry-run", func() {
+			Expect(runCommand("my-app", "--from-file", envPath, "--dry-run")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"+ ADDED", "hello world"},
+				[]string{"Dry run, the env variables have not been changed"},
+			))
+			Expect(appRepo.UpdateCallCount()).To(Equal(0))
+		})
+
+		Context("when the changed variables hold credentials", func() {
+			BeforeEach(func() {
+				app.EnvironmentVars["DB_PASSWORD"] = "old-secret"
+				requirementsFactory.Application = app
+
+				err := ioutil.WriteFile(envPath, []byte("DB_PASSWORD=new-secret\nAPI_KEY=added-secret\n"), 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("hides their values in the changes", func() {
+				Expect(runCommand("my-app", "--from-file", envPath)).To(BeTrue())
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"+ API_KEY", "[PRIVATE DATA HIDDEN]"},
+					[]string{"~ DB_PASSWORD", "[PRIVATE DATA HIDDEN] -> [PRIVATE DATA HIDDEN]"},
+				))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"secret"}))
+
+				_, params := appRepo.UpdateArgsForCall(0)
+				Expect((*params.EnvironmentVars)["DB_PASSWORD"]).To(Equal("new-secret"))
+			})
+
+			It("shows their values when given --show-secrets", func() {
+				Expect(runCommand("my-app", "--from-file", envPath, "--show-secrets")).To(BeTrue())
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"+ API_KEY", "added-secret"},
+					[]string{"~ DB_PASSWORD", "old-secret -> new-secret"},
+				))
+			})
+		})
+
+		It("refuses values that were hidden when the variables were shown", func() {
+			err := ioutil.WriteFile(envPath, []byte("KEPT=same\nDB_PASSWORD=\"[PRIVATE DATA HIDDEN]\"\nDB_URL=\"postgres://admin:[PRIVATE DATA HIDDEN]@db/app\"\n"), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(runCommand("my-app", "--from-file", envPath)).To(BeFalse())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"The values of DB_PASSWORD, DB_URL in", envPath, "are hidden credentials"},
+			))
+			Expect(appRepo.UpdateCallCount()).To(Equal(0))
+		})
+
+		It("does not update the app when nothing changes", func() {
+			err := ioutil.WriteFile(envPath, []byte("KEPT=same\n"), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(runCommand("my-app", "--from-file", envPath)).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"No changes to env variables"}))
+			Expect(appRepo.UpdateCallCount()).To(Equal(0))
+		})
+
+		It("tells the user when the file cannot be parsed", func() {
+			err := ioutil.WriteFile(envPath, []byte("KEPT=same\nBROKEN\n"), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(runCommand("my-app", "--from-file", envPath)).To(BeFalse())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Error reading env variables from", envPath, "Missing '=' on line 2"},
+			))
+			Expect(appRepo.UpdateCallCount()).To(Equal(0))
+		})
+	})
 })
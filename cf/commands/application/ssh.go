@@ -1,17 +1,25 @@
 package application
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/ssh"
 
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/api/appinstances"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/commands"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/net"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	sshCmd "github.com/cloudfoundry/cli/cf/ssh"
@@ -22,13 +30,18 @@ import (
 )
 
 type SSH struct {
-	ui            terminal.UI
-	config        coreconfig.Reader
-	gateway       net.Gateway
-	appReq        requirements.ApplicationRequirement
-	sshCodeGetter commands.SSHCodeGetter
-	opts          *options.SSHOptions
-	secureShell   sshCmd.SecureShell
+	ui               terminal.UI
+	config           coreconfig.Reader
+	gateway          net.Gateway
+	appReq           requirements.ApplicationRequirement
+	sshCodeGetter    commands.SSHCodeGetter
+	appInstancesRepo appinstances.AppInstancesRepository
+	opts             *options.SSHOptions
+	secureShell      sshCmd.SecureShell
+
+	// Stderr receives the remote standard error of commands run with
+	// --all-instances.
+	Stderr io.Writer
 }
 
 type sshInfo struct {
@@ -43,19 +56,29 @@ func init() {
 func (cmd *SSH) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["L"] = &flags.StringSliceFlag{ShortName: "L", Usage: T("Local port forward specification. This flag can be defined more than once.")}
+	fs["D"] = &flags.StringSliceFlag{ShortName: "D", Usage: T("Dynamic SOCKS5 port forward specification. This flag can be defined more than once.")}
+	fs["R"] = &flags.StringSliceFlag{ShortName: "R", Usage: T("Remote port forward specification. This flag can be defined more than once.")}
 	fs["command"] = &flags.StringSliceFlag{Name: "command", ShortName: "c", Usage: T("Command to run. This flag can be defined more than once.")}
 	fs["app-instance-index"] = &flags.IntFlag{Name: "app-instance-index", ShortName: "i", Usage: T("Application instance index")}
 	fs["skip-host-validation"] = &flags.BoolFlag{Name: "skip-host-validation", ShortName: "k", Usage: T("Skip host key validation")}
+	fs["reset-host-key"] = &flags.BoolFlag{Name: "reset-host-key", Usage: T("Replace the pinned host key of the SSH endpoint with the one it presents")}
 	fs["skip-remote-execution"] = &flags.BoolFlag{Name: "skip-remote-execution", ShortName: "N", Usage: T("Do not execute a remote command")}
 	fs["request-pseudo-tty"] = &flags.BoolFlag{Name: "request-pseudo-tty", ShortName: "t", Usage: T("Request pseudo-tty allocation")}
 	fs["force-pseudo-tty"] = &flags.BoolFlag{Name: "force-pseudo-tty", ShortName: "tt", Usage: T("Force pseudo-tty allocation")}
 	fs["disable-pseudo-tty"] = &flags.BoolFlag{Name: "disable-pseudo-tty", ShortName: "T", Usage: T("Disable pseudo-tty allocation")}
+	fs["all-instances"] = &flags.BoolFlag{Name: "all-instances", Usage: T("Run the command given with -c on every running instance")}
+	fs["record"] = &flags.StringFlag{Name: "record", Usage: T("Record the input and output of the session to a file, which can be played back with 'CF_NAME ssh-replay'")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "ssh",
 		Description: T("SSH to an application container instance"),
 		Usage: []string{
-			T("CF_NAME ssh APP_NAME [-i app-instance-index] [-c command] [-L [bind_address:]port:host:hostport] [--skip-host-validation] [--skip-remote-execution] [--request-pseudo-tty] [--force-pseudo-tty] [--disable-pseudo-tty]"),
+			T("CF_NAME ssh APP_NAME [-i app-instance-index] [-c command] [-L [bind_address:]port:host:hostport] [-D [bind_address:]port] [-R [bind_address:]port:host:hostport] [--skip-host-validation] [--reset-host-key] [--skip-remote-execution] [--request-pseudo-tty] [--force-pseudo-tty] [--disable-pseudo-tty] [--record FILE]"),
+			T("CF_NAME ssh APP_NAME --all-instances -c command"),
+		},
+		Examples: []string{
+			"CF_NAME ssh my-app --all-instances -c \"df -h\"",
+			"CF_NAME ssh my-app --record session.cast",
 		},
 		Flags: fs,
 	}
@@ -92,6 +115,8 @@ func (cmd *SSH) SetDependency(deps commandregistry.Dependency, pluginCall bool)
 	cmd.ui = deps.UI
 	cmd.config = deps.Config
 	cmd.gateway = deps.Gateways["cloud-controller"]
+	cmd.appInstancesRepo = deps.RepoLocator.GetAppInstancesRepository()
+	cmd.Stderr = os.Stderr
 
 	if deps.WildcardDependency != nil {
 		cmd.secureShell = deps.WildcardDependency.(sshCmd.SecureShell)
@@ -106,46 +131,68 @@ func (cmd *SSH) SetDependency(deps commandregistry.Dependency, pluginCall bool)
 }
 
 func (cmd *SSH) Execute(fc flags.FlagContext) error {
+	// forwarded connections are not part of the recorded session
+	unrecordable := ""
+	switch {
+	case cmd.opts.AllInstances:
+		unrecordable = cf.Name + " ssh --all-instances"
+	case cmd.opts.SkipRemoteExecution:
+		unrecordable = cf.Name + " ssh -N"
+	case len(cmd.opts.ForwardSpecs) > 0:
+		unrecordable = cf.Name + " ssh -L"
+	case len(cmd.opts.DynamicForwardSpecs) > 0:
+		unrecordable = cf.Name + " ssh -D"
+	case len(cmd.opts.RemoteForwardSpecs) > 0:
+		unrecordable = cf.Name + " ssh -R"
+	}
+
+	if unrecordable != "" {
+		err := checkRecordingNotRequired(cmd.config, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name, unrecordable)
+		if err != nil {
+			return err
+		}
+	}
+
 	app := cmd.appReq.GetApplication()
-	info, err := cmd.getSSHEndpointInfo()
+	info, err := getSSHEndpointInfo(cmd.gateway, cmd.config)
 	if err != nil {
 		return errors.New(T("Error getting SSH info:") + err.Error())
 	}
 
-	sshAuthCode, err := cmd.sshCodeGetter.Get()
+	if cmd.opts.AllInstances {
+		return cmd.executeOnAllInstances(app, info)
+	}
+
+	knownHosts, err := newKnownHosts()
 	if err != nil {
-		return errors.New(T("Error getting one time auth code: ") + err.Error())
+		return err
 	}
 
-	//init secureShell if it is not already set by SetDependency() with fakes
-	if cmd.secureShell == nil {
-		cmd.secureShell = sshCmd.NewSecureShell(
-			sshCmd.DefaultSecureDialer(),
-			sshTerminal.DefaultHelper(),
-			sshCmd.DefaultListenerFactory(),
-			30*time.Second,
-			app,
-			info.SSHEndpointFingerprint,
-			info.SSHEndpoint,
-			sshAuthCode,
-		)
+	secureShell, err := newSecureShell(cmd.sshCodeGetter, cmd.secureShell, knownHosts, app, info)
+	if err != nil {
+		return err
 	}
 
-	err = cmd.secureShell.Connect(cmd.opts)
+	err = secureShell.Connect(cmd.opts)
 	if err != nil {
 		return errors.New(T("Error opening SSH connection: ") + err.Error())
 	}
-	defer cmd.secureShell.Close()
+	defer secureShell.Close()
 
-	err = cmd.secureShell.LocalPortForward()
+	err = secureShell.LocalPortForward()
 	if err != nil {
 		return errors.New(T("Error forwarding port: ") + err.Error())
 	}
 
+	err = secureShell.RemotePortForward()
+	if err != nil {
+		return errors.New(T("Error forwarding remote port: ") + err.Error())
+	}
+
 	if cmd.opts.SkipRemoteExecution {
-		err = cmd.secureShell.Wait()
+		err = secureShell.Wait()
 	} else {
-		err = cmd.secureShell.InteractiveSession()
+		err = cmd.recordedInteractiveSession(secureShell, app)
 	}
 
 	if err != nil {
@@ -162,8 +209,285 @@ func (cmd *SSH) Execute(fc flags.FlagContext) error {
 	return nil
 }
 
-func (cmd *SSH) getSSHEndpointInfo() (sshInfo, error) {
+// recordedInteractiveSession runs the interactive session, recording it when
+// requested with --record or when the targeted space is listed in the
+// config. The recording is closed before returning, as a failed command
+// exits the CLI straight away.
+func (cmd *SSH) recordedInteractiveSession(secureShell sshCmd.SecureShell, app models.Application) error {
+	recording, err := cmd.newRecording(app)
+	if err != nil {
+		return errors.New(T("Unable to record session: ") + err.Error())
+	}
+
+	if recording == nil {
+		return secureShell.InteractiveSession()
+	}
+
+	secureShell.Record(recording)
+	err = secureShell.InteractiveSession()
+
+	recordingErr := recording.Close()
+	if recordingErr != nil {
+		cmd.ui.Warn("%s", T("Unable to record session: ")+recordingErr.Error())
+	}
+
+	return err
+}
+
+func (cmd *SSH) newRecording(app models.Application) (*sshCmd.Recording, error) {
+	path := cmd.opts.RecordPath
+
+	if path == "" {
+		if !recordingRequired(cmd.config, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name) {
+			return nil, nil
+		}
+		space := cmd.config.OrganizationFields().Name + "/" + cmd.config.SpaceFields().Name
+
+		dir, err := sshCmd.DefaultRecordingDir()
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.MkdirAll(dir, 0700)
+		if err != nil {
+			return nil, err
+		}
+
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d-%s.cast", app.Name, cmd.opts.Index, time.Now().Format("20060102T150405")))
+
+		cmd.ui.Say(T("Recording session to {{.Path}}, as required for space {{.Space}}",
+			map[string]interface{}{
+				"Path":  terminal.EntityNameColor(path),
+				"Space": terminal.EntityNameColor(space),
+			}))
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return sshCmd.NewRecording(file, sshCmd.RecordingMetadata{
+		User:     cmd.config.Username(),
+		Org:      cmd.config.OrganizationFields().Name,
+		Space:    cmd.config.SpaceFields().Name,
+		AppName:  app.Name,
+		AppGUID:  app.GUID,
+		Instance: cmd.opts.Index,
+	}), nil
+}
+
+// recordingRequired tells whether the space is listed in the config as one
+// whose SSH sessions must be recorded.
+func recordingRequired(config coreconfig.Reader, orgName string, spaceName string) bool {
+	return containsString(config.SSHRecordSpaces(), orgName+"/"+spaceName)
+}
+
+// checkRecordingNotRequired fails when the SSH sessions of the space must be
+// recorded, as the given command cannot record them.
+func checkRecordingNotRequired(config coreconfig.Reader, orgName string, spaceName string, command string) error {
+	if !recordingRequired(config, orgName, spaceName) {
+		return nil
+	}
+
+	return errors.New(T("SSH sessions in space {{.Space}} must be recorded, which is not possible with '{{.Command}}'",
+		map[string]interface{}{
+			"Space":   orgName + "/" + spaceName,
+			"Command": command,
+		}))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// newKnownHosts returns the pinned host keys kept in the CF home directory.
+func newKnownHosts() (sshCmd.KnownHosts, error) {
+	knownHostsPath, err := sshCmd.DefaultKnownHostsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return sshCmd.NewKnownHosts(knownHostsPath), nil
+}
+
+// newSecureShell fetches a new one time auth code and returns a secure shell
+// that uses it, or the given secureShell when it was set by SetDependency()
+// with fakes.
+func newSecureShell(sshCodeGetter commands.SSHCodeGetter, secureShell sshCmd.SecureShell, knownHosts sshCmd.KnownHosts, app models.Application, info sshInfo) (sshCmd.SecureShell, error) {
+	sshAuthCode, err := sshCodeGetter.Get()
+	if err != nil {
+		return nil, errors.New(T("Error getting one time auth code: ") + err.Error())
+	}
+
+	if secureShell != nil {
+		return secureShell, nil
+	}
+
+	return sshCmd.NewSecureShell(
+		sshCmd.DefaultSecureDialer(),
+		sshTerminal.DefaultHelper(),
+		sshCmd.DefaultListenerFactory(),
+		knownHosts,
+		30*time.Second,
+		app,
+		info.SSHEndpointFingerprint,
+		info.SSHEndpoint,
+		sshAuthCode,
+	), nil
+}
+
+// executeOnAllInstances runs the command on every running instance at once,
+// prefixing each line of output with the instance index, and fails if the
+// command failed on any of them.
+func (cmd *SSH) executeOnAllInstances(app models.Application, info sshInfo) error {
+	instances, err := cmd.appInstancesRepo.GetInstances(app.GUID)
+	if err != nil {
+		return errors.New(T("Error getting instances: ") + err.Error())
+	}
+
+	indices := []int{}
+	for index, instance := range instances {
+		if instance.State == models.InstanceRunning {
+			indices = append(indices, index)
+		}
+	}
+
+	if len(indices) == 0 {
+		return errors.New(T("App {{.AppName}} has no running instances", map[string]interface{}{"AppName": app.Name}))
+	}
+
+	cmd.ui.Say(T("Running {{.Command}} on {{.Count}} instances of app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		map[string]interface{}{
+			"Command":   terminal.EntityNameColor(strings.Join(cmd.opts.Command, " ")),
+			"Count":     len(indices),
+			"AppName":   terminal.EntityNameColor(app.Name),
+			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.config.Username()),
+		}))
+	cmd.ui.Say("")
+
+	knownHosts, err := newKnownHosts()
+	if err != nil {
+		return err
+	}
+
+	// one time auth codes can only be used for a single connection, so every
+	// instance gets its own secure shell, all sharing the pinned host keys
+	shells := make([]sshCmd.SecureShell, len(indices))
+	for i := range indices {
+		shells[i], err = newSecureShell(cmd.sshCodeGetter, cmd.secureShell, knownHosts, app, info)
+		if err != nil {
+			return err
+		}
+	}
+
+	results := make([]error, len(indices))
+	outputLock := &sync.Mutex{}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(indices))
+	for i, index := range indices {
+		go func(i int, index int) {
+			defer wg.Done()
+			results[i] = cmd.runOnInstance(shells[i], index, outputLock)
+		}(i, index)
+	}
+	wg.Wait()
+
+	cmd.ui.Say("")
+	table := cmd.ui.Table([]string{T("instance"), T("exit status")})
+	failed := 0
+	for i, index := range indices {
+		status := "0"
+		if results[i] != nil {
+			failed++
+			if exitError, ok := results[i].(*ssh.ExitError); ok {
+				status = fmt.Sprintf("%d", exitError.ExitStatus())
+			} else {
+				status = results[i].Error()
+			}
+		}
+		table.Add(fmt.Sprintf("#%d", index), status)
+	}
+	table.Print()
+
+	if failed > 0 {
+		return errors.New(T("Command failed on {{.Failed}} of {{.Total}} instances",
+			map[string]interface{}{"Failed": failed, "Total": len(indices)}))
+	}
+
+	return nil
+}
+
+func (cmd *SSH) runOnInstance(secureShell sshCmd.SecureShell, index int, outputLock *sync.Mutex) error {
+	opts := *cmd.opts
+	opts.Index = uint(index)
+
+	err := secureShell.Connect(&opts)
+	if err != nil {
+		return errors.New(T("Error opening SSH connection: ") + err.Error())
+	}
+	defer secureShell.Close()
+
+	prefix := fmt.Sprintf("[%d] ", index)
+	stdout := &prefixWriter{lock: outputLock, prefix: prefix, print: func(line string) {
+		cmd.ui.Say("%s", line)
+	}}
+	stderr := &prefixWriter{lock: outputLock, prefix: prefix, print: func(line string) {
+		fmt.Fprintln(cmd.Stderr, line)
+	}}
+
+	err = secureShell.CommandSession(stdout, stderr)
+	stdout.Flush()
+	stderr.Flush()
+	return err
+}
+
+// prefixWriter prints every complete line written to it with a prefix, so
+// the output of concurrent sessions can be told apart.
+type prefixWriter struct {
+	lock   *sync.Mutex
+	prefix string
+	print  func(line string)
+	buffer bytes.Buffer
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buffer.Write(p)
+
+	for {
+		line, err := w.buffer.ReadString('\n')
+		if err == io.EOF {
+			// keep the incomplete line until the rest of it arrives
+			w.buffer.WriteString(line)
+			return len(p), nil
+		}
+		w.say(strings.TrimSuffix(line, "\n"))
+	}
+}
+
+func (w *prefixWriter) Flush() {
+	if w.buffer.Len() > 0 {
+		w.say(w.buffer.String())
+		w.buffer.Reset()
+	}
+}
+
+func (w *prefixWriter) say(line string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.print(w.prefix + line)
+}
+
+func getSSHEndpointInfo(gateway net.Gateway, config coreconfig.Reader) (sshInfo, error) {
 	info := sshInfo{}
-	err := cmd.gateway.GetResource(cmd.config.APIEndpoint()+"/v2/info", &info)
+	err := gateway.GetResource(config.APIEndpoint()+"/v2/info", &info)
 	return info, err
 }
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	cfnet "github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	sshCmd "github.com/cloudfoundry/cli/cf/ssh"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+// ssh reads spaces in a Host line as separators, and *, ? and ! as patterns
+var unsafeHostAliasCharRegex = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+type SSHConfig struct {
+	ui      terminal.UI
+	config  coreconfig.Reader
+	gateway cfnet.Gateway
+	appReq  requirements.ApplicationRequirement
+}
+
+func init() {
+	commandregistry.Register(&SSHConfig{})
+}
+
+func (cmd *SSHConfig) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["app-instance-index"] = &flags.IntFlag{Name: "app-instance-index", ShortName: "i", Usage: T("Application instance index")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "ssh-config",
+		Description: T("Print an OpenSSH config block for connecting to an application container instance"),
+		Usage: []string{
+			T("CF_NAME ssh-config APP_NAME [-i app-instance-index]\n\n   The generated block uses 'CF_NAME ssh-proxy' as its ProxyCommand, which fetches a new one time auth code for every connection."),
+		},
+		Examples: []string{
+			"CF_NAME ssh-config my-app >> ~/.ssh/config",
+			"ssh cf-my-app",
+			"rsync -av cf-my-app:/home/vcap/logs/ ./logs/",
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *SSHConfig) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires APP_NAME as argument") + "\n\n" + commandregistry.Commands.CommandUsage("ssh-config"))
+	}
+
+	if fc.IsSet("i") && fc.Int("i") < 0 {
+		cmd.ui.Failed(fmt.Sprintf(T("Incorrect Usage:")+" %s\n\n%s", T("Value for flag 'app-instance-index' cannot be negative"), commandregistry.Commands.CommandUsage("ssh-config")))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[0])
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+
+	return reqs
+}
+
+func (cmd *SSHConfig) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.gateway = deps.Gateways["cloud-controller"]
+	return cmd
+}
+
+func (cmd *SSHConfig) Execute(fc flags.FlagContext) error {
+	err := checkRecordingNotRequired(cmd.config, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name, cf.Name+" ssh-config")
+	if err != nil {
+		return err
+	}
+
+	app := cmd.appReq.GetApplication()
+	info, err := getSSHEndpointInfo(cmd.gateway, cmd.config)
+	if err != nil {
+		return errors.New(T("Error getting SSH info:") + err.Error())
+	}
+
+	host, port, err := net.SplitHostPort(info.SSHEndpoint)
+	if err != nil {
+		return errors.New(T("Error getting SSH info:") + err.Error())
+	}
+
+	knownHostsPath, err := sshCmd.DefaultKnownHostsPath()
+	if err != nil {
+		return err
+	}
+
+	hostKeyPath, err := sshCmd.DefaultProxyHostKeyPath()
+	if err != nil {
+		return err
+	}
+
+	// ssh talks to the local proxy, so it checks the key of the proxy, and
+	// the proxy checks the key of the SSH endpoint
+	hostKey, err := sshCmd.LoadProxyHostKey(hostKeyPath)
+	if err != nil {
+		return errors.New(T("Unable to read the host key of the proxy: ") + err.Error())
+	}
+
+	err = sshCmd.NewKnownHosts(knownHostsPath).Pin(sshCmd.ProxyHostKeyAlias, hostKey.PublicKey())
+	if err != nil {
+		return errors.New(T("Unable to pin the host key of the proxy: ") + err.Error())
+	}
+
+	index := fc.Int("i")
+	alias := "cf-" + unsafeHostAliasCharRegex.ReplaceAllString(app.Name, "-")
+	if fc.IsSet("i") {
+		alias = fmt.Sprintf("%s-%d", alias, index)
+	}
+
+	cmd.ui.Say("Host %s", alias)
+	cmd.ui.Say("    HostName %s", host)
+	cmd.ui.Say("    Port %s", port)
+	cmd.ui.Say("    User cf:%s/%d", app.GUID, index)
+	cmd.ui.Say("    ProxyCommand %s ssh-proxy %%r", cf.Name)
+	cmd.ui.Say("    HostKeyAlias %s", sshCmd.ProxyHostKeyAlias)
+	cmd.ui.Say("    UserKnownHostsFile \"%s\"", knownHostsPath)
+	cmd.ui.Say("    StrictHostKeyChecking yes")
+	return nil
+}
@@ -0,0 +1,217 @@
+package application_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/net"
+	sshCmd "github.com/cloudfoundry/cli/cf/ssh"
+	"github.com/cloudfoundry/cli/testhelpers/cloudcontrollergateway"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testnet "github.com/cloudfoundry/cli/testhelpers/net"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+	"golang.org/x/crypto/ssh"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ssh-config command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		requirementsFactory *testreq.FakeReqFactory
+		configRepo          coreconfig.Repository
+		deps                commandregistry.Dependency
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{}
+		deps.Gateways = make(map[string]net.Gateway)
+	})
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = configRepo
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("ssh-config").SetDependency(deps, pluginCall))
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("ssh-config", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails with usage when not provided exactly one arg", func() {
+			requirementsFactory.LoginSuccess = true
+
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires", "argument"},
+			))
+		})
+
+		It("fails with usage when given a negative instance index", func() {
+			requirementsFactory.LoginSuccess = true
+
+			Expect(runCommand("my-app", "-i", "-1")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "cannot be negative"},
+			))
+		})
+
+		It("fails requirements when not logged in", func() {
+			Expect(runCommand("my-app")).To(BeFalse())
+		})
+
+		It("fails if the application is not found", func() {
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+			requirementsFactory.ApplicationFails = true
+
+			Expect(runCommand("my-app")).To(BeFalse())
+		})
+	})
+
+	Describe("printing the config", func() {
+		var (
+			testServer     *httptest.Server
+			cfHome         string
+			originalCFHome string
+			knownHostsPath string
+		)
+
+		BeforeEach(func() {
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+
+			app := models.Application{}
+			app.Name = "my-app"
+			app.GUID = "my-app-guid"
+			requirementsFactory.Application = app
+
+			getRequest := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/info",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body:   getInfoResponseBody,
+				},
+			})
+
+			testServer, _ = testnet.NewServer([]testnet.TestRequest{getRequest})
+			configRepo.SetAPIEndpoint(testServer.URL)
+			deps.Gateways["cloud-controller"] = cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
+
+			var err error
+			cfHome, err = ioutil.TempDir("", "ssh-config")
+			Expect(err).NotTo(HaveOccurred())
+			originalCFHome = os.Getenv("CF_HOME")
+			os.Setenv("CF_HOME", cfHome)
+
+			knownHostsPath, err = sshCmd.DefaultKnownHostsPath()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+			os.Setenv("CF_HOME", originalCFHome)
+			os.RemoveAll(cfHome)
+		})
+
+		It("prints a host block for the first instance that checks the host key of the proxy", func() {
+			Expect(runCommand("my-app")).To(BeTrue())
+
+			Expect(ui.Outputs).To(Equal([]string{
+				"Host cf-my-app",
+				"    HostName ssh.run.pivotal.io",
+				"    Port 2222",
+				"    User cf:my-app-guid/0",
+				"    ProxyCommand cf ssh-proxy %r",
+				"    HostKeyAlias cf-ssh-proxy",
+				"    UserKnownHostsFile \"" + knownHostsPath + "\"",
+				"    StrictHostKeyChecking yes",
+			}))
+		})
+
+		It("pins the host key of the proxy", func() {
+			Expect(runCommand("my-app")).To(BeTrue())
+
+			keyPath, err := sshCmd.DefaultProxyHostKeyPath()
+			Expect(err).NotTo(HaveOccurred())
+			hostKey, err := sshCmd.LoadProxyHostKey(keyPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			pinnedKey, found, err := sshCmd.NewKnownHosts(knownHostsPath).Lookup("cf-ssh-proxy")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(pinnedKey.Marshal()).To(Equal(hostKey.PublicKey().Marshal()))
+		})
+
+		It("keeps the host keys pinned by cf ssh", func() {
+			privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			endpointKey, err := ssh.NewPublicKey(&privateKey.PublicKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = sshCmd.NewKnownHosts(knownHostsPath).Pin("ssh.run.pivotal.io:2222", endpointKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(runCommand("my-app")).To(BeTrue())
+
+			pinnedKey, found, err := sshCmd.NewKnownHosts(knownHostsPath).Lookup("ssh.run.pivotal.io:2222")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(pinnedKey.Marshal()).To(Equal(endpointKey.Marshal()))
+		})
+
+		It("includes the instance index in the host alias when one is given", func() {
+			runCommand("my-app", "-i", "2")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Host cf-my-app-2"},
+				[]string{"User cf:my-app-guid/2"},
+			))
+		})
+
+		It("replaces the characters that ssh reads as separators or patterns in the host alias", func() {
+			app := models.Application{}
+			app.Name = "my app*?!"
+			app.GUID = "my-app-guid"
+			requirementsFactory.Application = app
+
+			Expect(runCommand("my app*?!")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Host cf-my-app---"},
+				[]string{"User cf:my-app-guid/0"},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Host cf-my app"}))
+		})
+
+		Context("when sessions in the targeted space must be recorded", func() {
+			It("refuses to print the config, as the sessions cannot be recorded", func() {
+				configRepo.SetSSHRecordSpaces([]string{"my-org/my-space"})
+
+				Expect(runCommand("my-app")).To(BeFalse())
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"SSH sessions in space my-org/my-space must be recorded", "cf ssh-config"},
+				))
+			})
+		})
+	})
+})
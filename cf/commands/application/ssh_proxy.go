@@ -0,0 +1,161 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/api/applications"
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	sshCmd "github.com/cloudfoundry/cli/cf/ssh"
+	"github.com/cloudfoundry/cli/cf/ssh/options"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+var sshUserPattern = regexp.MustCompile(`^cf:([^/]+)/(\d+)$`)
+
+type SSHProxy struct {
+	ui            terminal.UI
+	config        coreconfig.Reader
+	gateway       net.Gateway
+	appRepo       applications.ApplicationRepository
+	sshCodeGetter commands.SSHCodeGetter
+	secureShell   sshCmd.SecureShell
+
+	// Stdin and Stdout carry the SSH connection of the OpenSSH client.
+	Stdin  io.Reader
+	Stdout io.WriteCloser
+}
+
+func init() {
+	commandregistry.Register(&SSHProxy{})
+}
+
+func (cmd *SSHProxy) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:        "ssh-proxy",
+		Description: T("Relay an SSH connection on stdin and stdout to an application container instance"),
+		Usage: []string{
+			T("CF_NAME ssh-proxy cf:APP_GUID/INDEX\n\n   Intended to be used as an OpenSSH ProxyCommand, see 'CF_NAME ssh-config'. The application is looked up by the GUID in the SSH user, so the connection does not depend on the targeted space. A new one time auth code is fetched for every connection."),
+		},
+		Examples: []string{
+			"ssh -o ProxyCommand=\"CF_NAME ssh-proxy %r\" -p 2222 cf:APP_GUID/0@ssh.example.com",
+		},
+	}
+}
+
+func (cmd *SSHProxy) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires SSH user cf:APP_GUID/INDEX as argument") + "\n\n" + commandregistry.Commands.CommandUsage("ssh-proxy"))
+	}
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+
+	return reqs
+}
+
+func (cmd *SSHProxy) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.gateway = deps.Gateways["cloud-controller"]
+	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+
+	if deps.WildcardDependency != nil {
+		cmd.secureShell = deps.WildcardDependency.(sshCmd.SecureShell)
+	}
+
+	//get ssh-code for dependency
+	sshCodeGetter := commandregistry.Commands.FindCommand("ssh-code")
+	sshCodeGetter = sshCodeGetter.SetDependency(deps, false)
+	cmd.sshCodeGetter = sshCodeGetter.(commands.SSHCodeGetter)
+
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	return cmd
+}
+
+func (cmd *SSHProxy) Execute(fc flags.FlagContext) error {
+	user := fc.Args()[0]
+	matches := sshUserPattern.FindStringSubmatch(user)
+	if matches == nil {
+		return errors.New(T("Invalid SSH user {{.User}}, expected cf:APP_GUID/INDEX", map[string]interface{}{"User": user}))
+	}
+
+	app, err := cmd.appRepo.GetApp(matches[1])
+	if err != nil {
+		return err
+	}
+
+	// the app need not be in the targeted space, so its space is looked up
+	// to tell whether its sessions must be recorded
+	if len(cmd.config.SSHRecordSpaces()) > 0 {
+		spaceResource := new(resources.SpaceResource)
+		err = cmd.gateway.GetResource(fmt.Sprintf("%s/v2/spaces/%s?inline-relations-depth=1", cmd.config.APIEndpoint(), app.SpaceGUID), spaceResource)
+		if err != nil {
+			return err
+		}
+
+		space := spaceResource.ToModel()
+		err = checkRecordingNotRequired(cmd.config, space.Organization.Name, space.Name, cf.Name+" ssh-proxy")
+		if err != nil {
+			return err
+		}
+	}
+
+	info, err := getSSHEndpointInfo(cmd.gateway, cmd.config)
+	if err != nil {
+		return errors.New(T("Error getting SSH info:") + err.Error())
+	}
+
+	hostKeyPath, err := sshCmd.DefaultProxyHostKeyPath()
+	if err != nil {
+		return err
+	}
+
+	hostKey, err := sshCmd.LoadProxyHostKey(hostKeyPath)
+	if err != nil {
+		return errors.New(T("Unable to read the host key of the proxy: ") + err.Error())
+	}
+
+	knownHosts, err := newKnownHosts()
+	if err != nil {
+		return err
+	}
+
+	secureShell, err := newSecureShell(cmd.sshCodeGetter, cmd.secureShell, knownHosts, app, info)
+	if err != nil {
+		return err
+	}
+
+	index, _ := strconv.ParseUint(matches[2], 10, 32)
+	opts := &options.SSHOptions{
+		AppName: app.Name,
+		Index:   uint(index),
+	}
+
+	err = secureShell.Connect(opts)
+	if err != nil {
+		return errors.New(T("Error opening SSH connection: ") + err.Error())
+	}
+	defer secureShell.Close()
+
+	err = secureShell.Proxy(sshCmd.NewStdioConn(cmd.Stdin, cmd.Stdout), hostKey)
+	if err != nil {
+		return errors.New(T("Error: ") + err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,284 @@
+package application_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/applications/applicationsfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands/application"
+	"github.com/cloudfoundry/cli/cf/commands/commandsfakes"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/net"
+	sshCmd "github.com/cloudfoundry/cli/cf/ssh"
+	"github.com/cloudfoundry/cli/cf/ssh/sshfakes"
+	"github.com/cloudfoundry/cli/testhelpers/cloudcontrollergateway"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testnet "github.com/cloudfoundry/cli/testhelpers/net"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type nopWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+var _ = Describe("ssh-proxy command", func() {
+	var (
+		ui *testterm.FakeUI
+
+		sshCodeGetter         *commandsfakes.FakeSSHCodeGetter
+		originalSSHCodeGetter commandregistry.Command
+
+		requirementsFactory *testreq.FakeReqFactory
+		configRepo          coreconfig.Repository
+		deps                commandregistry.Dependency
+		appRepo             *applicationsfakes.FakeApplicationRepository
+
+		fakeSecureShell *sshfakes.FakeSecureShell
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{}
+		deps.Gateways = make(map[string]net.Gateway)
+		deps.WildcardDependency = nil
+		appRepo = new(applicationsfakes.FakeApplicationRepository)
+
+		//save original command and restore later
+		originalSSHCodeGetter = commandregistry.Commands.FindCommand("ssh-code")
+
+		sshCodeGetter = new(commandsfakes.FakeSSHCodeGetter)
+
+		//setup fakes to correctly interact with commandregistry
+		sshCodeGetter.SetDependencyStub = func(_ commandregistry.Dependency, _ bool) commandregistry.Command {
+			return sshCodeGetter
+		}
+		sshCodeGetter.MetaDataReturns(commandregistry.CommandMetadata{Name: "ssh-code"})
+	})
+
+	AfterEach(func() {
+		//restore original command
+		commandregistry.Register(originalSSHCodeGetter)
+	})
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+
+		//inject fake 'sshCodeGetter' into registry
+		commandregistry.Register(sshCodeGetter)
+
+		cmd := commandregistry.Commands.FindCommand("ssh-proxy").SetDependency(deps, pluginCall).(*application.SSHProxy)
+		cmd.Stdin = strings.NewReader("")
+		cmd.Stdout = nopWriteCloser{&bytes.Buffer{}}
+		commandregistry.Commands.SetCommand(cmd)
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("ssh-proxy", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("Requirements", func() {
+		BeforeEach(func() {
+			requirementsFactory.LoginSuccess = true
+		})
+
+		It("fails with usage when not provided exactly one arg", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires", "argument"},
+			))
+		})
+
+		It("fails requirements when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("cf:my-app-guid/0")).To(BeFalse())
+		})
+
+		It("does not require a targeted space", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand("not-a-cf-user")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Invalid SSH user not-a-cf-user"}))
+		})
+	})
+
+	Describe("proxying a connection", func() {
+		var (
+			testServer     *httptest.Server
+			cfHome         string
+			originalCFHome string
+		)
+
+		BeforeEach(func() {
+			requirementsFactory.LoginSuccess = true
+
+			app := models.Application{}
+			app.Name = "my-app"
+			app.GUID = "my-app-guid"
+			app.SpaceGUID = "my-space-guid"
+			app.State = "started"
+			app.EnableSSH = true
+			app.Diego = true
+			appRepo.GetAppReturns(app, nil)
+
+			fakeSecureShell = new(sshfakes.FakeSecureShell)
+			deps.WildcardDependency = fakeSecureShell
+
+			getRequest := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/info",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body:   getInfoResponseBody,
+				},
+			})
+
+			testServer, _ = testnet.NewServer([]testnet.TestRequest{getRequest})
+			configRepo.SetAPIEndpoint(testServer.URL)
+			deps.Gateways["cloud-controller"] = cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
+
+			var err error
+			cfHome, err = ioutil.TempDir("", "ssh-proxy")
+			Expect(err).NotTo(HaveOccurred())
+			originalCFHome = os.Getenv("CF_HOME")
+			os.Setenv("CF_HOME", cfHome)
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+			os.Setenv("CF_HOME", originalCFHome)
+			os.RemoveAll(cfHome)
+		})
+
+		It("looks up the app by the GUID in the SSH user, connects with a new auth code and proxies the connection", func() {
+			Expect(runCommand("cf:my-app-guid/3")).To(BeTrue())
+
+			Expect(appRepo.GetAppArgsForCall(0)).To(Equal("my-app-guid"))
+			Expect(sshCodeGetter.GetCallCount()).To(Equal(1))
+
+			Expect(fakeSecureShell.ConnectCallCount()).To(Equal(1))
+			opts := fakeSecureShell.ConnectArgsForCall(0)
+			Expect(opts.AppName).To(Equal("my-app"))
+			Expect(opts.Index).To(Equal(uint(3)))
+
+			Expect(fakeSecureShell.ProxyCallCount()).To(Equal(1))
+			Expect(fakeSecureShell.CloseCallCount()).To(Equal(1))
+		})
+
+		It("serves the connection with the host key of the proxy in the CF home directory", func() {
+			Expect(runCommand("cf:my-app-guid/0")).To(BeTrue())
+
+			keyPath, err := sshCmd.DefaultProxyHostKeyPath()
+			Expect(err).NotTo(HaveOccurred())
+			hostKey, err := sshCmd.LoadProxyHostKey(keyPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, proxyHostKey := fakeSecureShell.ProxyArgsForCall(0)
+			Expect(proxyHostKey.PublicKey().Marshal()).To(Equal(hostKey.PublicKey().Marshal()))
+		})
+
+		Context("when sessions in the space of the app must be recorded", func() {
+			BeforeEach(func() {
+				configRepo.SetSSHRecordSpaces([]string{"other-org/other-space"})
+
+				getSpaceRequest := apifakes.NewCloudControllerTestRequest(testnet.TestRequest{
+					Method: "GET",
+					Path:   "/v2/spaces/my-space-guid?inline-relations-depth=1",
+					Response: testnet.TestResponse{
+						Status: http.StatusOK,
+						Body: `{
+							"metadata": {"guid": "my-space-guid"},
+							"entity": {
+								"name": "other-space",
+								"organization": {"metadata": {"guid": "other-org-guid"}, "entity": {"name": "other-org"}}
+							}
+						}`,
+					},
+				})
+				testServer.Close()
+				testServer, _ = testnet.NewServer([]testnet.TestRequest{getSpaceRequest})
+				configRepo.SetAPIEndpoint(testServer.URL)
+				deps.Gateways["cloud-controller"] = cloudcontrollergateway.NewTestCloudControllerGateway(configRepo)
+			})
+
+			It("refuses to relay the connection, as it cannot be recorded", func() {
+				Expect(runCommand("cf:my-app-guid/0")).To(BeFalse())
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"SSH sessions in space other-org/other-space must be recorded", "cf ssh-proxy"},
+				))
+				Expect(sshCodeGetter.GetCallCount()).To(Equal(0))
+				Expect(fakeSecureShell.ConnectCallCount()).To(Equal(0))
+			})
+		})
+
+		It("fails when the SSH user does not name an app instance", func() {
+			Expect(runCommand("cf:my-app-guid")).To(BeFalse())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Invalid SSH user cf:my-app-guid, expected cf:APP_GUID/INDEX"},
+			))
+			Expect(appRepo.GetAppCallCount()).To(Equal(0))
+		})
+
+		It("fails when the app cannot be found", func() {
+			appRepo.GetAppReturns(models.Application{}, cferrors.NewModelNotFoundError("App", "my-app-guid"))
+
+			Expect(runCommand("cf:my-app-guid/0")).To(BeFalse())
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"FAILED"}, []string{"App", "my-app-guid", "not found"}))
+			Expect(fakeSecureShell.ConnectCallCount()).To(Equal(0))
+		})
+
+		It("notifies users when getting an auth code fails", func() {
+			sshCodeGetter.GetReturns("", errors.New("auth error"))
+
+			Expect(runCommand("cf:my-app-guid/0")).To(BeFalse())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Error getting one time auth code", "auth error"},
+			))
+			Expect(fakeSecureShell.ConnectCallCount()).To(Equal(0))
+		})
+
+		It("notifies users when connecting fails", func() {
+			fakeSecureShell.ConnectReturns(errors.New("dial error"))
+
+			Expect(runCommand("cf:my-app-guid/0")).To(BeFalse())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Error opening SSH connection", "dial error"},
+			))
+			Expect(fakeSecureShell.ProxyCallCount()).To(Equal(0))
+		})
+
+		It("notifies users when proxying fails", func() {
+			fakeSecureShell.ProxyReturns(errors.New("handshake failed"))
+
+			Expect(runCommand("cf:my-app-guid/0")).To(BeFalse())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Error", "handshake failed"},
+			))
+		})
+	})
+})
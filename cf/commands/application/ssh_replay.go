@@ -0,0 +1,99 @@
+package application
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	sshCmd "github.com/cloudfoundry/cli/cf/ssh"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type SSHReplay struct {
+	ui terminal.UI
+}
+
+func init() {
+	commandregistry.Register(&SSHReplay{})
+}
+
+func (cmd *SSHReplay) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["speed"] = &flags.Float64Flag{Name: "speed", Value: 1, Usage: T("Playback speed, relative to the recorded speed")}
+	fs["max-idle"] = &flags.IntFlag{Name: "max-idle", Usage: T("Shorten pauses longer than the given number of seconds")}
+	fs["no-delay"] = &flags.BoolFlag{Name: "no-delay", Usage: T("Print the whole output of the session at once")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "ssh-replay",
+		Description: T("Play back an SSH session recorded with 'CF_NAME ssh --record'"),
+		Usage: []string{
+			T("CF_NAME ssh-replay FILE [--speed SPEED] [--max-idle SECONDS] [--no-delay]"),
+		},
+		Examples: []string{
+			"CF_NAME ssh-replay session.cast --speed 2 --max-idle 1",
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *SSHReplay) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires FILE as argument") + "\n\n" + commandregistry.Commands.CommandUsage("ssh-replay"))
+	}
+
+	if fc.Float64("speed") < 0 || fc.Int("max-idle") < 0 {
+		cmd.ui.Failed(T("Incorrect Usage:") + " " + T("--speed and --max-idle cannot be negative") + "\n\n" + commandregistry.Commands.CommandUsage("ssh-replay"))
+	}
+
+	reqs := []requirements.Requirement{}
+	return reqs
+}
+
+func (cmd *SSHReplay) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	return cmd
+}
+
+func (cmd *SSHReplay) Execute(fc flags.FlagContext) error {
+	file, err := os.Open(fc.Args()[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	player, err := sshCmd.NewRecordingPlayer(file)
+	if err != nil {
+		return errors.New(T("Error reading recording: ") + err.Error())
+	}
+
+	metadata := player.Header.CF
+	cmd.ui.Say(T("Replaying SSH session to instance {{.Instance}} of app {{.AppName}} ({{.AppGUID}}) in org {{.OrgName}} / space {{.SpaceName}} by {{.Username}}, recorded at {{.Time}}",
+		map[string]interface{}{
+			"Instance":  metadata.Instance,
+			"AppName":   terminal.EntityNameColor(metadata.AppName),
+			"AppGUID":   metadata.AppGUID,
+			"OrgName":   terminal.EntityNameColor(metadata.Org),
+			"SpaceName": terminal.EntityNameColor(metadata.Space),
+			"Username":  terminal.EntityNameColor(metadata.User),
+			"Time":      time.Unix(player.Header.Timestamp, 0).Format(time.RFC1123Z),
+		}))
+	cmd.ui.Say("")
+
+	speed := fc.Float64("speed")
+	if fc.Bool("no-delay") {
+		speed = 0
+	}
+
+	err = player.Play(cmd.ui.Writer(), speed, time.Duration(fc.Int("max-idle"))*time.Second)
+	if err != nil {
+		return errors.New(T("Error reading recording: ") + err.Error())
+	}
+
+	cmd.ui.Say("")
+	cmd.ui.Say(T("End of recording"))
+	return nil
+}
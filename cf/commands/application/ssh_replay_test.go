@@ -0,0 +1,98 @@
+package application_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	io_helpers "github.com/cloudfoundry/cli/testhelpers/io"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ssh-replay command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		requirementsFactory *testreq.FakeReqFactory
+		deps                commandregistry.Dependency
+
+		tmpDir        string
+		recordingPath string
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		requirementsFactory = &testreq.FakeReqFactory{}
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "ssh-replay")
+		Expect(err).NotTo(HaveOccurred())
+		recordingPath = filepath.Join(tmpDir, "session.cast")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("ssh-replay").SetDependency(deps, pluginCall))
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("ssh-replay", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails with usage when not provided exactly one arg", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires FILE as argument"},
+			))
+		})
+
+		It("fails with usage when given a negative speed", func() {
+			Expect(runCommand("session.cast", "--speed", "-2")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "cannot be negative"},
+			))
+		})
+	})
+
+	It("prints who recorded the session and plays back its output", func() {
+		err := ioutil.WriteFile(recordingPath, []byte(`{"version":2,"width":80,"height":24,"timestamp":1476000000,"cf":{"user":"my-user","org":"my-org","space":"my-space","app_name":"my-app","app_guid":"my-app-guid","instance":1}}
+[0.1,"o","$ "]
+[0.5,"i","ls\r"]
+[0.6,"o","app\r\n"]
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		var passed bool
+		output := io_helpers.CaptureOutput(func() {
+			passed = runCommand(recordingPath, "--no-delay")
+		})
+
+		Expect(passed).To(BeTrue())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Replaying SSH session to instance 1 of app my-app (my-app-guid) in org my-org / space my-space by my-user"},
+			[]string{"End of recording"},
+		))
+		Expect(output).To(ContainElement("$ app\r"))
+	})
+
+	It("notifies users when the recording is invalid", func() {
+		err := ioutil.WriteFile(recordingPath, []byte(`{"version":1}`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(runCommand(recordingPath)).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Error reading recording", "Unsupported recording version 1"},
+		))
+	})
+})
@@ -1,16 +1,26 @@
 package application_test
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/appinstances/appinstancesfakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands/application"
 	"github.com/cloudfoundry/cli/cf/commands/commandsfakes"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/ssh/options"
 	"github.com/cloudfoundry/cli/cf/ssh/sshfakes"
 	"github.com/cloudfoundry/cli/testhelpers/cloudcontrollergateway"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
@@ -21,6 +31,7 @@ import (
 
 	. "github.com/cloudfoundry/cli/testhelpers/matchers"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -36,7 +47,9 @@ var _ = Describe("SSH command", func() {
 		deps                commandregistry.Dependency
 		ccGateway           net.Gateway
 
-		fakeSecureShell *sshfakes.FakeSecureShell
+		fakeSecureShell  *sshfakes.FakeSecureShell
+		appInstancesRepo *appinstancesfakes.FakeAppInstancesRepository
+		stderr           *bytes.Buffer
 	)
 
 	BeforeEach(func() {
@@ -44,6 +57,8 @@ var _ = Describe("SSH command", func() {
 		configRepo = testconfig.NewRepositoryWithDefaults()
 		requirementsFactory = &testreq.FakeReqFactory{}
 		deps.Gateways = make(map[string]net.Gateway)
+		appInstancesRepo = new(appinstancesfakes.FakeAppInstancesRepository)
+		stderr = &bytes.Buffer{}
 
 		//save original command and restore later
 		originalSSHCodeGetter = commandregistry.Commands.FindCommand("ssh-code")
@@ -65,11 +80,14 @@ var _ = Describe("SSH command", func() {
 	updateCommandDependency := func(pluginCall bool) {
 		deps.UI = ui
 		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetAppInstancesRepository(appInstancesRepo)
 
 		//inject fake 'sshCodeGetter' into registry
 		commandregistry.Register(sshCodeGetter)
 
-		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("ssh").SetDependency(deps, pluginCall))
+		cmd := commandregistry.Commands.FindCommand("ssh").SetDependency(deps, pluginCall).(*application.SSH)
+		cmd.Stderr = stderr
+		commandregistry.Commands.SetCommand(cmd)
 	}
 
 	runCommand := func(args ...string) bool {
@@ -289,6 +307,43 @@ var _ = Describe("SSH command", func() {
 				})
 			})
 
+			Context("Error port forwarding when -R is provided", func() {
+				It("notifies users", func() {
+					fakeSecureShell.RemotePortForwardReturns(errors.New("tcpip-forward request denied by peer"))
+
+					runCommand("my-app", "-R", "9999:localhost:5432")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Error forwarding remote port", "tcpip-forward request denied by peer"},
+					))
+					Expect(fakeSecureShell.InteractiveSessionCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when --reset-host-key is provided", func() {
+				It("asks the secure shell to replace the pinned host key", func() {
+					runCommand("my-app", "--reset-host-key")
+
+					Expect(fakeSecureShell.ConnectCallCount()).To(Equal(1))
+					opts := fakeSecureShell.ConnectArgsForCall(0)
+					Expect(opts.ResetHostKey).To(BeTrue())
+				})
+			})
+
+			Context("when -D and -R are provided", func() {
+				It("passes the forward specs to the secure shell", func() {
+					runCommand("my-app", "-N", "-D", "1080", "-R", "9999:localhost:5432")
+
+					Expect(fakeSecureShell.ConnectCallCount()).To(Equal(1))
+					opts := fakeSecureShell.ConnectArgsForCall(0)
+					Expect(opts.DynamicForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "localhost:1080"}))
+					Expect(opts.RemoteForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "localhost:9999", ConnectAddress: "localhost:5432"}))
+
+					Expect(fakeSecureShell.LocalPortForwardCallCount()).To(Equal(1))
+					Expect(fakeSecureShell.RemotePortForwardCallCount()).To(Equal(1))
+				})
+			})
+
 			Context("when -N is provided", func() {
 				It("calls secureShell.Wait()", func() {
 					fakeSecureShell.ConnectReturns(nil)
@@ -311,6 +366,257 @@ var _ = Describe("SSH command", func() {
 				})
 			})
 
+			Context("when --record is provided", func() {
+				var recordingPath string
+
+				BeforeEach(func() {
+					dir, err := ioutil.TempDir("", "ssh-recording")
+					Expect(err).NotTo(HaveOccurred())
+					recordingPath = filepath.Join(dir, "session.cast")
+
+					fakeSecureShell.InteractiveSessionStub = func() error {
+						recording := fakeSecureShell.RecordArgsForCall(0)
+						Expect(recording.Start(80, 24)).To(Succeed())
+						_, err := recording.Output().Write([]byte("hello\r\n"))
+						return err
+					}
+				})
+
+				AfterEach(func() {
+					os.RemoveAll(filepath.Dir(recordingPath))
+				})
+
+				It("records the session to the file", func() {
+					runCommand("my-app", "-i", "1", "--record", recordingPath)
+
+					Expect(fakeSecureShell.RecordCallCount()).To(Equal(1))
+					Expect(fakeSecureShell.InteractiveSessionCallCount()).To(Equal(1))
+
+					contents, err := ioutil.ReadFile(recordingPath)
+					Expect(err).NotTo(HaveOccurred())
+
+					lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+					Expect(lines).To(HaveLen(2))
+					Expect(lines[0]).To(ContainSubstring(`"user":"my-user"`))
+					Expect(lines[0]).To(ContainSubstring(`"app_guid":"my-app-guid"`))
+					Expect(lines[0]).To(ContainSubstring(`"instance":1`))
+					Expect(lines[1]).To(ContainSubstring(`"o","hello\r\n"]`))
+				})
+
+				It("notifies users when the file cannot be created", func() {
+					runCommand("my-app", "--record", filepath.Join(recordingPath, "missing", "session.cast"))
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Unable to record session"},
+					))
+					Expect(fakeSecureShell.InteractiveSessionCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when recording is required for the targeted space", func() {
+				var (
+					cfHome         string
+					originalCFHome string
+				)
+
+				BeforeEach(func() {
+					var err error
+					cfHome, err = ioutil.TempDir("", "cf-home")
+					Expect(err).NotTo(HaveOccurred())
+
+					originalCFHome = os.Getenv("CF_HOME")
+					os.Setenv("CF_HOME", cfHome)
+
+					configRepo.SetSSHRecordSpaces([]string{"my-org/my-space"})
+				})
+
+				AfterEach(func() {
+					os.Setenv("CF_HOME", originalCFHome)
+					os.RemoveAll(cfHome)
+				})
+
+				It("records the session to the recordings directory", func() {
+					runCommand("my-app")
+
+					Expect(fakeSecureShell.RecordCallCount()).To(Equal(1))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Recording session to", "ssh_recordings", "my-org/my-space"},
+					))
+
+					recordings, err := filepath.Glob(filepath.Join(cfHome, ".cf", "ssh_recordings", "my-app-0-*.cast"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(recordings).To(HaveLen(1))
+				})
+
+				It("refuses to run on all instances, as the sessions cannot be recorded", func() {
+					Expect(runCommand("my-app", "--all-instances", "-c", "df -h")).To(BeFalse())
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"SSH sessions in space my-org/my-space must be recorded", "cf ssh --all-instances"},
+					))
+					Expect(fakeSecureShell.ConnectCallCount()).To(Equal(0))
+				})
+
+				It("refuses to only forward ports, as the session cannot be recorded", func() {
+					Expect(runCommand("my-app", "-N", "-L", "8080:localhost:8080")).To(BeFalse())
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"SSH sessions in space my-org/my-space must be recorded", "cf ssh -N"},
+					))
+					Expect(fakeSecureShell.ConnectCallCount()).To(Equal(0))
+					Expect(fakeSecureShell.WaitCallCount()).To(Equal(0))
+				})
+
+				DescribeTable("refuses to forward ports next to the session, as the forwarded connections cannot be recorded",
+					func(flag string, spec string) {
+						Expect(runCommand("my-app", flag, spec)).To(BeFalse())
+
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{"FAILED"},
+							[]string{"SSH sessions in space my-org/my-space must be recorded", "cf ssh " + flag},
+						))
+						Expect(fakeSecureShell.ConnectCallCount()).To(Equal(0))
+						Expect(fakeSecureShell.InteractiveSessionCallCount()).To(Equal(0))
+					},
+					Entry("local forwarding", "-L", "8080:localhost:8080"),
+					Entry("dynamic forwarding", "-D", "1080"),
+					Entry("remote forwarding", "-R", "9090:localhost:9090"),
+				)
+
+				It("does not record sessions in other spaces", func() {
+					configRepo.SetSSHRecordSpaces([]string{"my-org/other-space"})
+
+					runCommand("my-app")
+
+					Expect(fakeSecureShell.RecordCallCount()).To(Equal(0))
+					Expect(fakeSecureShell.InteractiveSessionCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when --all-instances is provided", func() {
+				BeforeEach(func() {
+					appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{
+						{State: models.InstanceRunning},
+						{State: models.InstanceCrashed},
+						{State: models.InstanceRunning},
+					}, nil)
+
+					fakeSecureShell.CommandSessionStub = func(stdout io.Writer, stderr io.Writer) error {
+						fmt.Fprint(stdout, "Filesystem  Size\n/dev/sda1   10G")
+						fmt.Fprint(stderr, "df: warning\n")
+						return nil
+					}
+				})
+
+				It("runs the command on every running instance", func() {
+					Expect(runCommand("my-app", "--all-instances", "-c", "df -h")).To(BeTrue())
+
+					Expect(appInstancesRepo.GetInstancesCallCount()).To(Equal(1))
+					Expect(sshCodeGetter.GetCallCount()).To(Equal(2))
+					Expect(fakeSecureShell.ConnectCallCount()).To(Equal(2))
+
+					indices := []uint{}
+					for i := 0; i < fakeSecureShell.ConnectCallCount(); i++ {
+						opts := fakeSecureShell.ConnectArgsForCall(i)
+						Expect(opts.Command).To(Equal([]string{"df -h"}))
+						indices = append(indices, opts.Index)
+					}
+					Expect(indices).To(ConsistOf(uint(0), uint(2)))
+
+					Expect(fakeSecureShell.CommandSessionCallCount()).To(Equal(2))
+					Expect(fakeSecureShell.CloseCallCount()).To(Equal(2))
+					Expect(fakeSecureShell.InteractiveSessionCallCount()).To(Equal(0))
+				})
+
+				It("prefixes each line of output with the instance index", func() {
+					runCommand("my-app", "--all-instances", "-c", "df -h")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Running", "df -h", "2 instances", "my-app"},
+					))
+					for _, index := range []string{"[0] ", "[2] "} {
+						Expect(ui.Outputs).To(ContainElement(index + "Filesystem  Size"))
+						Expect(ui.Outputs).To(ContainElement(index + "/dev/sda1   10G"))
+					}
+				})
+
+				It("keeps the remote standard error on standard error", func() {
+					runCommand("my-app", "--all-instances", "-c", "df -h")
+
+					Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"df: warning"}))
+					lines := strings.Split(strings.TrimSuffix(stderr.String(), "\n"), "\n")
+					Expect(lines).To(ConsistOf("[0] df: warning", "[2] df: warning"))
+				})
+
+				It("prints a summary of the exit statuses", func() {
+					runCommand("my-app", "--all-instances", "-c", "df -h")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"instance", "exit status"},
+						[]string{"#0", "0"},
+						[]string{"#2", "0"},
+					))
+					Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"FAILED"}))
+				})
+
+				Context("when the command fails on some instances", func() {
+					BeforeEach(func() {
+						fakeSecureShell.ConnectStub = func(opts *options.SSHOptions) error {
+							if opts.Index == 2 {
+								return errors.New("dial error")
+							}
+							return nil
+						}
+					})
+
+					It("reports the failures and fails", func() {
+						Expect(runCommand("my-app", "--all-instances", "-c", "df -h")).To(BeFalse())
+
+						Expect(fakeSecureShell.CommandSessionCallCount()).To(Equal(1))
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{"#0", "0"},
+							[]string{"#2", "Error opening SSH connection", "dial error"},
+							[]string{"FAILED"},
+							[]string{"Command failed on 1 of 2 instances"},
+						))
+					})
+				})
+
+				Context("when the app has no running instances", func() {
+					BeforeEach(func() {
+						appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{
+							{State: models.InstanceCrashed},
+						}, nil)
+					})
+
+					It("fails without connecting", func() {
+						Expect(runCommand("my-app", "--all-instances", "-c", "df -h")).To(BeFalse())
+
+						Expect(fakeSecureShell.ConnectCallCount()).To(Equal(0))
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{"FAILED"},
+							[]string{"my-app", "has no running instances"},
+						))
+					})
+				})
+
+				Context("when getting the instances fails", func() {
+					BeforeEach(func() {
+						appInstancesRepo.GetInstancesReturns(nil, errors.New("instances error"))
+					})
+
+					It("notifies users", func() {
+						Expect(runCommand("my-app", "--all-instances", "-c", "df -h")).To(BeFalse())
+
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{"Error getting instances", "instances error"},
+						))
+					})
+				})
+			})
+
 			Context("when Wait() or InteractiveSession() returns error", func() {
 
 				It("notifities users", func() {
@@ -0,0 +1,162 @@
+package apply
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/featureflags"
+	"github.com/cloudfoundry/cli/cf/api/organizations"
+	"github.com/cloudfoundry/cli/cf/api/quotas"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups"
+	securitygroupspaces "github.com/cloudfoundry/cli/cf/api/securitygroups/spaces"
+	"github.com/cloudfoundry/cli/cf/api/spacequotas"
+	"github.com/cloudfoundry/cli/cf/api/spaces"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/foundation"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type Apply struct {
+	ui                  terminal.UI
+	config              coreconfig.Reader
+	quotaRepo           quotas.QuotaRepository
+	flagRepo            featureflags.FeatureFlagRepository
+	orgRepo             organizations.OrganizationRepository
+	spaceRepo           spaces.SpaceRepository
+	spaceQuotaRepo      spacequotas.SpaceQuotaRepository
+	securityGroupRepo   securitygroups.SecurityGroupRepo
+	securityGroupBinder securitygroupspaces.SecurityGroupSpaceBinder
+	userRepo            api.UserRepository
+}
+
+func init() {
+	commandregistry.Register(&Apply{})
+}
+
+func (cmd *Apply) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["f"] = &flags.StringFlag{ShortName: "f", Usage: T("Path to the file describing the orgs, spaces, quotas, roles and feature flags")}
+	fs["prune"] = &flags.BoolFlag{Name: "prune", Usage: T("Remove the spaces, space quotas, roles, security group bindings and space quota assignments that are not in the file")}
+	fs["dry-run"] = &flags.BoolFlag{Name: "dry-run", Usage: T("Print the changes without making them")}
+	fs["force"] = &flags.BoolFlag{Name: "force", Usage: T("Make the changes without confirmation when some of them are removals")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "apply",
+		Description: T("Change the orgs, spaces, quotas, roles and feature flags to match a file"),
+		Usage: []string{
+			T("CF_NAME apply -f FILE [--prune] [--dry-run] [--force]"),
+			"\n\n",
+			T("The changes are computed against the current state and printed before they are made. Role lists and security groups are only managed when they are given in the file."),
+			"\n\n",
+			T("With --prune, an empty list removes all the assignments. Orgs and org quotas that are not in the file are never deleted."),
+		},
+		Examples: []string{
+			"CF_NAME apply -f foundation.yml --dry-run",
+			"CF_NAME apply -f foundation.yml --prune",
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *Apply) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 0 || fc.String("f") == "" {
+		cmd.ui.Failed(T("Incorrect Usage. Requires the -f flag\n\n") + commandregistry.Commands.CommandUsage("apply"))
+	}
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+
+	return reqs
+}
+
+func (cmd *Apply) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.quotaRepo = deps.RepoLocator.GetQuotaRepository()
+	cmd.flagRepo = deps.RepoLocator.GetFeatureFlagRepository()
+	cmd.orgRepo = deps.RepoLocator.GetOrganizationRepository()
+	cmd.spaceRepo = deps.RepoLocator.GetSpaceRepository()
+	cmd.spaceQuotaRepo = deps.RepoLocator.GetSpaceQuotaRepository()
+	cmd.securityGroupRepo = deps.RepoLocator.GetSecurityGroupRepository()
+	cmd.securityGroupBinder = deps.RepoLocator.GetSecurityGroupSpaceBinder()
+	cmd.userRepo = deps.RepoLocator.GetUserRepository()
+	return cmd
+}
+
+func (cmd *Apply) Execute(c flags.FlagContext) error {
+	path := c.String("f")
+
+	f, err := foundation.Read(path)
+	if err != nil {
+		return errors.New(T("Error reading {{.Path}}: {{.Err}}", map[string]interface{}{"Path": path, "Err": err.Error()}))
+	}
+
+	cmd.ui.Say(T("Planning changes to match {{.Path}} as {{.Username}}...",
+		map[string]interface{}{
+			"Path":     terminal.EntityNameColor(path),
+			"Username": terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	planner := foundation.NewPlanner(cmd.quotaRepo, cmd.flagRepo, cmd.orgRepo, cmd.spaceRepo, cmd.spaceQuotaRepo,
+		cmd.securityGroupRepo, cmd.securityGroupBinder, cmd.userRepo, c.Bool("prune"))
+	changes, err := planner.Plan(f)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		cmd.ui.Ok()
+		cmd.ui.Say(T("No changes, the foundation already matches {{.Path}}", map[string]interface{}{"Path": path}))
+		return nil
+	}
+
+	deletes := 0
+	cmd.ui.Say("")
+	for _, change := range changes {
+		cmd.ui.Say("%s", colorChange(change))
+		if change.Action == foundation.DeleteAction {
+			deletes++
+		}
+	}
+	cmd.ui.Say("")
+
+	if c.Bool("dry-run") {
+		cmd.ui.Say(T("Dry run, nothing has been changed"))
+		return nil
+	}
+
+	if deletes > 0 && !c.Bool("force") {
+		if !cmd.ui.Confirm(T("Really make these changes, including {{.Count}} removals?", map[string]interface{}{"Count": deletes})) {
+			return nil
+		}
+	}
+
+	for _, change := range changes {
+		err = change.Apply()
+		if err != nil {
+			return errors.New(T("Error applying '{{.Change}}': {{.Err}}",
+				map[string]interface{}{"Change": change.Description, "Err": err.Error()}))
+		}
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("Applied {{.Count}} changes", map[string]interface{}{"Count": len(changes)}))
+	return nil
+}
+
+func colorChange(change foundation.Change) string {
+	line := change.Action + " " + change.Description
+	switch change.Action {
+	case foundation.CreateAction:
+		return terminal.SuccessColor(line)
+	case foundation.DeleteAction:
+		return terminal.FailureColor(line)
+	default:
+		return terminal.WarningColor(line)
+	}
+}
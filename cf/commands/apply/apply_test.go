@@ -0,0 +1,389 @@
+package apply_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/featureflags/featureflagsfakes"
+	"github.com/cloudfoundry/cli/cf/api/organizations/organizationsfakes"
+	"github.com/cloudfoundry/cli/cf/api/quotas/quotasfakes"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/securitygroupsfakes"
+	securitygroupspacesfakes "github.com/cloudfoundry/cli/cf/api/securitygroups/spaces/spacesfakes"
+	"github.com/cloudfoundry/cli/cf/api/spacequotas/spacequotasfakes"
+	"github.com/cloudfoundry/cli/cf/api/spaces/spacesfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("apply command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		config              coreconfig.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		quotaRepo           *quotasfakes.FakeQuotaRepository
+		flagRepo            *featureflagsfakes.FakeFeatureFlagRepository
+		orgRepo             *organizationsfakes.FakeOrganizationRepository
+		spaceRepo           *spacesfakes.FakeSpaceRepository
+		spaceQuotaRepo      *spacequotasfakes.FakeSpaceQuotaRepository
+		securityGroupRepo   *securitygroupsfakes.FakeSecurityGroupRepo
+		securityGroupBinder *securitygroupspacesfakes.FakeSecurityGroupSpaceBinder
+		userRepo            *apifakes.FakeUserRepository
+		deps                commandregistry.Dependency
+
+		dir  string
+		path string
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = config
+		deps.RepoLocator = deps.RepoLocator.SetQuotaRepository(quotaRepo)
+		deps.RepoLocator = deps.RepoLocator.SetFeatureFlagRepository(flagRepo)
+		deps.RepoLocator = deps.RepoLocator.SetOrganizationRepository(orgRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceRepository(spaceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceQuotaRepository(spaceQuotaRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSecurityGroupRepository(securityGroupRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSecurityGroupSpaceBinder(securityGroupBinder)
+		deps.RepoLocator = deps.RepoLocator.SetUserRepository(userRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("apply").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		quotaRepo = new(quotasfakes.FakeQuotaRepository)
+		flagRepo = new(featureflagsfakes.FakeFeatureFlagRepository)
+		orgRepo = new(organizationsfakes.FakeOrganizationRepository)
+		spaceRepo = new(spacesfakes.FakeSpaceRepository)
+		spaceQuotaRepo = new(spacequotasfakes.FakeSpaceQuotaRepository)
+		securityGroupRepo = new(securitygroupsfakes.FakeSecurityGroupRepo)
+		securityGroupBinder = new(securitygroupspacesfakes.FakeSecurityGroupSpaceBinder)
+		userRepo = new(apifakes.FakeUserRepository)
+
+		var err error
+		dir, err = ioutil.TempDir("", "apply")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "foundation.yml")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(contents string) {
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("apply", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("-f", path)).To(BeFalse())
+		})
+
+		It("fails with usage when not given a file", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires the -f flag"},
+			))
+		})
+	})
+
+	Context("when nothing exists yet", func() {
+		BeforeEach(func() {
+			write(`
+quotas:
+- name: small
+  memory_limit: 2G
+orgs:
+- name: my-org
+  quota: small
+  managers: [alice]
+  space_quotas:
+  - name: tiny
+    memory_limit: 512M
+  spaces:
+  - name: production
+    space_quota: tiny
+    allow_ssh: false
+    developers: [bob]
+    security_groups: [public-networks]
+`)
+
+			quotaRepo.FindByNameStub = func(name string) (models.QuotaFields, error) {
+				if quotaRepo.CreateCallCount() == 0 {
+					return models.QuotaFields{}, cferrors.NewModelNotFoundError("Quota", name)
+				}
+				return models.QuotaFields{GUID: "small-guid", Name: name}, nil
+			}
+			orgRepo.FindByNameStub = func(name string) (models.Organization, error) {
+				if orgRepo.CreateCallCount() == 0 {
+					return models.Organization{}, cferrors.NewModelNotFoundError("Organization", name)
+				}
+				org := models.Organization{}
+				org.GUID = "my-org-guid"
+				org.Name = name
+				return org, nil
+			}
+			spaceQuotaRepo.FindByNameAndOrgGUIDReturns(models.SpaceQuota{GUID: "tiny-guid", Name: "tiny"}, nil)
+			spaceRepo.CreateReturns(models.Space{SpaceFields: models.SpaceFields{GUID: "production-guid", Name: "production"}}, nil)
+			securityGroupRepo.ReadReturns(models.SecurityGroup{SecurityGroupFields: models.SecurityGroupFields{GUID: "public-networks-guid"}}, nil)
+		})
+
+		It("creates everything in order", func() {
+			Expect(runCommand("-f", path)).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Planning changes to match", path, "my-user"},
+				[]string{"+ create quota small"},
+				[]string{"+ create org my-org"},
+				[]string{"+ create space quota tiny in org my-org"},
+				[]string{"+ assign role OrgManager to alice in org my-org"},
+				[]string{"+ create space production in org my-org"},
+				[]string{"~ disallow SSH in space production in org my-org"},
+				[]string{"+ assign role SpaceDeveloper to bob in space production in org my-org"},
+				[]string{"+ bind security group public-networks to space production in org my-org"},
+				[]string{"OK"},
+				[]string{"Applied 8 changes"},
+			))
+			Expect(ui.Prompts).To(BeEmpty())
+
+			Expect(quotaRepo.CreateArgsForCall(0).MemoryLimit).To(Equal(int64(2048)))
+			Expect(orgRepo.CreateArgsForCall(0).QuotaDefinition.GUID).To(Equal("small-guid"))
+
+			spaceQuota := spaceQuotaRepo.CreateArgsForCall(0)
+			Expect(spaceQuota.Name).To(Equal("tiny"))
+			Expect(spaceQuota.OrgGUID).To(Equal("my-org-guid"))
+
+			username, orgGUID, role := userRepo.SetOrgRoleByUsernameArgsForCall(0)
+			Expect(username).To(Equal("alice"))
+			Expect(orgGUID).To(Equal("my-org-guid"))
+			Expect(role).To(Equal(models.RoleOrgManager))
+
+			name, orgGUID, spaceQuotaGUID := spaceRepo.CreateArgsForCall(0)
+			Expect(name).To(Equal("production"))
+			Expect(orgGUID).To(Equal("my-org-guid"))
+			Expect(spaceQuotaGUID).To(Equal("tiny-guid"))
+
+			spaceGUID, allow := spaceRepo.SetAllowSSHArgsForCall(0)
+			Expect(spaceGUID).To(Equal("production-guid"))
+			Expect(allow).To(BeFalse())
+
+			username, spaceGUID, orgGUID, role = userRepo.SetSpaceRoleByUsernameArgsForCall(0)
+			Expect(username).To(Equal("bob"))
+			Expect(spaceGUID).To(Equal("production-guid"))
+			Expect(orgGUID).To(Equal("my-org-guid"))
+			Expect(role).To(Equal(models.RoleSpaceDeveloper))
+
+			groupGUID, spaceGUID := securityGroupBinder.BindSpaceArgsForCall(0)
+			Expect(groupGUID).To(Equal("public-networks-guid"))
+			Expect(spaceGUID).To(Equal("production-guid"))
+		})
+
+		It("only prints the plan with --dry-run", func() {
+			Expect(runCommand("-f", path, "--dry-run")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"+ create org my-org"},
+				[]string{"Dry run, nothing has been changed"},
+			))
+			Expect(quotaRepo.CreateCallCount()).To(Equal(0))
+			Expect(orgRepo.CreateCallCount()).To(Equal(0))
+			Expect(spaceRepo.CreateCallCount()).To(Equal(0))
+		})
+
+		It("fails when a security group does not exist", func() {
+			securityGroupRepo.ReadReturns(models.SecurityGroup{}, cferrors.NewModelNotFoundError("security group", "public-networks"))
+
+			Expect(runCommand("-f", path)).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"public-networks", "not found"},
+			))
+			Expect(orgRepo.CreateCallCount()).To(Equal(0))
+		})
+
+		It("stops at the first change that fails", func() {
+			orgRepo.CreateReturns(errors.New("server error"))
+
+			Expect(runCommand("-f", path)).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Error applying 'create org my-org': server error"},
+			))
+			Expect(quotaRepo.CreateCallCount()).To(Equal(1))
+			Expect(spaceRepo.CreateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the org exists", func() {
+		BeforeEach(func() {
+			org := models.Organization{}
+			org.GUID = "my-org-guid"
+			org.Name = "my-org"
+			org.QuotaDefinition = models.QuotaFields{Name: "small"}
+			// the lists inlined in the org are capped by the server
+			org.Spaces = []models.SpaceFields{{GUID: "production-guid", Name: "production"}}
+			orgRepo.FindByNameReturns(org, nil)
+			quotaRepo.FindByNameReturns(models.QuotaFields{GUID: "small-guid", Name: "small"}, nil)
+
+			spaceQuotaRepo.FindByOrgReturns([]models.SpaceQuota{
+				{GUID: "old-guid", Name: "old", AppInstanceLimit: -1, InstanceMemoryLimit: -1},
+			}, nil)
+
+			production := models.Space{}
+			production.GUID = "production-guid"
+			production.Name = "production"
+			production.AllowSSH = true
+			production.SpaceQuotaGUID = "old-guid"
+			production.SecurityGroups = []models.SecurityGroupFields{{GUID: "legacy-guid", Name: "legacy"}}
+			staging := models.Space{}
+			staging.GUID = "staging-guid"
+			staging.Name = "staging"
+			spaceRepo.ListSpacesFromOrgStub = func(orgGUID string, callback func(models.Space) bool) error {
+				Expect(orgGUID).To(Equal("my-org-guid"))
+				for _, space := range []models.Space{production, staging} {
+					if !callback(space) {
+						break
+					}
+				}
+				return nil
+			}
+
+			userRepo.ListUsersInOrgForRoleWithNoUAAStub = func(orgGUID string, role models.Role) ([]models.UserFields, error) {
+				if role == models.RoleOrgManager {
+					return []models.UserFields{{Username: "alice"}, {Username: "mallory"}}, nil
+				}
+				return nil, nil
+			}
+
+			flagRepo.ListReturns([]models.FeatureFlag{{Name: "diego_docker", Enabled: false}}, nil)
+		})
+
+		It("says there is nothing to change when the foundation matches", func() {
+			write(`
+orgs:
+- name: my-org
+  quota: small
+  managers: [alice, mallory]
+  space_quotas:
+  - name: old
+  spaces:
+  - name: production
+    space_quota: old
+    allow_ssh: true
+    security_groups: [legacy]
+  - name: staging
+`)
+
+			Expect(runCommand("-f", path, "--prune")).To(BeTrue())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"OK"},
+				[]string{"No changes"},
+			))
+			Expect(spaceRepo.FindByNameInOrgCallCount()).To(Equal(0))
+		})
+
+		It("fails when a feature flag is unknown", func() {
+			write("feature_flags:\n  no_such_flag: true\n")
+
+			Expect(runCommand("-f", path)).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Unknown feature flag no_such_flag"},
+			))
+		})
+
+		Context("with --prune", func() {
+			BeforeEach(func() {
+				write(`
+feature_flags:
+  diego_docker: true
+orgs:
+- name: my-org
+  managers: [alice]
+  spaces:
+  - name: production
+    security_groups: []
+`)
+			})
+
+			It("removes what is not in the file after confirmation", func() {
+				ui.Inputs = []string{"y"}
+
+				Expect(runCommand("-f", path, "--prune")).To(BeTrue())
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"~ enable feature flag diego_docker"},
+					[]string{"- remove role OrgManager from mallory in org my-org"},
+					[]string{"- unassign the space quota of space production in org my-org"},
+					[]string{"- unbind security group legacy from space production in org my-org"},
+					[]string{"- delete space staging in org my-org"},
+					[]string{"- delete space quota old in org my-org"},
+					[]string{"Applied 6 changes"},
+				))
+				Expect(ui.Prompts).To(ContainSubstrings([]string{"Really make these changes", "5 removals"}))
+
+				name, enabled := flagRepo.UpdateArgsForCall(0)
+				Expect(name).To(Equal("diego_docker"))
+				Expect(enabled).To(BeTrue())
+
+				username, orgGUID, role := userRepo.UnsetOrgRoleByUsernameArgsForCall(0)
+				Expect(username).To(Equal("mallory"))
+				Expect(orgGUID).To(Equal("my-org-guid"))
+				Expect(role).To(Equal(models.RoleOrgManager))
+
+				spaceGUID, quotaGUID := spaceQuotaRepo.UnassignQuotaFromSpaceArgsForCall(0)
+				Expect(spaceGUID).To(Equal("production-guid"))
+				Expect(quotaGUID).To(Equal("old-guid"))
+
+				groupGUID, spaceGUID := securityGroupBinder.UnbindSpaceArgsForCall(0)
+				Expect(groupGUID).To(Equal("legacy-guid"))
+				Expect(spaceGUID).To(Equal("production-guid"))
+
+				Expect(spaceRepo.DeleteArgsForCall(0)).To(Equal("staging-guid"))
+				Expect(spaceQuotaRepo.DeleteArgsForCall(0)).To(Equal("old-guid"))
+				Expect(orgRepo.DeleteCallCount()).To(Equal(0))
+			})
+
+			It("changes nothing when the confirmation is declined", func() {
+				ui.Inputs = []string{"n"}
+
+				Expect(runCommand("-f", path, "--prune")).To(BeTrue())
+				Expect(flagRepo.UpdateCallCount()).To(Equal(0))
+				Expect(spaceRepo.DeleteCallCount()).To(Equal(0))
+			})
+
+			It("does not ask for confirmation with --force", func() {
+				Expect(runCommand("-f", path, "--prune", "--force")).To(BeTrue())
+				Expect(ui.Prompts).To(BeEmpty())
+				Expect(spaceRepo.DeleteCallCount()).To(Equal(1))
+			})
+
+			It("leaves the unlisted items alone without --prune", func() {
+				Expect(runCommand("-f", path)).To(BeTrue())
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"Applied 1 changes"}))
+				Expect(ui.Prompts).To(BeEmpty())
+				Expect(userRepo.UnsetOrgRoleByUsernameCallCount()).To(Equal(0))
+				Expect(spaceRepo.DeleteCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
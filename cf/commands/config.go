@@ -2,7 +2,9 @@ package commands
 
 import (
 	"errors"
+	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
@@ -28,12 +30,14 @@ func (cmd *ConfigCommands) MetaData() commandregistry.CommandMetadata {
 	fs["trace"] = &flags.StringFlag{Name: "trace", Usage: T("Trace HTTP requests")}
 	fs["color"] = &flags.StringFlag{Name: "color", Usage: T("Enable or disable color")}
 	fs["locale"] = &flags.StringFlag{Name: "locale", Usage: T("Set default locale. If LOCALE is 'CLEAR', previous locale is deleted.")}
+	fs["ssh-record-spaces"] = &flags.StringFlag{Name: "ssh-record-spaces", Usage: T("Comma separated list of ORG/SPACE in which every 'cf ssh' session is recorded, and in which SSH access that cannot be recorded, such as 'cf scp', is refused. If it is 'CLEAR', sessions are only recorded when requested.")}
+	fs["secret-pattern"] = &flags.StringSliceFlag{Name: "secret-pattern", Usage: T("Regular expression for the names of further keys whose values are hidden by 'cf env', 'cf service-key' and 'cf curl', flag can be specified multiple times. If it is 'CLEAR', only the default names are hidden.")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "config",
 		Description: T("Write default values to the config"),
 		Usage: []string{
-			T("CF_NAME config [--async-timeout TIMEOUT_IN_MINUTES] [--trace (true | false | path/to/file)] [--color (true | false)] [--locale (LOCALE | CLEAR)]"),
+			T("CF_NAME config [--async-timeout TIMEOUT_IN_MINUTES] [--trace (true | false | path/to/file)] [--color (true | false)] [--locale (LOCALE | CLEAR)] [--ssh-record-spaces (ORG/SPACE,... | CLEAR)] [--secret-pattern (PATTERN | CLEAR)]"),
 		},
 		Flags: fs,
 	}
@@ -51,7 +55,7 @@ func (cmd *ConfigCommands) SetDependency(deps commandregistry.Dependency, plugin
 }
 
 func (cmd *ConfigCommands) Execute(context flags.FlagContext) error {
-	if !context.IsSet("trace") && !context.IsSet("async-timeout") && !context.IsSet("color") && !context.IsSet("locale") {
+	if !context.IsSet("trace") && !context.IsSet("async-timeout") && !context.IsSet("color") && !context.IsSet("locale") && !context.IsSet("ssh-record-spaces") && !context.IsSet("secret-pattern") {
 		return errors.New(T("Incorrect Usage") + "\n\n" + commandregistry.Commands.CommandUsage("config"))
 	}
 
@@ -80,6 +84,39 @@ func (cmd *ConfigCommands) Execute(context flags.FlagContext) error {
 		}
 	}
 
+	if context.IsSet("ssh-record-spaces") {
+		value := context.String("ssh-record-spaces")
+		if value == "CLEAR" {
+			cmd.config.SetSSHRecordSpaces(nil)
+		} else {
+			spaces := []string{}
+			for _, space := range strings.Split(value, ",") {
+				space = strings.TrimSpace(space)
+				parts := strings.Split(space, "/")
+				if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+					return errors.New(T("Incorrect Usage") + "\n\n" + commandregistry.Commands.CommandUsage("config"))
+				}
+				spaces = append(spaces, space)
+			}
+			cmd.config.SetSSHRecordSpaces(spaces)
+		}
+	}
+
+	if context.IsSet("secret-pattern") {
+		patterns := context.StringSlice("secret-pattern")
+		if len(patterns) == 1 && patterns[0] == "CLEAR" {
+			cmd.config.SetSecretPatterns(nil)
+		} else {
+			for _, pattern := range patterns {
+				_, err := regexp.Compile(pattern)
+				if err != nil {
+					return errors.New(T("Invalid secret pattern '{{.Pattern}}': {{.Err}}", map[string]interface{}{"Pattern": pattern, "Err": err.Error()}))
+				}
+			}
+			cmd.config.SetSecretPatterns(patterns)
+		}
+	}
+
 	if context.IsSet("locale") {
 		locale := context.String("locale")
 
@@ -125,4 +125,46 @@ var _ = Describe("config command", func() {
 			})
 		})
 	})
+
+	Context("--ssh-record-spaces flag", func() {
+		It("stores the spaces in which ssh sessions are recorded", func() {
+			runCommand("--ssh-record-spaces", "prod-org/prod, other-org/live")
+			Expect(configRepo.SSHRecordSpaces()).To(Equal([]string{"prod-org/prod", "other-org/live"}))
+		})
+
+		It("clears the spaces when CLEAR is provided", func() {
+			configRepo.SetSSHRecordSpaces([]string{"prod-org/prod"})
+
+			runCommand("--ssh-record-spaces", "CLEAR")
+			Expect(configRepo.SSHRecordSpaces()).To(BeEmpty())
+		})
+
+		It("fails with usage when a space is not given as ORG/SPACE", func() {
+			runCommand("--ssh-record-spaces", "prod")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage"},
+			))
+		})
+	})
+	Context("--secret-pattern flag", func() {
+		It("stores the patterns of the names of further secrets", func() {
+			runCommand("--secret-pattern", "(?i)^db_", "--secret-pattern", "x{1,3}")
+			Expect(configRepo.SecretPatterns()).To(Equal([]string{"(?i)^db_", "x{1,3}"}))
+		})
+
+		It("clears the patterns when CLEAR is provided", func() {
+			configRepo.SetSecretPatterns([]string{"(?i)^db_"})
+
+			runCommand("--secret-pattern", "CLEAR")
+			Expect(configRepo.SecretPatterns()).To(BeEmpty())
+		})
+
+		It("fails when a pattern is not a valid regular expression", func() {
+			runCommand("--secret-pattern", "(")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Invalid secret pattern '('"},
+			))
+			Expect(configRepo.SecretPatterns()).To(BeEmpty())
+		})
+	})
 })
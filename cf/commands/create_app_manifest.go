@@ -2,9 +2,7 @@ package commands
 
 import (
 	"errors"
-	"fmt"
 	"os"
-	"sort"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/flags"
@@ -15,7 +13,6 @@ import (
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/manifest"
-	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
 )
@@ -101,7 +98,7 @@ func (cmd *CreateAppManifest) Execute(c flags.FlagContext) error {
 	}
 	defer f.Close()
 
-	err = cmd.createManifest(application)
+	err = manifest.AddApplication(cmd.manifest, application)
 	if err != nil {
 		return err
 	}
@@ -115,71 +112,3 @@ func (cmd *CreateAppManifest) Execute(c flags.FlagContext) error {
 	cmd.ui.Say("")
 	return nil
 }
-
-func (cmd *CreateAppManifest) createManifest(app models.Application) error {
-	cmd.manifest.Memory(app.Name, app.Memory)
-	cmd.manifest.Instances(app.Name, app.InstanceCount)
-	cmd.manifest.Stack(app.Name, app.Stack.Name)
-
-	if len(app.AppPorts) > 0 {
-		cmd.manifest.AppPorts(app.Name, app.AppPorts)
-	}
-
-	if app.Command != "" {
-		cmd.manifest.StartCommand(app.Name, app.Command)
-	}
-
-	if app.BuildpackURL != "" {
-		cmd.manifest.BuildpackURL(app.Name, app.BuildpackURL)
-	}
-
-	if len(app.Services) > 0 {
-		for _, service := range app.Services {
-			cmd.manifest.Service(app.Name, service.Name)
-		}
-	}
-
-	if app.HealthCheckTimeout > 0 {
-		cmd.manifest.HealthCheckTimeout(app.Name, app.HealthCheckTimeout)
-	}
-
-	if len(app.EnvironmentVars) > 0 {
-		sorted := sortEnvVar(app.EnvironmentVars)
-		for _, envVarKey := range sorted {
-			switch app.EnvironmentVars[envVarKey].(type) {
-			default:
-				return errors.New(T("Failed to create manifest, unable to parse environment variable: ") + envVarKey)
-			case float64:
-				//json.Unmarshal turn all numbers to float64
-				value := int(app.EnvironmentVars[envVarKey].(float64))
-				cmd.manifest.EnvironmentVars(app.Name, envVarKey, fmt.Sprintf("%d", value))
-			case bool:
-				cmd.manifest.EnvironmentVars(app.Name, envVarKey, fmt.Sprintf("%t", app.EnvironmentVars[envVarKey].(bool)))
-			case string:
-				cmd.manifest.EnvironmentVars(app.Name, envVarKey, app.EnvironmentVars[envVarKey].(string))
-			}
-		}
-	}
-
-	if len(app.Routes) > 0 {
-		for i := 0; i < len(app.Routes); i++ {
-			cmd.manifest.Domain(app.Name, app.Routes[i].Host, app.Routes[i].Domain.Name)
-		}
-	}
-
-	if app.DiskQuota != 0 {
-		cmd.manifest.DiskQuota(app.Name, app.DiskQuota)
-	}
-
-	return nil
-}
-
-func sortEnvVar(vars map[string]interface{}) []string {
-	var varsAry []string
-	for k := range vars {
-		varsAry = append(varsAry, k)
-	}
-	sort.Strings(varsAry)
-
-	return varsAry
-}
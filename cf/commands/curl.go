@@ -38,18 +38,22 @@ func (cmd *Curl) MetaData() commandregistry.CommandMetadata {
 	fs["H"] = &flags.StringSliceFlag{ShortName: "H", Usage: T("Custom headers to include in the request, flag can be specified multiple times")}
 	fs["d"] = &flags.StringFlag{ShortName: "d", Usage: T("HTTP data to include in the request body, or '@' followed by a file name to read the data from")}
 	fs["output"] = &flags.StringFlag{Name: "output", Usage: T("Write curl body to FILE instead of stdout")}
+	fs["show-secrets"] = &flags.BoolFlag{Name: "show-secrets", Usage: T("Show passwords, keys and other credentials in the response instead of hiding them")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "curl",
 		Description: T("Executes a request to the targeted API endpoint"),
 		Usage: []string{
-			T(`CF_NAME curl PATH [-iv] [-X METHOD] [-H HEADER] [-d DATA] [--output FILE]
+			T(`CF_NAME curl PATH [-iv] [-X METHOD] [-H HEADER] [-d DATA] [--output FILE] [--show-secrets]
 
    By default 'CF_NAME curl' will perform a GET to the specified PATH. If data
    is provided via -d, a POST will be performed instead, and the Content-Type
    will be set to application/json. You may override headers with -H and the
    request method with -X.
 
+   Credentials in the response are hidden unless --show-secrets is given or
+   the response is written to a file with --output.
+
    For API documentation, please visit http://apidocs.cloudfoundry.org.`),
 		},
 		Examples: []string{
@@ -126,6 +130,14 @@ func (cmd *Curl) Execute(c flags.FlagContext) error {
 			}
 		}
 
+		if !c.Bool("show-secrets") {
+			masker, err := trace.NewSecretMasker(cmd.config.SecretPatterns())
+			if err != nil {
+				return err
+			}
+			responseBody = masker.Mask(responseBody)
+		}
+
 		cmd.ui.Say(responseBody)
 	}
 	return nil
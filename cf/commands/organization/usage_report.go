@@ -0,0 +1,361 @@
+package organization
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api/organizations"
+	"github.com/cloudfoundry/cli/cf/api/usageevents"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+const (
+	usageReportDateFormat      = "2006-01-02"
+	managedServiceInstanceType = "managed_service_instance"
+)
+
+type UsageReport struct {
+	ui              terminal.UI
+	config          coreconfig.Reader
+	orgRepo         organizations.OrganizationRepository
+	usageEventsRepo usageevents.UsageEventsRepository
+}
+
+type spaceUsage struct {
+	OrgGUID             string  `json:"-"`
+	GUID                string  `json:"guid"`
+	Name                string  `json:"name"`
+	MemoryGBHours       float64 `json:"memory_gb_hours"`
+	ServiceInstanceDays float64 `json:"service_instance_days"`
+}
+
+type orgUsage struct {
+	GUID                string        `json:"guid"`
+	Name                string        `json:"name"`
+	MemoryGBHours       float64       `json:"memory_gb_hours"`
+	ServiceInstanceDays float64       `json:"service_instance_days"`
+	Spaces              []*spaceUsage `json:"spaces"`
+}
+
+type usageReport struct {
+	From time.Time   `json:"from"`
+	To   time.Time   `json:"to"`
+	Orgs []*orgUsage `json:"orgs"`
+}
+
+// usagePeriod is the time from which an app ran with the given memory or a
+// service instance existed, until the next event about it.
+type usagePeriod struct {
+	start     time.Time
+	orgGUID   string
+	spaceGUID string
+	memoryMB  int64
+}
+
+func init() {
+	commandregistry.Register(&UsageReport{})
+}
+
+func (cmd *UsageReport) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["from"] = &flags.StringFlag{Name: "from", Usage: T("Start of the report, as YYYY-MM-DD or an RFC 3339 time")}
+	fs["to"] = &flags.StringFlag{Name: "to", Usage: T("End of the report, as YYYY-MM-DD (inclusive) or an RFC 3339 time")}
+	fs["json"] = &flags.BoolFlag{Name: "json", Usage: T("Show the report as JSON instead of CSV")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "usage-report",
+		Description: T("Report the memory and service instance usage of every org and space"),
+		Usage: []string{
+			T("CF_NAME usage-report --from DATE --to DATE [--json]"),
+			"\n\n",
+			T("The usage is computed from the app and service usage events. Memory is reported in GB-hours of running app instances, and service instances in days of managed service instances. In CSV, the row of each org with an empty space holds the totals of the org."),
+			"\n\n",
+			T("Apps that were started and service instances that were created before the oldest usage event kept by Cloud Controller are not counted."),
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *UsageReport) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("No argument required"),
+		func() bool {
+			return len(fc.Args()) != 0
+		},
+	)
+
+	datesReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("--from and --to are required"),
+		func() bool {
+			return fc.String("from") == "" || fc.String("to") == ""
+		},
+	)
+
+	reqs := []requirements.Requirement{
+		usageReq,
+		datesReq,
+		requirementsFactory.NewLoginRequirement(),
+	}
+
+	return reqs
+}
+
+func (cmd *UsageReport) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.orgRepo = deps.RepoLocator.GetOrganizationRepository()
+	cmd.usageEventsRepo = deps.RepoLocator.GetUsageEventsRepository()
+	return cmd
+}
+
+func (cmd *UsageReport) Execute(c flags.FlagContext) error {
+	from, err := parseReportTime(c.String("from"), false)
+	if err != nil {
+		return err
+	}
+	to, err := parseReportTime(c.String("to"), true)
+	if err != nil {
+		return err
+	}
+	if !to.After(from) {
+		return errors.New(T("--to must be after --from"))
+	}
+
+	report, err := cmd.buildReport(from, to)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.ui.Say("%s", string(jsonBytes))
+		return nil
+	}
+
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	_ = writer.Write([]string{"org", "space", "memory_gb_hours", "service_instance_days"})
+	for _, org := range report.Orgs {
+		_ = writer.Write([]string{org.Name, "", formatUsage(org.MemoryGBHours), formatUsage(org.ServiceInstanceDays)})
+		for _, space := range org.Spaces {
+			_ = writer.Write([]string{org.Name, space.Name, formatUsage(space.MemoryGBHours), formatUsage(space.ServiceInstanceDays)})
+		}
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return err
+	}
+
+	cmd.ui.Say("%s", strings.TrimSuffix(buffer.String(), "\n"))
+	return nil
+}
+
+func (cmd *UsageReport) buildReport(from time.Time, to time.Time) (usageReport, error) {
+	end := to
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+
+	spacesByGUID := map[string]*spaceUsage{}
+	spaceFor := func(orgGUID string, spaceGUID string) *spaceUsage {
+		space, found := spacesByGUID[spaceGUID]
+		if !found {
+			space = &spaceUsage{OrgGUID: orgGUID, GUID: spaceGUID, Name: spaceGUID}
+			spacesByGUID[spaceGUID] = space
+		}
+		return space
+	}
+
+	// overlap is the part of the period, up to the given time, that falls
+	// within the report.
+	overlap := func(period usagePeriod, until time.Time) time.Duration {
+		start, stop := period.start, until
+		if start.Before(from) {
+			start = from
+		}
+		if stop.After(end) {
+			stop = end
+		}
+		if !stop.After(start) {
+			return 0
+		}
+		return stop.Sub(start)
+	}
+
+	runningApps := map[string]usagePeriod{}
+	stopApp := func(appGUID string, at time.Time) {
+		period, found := runningApps[appGUID]
+		if !found {
+			return
+		}
+		delete(runningApps, appGUID)
+
+		if duration := overlap(period, at); duration > 0 {
+			space := spaceFor(period.orgGUID, period.spaceGUID)
+			space.MemoryGBHours += float64(period.memoryMB) / 1024 * duration.Hours()
+		}
+	}
+
+	err := cmd.usageEventsRepo.ListAppUsageEvents(func(event models.AppUsageEvent) bool {
+		if !event.CreatedAt.Before(end) {
+			return false
+		}
+
+		if event.SpaceName != "" {
+			spaceFor(event.OrgGUID, event.SpaceGUID).Name = event.SpaceName
+		}
+
+		switch event.State {
+		case "STARTED":
+			stopApp(event.AppGUID, event.CreatedAt)
+			runningApps[event.AppGUID] = usagePeriod{
+				start:     event.CreatedAt,
+				orgGUID:   event.OrgGUID,
+				spaceGUID: event.SpaceGUID,
+				memoryMB:  int64(event.InstanceCount) * event.MemoryInMBPerInstance,
+			}
+		case "STOPPED":
+			stopApp(event.AppGUID, event.CreatedAt)
+		}
+		return true
+	})
+	if err != nil {
+		return usageReport{}, err
+	}
+	for appGUID := range runningApps {
+		stopApp(appGUID, end)
+	}
+
+	serviceInstances := map[string]usagePeriod{}
+	deleteServiceInstance := func(instanceGUID string, at time.Time) {
+		period, found := serviceInstances[instanceGUID]
+		if !found {
+			return
+		}
+		delete(serviceInstances, instanceGUID)
+
+		if duration := overlap(period, at); duration > 0 {
+			space := spaceFor(period.orgGUID, period.spaceGUID)
+			space.ServiceInstanceDays += duration.Hours() / 24
+		}
+	}
+
+	err = cmd.usageEventsRepo.ListServiceUsageEvents(func(event models.ServiceUsageEvent) bool {
+		if !event.CreatedAt.Before(end) {
+			return false
+		}
+		if event.ServiceInstanceType != managedServiceInstanceType {
+			return true
+		}
+
+		if event.SpaceName != "" {
+			spaceFor(event.OrgGUID, event.SpaceGUID).Name = event.SpaceName
+		}
+
+		switch event.State {
+		case "CREATED":
+			if _, found := serviceInstances[event.ServiceInstanceGUID]; !found {
+				serviceInstances[event.ServiceInstanceGUID] = usagePeriod{
+					start:     event.CreatedAt,
+					orgGUID:   event.OrgGUID,
+					spaceGUID: event.SpaceGUID,
+				}
+			}
+		case "DELETED":
+			deleteServiceInstance(event.ServiceInstanceGUID, event.CreatedAt)
+		}
+		return true
+	})
+	if err != nil {
+		return usageReport{}, err
+	}
+	for instanceGUID := range serviceInstances {
+		deleteServiceInstance(instanceGUID, end)
+	}
+
+	orgs, err := cmd.orgRepo.ListOrgs(orgLimit)
+	if err != nil {
+		return usageReport{}, err
+	}
+	orgNames := map[string]string{}
+	for _, org := range orgs {
+		orgNames[org.GUID] = org.Name
+	}
+
+	report := usageReport{From: from, To: to, Orgs: []*orgUsage{}}
+	orgsByGUID := map[string]*orgUsage{}
+	for _, space := range spacesByGUID {
+		if space.MemoryGBHours == 0 && space.ServiceInstanceDays == 0 {
+			continue
+		}
+
+		org, found := orgsByGUID[space.OrgGUID]
+		if !found {
+			org = &orgUsage{GUID: space.OrgGUID, Name: space.OrgGUID, Spaces: []*spaceUsage{}}
+			if name, found := orgNames[space.OrgGUID]; found {
+				org.Name = name
+			}
+			orgsByGUID[space.OrgGUID] = org
+			report.Orgs = append(report.Orgs, org)
+		}
+
+		org.MemoryGBHours += space.MemoryGBHours
+		org.ServiceInstanceDays += space.ServiceInstanceDays
+		org.Spaces = append(org.Spaces, space)
+	}
+
+	sort.Sort(orgUsagesByName(report.Orgs))
+	for _, org := range report.Orgs {
+		sort.Sort(spaceUsagesByName(org.Spaces))
+	}
+
+	return report, nil
+}
+
+// parseReportTime accepts a date, which is taken as the start of the day in
+// UTC or, with endOfDay, as the end of the day, or an RFC 3339 time.
+func parseReportTime(value string, endOfDay bool) (time.Time, error) {
+	if date, err := time.Parse(usageReportDateFormat, value); err == nil {
+		if endOfDay {
+			date = date.AddDate(0, 0, 1)
+		}
+		return date, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New(T("Invalid date {{.Date}}: use YYYY-MM-DD or an RFC 3339 time", map[string]interface{}{"Date": value}))
+	}
+	return parsed.UTC(), nil
+}
+
+func formatUsage(value float64) string {
+	return fmt.Sprintf("%.2f", value)
+}
+
+type orgUsagesByName []*orgUsage
+
+func (orgs orgUsagesByName) Len() int           { return len(orgs) }
+func (orgs orgUsagesByName) Swap(i, j int)      { orgs[i], orgs[j] = orgs[j], orgs[i] }
+func (orgs orgUsagesByName) Less(i, j int) bool { return orgs[i].Name < orgs[j].Name }
+
+type spaceUsagesByName []*spaceUsage
+
+func (spaces spaceUsagesByName) Len() int           { return len(spaces) }
+func (spaces spaceUsagesByName) Swap(i, j int)      { spaces[i], spaces[j] = spaces[j], spaces[i] }
+func (spaces spaceUsagesByName) Less(i, j int) bool { return spaces[i].Name < spaces[j].Name }
@@ -0,0 +1,230 @@
+package organization_test
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api/organizations/organizationsfakes"
+	"github.com/cloudfoundry/cli/cf/api/usageevents/usageeventsfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/flags"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("usage-report command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		config              coreconfig.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		orgRepo             *organizationsfakes.FakeOrganizationRepository
+		usageEventsRepo     *usageeventsfakes.FakeUsageEventsRepository
+		appEvents           []models.AppUsageEvent
+		serviceEvents       []models.ServiceUsageEvent
+		deps                commandregistry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = config
+		deps.RepoLocator = deps.RepoLocator.SetOrganizationRepository(orgRepo)
+		deps.RepoLocator = deps.RepoLocator.SetUsageEventsRepository(usageEventsRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("usage-report").SetDependency(deps, pluginCall))
+	}
+
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		Expect(err).NotTo(HaveOccurred())
+		return parsed
+	}
+
+	appEvent := func(state string, timestamp string, appGUID string, spaceName string, instances int, memory int64) models.AppUsageEvent {
+		return models.AppUsageEvent{
+			State:                 state,
+			CreatedAt:             at(timestamp),
+			AppGUID:               appGUID,
+			SpaceGUID:             spaceName + "-guid",
+			SpaceName:             spaceName,
+			OrgGUID:               "org-one-guid",
+			InstanceCount:         instances,
+			MemoryInMBPerInstance: memory,
+		}
+	}
+
+	serviceEvent := func(state string, timestamp string, instanceGUID string, instanceType string, spaceName string) models.ServiceUsageEvent {
+		return models.ServiceUsageEvent{
+			State:               state,
+			CreatedAt:           at(timestamp),
+			ServiceInstanceGUID: instanceGUID,
+			ServiceInstanceType: instanceType,
+			SpaceGUID:           spaceName + "-guid",
+			SpaceName:           spaceName,
+			OrgGUID:             "org-one-guid",
+		}
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		orgRepo = new(organizationsfakes.FakeOrganizationRepository)
+		usageEventsRepo = new(usageeventsfakes.FakeUsageEventsRepository)
+
+		org := models.Organization{}
+		org.GUID = "org-one-guid"
+		org.Name = "org-one"
+		orgRepo.ListOrgsReturns([]models.Organization{org}, nil)
+
+		appEvents = []models.AppUsageEvent{
+			appEvent("STARTED", "2016-04-01T00:00:00Z", "old-app-guid", "old", 1, 1024),
+			appEvent("STOPPED", "2016-04-02T00:00:00Z", "old-app-guid", "old", 1, 1024),
+			appEvent("STARTED", "2016-04-30T12:00:00Z", "app-guid", "dev", 2, 512),
+			appEvent("STOPPED", "2016-05-01T12:00:00Z", "app-guid", "dev", 2, 512),
+			appEvent("STARTED", "2016-05-01T18:00:00Z", "app-guid", "dev", 1, 2048),
+			appEvent("STARTED", "2016-05-02T00:00:00Z", "other-app-guid", "prod", 1, 1024),
+			appEvent("BUILDPACK_SET", "2016-05-02T01:00:00Z", "other-app-guid", "prod", 1, 1024),
+			appEvent("STOPPED", "2016-05-02T06:00:00Z", "other-app-guid", "prod", 1, 1024),
+			appEvent("STARTED", "2016-05-03T00:00:00Z", "late-app-guid", "prod", 10, 1024),
+		}
+		usageEventsRepo.ListAppUsageEventsStub = func(cb func(models.AppUsageEvent) bool) error {
+			for _, event := range appEvents {
+				if !cb(event) {
+					break
+				}
+			}
+			return nil
+		}
+
+		serviceEvents = []models.ServiceUsageEvent{
+			serviceEvent("CREATED", "2016-04-30T00:00:00Z", "db-guid", "managed_service_instance", "dev"),
+			serviceEvent("CREATED", "2016-04-30T00:00:00Z", "ups-guid", "user_provided_service_instance", "dev"),
+			serviceEvent("DELETED", "2016-05-02T00:00:00Z", "db-guid", "managed_service_instance", "dev"),
+			serviceEvent("CREATED", "2016-05-02T12:00:00Z", "cache-guid", "managed_service_instance", "prod"),
+		}
+		usageEventsRepo.ListServiceUsageEventsStub = func(cb func(models.ServiceUsageEvent) bool) error {
+			for _, event := range serviceEvents {
+				if !cb(event) {
+					break
+				}
+			}
+			return nil
+		}
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("usage-report", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		var (
+			cmd         commandregistry.Command
+			flagContext flags.FlagContext
+		)
+
+		BeforeEach(func() {
+			cmd = commandregistry.Commands.FindCommand("usage-report")
+			flagContext = flags.NewFlagContext(cmd.MetaData().Flags)
+		})
+
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("--from", "2016-05-01", "--to", "2016-05-02")).To(BeFalse())
+		})
+
+		It("fails with usage when --from or --to is missing", func() {
+			Expect(flagContext.Parse("--from", "2016-05-01")).To(Succeed())
+			reqs := cmd.Requirements(requirementsFactory, flagContext)
+
+			err := testcmd.RunRequirements(reqs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("--from and --to are required"))
+		})
+
+		It("fails with usage when given arguments", func() {
+			Expect(flagContext.Parse("--from", "2016-05-01", "--to", "2016-05-02", "blahblah")).To(Succeed())
+			reqs := cmd.Requirements(requirementsFactory, flagContext)
+
+			err := testcmd.RunRequirements(reqs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("No argument required"))
+		})
+	})
+
+	It("reports the usage of every org and space as CSV", func() {
+		Expect(runCommand("--from", "2016-05-01", "--to", "2016-05-02")).To(BeTrue())
+
+		Expect(ui.Outputs).To(Equal([]string{
+			"org,space,memory_gb_hours,service_instance_days",
+			"org-one,,78.00,1.50",
+			"org-one,dev,72.00,1.00",
+			"org-one,prod,6.00,0.50",
+		}))
+	})
+
+	It("reports the usage as JSON", func() {
+		Expect(runCommand("--from", "2016-05-01T12:00:00Z", "--to", "2016-05-02", "--json")).To(BeTrue())
+
+		var report map[string]interface{}
+		Expect(json.Unmarshal([]byte(strings.Join(ui.Outputs, "\n")), &report)).To(Succeed())
+
+		Expect(report["from"]).To(Equal("2016-05-01T12:00:00Z"))
+		Expect(report["to"]).To(Equal("2016-05-03T00:00:00Z"))
+
+		orgs := report["orgs"].([]interface{})
+		Expect(orgs).To(HaveLen(1))
+		org := orgs[0].(map[string]interface{})
+		Expect(org["name"]).To(Equal("org-one"))
+		Expect(org["memory_gb_hours"]).To(Equal(float64(66)))
+		Expect(org["service_instance_days"]).To(Equal(float64(1)))
+
+		spaces := org["spaces"].([]interface{})
+		Expect(spaces).To(HaveLen(2))
+		Expect(spaces[0]).To(Equal(map[string]interface{}{
+			"guid":                  "dev-guid",
+			"name":                  "dev",
+			"memory_gb_hours":       float64(60),
+			"service_instance_days": 0.5,
+		}))
+	})
+
+	It("names orgs that no longer exist by their GUID", func() {
+		orgRepo.ListOrgsReturns([]models.Organization{}, nil)
+
+		Expect(runCommand("--from", "2016-05-01", "--to", "2016-05-02")).To(BeTrue())
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"org-one-guid,dev,72.00,1.00"}))
+	})
+
+	It("fails when a date is invalid", func() {
+		Expect(runCommand("--from", "May 1st", "--to", "2016-05-02")).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Invalid date May 1st"},
+		))
+	})
+
+	It("fails when --to is not after --from", func() {
+		Expect(runCommand("--from", "2016-05-03", "--to", "2016-05-01")).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"--to must be after --from"}))
+	})
+
+	It("fails when the usage events cannot be listed", func() {
+		usageEventsRepo.ListAppUsageEventsReturns(errors.New("not authorized"))
+		usageEventsRepo.ListAppUsageEventsStub = nil
+
+		Expect(runCommand("--from", "2016-05-01", "--to", "2016-05-02")).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"not authorized"},
+		))
+	})
+})
@@ -0,0 +1,354 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/quotas"
+	"github.com/cloudfoundry/cli/cf/api/spacequotas"
+	"github.com/cloudfoundry/cli/cf/api/spaces"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+const defaultUsageThreshold = 80
+
+// quotaLimits holds the limits of an org or space quota, where -1 means
+// unlimited as in CC.
+type quotaLimits struct {
+	Name      string `json:"name"`
+	MemoryMB  int64  `json:"memory_mb"`
+	Instances int    `json:"instances"`
+	Services  int    `json:"services"`
+	Routes    int    `json:"routes"`
+}
+
+type quotaUsed struct {
+	MemoryMB  int64 `json:"memory_mb"`
+	Instances int   `json:"instances"`
+	Services  int   `json:"services"`
+	Routes    int   `json:"routes"`
+}
+
+func (used *quotaUsed) add(other quotaUsed) {
+	used.MemoryMB += other.MemoryMB
+	used.Instances += other.Instances
+	used.Services += other.Services
+	used.Routes += other.Routes
+}
+
+type spaceQuotaUsage struct {
+	Name       string       `json:"name"`
+	Quota      *quotaLimits `json:"quota,omitempty"`
+	Used       quotaUsed    `json:"used"`
+	NearLimits []string     `json:"near_limits"`
+}
+
+type orgQuotaUsage struct {
+	Org        string            `json:"org"`
+	Quota      quotaLimits       `json:"quota"`
+	Used       quotaUsed         `json:"used"`
+	NearLimits []string          `json:"near_limits"`
+	Spaces     []spaceQuotaUsage `json:"spaces"`
+}
+
+type QuotaUsage struct {
+	ui             terminal.UI
+	config         coreconfig.Reader
+	quotaRepo      quotas.QuotaRepository
+	spaceQuotaRepo spacequotas.SpaceQuotaRepository
+	spaceRepo      spaces.SpaceRepository
+	appSummaryRepo api.AppSummaryRepository
+	routeRepo      api.RouteRepository
+	orgReq         requirements.OrganizationRequirement
+}
+
+func init() {
+	commandregistry.Register(&QuotaUsage{})
+}
+
+func (cmd *QuotaUsage) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["threshold"] = &flags.IntFlag{Name: "threshold", Usage: T("Percentage of a limit from which a resource is reported as near its limit (Default: 80)")}
+	fs["json"] = &flags.BoolFlag{Name: "json", Usage: T("Show the usage as JSON")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "quota-usage",
+		Description: T("Show how much of the org quota and of its space quotas is used"),
+		Usage: []string{
+			T("CF_NAME quota-usage ORG [--threshold PERCENT] [--json]"),
+			"\n\n",
+			T("Memory is the memory of the instances of started apps. Services do not include user-provided service instances."),
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *QuotaUsage) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("quota-usage"))
+	}
+
+	cmd.orgReq = requirementsFactory.NewOrganizationRequirement(fc.Args()[0])
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		cmd.orgReq,
+	}
+
+	return reqs
+}
+
+func (cmd *QuotaUsage) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.quotaRepo = deps.RepoLocator.GetQuotaRepository()
+	cmd.spaceQuotaRepo = deps.RepoLocator.GetSpaceQuotaRepository()
+	cmd.spaceRepo = deps.RepoLocator.GetSpaceRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.routeRepo = deps.RepoLocator.GetRouteRepository()
+	return cmd
+}
+
+func (cmd *QuotaUsage) Execute(c flags.FlagContext) error {
+	org := cmd.orgReq.GetOrganization()
+
+	threshold := defaultUsageThreshold
+	if c.IsSet("threshold") {
+		threshold = c.Int("threshold")
+	}
+
+	if !c.Bool("json") {
+		cmd.ui.Say(T("Getting quota usage of org {{.OrgName}} as {{.Username}}...",
+			map[string]interface{}{
+				"OrgName":  terminal.EntityNameColor(org.Name),
+				"Username": terminal.EntityNameColor(cmd.config.Username()),
+			}))
+	}
+
+	usage, err := cmd.collect(org, threshold)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		jsonBytes, err := json.MarshalIndent(usage, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.ui.Say("%s", string(jsonBytes))
+		return nil
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	cmd.ui.Say(T("org quota: {{.QuotaName}}", map[string]interface{}{"QuotaName": terminal.EntityNameColor(usage.Quota.Name)}))
+	table := cmd.ui.Table([]string{T("resource"), T("used"), T("limit"), T("usage")})
+	for _, row := range usageRows(usage.Quota, usage.Used) {
+		table.Add(row.name, row.used, row.limit, colorUsage(row.percent, threshold))
+	}
+	table.Print()
+	cmd.ui.Say("")
+
+	if len(usage.Spaces) == 0 {
+		cmd.ui.Say(T("No spaces found"))
+		return nil
+	}
+
+	table = cmd.ui.Table([]string{T("space"), T("space quota"), T("memory"), T("instances"), T("services"), T("routes"), T("near limits")})
+	for _, space := range usage.Spaces {
+		if space.Quota == nil {
+			table.Add(space.Name, "",
+				formatMemory(space.Used.MemoryMB), strconv.Itoa(space.Used.Instances),
+				strconv.Itoa(space.Used.Services), strconv.Itoa(space.Used.Routes), "")
+			continue
+		}
+
+		cells := []string{space.Name, space.Quota.Name}
+		for _, row := range usageRows(*space.Quota, space.Used) {
+			cells = append(cells, fmt.Sprintf("%s / %s", row.used, row.limit))
+		}
+		cells = append(cells, terminal.WarningColor(strings.Join(space.NearLimits, ", ")))
+		table.Add(cells...)
+	}
+	table.Print()
+
+	return nil
+}
+
+func (cmd *QuotaUsage) collect(org models.Organization, threshold int) (orgQuotaUsage, error) {
+	orgQuota, err := cmd.quotaRepo.FindByName(org.QuotaDefinition.Name)
+	if err != nil {
+		return orgQuotaUsage{}, err
+	}
+
+	spaceQuotas, err := cmd.spaceQuotaRepo.FindByOrg(org.GUID)
+	if err != nil {
+		return orgQuotaUsage{}, err
+	}
+
+	spaceQuotasByGUID := map[string]models.SpaceQuota{}
+	for _, spaceQuota := range spaceQuotas {
+		spaceQuotasByGUID[spaceQuota.GUID] = spaceQuota
+	}
+
+	usage := orgQuotaUsage{
+		Org: org.Name,
+		Quota: quotaLimits{
+			Name:      orgQuota.Name,
+			MemoryMB:  orgQuota.MemoryLimit,
+			Instances: orgQuota.AppInstanceLimit,
+			Services:  orgQuota.ServicesLimit,
+			Routes:    orgQuota.RoutesLimit,
+		},
+		Spaces: []spaceQuotaUsage{},
+	}
+
+	spaces := []models.Space{}
+	err = cmd.spaceRepo.ListSpacesFromOrg(org.GUID, func(space models.Space) bool {
+		spaces = append(spaces, space)
+		return true
+	})
+	if err != nil {
+		return orgQuotaUsage{}, err
+	}
+
+	for _, space := range spaces {
+		used, err := cmd.usedBySpace(space.GUID)
+		if err != nil {
+			return orgQuotaUsage{}, err
+		}
+		usage.Used.add(used)
+
+		spaceUsage := spaceQuotaUsage{Name: space.Name, Used: used, NearLimits: []string{}}
+		if spaceQuota, found := spaceQuotasByGUID[space.SpaceQuotaGUID]; found {
+			spaceUsage.Quota = &quotaLimits{
+				Name:      spaceQuota.Name,
+				MemoryMB:  spaceQuota.MemoryLimit,
+				Instances: spaceQuota.AppInstanceLimit,
+				Services:  spaceQuota.ServicesLimit,
+				Routes:    spaceQuota.RoutesLimit,
+			}
+			spaceUsage.NearLimits = nearLimits(*spaceUsage.Quota, used, threshold)
+		}
+		usage.Spaces = append(usage.Spaces, spaceUsage)
+	}
+
+	usage.NearLimits = nearLimits(usage.Quota, usage.Used, threshold)
+	return usage, nil
+}
+
+func (cmd *QuotaUsage) usedBySpace(spaceGUID string) (quotaUsed, error) {
+	used := quotaUsed{}
+
+	apps, serviceInstances, err := cmd.appSummaryRepo.GetSpaceSummary(spaceGUID)
+	if err != nil {
+		return used, err
+	}
+	for _, app := range apps {
+		if app.State == "started" {
+			used.Instances += app.InstanceCount
+			used.MemoryMB += int64(app.InstanceCount) * app.Memory
+		}
+	}
+
+	for _, serviceInstance := range serviceInstances {
+		if !serviceInstance.IsUserProvided() {
+			used.Services++
+		}
+	}
+
+	err = cmd.routeRepo.ListRoutesInSpace(spaceGUID, func(models.Route) bool {
+		used.Routes++
+		return true
+	})
+	if err != nil {
+		return used, err
+	}
+
+	return used, nil
+}
+
+type usageRow struct {
+	name    string
+	used    string
+	limit   string
+	percent int
+}
+
+// usageRows has a percent of -1 for the unlimited resources.
+func usageRows(limits quotaLimits, used quotaUsed) []usageRow {
+	return []usageRow{
+		{T("memory"), formatMemory(used.MemoryMB), formatMemoryLimit(limits.MemoryMB), percentOf(used.MemoryMB, limits.MemoryMB)},
+		{T("instances"), strconv.Itoa(used.Instances), formatLimit(limits.Instances), percentOf(int64(used.Instances), int64(limits.Instances))},
+		{T("services"), strconv.Itoa(used.Services), formatLimit(limits.Services), percentOf(int64(used.Services), int64(limits.Services))},
+		{T("routes"), strconv.Itoa(used.Routes), formatLimit(limits.Routes), percentOf(int64(used.Routes), int64(limits.Routes))},
+	}
+}
+
+func nearLimits(limits quotaLimits, used quotaUsed, threshold int) []string {
+	names := []string{}
+	for _, row := range usageRows(limits, used) {
+		if row.percent >= threshold {
+			names = append(names, row.name)
+		}
+	}
+	return names
+}
+
+func percentOf(used int64, limit int64) int {
+	switch {
+	case limit < 0:
+		return -1
+	case limit == 0 && used == 0:
+		return 0
+	case limit == 0:
+		return 100
+	default:
+		return int(used * 100 / limit)
+	}
+}
+
+func colorUsage(percent int, threshold int) string {
+	if percent < 0 {
+		return ""
+	}
+
+	formatted := fmt.Sprintf("%d%%", percent)
+	switch {
+	case percent >= 100:
+		return terminal.FailureColor(formatted)
+	case percent >= threshold:
+		return terminal.WarningColor(formatted)
+	default:
+		return formatted
+	}
+}
+
+func formatMemory(megabytes int64) string {
+	return formatters.ByteSize(megabytes * formatters.MEGABYTE)
+}
+
+func formatMemoryLimit(megabytes int64) string {
+	if megabytes < 0 {
+		return T("unlimited")
+	}
+	return formatMemory(megabytes)
+}
+
+func formatLimit(limit int) string {
+	if limit < 0 {
+		return T("unlimited")
+	}
+	return strconv.Itoa(limit)
+}
@@ -0,0 +1,214 @@
+package quota_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/quotas/quotasfakes"
+	"github.com/cloudfoundry/cli/cf/api/spacequotas/spacequotasfakes"
+	"github.com/cloudfoundry/cli/cf/api/spaces/spacesfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("quota-usage command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		requirementsFactory *testreq.FakeReqFactory
+		config              coreconfig.Repository
+		quotaRepo           *quotasfakes.FakeQuotaRepository
+		spaceQuotaRepo      *spacequotasfakes.FakeSpaceQuotaRepository
+		spaceRepo           *spacesfakes.FakeSpaceRepository
+		appSummaryRepo      *apifakes.FakeAppSummaryRepository
+		routeRepo           *apifakes.FakeRouteRepository
+		deps                commandregistry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = config
+		deps.RepoLocator = deps.RepoLocator.SetQuotaRepository(quotaRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceQuotaRepository(spaceQuotaRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceRepository(spaceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetRouteRepository(routeRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("quota-usage").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepositoryWithDefaults()
+		quotaRepo = new(quotasfakes.FakeQuotaRepository)
+		spaceQuotaRepo = new(spacequotasfakes.FakeSpaceQuotaRepository)
+		spaceRepo = new(spacesfakes.FakeSpaceRepository)
+		appSummaryRepo = new(apifakes.FakeAppSummaryRepository)
+		routeRepo = new(apifakes.FakeRouteRepository)
+
+		org := models.Organization{}
+		org.Name = "my-org"
+		org.GUID = "my-org-guid"
+		org.QuotaDefinition.Name = "org-quota"
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, Organization: org}
+
+		quotaRepo.FindByNameReturns(models.QuotaFields{
+			Name:             "org-quota",
+			MemoryLimit:      4096,
+			AppInstanceLimit: -1,
+			ServicesLimit:    10,
+			RoutesLimit:      4,
+		}, nil)
+		spaceQuotaRepo.FindByOrgReturns([]models.SpaceQuota{{
+			GUID:             "small-guid",
+			Name:             "small",
+			MemoryLimit:      1024,
+			AppInstanceLimit: 10,
+			ServicesLimit:    1,
+			RoutesLimit:      -1,
+		}}, nil)
+
+		spaceRepo.ListSpacesFromOrgStub = func(orgGUID string, callback func(models.Space) bool) error {
+			Expect(orgGUID).To(Equal("my-org-guid"))
+			for _, name := range []string{"dev", "prod"} {
+				space := models.Space{}
+				space.Name = name
+				space.GUID = name + "-guid"
+				if name == "dev" {
+					space.SpaceQuotaGUID = "small-guid"
+				}
+				if !callback(space) {
+					break
+				}
+			}
+			return nil
+		}
+
+		appSummaryRepo.GetSpaceSummaryStub = func(spaceGUID string) ([]models.Application, []models.ServiceInstance, error) {
+			managed := models.ServiceInstance{}
+			managed.ServicePlan.GUID = "plan-guid"
+			userProvided := models.ServiceInstance{}
+			serviceInstances := []models.ServiceInstance{managed, userProvided}
+
+			if spaceGUID != "dev-guid" {
+				return []models.Application{}, serviceInstances, nil
+			}
+			started := models.Application{}
+			started.State = "started"
+			started.InstanceCount = 2
+			started.Memory = 512
+			stopped := models.Application{}
+			stopped.State = "stopped"
+			stopped.InstanceCount = 4
+			stopped.Memory = 1024
+			return []models.Application{started, stopped}, serviceInstances, nil
+		}
+
+		routeRepo.ListRoutesInSpaceStub = func(spaceGUID string, cb func(models.Route) bool) error {
+			cb(models.Route{})
+			cb(models.Route{})
+			return nil
+		}
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("quota-usage", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-org")).To(BeFalse())
+		})
+
+		It("fails with usage when no org is given", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires an argument"},
+			))
+		})
+	})
+
+	It("shows the usage of the org quota and of the space quotas", func() {
+		Expect(runCommand("my-org")).To(BeTrue())
+
+		Expect(spaceQuotaRepo.FindByOrgArgsForCall(0)).To(Equal("my-org-guid"))
+		Expect(quotaRepo.FindByNameArgsForCall(0)).To(Equal("org-quota"))
+		Expect(appSummaryRepo.GetSpaceSummaryCallCount()).To(Equal(2))
+		Expect(appSummaryRepo.GetSpaceSummaryArgsForCall(0)).To(Equal("dev-guid"))
+		Expect(appSummaryRepo.GetSpaceSummaryArgsForCall(1)).To(Equal("prod-guid"))
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Getting quota usage of org", "my-org", "my-user"},
+			[]string{"OK"},
+			[]string{"org quota:", "org-quota"},
+			[]string{"memory", "1G", "4G", "25%"},
+			[]string{"instances", "2", "unlimited"},
+			[]string{"services", "2", "10", "20%"},
+			[]string{"routes", "4", "4", "100%"},
+			[]string{"space", "space quota", "memory", "instances", "services", "routes", "near limits"},
+			[]string{"dev", "small", "1G / 1G", "2 / 10", "1 / 1", "2 / unlimited", "memory, services"},
+			[]string{"prod", "0", "0", "1", "2"},
+		))
+	})
+
+	It("uses the given threshold", func() {
+		Expect(runCommand("--threshold", "10", "my-org")).To(BeTrue())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"dev", "small", "memory, instances, services"},
+		))
+	})
+
+	It("shows the usage as JSON", func() {
+		Expect(runCommand("--json", "my-org")).To(BeTrue())
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Getting quota usage"}))
+
+		var usage map[string]interface{}
+		Expect(json.Unmarshal([]byte(strings.Join(ui.Outputs, "\n")), &usage)).To(Succeed())
+
+		Expect(usage["org"]).To(Equal("my-org"))
+		Expect(usage["used"]).To(Equal(map[string]interface{}{
+			"memory_mb": float64(1024),
+			"instances": float64(2),
+			"services":  float64(2),
+			"routes":    float64(4),
+		}))
+		Expect(usage["near_limits"]).To(Equal([]interface{}{"routes"}))
+
+		spaces := usage["spaces"].([]interface{})
+		Expect(spaces).To(HaveLen(2))
+		Expect(spaces[0].(map[string]interface{})["near_limits"]).To(Equal([]interface{}{"memory", "services"}))
+		Expect(spaces[1].(map[string]interface{})).NotTo(HaveKey("quota"))
+	})
+
+	It("fails when the org quota cannot be found", func() {
+		quotaRepo.FindByNameReturns(models.QuotaFields{}, errors.New("quota not found"))
+
+		Expect(runCommand("my-org")).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"quota not found"},
+		))
+	})
+
+	It("fails when the spaces of the org cannot be listed", func() {
+		spaceRepo.ListSpacesFromOrgStub = nil
+		spaceRepo.ListSpacesFromOrgReturns(errors.New("server error"))
+
+		Expect(runCommand("my-org")).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"server error"},
+		))
+		Expect(appSummaryRepo.GetSpaceSummaryCallCount()).To(Equal(0))
+	})
+})
@@ -9,8 +9,8 @@ import (
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/securitygroups/rules"
 	"github.com/cloudfoundry/cli/cf/terminal"
-	"github.com/cloudfoundry/cli/json"
 )
 
 type CreateSecurityGroup struct {
@@ -45,6 +45,8 @@ func (cmd *CreateSecurityGroup) MetaData() commandregistry.CommandMetadata {
 			primaryUsage,
 			"\n\n",
 			secondaryUsage,
+			"\n\n",
+			yamlRulesUsage(),
 		},
 	}
 }
@@ -71,18 +73,12 @@ func (cmd *CreateSecurityGroup) SetDependency(deps commandregistry.Dependency, p
 func (cmd *CreateSecurityGroup) Execute(context flags.FlagContext) error {
 	name := context.Args()[0]
 	pathToJSONFile := context.Args()[1]
-	rules, err := json.ParseJSONArray(pathToJSONFile)
+	groupRules, warnings, err := rules.ReadFile(pathToJSONFile)
 	if err != nil {
-		return errors.New(T(`Incorrect json format: file: {{.JSONFile}}
-		
-Valid json file example:
-[
-  {
-    "protocol": "tcp",
-    "destination": "10.244.1.18",
-    "ports": "3306"
-  }
-]`, map[string]interface{}{"JSONFile": pathToJSONFile}))
+		return err
+	}
+	for _, warning := range warnings {
+		cmd.ui.Warn(warning)
 	}
 
 	cmd.ui.Say(T("Creating security group {{.security_group}} as {{.username}}",
@@ -91,7 +87,7 @@ Valid json file example:
 			"username":       terminal.EntityNameColor(cmd.configRepo.Username()),
 		}))
 
-	err = cmd.securityGroupRepo.Create(name, rules)
+	err = cmd.securityGroupRepo.Create(name, groupRules)
 
 	httpErr, ok := err.(errors.HTTPError)
 	if ok && httpErr.ErrorCode() == errors.SecurityGroupNameTaken {
@@ -111,3 +107,11 @@ Valid json file example:
 	cmd.ui.Ok()
 	return nil
 }
+
+func yamlRulesUsage() string {
+	return T(`   The file can also be YAML when its name ends in .yml or .yaml:
+   - protocol: tcp
+     destination: 10.244.1.18
+     ports: "3306"
+     description: MySQL`)
+}
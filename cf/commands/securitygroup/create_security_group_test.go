@@ -3,6 +3,7 @@ package securitygroup_test
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/cloudfoundry/cli/cf/api/securitygroups/securitygroupsfakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
@@ -144,4 +145,104 @@ var _ = Describe("create-security-group command", func() {
 			})
 		})
 	})
+
+	Describe("reading the rules", func() {
+		var dir string
+
+		BeforeEach(func() {
+			requirementsFactory.LoginSuccess = true
+
+			var err error
+			dir, err = ioutil.TempDir("", "create-security-group")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		write := func(name string, contents string) string {
+			path := filepath.Join(dir, name)
+			Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+			return path
+		}
+
+		It("reads the rules from a YAML file", func() {
+			path := write("rules.yaml", `
+- protocol: icmp
+  destination: 10.0.11.4
+  type: 0
+  code: -1
+- protocol: tcp
+  destination: 10.0.11.0-10.0.11.255
+  ports: 80,443
+  description: web
+`)
+
+			Expect(runCommand("my-group", path)).To(BeTrue())
+
+			_, rules := securityGroupRepo.CreateArgsForCall(0)
+			Expect(rules).To(Equal([]map[string]interface{}{
+				{"protocol": "icmp", "destination": "10.0.11.4", "type": 0, "code": -1},
+				{"protocol": "tcp", "destination": "10.0.11.0-10.0.11.255", "ports": "80,443", "description": "web"},
+			}))
+		})
+
+		It("reports the line of every invalid rule in a YAML file", func() {
+			path := write("rules.yml", `# database access
+- protocol: tcp
+  destination: 10.0.11.0/24
+  ports: "5432"
+- protocol: sctp
+  destination: 10.0.11.4
+- protocol: icmp
+  destination: 10.0.11.4
+  ports: "80"
+`)
+
+			Expect(runCommand("my-group", path)).To(BeFalse())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"The rules in", path, "are invalid"},
+				[]string{"line 5: rule #2: unknown protocol sctp"},
+				[]string{"line 7: rule #3: ports are only allowed in tcp and udp rules"},
+			))
+			Expect(securityGroupRepo.CreateCallCount()).To(Equal(0))
+		})
+
+		It("warns about the invalid rules in a JSON file and passes them on unchanged", func() {
+			path := write("rules.json", `[
+  {
+    "protocol": "tcp",
+    "destination": "10.0.11.0/24",
+    "ports": "5432",
+    "description": "the {database}"
+  },
+  {"protocol": "udp", "destination": "10.0.0.2", "ports": "53", "log": "yes"},
+  {"protocol": "all", "destination": "10.0.0.0/8", "prots": "53"}
+]`)
+
+			Expect(runCommand("my-group", path)).To(BeTrue())
+
+			Expect(ui.WarnOutputs).To(ContainSubstrings(
+				[]string{"The rules in", path, "might be invalid"},
+				[]string{"line 8: rule #2: log must be true or false"},
+				[]string{"line 9: rule #3: unknown field prots"},
+			))
+
+			_, rules := securityGroupRepo.CreateArgsForCall(0)
+			Expect(rules).To(HaveLen(3))
+			Expect(rules[2]).To(Equal(map[string]interface{}{"protocol": "all", "destination": "10.0.0.0/8", "prots": "53"}))
+		})
+
+		It("reports YAML syntax errors", func() {
+			path := write("rules.yml", "- protocol: tcp\n  destination: [\n")
+
+			Expect(runCommand("my-group", path)).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect yaml format", path},
+			))
+		})
+	})
 })
@@ -0,0 +1,183 @@
+package securitygroup
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/cli/cf/api/securitygroups"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/defaults/running"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/securitygroups/rules"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type LintSecurityGroups struct {
+	ui                       terminal.UI
+	configRepo               coreconfig.Reader
+	securityGroupRepo        securitygroups.SecurityGroupRepo
+	runningSecurityGroupRepo running.RunningSecurityGroupsRepo
+}
+
+type lintedRule struct {
+	rules.Rule
+	group      string
+	groupIndex int
+	index      int
+	running    bool
+}
+
+func (rule lintedRule) before(other lintedRule) bool {
+	if rule.groupIndex != other.groupIndex {
+		return rule.groupIndex < other.groupIndex
+	}
+	return rule.index < other.index
+}
+
+func init() {
+	commandregistry.Register(&LintSecurityGroups{})
+}
+
+func (cmd *LintSecurityGroups) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:        "lint-security-groups",
+		Description: T("Find invalid, overly broad, duplicate and shadowed security group rules"),
+		Usage: []string{
+			"CF_NAME lint-security-groups",
+			"\n\n",
+			T("Rules are compared with the other rules of their security group and with the rules of the running security groups, which apply to every app."),
+		},
+	}
+}
+
+func (cmd *LintSecurityGroups) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("No argument required"),
+		func() bool {
+			return len(fc.Args()) != 0
+		},
+	)
+
+	reqs := []requirements.Requirement{
+		usageReq,
+		requirementsFactory.NewLoginRequirement(),
+	}
+	return reqs
+}
+
+func (cmd *LintSecurityGroups) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.configRepo = deps.Config
+	cmd.securityGroupRepo = deps.RepoLocator.GetSecurityGroupRepository()
+	cmd.runningSecurityGroupRepo = deps.RepoLocator.GetRunningSecurityGroupsRepository()
+	return cmd
+}
+
+func (cmd *LintSecurityGroups) Execute(c flags.FlagContext) error {
+	cmd.ui.Say(T("Linting security groups as {{.Username}}...",
+		map[string]interface{}{
+			"Username": terminal.EntityNameColor(cmd.configRepo.Username()),
+		}))
+
+	securityGroups, err := cmd.securityGroupRepo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	runningGroups, err := cmd.runningSecurityGroupRepo.List()
+	if err != nil {
+		return err
+	}
+
+	isRunning := map[string]bool{}
+	for _, group := range runningGroups {
+		isRunning[group.GUID] = true
+	}
+
+	table := cmd.ui.Table([]string{T("security group"), T("rule"), T("problem")})
+	problems := 0
+	addProblem := func(group string, index int, rule string, problem string) {
+		table.Add(group, fmt.Sprintf("#%d %s", index, rule), problem)
+		problems++
+	}
+
+	linted := []lintedRule{}
+	for groupIndex, group := range securityGroups {
+		for index, rule := range group.Rules {
+			parsed, err := rules.Parse(rule)
+			if err != nil {
+				addProblem(group.Name, index+1, "", T("invalid: {{.Err}}", map[string]interface{}{"Err": err.Error()}))
+				continue
+			}
+
+			linted = append(linted, lintedRule{
+				Rule:       parsed,
+				group:      group.Name,
+				groupIndex: groupIndex,
+				index:      index + 1,
+				running:    isRunning[group.GUID],
+			})
+		}
+	}
+
+	for _, rule := range linted {
+		if rule.IsOverlyBroad() {
+			addProblem(rule.group, rule.index, rule.String(), T("allows all ports to every address"))
+		}
+
+		if problem := redundancy(rule, linted); problem != "" {
+			addProblem(rule.group, rule.index, rule.String(), problem)
+		}
+	}
+
+	if problems == 0 {
+		cmd.ui.Ok()
+		cmd.ui.Say("")
+		cmd.ui.Say(T("No problems found"))
+		return nil
+	}
+
+	cmd.ui.Say("")
+	table.Print()
+	return errors.New(T("Found {{.Count}} problems in the security groups", map[string]interface{}{"Count": problems}))
+}
+
+// redundancy describes the first rule that makes the given rule redundant,
+// either by allowing the same traffic or by allowing more. Of two duplicate
+// rules in the same set only the later one is reported, while a rule that
+// duplicates a running security group rule is always reported.
+func redundancy(rule lintedRule, linted []lintedRule) string {
+	for _, other := range linted {
+		if other.groupIndex == rule.groupIndex && other.index == rule.index {
+			continue
+		}
+		if other.groupIndex != rule.groupIndex && !other.running {
+			continue
+		}
+		if !other.Covers(rule.Rule) {
+			continue
+		}
+
+		duplicate := rule.Covers(other.Rule)
+		if duplicate && rule.before(other) && (other.groupIndex == rule.groupIndex || rule.running) {
+			continue
+		}
+
+		params := map[string]interface{}{"Index": other.index, "SecurityGroup": other.group}
+		switch {
+		case duplicate && other.groupIndex == rule.groupIndex:
+			return T("duplicates rule #{{.Index}}", params)
+		case duplicate:
+			return T("duplicates rule #{{.Index}} of running security group {{.SecurityGroup}}", params)
+		case other.groupIndex == rule.groupIndex:
+			return T("shadowed by rule #{{.Index}}", params)
+		default:
+			return T("shadowed by rule #{{.Index}} of running security group {{.SecurityGroup}}", params)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,144 @@
+package securitygroup_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/defaults/running/runningfakes"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/securitygroupsfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("lint-security-groups command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          coreconfig.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		repo                *securitygroupsfakes.FakeSecurityGroupRepo
+		runningRepo         *runningfakes.FakeRunningSecurityGroupsRepo
+		deps                commandregistry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetSecurityGroupRepository(repo)
+		deps.RepoLocator = deps.RepoLocator.SetRunningSecurityGroupRepository(runningRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("lint-security-groups").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		repo = new(securitygroupsfakes.FakeSecurityGroupRepo)
+		runningRepo = new(runningfakes.FakeRunningSecurityGroupsRepo)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("lint-security-groups", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	securityGroup := func(name string, rules ...map[string]interface{}) models.SecurityGroup {
+		group := models.SecurityGroup{}
+		group.Name = name
+		group.GUID = name + "-guid"
+		group.Rules = rules
+		return group
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand()).To(BeFalse())
+		})
+
+		It("fails when given arguments", func() {
+			Expect(runCommand("blahblah")).To(BeFalse())
+		})
+	})
+
+	It("says when there are no problems", func() {
+		repo.FindAllReturns([]models.SecurityGroup{
+			securityGroup("dns", map[string]interface{}{"protocol": "udp", "destination": "0.0.0.0/0", "ports": "53"}),
+			securityGroup("database", map[string]interface{}{"protocol": "tcp", "destination": "10.0.11.0/24", "ports": "5432"}),
+		}, nil)
+
+		Expect(runCommand()).To(BeTrue())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Linting security groups as", "my-user"},
+			[]string{"OK"},
+			[]string{"No problems found"},
+		))
+	})
+
+	It("reports invalid, broad, duplicate and shadowed rules", func() {
+		repo.FindAllReturns([]models.SecurityGroup{
+			securityGroup("open",
+				map[string]interface{}{"protocol": "tcp", "destination": "0.0.0.0/0", "ports": "1-65535"},
+			),
+			securityGroup("database",
+				map[string]interface{}{"protocol": "tcp", "destination": "10.0.11.0/24", "ports": "5432-5440"},
+				map[string]interface{}{"protocol": "tcp", "destination": "10.0.11.4", "ports": "5432"},
+				map[string]interface{}{"protocol": "tcp", "destination": "10.0.11.0-10.0.11.255", "ports": "5432-5440"},
+				map[string]interface{}{"protocol": "tcp", "destination": "10.0.11.0/33", "ports": "5432"},
+			),
+		}, nil)
+
+		Expect(runCommand()).To(BeFalse())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"security group", "rule", "problem"},
+			[]string{"database", "#4", "invalid", "invalid destination 10.0.11.0/33"},
+			[]string{"open", "#1 tcp 0.0.0.0/0:1-65535", "allows all ports to every address"},
+			[]string{"database", "#2 tcp 10.0.11.4:5432", "shadowed by rule #1"},
+			[]string{"database", "#3", "duplicates rule #1"},
+			[]string{"FAILED"},
+			[]string{"Found 4 problems"},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"database", "#1 tcp", "duplicates"}))
+	})
+
+	It("compares rules with the rules of the running security groups", func() {
+		repo.FindAllReturns([]models.SecurityGroup{
+			securityGroup("database",
+				map[string]interface{}{"protocol": "tcp", "destination": "10.0.11.4", "ports": "5432"},
+				map[string]interface{}{"protocol": "udp", "destination": "10.0.0.2", "ports": "53"},
+			),
+			securityGroup("private",
+				map[string]interface{}{"protocol": "all", "destination": "10.0.0.0-10.255.255.255"},
+			),
+			securityGroup("dns",
+				map[string]interface{}{"protocol": "udp", "destination": "10.0.0.2", "ports": "53"},
+			),
+		}, nil)
+		runningRepo.ListReturns([]models.SecurityGroupFields{{Name: "private", GUID: "private-guid"}}, nil)
+
+		Expect(runCommand()).To(BeFalse())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"database", "#1", "shadowed by rule #1 of running security group private"},
+			[]string{"database", "#2", "shadowed by rule #1 of running security group private"},
+			[]string{"dns", "#1", "shadowed by rule #1 of running security group private"},
+			[]string{"Found 3 problems"},
+		))
+	})
+
+	It("fails when the security groups cannot be read", func() {
+		repo.FindAllReturns(nil, errors.New("server error"))
+
+		Expect(runCommand()).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"server error"},
+		))
+	})
+})
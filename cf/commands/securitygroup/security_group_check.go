@@ -0,0 +1,290 @@
+package securitygroup
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/defaults/running"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/defaults/staging"
+	"github.com/cloudfoundry/cli/cf/api/spaces"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/securitygroups/rules"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+const (
+	icmpEchoRequest = 8
+	icmpEchoCode    = 0
+)
+
+type SecurityGroupCheck struct {
+	ui                       terminal.UI
+	configRepo               coreconfig.Reader
+	runningSecurityGroupRepo running.RunningSecurityGroupsRepo
+	stagingSecurityGroupRepo staging.StagingSecurityGroupsRepo
+	spaceRepo                spaces.SpaceRepository
+	appReq                   requirements.ApplicationRequirement
+	lifecycle                string
+}
+
+func init() {
+	commandregistry.Register(&SecurityGroupCheck{})
+}
+
+func (cmd *SecurityGroupCheck) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["protocol"] = &flags.StringFlag{Name: "protocol", Usage: T("Protocol of the traffic: tcp, udp or icmp (Default: tcp)")}
+	fs["icmp-type"] = &flags.IntFlag{Name: "icmp-type", Usage: T("ICMP type of the traffic (Default: 8, echo request)")}
+	fs["icmp-code"] = &flags.IntFlag{Name: "icmp-code", Usage: T("ICMP code of the traffic (Default: 0)")}
+	fs["lifecycle"] = &flags.StringFlag{Name: "lifecycle", Usage: T("Lifecycle whose security groups decide whether the check fails: running or staging (Default: running)")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "security-group-check",
+		Description: T("Show which security groups let an app reach a destination"),
+		Usage: []string{
+			T("CF_NAME security-group-check APP_NAME HOST:PORT [--protocol tcp|udp] [--lifecycle running|staging]"),
+			"\n   ",
+			T("CF_NAME security-group-check APP_NAME HOST --protocol icmp [--icmp-type TYPE] [--icmp-code CODE] [--lifecycle running|staging]"),
+			"\n\n",
+			T("The rules of the running and staging security groups and of the groups bound to the space of the app are evaluated locally. A HOST name is resolved with the local DNS, which can return different addresses than the DNS used by the app."),
+			"\n\n",
+			T("Both lifecycles are shown, but the check only fails when the traffic is denied in the given lifecycle."),
+		},
+		Examples: []string{
+			"CF_NAME security-group-check my-app 10.0.11.4:5432",
+			"CF_NAME security-group-check my-app dns.example.com:53 --protocol udp",
+			"CF_NAME security-group-check my-app 10.0.11.4 --protocol icmp",
+			"CF_NAME security-group-check my-app buildpacks.example.com:443 --lifecycle staging",
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *SecurityGroupCheck) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 2 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires APP_NAME and DESTINATION as arguments\n\n") + commandregistry.Commands.CommandUsage("security-group-check"))
+	}
+
+	cmd.lifecycle = "running"
+	if fc.IsSet("lifecycle") {
+		cmd.lifecycle = fc.String("lifecycle")
+	}
+	if cmd.lifecycle != "running" && cmd.lifecycle != "staging" {
+		cmd.ui.Failed(T("Incorrect Usage. --lifecycle must be running or staging\n\n") + commandregistry.Commands.CommandUsage("security-group-check"))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[0])
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+
+	return reqs
+}
+
+func (cmd *SecurityGroupCheck) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.configRepo = deps.Config
+	cmd.runningSecurityGroupRepo = deps.RepoLocator.GetRunningSecurityGroupsRepository()
+	cmd.stagingSecurityGroupRepo = deps.RepoLocator.GetStagingSecurityGroupsRepository()
+	cmd.spaceRepo = deps.RepoLocator.GetSpaceRepository()
+	return cmd
+}
+
+func (cmd *SecurityGroupCheck) Execute(c flags.FlagContext) error {
+	app := cmd.appReq.GetApplication()
+	destination := c.Args()[1]
+
+	conns, err := cmd.connections(destination, c)
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Say(T("Checking whether app {{.AppName}} can reach {{.Destination}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		map[string]interface{}{
+			"AppName":     terminal.EntityNameColor(app.Name),
+			"Destination": terminal.EntityNameColor(destination),
+			"OrgName":     terminal.EntityNameColor(cmd.configRepo.OrganizationFields().Name),
+			"SpaceName":   terminal.EntityNameColor(cmd.configRepo.SpaceFields().Name),
+			"Username":    terminal.EntityNameColor(cmd.configRepo.Username()),
+		}))
+
+	space, err := cmd.spaceRepo.FindByName(cmd.configRepo.SpaceFields().Name)
+	if err != nil {
+		return err
+	}
+
+	groups, err := effectiveSecurityGroups(cmd.runningSecurityGroupRepo, cmd.stagingSecurityGroupRepo, space)
+	if err != nil {
+		return err
+	}
+
+	lifecycles := []struct {
+		lifecycle string
+		name      string
+		groups    []models.SecurityGroupFields
+	}{
+		{lifecycle: "running", name: T("running")},
+		{lifecycle: "staging", name: T("staging")},
+	}
+	for _, group := range groups {
+		if group.Running {
+			lifecycles[0].groups = append(lifecycles[0].groups, group.SecurityGroupFields)
+		}
+		if group.Staging {
+			lifecycles[1].groups = append(lifecycles[1].groups, group.SecurityGroupFields)
+		}
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	parsedRules := map[string][]numberedRule{}
+
+	denied := false
+	table := cmd.ui.Table([]string{T("lifecycle"), T("destination"), T("result"), T("security group"), T("rule")})
+	for _, lifecycle := range lifecycles {
+		lifecycleRules := cmd.parseRules(lifecycle.groups, parsedRules)
+
+		for _, conn := range conns {
+			allowed := false
+			for _, rule := range lifecycleRules {
+				if rule.Allows(conn) {
+					allowed = true
+					table.Add(lifecycle.name, conn.String(), terminal.SuccessColor(T("allowed")), rule.group, fmt.Sprintf("#%d %s", rule.index, rule))
+				}
+			}
+
+			if !allowed {
+				// the other lifecycle is only shown for information
+				if lifecycle.lifecycle == cmd.lifecycle {
+					denied = true
+				}
+				table.Add(lifecycle.name, conn.String(), terminal.FailureColor(T("denied")), "", T("no rule allows this traffic"))
+			}
+		}
+	}
+	table.Print()
+
+	if denied {
+		return errors.New(T("No {{.Lifecycle}} rule allows some of the traffic from app {{.AppName}} to {{.Destination}}",
+			map[string]interface{}{"Lifecycle": cmd.lifecycle, "AppName": app.Name, "Destination": destination}))
+	}
+
+	return nil
+}
+
+type numberedRule struct {
+	rules.Rule
+	group string
+	index int
+}
+
+// parseRules warns about the rules that cannot be evaluated and leaves them
+// out. The rules of each group are parsed once and kept in parsed, as the
+// groups bound to the space apply to both lifecycles.
+func (cmd *SecurityGroupCheck) parseRules(groups []models.SecurityGroupFields, parsed map[string][]numberedRule) []numberedRule {
+	numbered := []numberedRule{}
+
+	for _, group := range groups {
+		groupRules, found := parsed[group.GUID]
+		if !found {
+			groupRules = cmd.parseGroupRules(group)
+			parsed[group.GUID] = groupRules
+		}
+		numbered = append(numbered, groupRules...)
+	}
+
+	return numbered
+}
+
+func (cmd *SecurityGroupCheck) parseGroupRules(group models.SecurityGroupFields) []numberedRule {
+	numbered := []numberedRule{}
+
+	for index, rule := range group.Rules {
+		parsed, err := rules.Parse(rule)
+		if err != nil {
+			cmd.ui.Warn(T("Skipping rule #{{.Index}} of security group {{.SecurityGroup}}: {{.Err}}",
+				map[string]interface{}{
+					"Index":         index + 1,
+					"SecurityGroup": group.Name,
+					"Err":           err.Error(),
+				}))
+			continue
+		}
+
+		numbered = append(numbered, numberedRule{Rule: parsed, group: group.Name, index: index + 1})
+	}
+
+	return numbered
+}
+
+// connections resolves the destination to the connections to check, one for
+// each IPv4 address of the host.
+func (cmd *SecurityGroupCheck) connections(destination string, c flags.FlagContext) ([]rules.Connection, error) {
+	template := rules.Connection{Protocol: "tcp", Type: icmpEchoRequest, Code: icmpEchoCode}
+	if c.IsSet("protocol") {
+		template.Protocol = c.String("protocol")
+	}
+	if c.IsSet("icmp-type") {
+		template.Type = c.Int("icmp-type")
+	}
+	if c.IsSet("icmp-code") {
+		template.Code = c.Int("icmp-code")
+	}
+
+	host := destination
+	switch template.Protocol {
+	case "tcp", "udp":
+		var port string
+		var err error
+		host, port, err = net.SplitHostPort(destination)
+		if err != nil {
+			return nil, errors.New(T("The destination {{.Destination}} must be HOST:PORT for the {{.Protocol}} protocol",
+				map[string]interface{}{"Destination": destination, "Protocol": template.Protocol}))
+		}
+
+		template.Port, err = strconv.Atoi(port)
+		if err != nil || template.Port < 1 || template.Port > 65535 {
+			return nil, errors.New(T("invalid port {{.Port}}", map[string]interface{}{"Port": port}))
+		}
+	case "icmp":
+	default:
+		return nil, errors.New(T("unknown protocol {{.Protocol}}", map[string]interface{}{"Protocol": template.Protocol}))
+	}
+
+	var addresses []net.IP
+	if ip := rules.ParseIPv4(host); ip != nil {
+		addresses = []net.IP{ip}
+	} else {
+		resolved, err := net.LookupIP(host)
+		if err != nil {
+			return nil, errors.New(T("Could not resolve {{.Host}}: {{.Err}}", map[string]interface{}{"Host": host, "Err": err.Error()}))
+		}
+		for _, ip := range resolved {
+			if ip.To4() != nil {
+				addresses = append(addresses, ip.To4())
+			}
+		}
+		if len(addresses) == 0 {
+			return nil, errors.New(T("{{.Host}} has no IPv4 address", map[string]interface{}{"Host": host}))
+		}
+	}
+
+	conns := []rules.Connection{}
+	for _, ip := range addresses {
+		conn := template
+		conn.IP = ip
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
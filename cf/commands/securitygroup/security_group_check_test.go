@@ -0,0 +1,237 @@
+package securitygroup_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/defaults/running/runningfakes"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/defaults/staging/stagingfakes"
+	"github.com/cloudfoundry/cli/cf/api/spaces/spacesfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("security-group-check command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          coreconfig.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		runningRepo         *runningfakes.FakeRunningSecurityGroupsRepo
+		stagingRepo         *stagingfakes.FakeStagingSecurityGroupsRepo
+		spaceRepo           *spacesfakes.FakeSpaceRepository
+		deps                commandregistry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetRunningSecurityGroupRepository(runningRepo)
+		deps.RepoLocator = deps.RepoLocator.SetStagingSecurityGroupRepository(stagingRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceRepository(spaceRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("security-group-check").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
+		requirementsFactory.Application = models.Application{}
+		requirementsFactory.Application.Name = "my-app"
+		runningRepo = new(runningfakes.FakeRunningSecurityGroupsRepo)
+		stagingRepo = new(stagingfakes.FakeStagingSecurityGroupsRepo)
+		spaceRepo = new(spacesfakes.FakeSpaceRepository)
+
+		runningRepo.ListReturns([]models.SecurityGroupFields{
+			{
+				Name: "public_networks",
+				GUID: "public-guid",
+				Rules: []map[string]interface{}{
+					{"protocol": "all", "destination": "0.0.0.0-9.255.255.255"},
+					{"protocol": "all", "destination": "11.0.0.0-169.253.255.255"},
+				},
+			},
+			{
+				Name: "dns",
+				GUID: "dns-guid",
+				Rules: []map[string]interface{}{
+					{"protocol": "udp", "destination": "0.0.0.0/0", "ports": "53"},
+				},
+			},
+		}, nil)
+		stagingRepo.ListReturns([]models.SecurityGroupFields{
+			{
+				Name: "dns",
+				GUID: "dns-guid",
+				Rules: []map[string]interface{}{
+					{"protocol": "udp", "destination": "0.0.0.0/0", "ports": "53"},
+				},
+			},
+		}, nil)
+
+		space := models.Space{}
+		space.Name = "my-space"
+		space.SecurityGroups = []models.SecurityGroupFields{
+			{
+				Name: "database",
+				GUID: "database-guid",
+				Rules: []map[string]interface{}{
+					{"protocol": "tcp", "destination": "10.0.11.0/24", "ports": "5432,6000-6010"},
+					{"protocol": "icmp", "destination": "10.0.11.4", "type": float64(0), "code": float64(-1)},
+				},
+			},
+		}
+		spaceRepo.FindByNameReturns(space, nil)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("security-group-check", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-app", "10.0.11.4:5432")).To(BeFalse())
+		})
+
+		It("fails when no space is targeted", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand("my-app", "10.0.11.4:5432")).To(BeFalse())
+		})
+
+		It("fails when the app does not exist", func() {
+			requirementsFactory.ApplicationFails = true
+			Expect(runCommand("my-app", "10.0.11.4:5432")).To(BeFalse())
+		})
+
+		It("fails with usage when not given an app and a destination", func() {
+			runCommand("my-app")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires APP_NAME and DESTINATION as arguments"},
+			))
+		})
+
+		It("fails with usage when given an unknown lifecycle", func() {
+			runCommand("my-app", "10.0.11.4:5432", "--lifecycle", "building")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--lifecycle must be running or staging"},
+			))
+		})
+	})
+
+	It("reports the groups and rules that let the app reach the destination", func() {
+		Expect(runCommand("my-app", "10.0.11.4:6005")).To(BeTrue())
+
+		Expect(requirementsFactory.ApplicationName).To(Equal("my-app"))
+		Expect(spaceRepo.FindByNameArgsForCall(0)).To(Equal("my-space"))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Checking whether app", "my-app", "10.0.11.4:6005", "my-org", "my-space", "my-user"},
+			[]string{"OK"},
+			[]string{"lifecycle", "destination", "result", "security group", "rule"},
+			[]string{"running", "10.0.11.4:6005/tcp", "allowed", "database", "#1 tcp 10.0.11.0/24:5432,6000-6010"},
+			[]string{"staging", "10.0.11.4:6005/tcp", "allowed", "database", "#1"},
+		))
+	})
+
+	It("evaluates address ranges and protocol all", func() {
+		Expect(runCommand("my-app", "9.1.2.3:443")).To(BeTrue())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"running", "9.1.2.3:443/tcp", "allowed", "public_networks", "#1 all 0.0.0.0-9.255.255.255"},
+			[]string{"staging", "9.1.2.3:443/tcp", "denied", "no rule allows this traffic"},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"FAILED"}))
+	})
+
+	It("fails when the traffic is denied in the lifecycle given with --lifecycle", func() {
+		Expect(runCommand("my-app", "9.1.2.3:443", "--lifecycle", "staging")).To(BeFalse())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"running", "9.1.2.3:443/tcp", "allowed", "public_networks"},
+			[]string{"staging", "9.1.2.3:443/tcp", "denied"},
+			[]string{"FAILED"},
+			[]string{"No staging rule allows some of the traffic from app my-app to 9.1.2.3:443"},
+		))
+	})
+
+	It("fails when no rule allows the traffic", func() {
+		Expect(runCommand("my-app", "10.0.11.4:5433")).To(BeFalse())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"running", "10.0.11.4:5433/tcp", "denied"},
+			[]string{"staging", "10.0.11.4:5433/tcp", "denied"},
+			[]string{"FAILED"},
+			[]string{"No running rule allows some of the traffic from app my-app to 10.0.11.4:5433"},
+		))
+	})
+
+	It("checks udp traffic", func() {
+		Expect(runCommand("my-app", "172.16.0.2:53", "--protocol", "udp")).To(BeTrue())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"running", "172.16.0.2:53/udp", "allowed", "dns", "#1 udp 0.0.0.0/0:53"},
+			[]string{"staging", "172.16.0.2:53/udp", "allowed", "dns"},
+		))
+	})
+
+	It("checks icmp traffic by type and code", func() {
+		Expect(runCommand("my-app", "10.0.11.4", "--protocol", "icmp", "--icmp-type", "0", "--icmp-code", "3")).To(BeTrue())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"running", "10.0.11.4 icmp type 0 code 3", "allowed", "database", "#2"},
+		))
+
+		ui.Outputs = []string{}
+		Expect(runCommand("my-app", "10.0.11.4", "--protocol", "icmp")).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"running", "10.0.11.4 icmp type 8 code 0", "denied"},
+		))
+	})
+
+	It("warns about the rules it cannot evaluate", func() {
+		space := models.Space{}
+		space.SecurityGroups = []models.SecurityGroupFields{
+			{
+				Name: "broken",
+				GUID: "broken-guid",
+				Rules: []map[string]interface{}{
+					{"protocol": "tcp", "destination": "10.0.0.0/33", "ports": "80"},
+					{"protocol": "tcp", "destination": "10.0.0.0/8", "ports": "80"},
+				},
+			},
+		}
+		spaceRepo.FindByNameReturns(space, nil)
+
+		Expect(runCommand("my-app", "10.1.1.1:80")).To(BeTrue())
+
+		Expect(ui.WarnOutputs).To(ContainSubstrings(
+			[]string{"Skipping rule #1 of security group broken", "invalid destination 10.0.0.0/33"},
+		))
+		Expect(ui.WarnOutputs).To(HaveLen(1))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"running", "10.1.1.1:80/tcp", "allowed", "broken", "#2"},
+		))
+	})
+
+	It("fails when the destination has no port", func() {
+		Expect(runCommand("my-app", "10.0.11.4")).To(BeFalse())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"must be HOST:PORT"},
+		))
+	})
+
+	It("fails with an unknown protocol", func() {
+		Expect(runCommand("my-app", "10.0.11.4:80", "--protocol", "sctp")).To(BeFalse())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"unknown protocol sctp"},
+		))
+	})
+})
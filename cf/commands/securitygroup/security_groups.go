@@ -2,11 +2,15 @@ package securitygroup
 
 import (
 	"fmt"
+	"strings"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/flags"
 
 	"github.com/cloudfoundry/cli/cf/api/securitygroups"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/defaults/running"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/defaults/staging"
+	"github.com/cloudfoundry/cli/cf/api/spaces"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/models"
@@ -15,9 +19,13 @@ import (
 )
 
 type SecurityGroups struct {
-	ui                terminal.UI
-	securityGroupRepo securitygroups.SecurityGroupRepo
-	configRepo        coreconfig.Reader
+	ui                       terminal.UI
+	securityGroupRepo        securitygroups.SecurityGroupRepo
+	runningSecurityGroupRepo running.RunningSecurityGroupsRepo
+	stagingSecurityGroupRepo staging.StagingSecurityGroupsRepo
+	spaceRepo                spaces.SpaceRepository
+	configRepo               coreconfig.Reader
+	appReq                   requirements.ApplicationRequirement
 }
 
 func init() {
@@ -25,12 +33,17 @@ func init() {
 }
 
 func (cmd *SecurityGroups) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["space"] = &flags.StringFlag{Name: "space", Usage: T("Show the security groups that apply to the apps of a space in the targeted org")}
+	fs["app"] = &flags.StringFlag{Name: "app", Usage: T("Show the security groups that apply to an app in the targeted space")}
+
 	return commandregistry.CommandMetadata{
 		Name:        "security-groups",
 		Description: T("List all security groups"),
 		Usage: []string{
-			"CF_NAME security-groups",
+			"CF_NAME security-groups [--space SPACE | --app APP]",
 		},
+		Flags: fs,
 	}
 }
 
@@ -42,10 +55,28 @@ func (cmd *SecurityGroups) Requirements(requirementsFactory requirements.Factory
 		},
 	)
 
+	flagsReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("Cannot specify both --space and --app"),
+		func() bool {
+			return fc.IsSet("space") && fc.IsSet("app")
+		},
+	)
+
 	reqs := []requirements.Requirement{
 		usageReq,
+		flagsReq,
 		requirementsFactory.NewLoginRequirement(),
 	}
+
+	if fc.IsSet("space") {
+		reqs = append(reqs, requirementsFactory.NewTargetedOrgRequirement())
+	}
+
+	if fc.IsSet("app") {
+		cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.String("app"))
+		reqs = append(reqs, requirementsFactory.NewTargetedSpaceRequirement(), cmd.appReq)
+	}
+
 	return reqs
 }
 
@@ -53,10 +84,34 @@ func (cmd *SecurityGroups) SetDependency(deps commandregistry.Dependency, plugin
 	cmd.ui = deps.UI
 	cmd.configRepo = deps.Config
 	cmd.securityGroupRepo = deps.RepoLocator.GetSecurityGroupRepository()
+	cmd.runningSecurityGroupRepo = deps.RepoLocator.GetRunningSecurityGroupsRepository()
+	cmd.stagingSecurityGroupRepo = deps.RepoLocator.GetStagingSecurityGroupsRepository()
+	cmd.spaceRepo = deps.RepoLocator.GetSpaceRepository()
 	return cmd
 }
 
 func (cmd *SecurityGroups) Execute(c flags.FlagContext) error {
+	if c.IsSet("space") {
+		cmd.ui.Say(T("Getting security groups that apply to space {{.SpaceName}} in org {{.OrgName}} as {{.Username}}",
+			map[string]interface{}{
+				"SpaceName": terminal.EntityNameColor(c.String("space")),
+				"OrgName":   terminal.EntityNameColor(cmd.configRepo.OrganizationFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.configRepo.Username()),
+			}))
+		return cmd.listEffective(c.String("space"))
+	}
+
+	if c.IsSet("app") {
+		cmd.ui.Say(T("Getting security groups that apply to app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(cmd.appReq.GetApplication().Name),
+				"OrgName":   terminal.EntityNameColor(cmd.configRepo.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.configRepo.SpaceFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.configRepo.Username()),
+			}))
+		return cmd.listEffective(cmd.configRepo.SpaceFields().Name)
+	}
+
 	cmd.ui.Say(T("Getting security groups as {{.username}}",
 		map[string]interface{}{
 			"username": terminal.EntityNameColor(cmd.configRepo.Username()),
@@ -88,6 +143,41 @@ func (cmd *SecurityGroups) Execute(c flags.FlagContext) error {
 	return nil
 }
 
+func (cmd *SecurityGroups) listEffective(spaceName string) error {
+	space, err := cmd.spaceRepo.FindByName(spaceName)
+	if err != nil {
+		return err
+	}
+
+	groups, err := effectiveSecurityGroups(cmd.runningSecurityGroupRepo, cmd.stagingSecurityGroupRepo, space)
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	if len(groups) == 0 {
+		cmd.ui.Say(T("No security groups"))
+		return nil
+	}
+
+	table := cmd.ui.Table([]string{"", T("Name"), T("Lifecycle"), T("Applied by")})
+	for index, group := range groups {
+		lifecycles := []string{}
+		if group.Running {
+			lifecycles = append(lifecycles, T("running"))
+		}
+		if group.Staging {
+			lifecycles = append(lifecycles, T("staging"))
+		}
+
+		table.Add(fmt.Sprintf("#%d", index), group.Name, strings.Join(lifecycles, ", "), strings.Join(group.Sources, ", "))
+	}
+	table.Print()
+	return nil
+}
+
 type table interface {
 	Add(row ...string)
 	Print()
@@ -105,3 +195,54 @@ func (cmd SecurityGroups) printSpaces(table table, securityGroup models.Security
 		}
 	}
 }
+
+// effectiveSecurityGroup is a security group that applies to the apps of a
+// space, together with the lifecycles it applies to and why.
+type effectiveSecurityGroup struct {
+	models.SecurityGroupFields
+	Running bool
+	Staging bool
+	Sources []string
+}
+
+// effectiveSecurityGroups returns the union of the running and staging
+// security groups and of the groups bound to the space, in that order.
+func effectiveSecurityGroups(runningRepo running.RunningSecurityGroupsRepo, stagingRepo staging.StagingSecurityGroupsRepo, space models.Space) ([]effectiveSecurityGroup, error) {
+	runningGroups, err := runningRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	stagingGroups, err := stagingRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []*effectiveSecurityGroup{}
+	groupsByGUID := map[string]*effectiveSecurityGroup{}
+
+	add := func(fields []models.SecurityGroupFields, source string, isRunning bool, isStaging bool) {
+		for _, field := range fields {
+			group, found := groupsByGUID[field.GUID]
+			if !found {
+				group = &effectiveSecurityGroup{SecurityGroupFields: field}
+				groupsByGUID[field.GUID] = group
+				groups = append(groups, group)
+			}
+
+			group.Running = group.Running || isRunning
+			group.Staging = group.Staging || isStaging
+			group.Sources = append(group.Sources, source)
+		}
+	}
+
+	add(runningGroups, T("running set"), true, false)
+	add(stagingGroups, T("staging set"), false, true)
+	add(space.SecurityGroups, T("space {{.SpaceName}}", map[string]interface{}{"SpaceName": space.Name}), true, true)
+
+	effective := []effectiveSecurityGroup{}
+	for _, group := range groups {
+		effective = append(effective, *group)
+	}
+	return effective, nil
+}
@@ -1,7 +1,10 @@
 package securitygroup_test
 
 import (
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/defaults/running/runningfakes"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/defaults/staging/stagingfakes"
 	"github.com/cloudfoundry/cli/cf/api/securitygroups/securitygroupsfakes"
+	"github.com/cloudfoundry/cli/cf/api/spaces/spacesfakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/errors"
@@ -22,6 +25,9 @@ var _ = Describe("list-security-groups command", func() {
 	var (
 		ui                  *testterm.FakeUI
 		repo                *securitygroupsfakes.FakeSecurityGroupRepo
+		runningRepo         *runningfakes.FakeRunningSecurityGroupsRepo
+		stagingRepo         *stagingfakes.FakeStagingSecurityGroupsRepo
+		spaceRepo           *spacesfakes.FakeSpaceRepository
 		requirementsFactory *testreq.FakeReqFactory
 		configRepo          coreconfig.Repository
 		deps                commandregistry.Dependency
@@ -30,6 +36,9 @@ var _ = Describe("list-security-groups command", func() {
 	updateCommandDependency := func(pluginCall bool) {
 		deps.UI = ui
 		deps.RepoLocator = deps.RepoLocator.SetSecurityGroupRepository(repo)
+		deps.RepoLocator = deps.RepoLocator.SetRunningSecurityGroupRepository(runningRepo)
+		deps.RepoLocator = deps.RepoLocator.SetStagingSecurityGroupRepository(stagingRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceRepository(spaceRepo)
 		deps.Config = configRepo
 		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("security-groups").SetDependency(deps, pluginCall))
 	}
@@ -38,6 +47,9 @@ var _ = Describe("list-security-groups command", func() {
 		ui = &testterm.FakeUI{}
 		requirementsFactory = &testreq.FakeReqFactory{}
 		repo = new(securitygroupsfakes.FakeSecurityGroupRepo)
+		runningRepo = new(runningfakes.FakeRunningSecurityGroupsRepo)
+		stagingRepo = new(stagingfakes.FakeStagingSecurityGroupsRepo)
+		spaceRepo = new(spacesfakes.FakeSpaceRepository)
 		configRepo = testconfig.NewRepositoryWithDefaults()
 	})
 
@@ -70,6 +82,93 @@ var _ = Describe("list-security-groups command", func() {
 				Expect(err.Error()).To(ContainSubstring("Incorrect Usage"))
 				Expect(err.Error()).To(ContainSubstring("No argument required"))
 			})
+
+			It("should fail with usage when given both --space and --app", func() {
+				flagContext.Parse("--space", "my-space", "--app", "my-app")
+
+				reqs := cmd.Requirements(requirementsFactory, flagContext)
+
+				err := testcmd.RunRequirements(reqs)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Cannot specify both --space and --app"))
+			})
+		})
+
+		It("should fail with --space when no org is targeted", func() {
+			requirementsFactory.LoginSuccess = true
+			Expect(runCommand("--space", "my-space")).To(BeFalse())
+		})
+
+		It("should fail with --app when the app does not exist", func() {
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+			requirementsFactory.ApplicationFails = true
+			Expect(runCommand("--app", "my-app")).To(BeFalse())
+		})
+	})
+
+	Describe("the security groups that apply to a space or app", func() {
+		BeforeEach(func() {
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedOrgSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+			requirementsFactory.Application = models.Application{}
+			requirementsFactory.Application.Name = "my-app"
+
+			runningRepo.ListReturns([]models.SecurityGroupFields{
+				{Name: "public_networks", GUID: "public-guid"},
+				{Name: "dns", GUID: "dns-guid"},
+			}, nil)
+			stagingRepo.ListReturns([]models.SecurityGroupFields{
+				{Name: "dns", GUID: "dns-guid"},
+			}, nil)
+
+			space := models.Space{}
+			space.Name = "other-space"
+			space.SecurityGroups = []models.SecurityGroupFields{
+				{Name: "database", GUID: "database-guid"},
+				{Name: "public_networks", GUID: "public-guid"},
+			}
+			spaceRepo.FindByNameReturns(space, nil)
+		})
+
+		It("lists the union of the running and staging sets and of the space bindings", func() {
+			Expect(runCommand("--space", "other-space")).To(BeTrue())
+
+			Expect(spaceRepo.FindByNameArgsForCall(0)).To(Equal("other-space"))
+			Expect(repo.FindAllCallCount()).To(Equal(0))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Getting security groups that apply to space", "other-space", "my-org", "my-user"},
+				[]string{"OK"},
+				[]string{"Name", "Lifecycle", "Applied by"},
+				[]string{"#0", "public_networks", "running, staging", "running set, space other-space"},
+				[]string{"#1", "dns", "running, staging", "running set, staging set"},
+				[]string{"#2", "database", "running, staging", "space other-space"},
+			))
+		})
+
+		It("lists the security groups of the space of the app", func() {
+			spaceRepo.FindByNameReturns(models.Space{}, nil)
+
+			Expect(runCommand("--app", "my-app")).To(BeTrue())
+
+			Expect(requirementsFactory.ApplicationName).To(Equal("my-app"))
+			Expect(spaceRepo.FindByNameArgsForCall(0)).To(Equal("my-space"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Getting security groups that apply to app", "my-app", "my-space"},
+				[]string{"#0", "public_networks", "running", "running set"},
+				[]string{"#1", "dns", "running, staging", "running set, staging set"},
+			))
+		})
+
+		It("fails when the space cannot be found", func() {
+			spaceRepo.FindByNameReturns(models.Space{}, errors.NewModelNotFoundError("Space", "other-space"))
+
+			Expect(runCommand("--space", "other-space")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"other-space", "not found"},
+			))
 		})
 	})
 
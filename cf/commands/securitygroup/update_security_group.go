@@ -1,6 +1,9 @@
 package securitygroup
 
 import (
+	"encoding/json"
+	"fmt"
+
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/flags"
 
@@ -8,8 +11,8 @@ import (
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/securitygroups/rules"
 	"github.com/cloudfoundry/cli/cf/terminal"
-	"github.com/cloudfoundry/cli/json"
 )
 
 type UpdateSecurityGroup struct {
@@ -23,9 +26,14 @@ func init() {
 }
 
 func (cmd *UpdateSecurityGroup) MetaData() commandregistry.CommandMetadata {
-	primaryUsage := T("CF_NAME update-security-group SECURITY_GROUP PATH_TO_JSON_RULES_FILE")
+	primaryUsage := T("CF_NAME update-security-group SECURITY_GROUP PATH_TO_JSON_RULES_FILE [--diff [-f]]")
 	secondaryUsage := T("   The provided path can be an absolute or relative path to a file.\n   It should have a single array with JSON objects inside describing the rules.")
 	tipUsage := T("TIP: Changes will not apply to existing running applications until they are restarted.")
+
+	fs := make(map[string]flags.FlagSet)
+	fs["diff"] = &flags.BoolFlag{Name: "diff", Usage: T("Show the rules that are added and removed, and ask for confirmation before updating")}
+	fs["f"] = &flags.BoolFlag{ShortName: "f", Usage: T("Force update without confirmation when using --diff")}
+
 	return commandregistry.CommandMetadata{
 		Name:        "update-security-group",
 		Description: T("Update a security group"),
@@ -34,8 +42,11 @@ func (cmd *UpdateSecurityGroup) MetaData() commandregistry.CommandMetadata {
 			"\n\n",
 			secondaryUsage,
 			"\n\n",
+			yamlRulesUsage(),
+			"\n\n",
 			tipUsage,
 		},
+		Flags: fs,
 	}
 }
 
@@ -63,17 +74,41 @@ func (cmd *UpdateSecurityGroup) Execute(context flags.FlagContext) error {
 	}
 
 	pathToJSONFile := context.Args()[1]
-	rules, err := json.ParseJSONArray(pathToJSONFile)
+	groupRules, warnings, err := rules.ReadFile(pathToJSONFile)
 	if err != nil {
 		return err
 	}
+	for _, warning := range warnings {
+		cmd.ui.Warn(warning)
+	}
+
+	if context.Bool("diff") {
+		changed, err := cmd.showDiff(name, securityGroup.Rules, groupRules)
+		if err != nil {
+			return err
+		}
+
+		if !changed {
+			cmd.ui.Ok()
+			cmd.ui.Say(T("The rules of security group {{.security_group}} are already up to date",
+				map[string]interface{}{"security_group": terminal.EntityNameColor(name)}))
+			return nil
+		}
+
+		if !context.Bool("f") {
+			if !cmd.ui.Confirm(T("Really update the rules of security group {{.security_group}}?",
+				map[string]interface{}{"security_group": name})) {
+				return nil
+			}
+		}
+	}
 
 	cmd.ui.Say(T("Updating security group {{.security_group}} as {{.username}}",
 		map[string]interface{}{
 			"security_group": terminal.EntityNameColor(name),
 			"username":       terminal.EntityNameColor(cmd.configRepo.Username()),
 		}))
-	err = cmd.securityGroupRepo.Update(securityGroup.GUID, rules)
+	err = cmd.securityGroupRepo.Update(securityGroup.GUID, groupRules)
 	if err != nil {
 		return err
 	}
@@ -83,3 +118,62 @@ func (cmd *UpdateSecurityGroup) Execute(context flags.FlagContext) error {
 	cmd.ui.Say(T("TIP: Changes will not apply to existing running applications until they are restarted."))
 	return nil
 }
+
+// showDiff prints the rules that the update removes and adds, comparing the
+// rules by their JSON encoding, and tells whether there are any.
+func (cmd *UpdateSecurityGroup) showDiff(name string, current []map[string]interface{}, updated []map[string]interface{}) (bool, error) {
+	cmd.ui.Say(T("Comparing the rules of security group {{.security_group}} as {{.username}}",
+		map[string]interface{}{
+			"security_group": terminal.EntityNameColor(name),
+			"username":       terminal.EntityNameColor(cmd.configRepo.Username()),
+		}))
+	cmd.ui.Say("")
+
+	currentRules, err := encodeRules(current)
+	if err != nil {
+		return false, err
+	}
+	updatedRules, err := encodeRules(updated)
+	if err != nil {
+		return false, err
+	}
+
+	remaining := map[string]int{}
+	for _, rule := range updatedRules {
+		remaining[rule]++
+	}
+
+	unchanged := 0
+	for _, rule := range currentRules {
+		if remaining[rule] > 0 {
+			remaining[rule]--
+			unchanged++
+			continue
+		}
+		cmd.ui.Say(terminal.FailureColor(fmt.Sprintf("- %s", rule)))
+	}
+
+	for _, rule := range updatedRules {
+		if remaining[rule] > 0 {
+			remaining[rule]--
+			cmd.ui.Say(terminal.SuccessColor(fmt.Sprintf("+ %s", rule)))
+		}
+	}
+
+	cmd.ui.Say("")
+	cmd.ui.Say(T("{{.Unchanged}} rules unchanged", map[string]interface{}{"Unchanged": unchanged}))
+
+	return unchanged != len(currentRules) || unchanged != len(updatedRules), nil
+}
+
+func encodeRules(groupRules []map[string]interface{}) ([]string, error) {
+	encoded := []string{}
+	for _, rule := range groupRules {
+		bytes, err := json.Marshal(rule)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, string(bytes))
+	}
+	return encoded, nil
+}
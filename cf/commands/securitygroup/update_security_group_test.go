@@ -3,6 +3,7 @@ package securitygroup_test
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/cloudfoundry/cli/cf/api/securitygroups/securitygroupsfakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
@@ -142,4 +143,102 @@ var _ = Describe("update-security-group command", func() {
 			})
 		})
 	})
+
+	Describe("updating with --diff", func() {
+		var (
+			dir  string
+			path string
+		)
+
+		BeforeEach(func() {
+			requirementsFactory.LoginSuccess = true
+			securityGroup := models.SecurityGroup{
+				SecurityGroupFields: models.SecurityGroupFields{
+					Name: "my-group-name",
+					GUID: "my-group-guid",
+					Rules: []map[string]interface{}{
+						{"protocol": "tcp", "destination": "10.0.11.0/24", "ports": "5432"},
+						{"protocol": "icmp", "destination": "10.0.11.4", "type": float64(0), "code": float64(0)},
+					},
+				},
+			}
+			securityGroupRepo.ReadReturns(securityGroup, nil)
+
+			var err error
+			dir, err = ioutil.TempDir("", "update-security-group")
+			Expect(err).NotTo(HaveOccurred())
+			path = filepath.Join(dir, "rules.yml")
+			Expect(ioutil.WriteFile(path, []byte(`
+- protocol: tcp
+  destination: 10.0.11.0/24
+  ports: "5432"
+- protocol: udp
+  destination: 10.0.0.2
+  ports: "53"
+  log: true
+`), 0600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("shows the removed and added rules and updates after confirmation", func() {
+			ui.Inputs = []string{"y"}
+
+			Expect(runCommand("my-group-name", path, "--diff")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Comparing the rules of security group", "my-group-name", "my-user"},
+				[]string{`- {"code":0,"destination":"10.0.11.4","protocol":"icmp","type":0}`},
+				[]string{`+ {"destination":"10.0.0.2","log":true,"ports":"53","protocol":"udp"}`},
+				[]string{"1 rules unchanged"},
+				[]string{"Updating security group", "my-group-name"},
+				[]string{"OK"},
+			))
+			Expect(ui.Prompts).To(ContainSubstrings([]string{"Really update the rules of security group my-group-name"}))
+
+			guid, rules := securityGroupRepo.UpdateArgsForCall(0)
+			Expect(guid).To(Equal("my-group-guid"))
+			Expect(rules).To(Equal([]map[string]interface{}{
+				{"protocol": "tcp", "destination": "10.0.11.0/24", "ports": "5432"},
+				{"protocol": "udp", "destination": "10.0.0.2", "ports": "53", "log": true},
+			}))
+		})
+
+		It("does not update when the user declines", func() {
+			ui.Inputs = []string{"n"}
+
+			Expect(runCommand("my-group-name", path, "--diff")).To(BeTrue())
+			Expect(securityGroupRepo.UpdateCallCount()).To(Equal(0))
+		})
+
+		It("does not ask for confirmation with -f", func() {
+			Expect(runCommand("my-group-name", path, "--diff", "-f")).To(BeTrue())
+
+			Expect(ui.Prompts).To(BeEmpty())
+			Expect(securityGroupRepo.UpdateCallCount()).To(Equal(1))
+		})
+
+		It("does not update when the rules have not changed", func() {
+			Expect(ioutil.WriteFile(path, []byte(`
+- protocol: icmp
+  destination: 10.0.11.4
+  type: 0
+  code: 0
+- protocol: tcp
+  destination: 10.0.11.0/24
+  ports: "5432"
+`), 0600)).To(Succeed())
+
+			Expect(runCommand("my-group-name", path, "--diff")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"2 rules unchanged"},
+				[]string{"already up to date"},
+			))
+			Expect(ui.Prompts).To(BeEmpty())
+			Expect(securityGroupRepo.UpdateCallCount()).To(Equal(0))
+		})
+	})
 })
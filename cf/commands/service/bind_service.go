@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cloudfoundry/cli/cf"
 	"github.com/cloudfoundry/cli/cf/api"
@@ -26,8 +27,11 @@ type BindService struct {
 	ui                 terminal.UI
 	config             coreconfig.Reader
 	serviceBindingRepo api.ServiceBindingRepository
+	serviceRepo        api.ServiceRepository
 	appReq             requirements.ApplicationRequirement
 	serviceInstanceReq requirements.ServiceInstanceRequirement
+
+	PollInterval time.Duration
 }
 
 func init() {
@@ -35,7 +39,7 @@ func init() {
 }
 
 func (cmd *BindService) MetaData() commandregistry.CommandMetadata {
-	baseUsage := T("CF_NAME bind-service APP_NAME SERVICE_INSTANCE [-c PARAMETERS_AS_JSON]")
+	baseUsage := T("CF_NAME bind-service APP_NAME SERVICE_INSTANCE [-c PARAMETERS_AS_JSON] [--wait [--timeout MINUTES]]")
 	paramsUsage := T(`   Optionally provide service-specific configuration parameters in a valid JSON object in-line:
 
    CF_NAME bind-service APP_NAME SERVICE_INSTANCE -c '{"name":"value","name":"value"}'
@@ -48,9 +52,11 @@ func (cmd *BindService) MetaData() commandregistry.CommandMetadata {
    {
       "permissions": "read-only"
    }`)
+	waitUsage := T(`   With --wait, an asynchronous operation in progress on the service instance, such as its creation, is waited for before binding.`)
 
 	fs := make(map[string]flags.FlagSet)
 	fs["c"] = &flags.StringFlag{ShortName: "c", Usage: T("Valid JSON object containing service-specific configuration parameters, provided either in-line or in a file. For a list of supported configuration parameters, see documentation for the particular service offering.")}
+	addWaitFlags(fs)
 
 	return commandregistry.CommandMetadata{
 		Name:        "bind-service",
@@ -60,6 +66,8 @@ func (cmd *BindService) MetaData() commandregistry.CommandMetadata {
 			baseUsage,
 			"\n\n",
 			paramsUsage,
+			"\n\n",
+			waitUsage,
 		},
 		Examples: []string{
 			fmt.Sprintf("%s:", T(`Linux/Mac`)),
@@ -81,6 +89,7 @@ func (cmd *BindService) Requirements(requirementsFactory requirements.Factory, f
 	if len(fc.Args()) != 2 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires APP_NAME and SERVICE_INSTANCE as arguments\n\n") + commandregistry.Commands.CommandUsage("bind-service"))
 	}
+	checkWaitFlags(cmd.ui, fc, "bind-service")
 
 	serviceName := fc.Args()[1]
 
@@ -100,6 +109,8 @@ func (cmd *BindService) SetDependency(deps commandregistry.Dependency, pluginCal
 	cmd.ui = deps.UI
 	cmd.config = deps.Config
 	cmd.serviceBindingRepo = deps.RepoLocator.GetServiceBindingRepository()
+	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
+	cmd.PollInterval = DefaultServicePollInterval
 	return cmd
 }
 
@@ -122,6 +133,13 @@ func (cmd *BindService) Execute(c flags.FlagContext) error {
 			"CurrentUser":         terminal.EntityNameColor(cmd.config.Username()),
 		}))
 
+	if c.Bool("wait") {
+		err = newServiceOperationWaiter(cmd.ui, cmd.serviceRepo, c, cmd.PollInterval).Wait(serviceInstance.Name, false)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = cmd.BindApplication(app, serviceInstance, paramsMap)
 	if err != nil {
 		if httperr, ok := err.(errors.HTTPError); ok && httperr.ErrorCode() == errors.ServiceBindingAppServiceTaken {
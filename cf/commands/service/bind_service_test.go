@@ -4,9 +4,11 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/cloudfoundry/cli/cf/api/apifakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands/service"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/models"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
@@ -26,6 +28,7 @@ var _ = Describe("bind-service command", func() {
 		requirementsFactory *testreq.FakeReqFactory
 		config              coreconfig.Repository
 		serviceBindingRepo  *apifakes.FakeServiceBindingRepository
+		serviceRepo         *apifakes.FakeServiceRepository
 		deps                commandregistry.Dependency
 	)
 
@@ -33,7 +36,10 @@ var _ = Describe("bind-service command", func() {
 		deps.UI = ui
 		deps.Config = config
 		deps.RepoLocator = deps.RepoLocator.SetServiceBindingRepository(serviceBindingRepo)
-		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("bind-service").SetDependency(deps, pluginCall))
+		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
+		cmd := commandregistry.Commands.FindCommand("bind-service").SetDependency(deps, pluginCall).(*service.BindService)
+		cmd.PollInterval = time.Millisecond
+		commandregistry.Commands.SetCommand(cmd)
 	}
 
 	BeforeEach(func() {
@@ -41,6 +47,7 @@ var _ = Describe("bind-service command", func() {
 		config = testconfig.NewRepositoryWithDefaults()
 		requirementsFactory = new(testreq.FakeReqFactory)
 		serviceBindingRepo = new(apifakes.FakeServiceBindingRepository)
+		serviceRepo = new(apifakes.FakeServiceRepository)
 	})
 
 	var callBindService = func(args []string) bool {
@@ -116,6 +123,49 @@ var _ = Describe("bind-service command", func() {
 			))
 		})
 
+		Context("when --wait is given", func() {
+			var inProgress models.ServiceInstance
+
+			BeforeEach(func() {
+				inProgress = serviceInstance
+				inProgress.LastOperation = models.LastOperationFields{Type: "create", State: "in progress", Description: "provisioning"}
+				succeeded := serviceInstance
+				succeeded.LastOperation = models.LastOperationFields{Type: "create", State: "succeeded"}
+
+				serviceRepo.FindInstanceByNameStub = func(name string) (models.ServiceInstance, error) {
+					if serviceRepo.FindInstanceByNameCallCount() < 3 {
+						return inProgress, nil
+					}
+					return succeeded, nil
+				}
+			})
+
+			It("waits for the operation in progress on the service instance before binding", func() {
+				Expect(callBindService([]string{"my-app", "my-service", "--wait"})).To(BeTrue())
+
+				Expect(serviceRepo.FindInstanceByNameCallCount()).To(Equal(3))
+				Expect(serviceRepo.FindInstanceByNameArgsForCall(0)).To(Equal("my-service"))
+				Expect(serviceBindingRepo.CreateCallCount()).To(Equal(1))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Binding service", "my-service", "my-app"},
+					[]string{"Waiting for the create of service instance my-service to finish..."},
+					[]string{"provisioning"},
+					[]string{"OK"},
+				))
+			})
+
+			It("does not bind when the operation fails", func() {
+				inProgress.LastOperation.State = "failed"
+
+				Expect(callBindService([]string{"my-app", "my-service", "--wait"})).To(BeFalse())
+				Expect(serviceBindingRepo.CreateCallCount()).To(BeZero())
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"The create of service instance my-service failed: provisioning"},
+				))
+			})
+		})
+
 		It("fails with usage when called without a service instance and app", func() {
 			callBindService([]string{"my-service"})
 			Expect(ui.Outputs).To(ContainSubstrings(
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cloudfoundry/cli/cf/actors/servicebuilder"
 	"github.com/cloudfoundry/cli/cf/api"
@@ -17,11 +18,19 @@ import (
 	"github.com/cloudfoundry/cli/json"
 )
 
+const (
+	DefaultServiceOperationTimeout = 30 * time.Minute
+	DefaultServicePollInterval     = 2 * time.Second
+	maxServicePollInterval         = 30 * time.Second
+)
+
 type CreateService struct {
 	ui             terminal.UI
 	config         coreconfig.Reader
 	serviceRepo    api.ServiceRepository
 	serviceBuilder servicebuilder.ServiceBuilder
+
+	PollInterval time.Duration
 }
 
 func init() {
@@ -32,8 +41,9 @@ func (cmd *CreateService) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["c"] = &flags.StringFlag{ShortName: "c", Usage: T("Valid JSON object containing service-specific configuration parameters, provided either in-line or in a file. For a list of supported configuration parameters, see documentation for the particular service offering.")}
 	fs["t"] = &flags.StringFlag{ShortName: "t", Usage: T("User provided tags")}
+	addWaitFlags(fs)
 
-	baseUsage := T("CF_NAME create-service SERVICE PLAN SERVICE_INSTANCE [-c PARAMETERS_AS_JSON] [-t TAGS]")
+	baseUsage := T("CF_NAME create-service SERVICE PLAN SERVICE_INSTANCE [-c PARAMETERS_AS_JSON] [-t TAGS] [--wait [--timeout MINUTES]]")
 	paramsUsage := T(`   Optionally provide service-specific configuration parameters in a valid JSON object in-line:
 
    CF_NAME create-service SERVICE PLAN SERVICE_INSTANCE -c '{"name":"value","name":"value"}'
@@ -76,6 +86,8 @@ func (cmd *CreateService) MetaData() commandregistry.CommandMetadata {
 			`CF_NAME create-service db-service silver mydb -c ~/workspace/tmp/instance_config.json`,
 			``,
 			`CF_NAME create-service db-service silver mydb -t "list, of, tags"`,
+			``,
+			`CF_NAME create-service db-service silver mydb --wait --timeout 60`,
 		},
 		Flags: fs,
 	}
@@ -85,6 +97,7 @@ func (cmd *CreateService) Requirements(requirementsFactory requirements.Factory,
 	if len(fc.Args()) != 3 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires service, service plan, service instance as arguments\n\n") + commandregistry.Commands.CommandUsage("create-service"))
 	}
+	checkWaitFlags(cmd.ui, fc, "create-service")
 
 	reqs := []requirements.Requirement{
 		requirementsFactory.NewLoginRequirement(),
@@ -99,6 +112,7 @@ func (cmd *CreateService) SetDependency(deps commandregistry.Dependency, pluginC
 	cmd.config = deps.Config
 	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
 	cmd.serviceBuilder = deps.ServiceBuilder
+	cmd.PollInterval = DefaultServicePollInterval
 	return cmd
 }
 
@@ -128,9 +142,17 @@ func (cmd *CreateService) Execute(c flags.FlagContext) error {
 
 	switch err.(type) {
 	case nil:
-		err := printSuccessMessageForServiceInstance(serviceInstanceName, cmd.serviceRepo, cmd.ui)
-		if err != nil {
-			return err
+		if c.Bool("wait") {
+			err = newServiceOperationWaiter(cmd.ui, cmd.serviceRepo, c, cmd.PollInterval).Wait(serviceInstanceName, false)
+			if err != nil {
+				return err
+			}
+			cmd.ui.Ok()
+		} else {
+			err = printSuccessMessageForServiceInstance(serviceInstanceName, cmd.serviceRepo, cmd.ui)
+			if err != nil {
+				return err
+			}
 		}
 
 		if !plan.Free {
@@ -181,3 +203,106 @@ func findPlanFromOfferings(offerings models.ServiceOfferings, name string) (plan
 	))
 	return
 }
+
+// serviceOperationWaiter polls the last operation of a service instance
+// until an asynchronous broker reports that it succeeded or failed.
+type serviceOperationWaiter struct {
+	ui           terminal.UI
+	serviceRepo  api.ServiceRepository
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+func addWaitFlags(fs map[string]flags.FlagSet) {
+	fs["wait"] = &flags.BoolFlag{Name: "wait", Usage: T("Wait until the asynchronous operation of the service instance succeeds or fails")}
+	fs["timeout"] = &flags.IntFlag{Name: "timeout", Usage: T("Minutes to wait with --wait (Default: 30)")}
+}
+
+// checkWaitFlags fails with the usage of the command when --timeout is
+// given without --wait.
+func checkWaitFlags(ui terminal.UI, fc flags.FlagContext, commandName string) {
+	if fc.IsSet("timeout") && !fc.Bool("wait") {
+		ui.Failed(T("Incorrect Usage. --timeout can only be used with --wait\n\n") + commandregistry.Commands.CommandUsage(commandName))
+	}
+}
+
+func newServiceOperationWaiter(ui terminal.UI, serviceRepo api.ServiceRepository, fc flags.FlagContext, pollInterval time.Duration) serviceOperationWaiter {
+	timeout := DefaultServiceOperationTimeout
+	if fc.IsSet("timeout") {
+		timeout = time.Duration(fc.Int("timeout")) * time.Minute
+	}
+
+	return serviceOperationWaiter{
+		ui:           ui,
+		serviceRepo:  serviceRepo,
+		timeout:      timeout,
+		pollInterval: pollInterval,
+	}
+}
+
+// Wait returns once the operation in progress on the service instance, if
+// any, has succeeded. When deleting, the instance no longer existing means
+// the operation succeeded.
+func (waiter serviceOperationWaiter) Wait(serviceInstanceName string, deleting bool) error {
+	deadline := time.Now().Add(waiter.timeout)
+	interval := waiter.pollInterval
+	announced := false
+	lastDescription := ""
+
+	for {
+		instance, err := waiter.serviceRepo.FindInstanceByName(serviceInstanceName)
+		switch err.(type) {
+		case nil:
+		case *errors.ModelNotFoundError:
+			if deleting {
+				return nil
+			}
+			return err
+		default:
+			return err
+		}
+
+		operation := instance.LastOperation
+		params := map[string]interface{}{
+			"Operation":   operation.Type,
+			"ServiceName": terminal.EntityNameColor(serviceInstanceName),
+			"Description": operation.Description,
+			"Timeout":     waiter.timeout.String(),
+		}
+
+		switch operation.State {
+		case "in progress":
+		case "failed":
+			if operation.Description == "" {
+				return errors.New(T("The {{.Operation}} of service instance {{.ServiceName}} failed", params))
+			}
+			return errors.New(T("The {{.Operation}} of service instance {{.ServiceName}} failed: {{.Description}}", params))
+		default:
+			return nil
+		}
+
+		if !announced {
+			waiter.ui.Say(T("Waiting for the {{.Operation}} of service instance {{.ServiceName}} to finish...", params))
+			announced = true
+		}
+		if operation.Description != "" && operation.Description != lastDescription {
+			waiter.ui.Say("   " + operation.Description)
+			lastDescription = operation.Description
+		}
+
+		remaining := deadline.Sub(time.Now())
+		if remaining <= 0 {
+			return errors.New(T("Timed out after {{.Timeout}} waiting for the {{.Operation}} of service instance {{.ServiceName}}", params))
+		}
+
+		if interval > remaining {
+			interval = remaining
+		}
+		time.Sleep(interval)
+
+		interval *= 2
+		if interval > maxServicePollInterval {
+			interval = maxServicePollInterval
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/cloudfoundry/cli/cf/actors/servicebuilder/servicebuilderfakes"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
@@ -17,6 +19,7 @@ import (
 	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
 
 	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands/service"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -42,7 +45,9 @@ var _ = Describe("create-service command", func() {
 		deps.Config = config
 		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
 		deps.ServiceBuilder = serviceBuilder
-		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("create-service").SetDependency(deps, pluginCall))
+		cmd := commandregistry.Commands.FindCommand("create-service").SetDependency(deps, pluginCall).(*service.CreateService)
+		cmd.PollInterval = time.Millisecond
+		commandregistry.Commands.SetCommand(cmd)
 	}
 
 	BeforeEach(func() {
@@ -75,6 +80,13 @@ var _ = Describe("create-service command", func() {
 	}
 
 	Describe("requirements", func() {
+		It("fails with usage when --timeout is given without --wait", func() {
+			callCreateService([]string{"cleardb", "spark", "my-cleardb-service", "--timeout", "5"})
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--timeout can only be used with --wait"},
+			))
+		})
+
 		It("passes when logged in and a space is targeted", func() {
 			Expect(callCreateService([]string{"cleardb", "spark", "my-cleardb-service"})).To(BeTrue())
 		})
@@ -240,6 +252,69 @@ var _ = Describe("create-service command", func() {
 				[]string{"FAILED"},
 				[]string{"Error finding instance"}))
 		})
+
+		Context("when --wait is given", func() {
+			var lastOperations []models.LastOperationFields
+
+			BeforeEach(func() {
+				lastOperations = []models.LastOperationFields{
+					{Type: "create", State: "in progress", Description: "provisioning the cluster"},
+					{Type: "create", State: "in progress", Description: "provisioning the cluster"},
+					{Type: "create", State: "in progress", Description: "configuring backups"},
+					{Type: "create", State: "succeeded", Description: "done"},
+				}
+				serviceRepo.FindInstanceByNameStub = func(name string) (models.ServiceInstance, error) {
+					instance := serviceInstance
+					instance.LastOperation = lastOperations[0]
+					if len(lastOperations) > 1 {
+						lastOperations = lastOperations[1:]
+					}
+					return instance, nil
+				}
+			})
+
+			It("polls the last operation until it succeeds", func() {
+				Expect(callCreateService([]string{"cleardb", "spark", "my-cleardb-service", "--wait"})).To(BeTrue())
+
+				Expect(serviceRepo.FindInstanceByNameCallCount()).To(Equal(4))
+				Expect(serviceRepo.FindInstanceByNameArgsForCall(0)).To(Equal("my-cleardb-service"))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Creating service instance", "my-cleardb-service"},
+					[]string{"Waiting for the create of service instance my-cleardb-service to finish..."},
+					[]string{"provisioning the cluster"},
+					[]string{"configuring backups"},
+					[]string{"OK"},
+				))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Create in progress"}))
+
+				descriptions := 0
+				for _, line := range ui.Outputs {
+					if strings.Contains(line, "provisioning the cluster") {
+						descriptions++
+					}
+				}
+				Expect(descriptions).To(Equal(1))
+			})
+
+			It("fails when the operation fails", func() {
+				lastOperations[3] = models.LastOperationFields{Type: "create", State: "failed", Description: "out of capacity"}
+
+				Expect(callCreateService([]string{"cleardb", "spark", "my-cleardb-service", "--wait"})).To(BeFalse())
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"The create of service instance my-cleardb-service failed: out of capacity"},
+				))
+			})
+
+			It("fails when the operation does not finish before the timeout", func() {
+				Expect(callCreateService([]string{"cleardb", "spark", "my-cleardb-service", "--wait", "--timeout", "0"})).To(BeFalse())
+				Expect(serviceRepo.FindInstanceByNameCallCount()).To(Equal(1))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Timed out after 0s waiting for the create of service instance my-cleardb-service"},
+				))
+			})
+		})
 	})
 
 	Describe("warning the user about paid services", func() {
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/cloudfoundry/cli/cf/api"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
@@ -16,6 +18,8 @@ type DeleteService struct {
 	config             coreconfig.Reader
 	serviceRepo        api.ServiceRepository
 	serviceInstanceReq requirements.ServiceInstanceRequirement
+
+	PollInterval time.Duration
 }
 
 func init() {
@@ -25,13 +29,14 @@ func init() {
 func (cmd *DeleteService) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["f"] = &flags.BoolFlag{ShortName: "f", Usage: T("Force deletion without confirmation")}
+	addWaitFlags(fs)
 
 	return commandregistry.CommandMetadata{
 		Name:        "delete-service",
 		ShortName:   "ds",
 		Description: T("Delete a service instance"),
 		Usage: []string{
-			T("CF_NAME delete-service SERVICE_INSTANCE [-f]"),
+			T("CF_NAME delete-service SERVICE_INSTANCE [-f] [--wait [--timeout MINUTES]]"),
 		},
 		Flags: fs,
 	}
@@ -41,6 +46,7 @@ func (cmd *DeleteService) Requirements(requirementsFactory requirements.Factory,
 	if len(fc.Args()) != 1 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("delete-service"))
 	}
+	checkWaitFlags(cmd.ui, fc, "delete-service")
 
 	reqs := []requirements.Requirement{
 		requirementsFactory.NewLoginRequirement(),
@@ -53,6 +59,7 @@ func (cmd *DeleteService) SetDependency(deps commandregistry.Dependency, pluginC
 	cmd.ui = deps.UI
 	cmd.config = deps.Config
 	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
+	cmd.PollInterval = DefaultServicePollInterval
 	return cmd
 }
 
@@ -90,6 +97,15 @@ func (cmd *DeleteService) Execute(c flags.FlagContext) error {
 		return err
 	}
 
+	if c.Bool("wait") {
+		err = newServiceOperationWaiter(cmd.ui, cmd.serviceRepo, c, cmd.PollInterval).Wait(serviceName, true)
+		if err != nil {
+			return err
+		}
+		cmd.ui.Ok()
+		return nil
+	}
+
 	err = printSuccessMessageForServiceInstance(serviceName, cmd.serviceRepo, cmd.ui)
 	if err != nil {
 		cmd.ui.Ok()
@@ -1,8 +1,11 @@
 package service_test
 
 import (
+	"time"
+
 	"github.com/cloudfoundry/cli/cf/api/apifakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands/service"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/models"
@@ -30,7 +33,9 @@ var _ = Describe("delete-service command", func() {
 		deps.UI = ui
 		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
 		deps.Config = configRepo
-		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("delete-service").SetDependency(deps, pluginCall))
+		cmd := commandregistry.Commands.FindCommand("delete-service").SetDependency(deps, pluginCall).(*service.DeleteService)
+		cmd.PollInterval = time.Millisecond
+		commandregistry.Commands.SetCommand(cmd)
 	}
 
 	BeforeEach(func() {
@@ -146,6 +151,51 @@ var _ = Describe("delete-service command", func() {
 			})
 		})
 
+		Context("when --wait is given", func() {
+			BeforeEach(func() {
+				serviceInstance = models.ServiceInstance{}
+				serviceInstance.Name = "my-service"
+				serviceInstance.GUID = "my-service-guid"
+				serviceInstance.LastOperation = models.LastOperationFields{Type: "delete", State: "in progress", Description: "deprovisioning"}
+
+				instances := []models.ServiceInstance{serviceInstance, serviceInstance, serviceInstance}
+				serviceRepo.FindInstanceByNameStub = func(name string) (models.ServiceInstance, error) {
+					if len(instances) == 0 {
+						return models.ServiceInstance{}, errors.NewModelNotFoundError("Service instance", name)
+					}
+					instance := instances[0]
+					instances = instances[1:]
+					return instance, nil
+				}
+			})
+
+			It("waits until the service instance is gone", func() {
+				Expect(runCommand("-f", "--wait", "my-service")).To(BeTrue())
+
+				Expect(serviceRepo.DeleteServiceArgsForCall(0)).To(Equal(serviceInstance))
+				Expect(serviceRepo.FindInstanceByNameCallCount()).To(Equal(4))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Deleting service", "my-service"},
+					[]string{"Waiting for the delete of service instance my-service to finish..."},
+					[]string{"deprovisioning"},
+					[]string{"OK"},
+				))
+			})
+
+			It("fails when the deletion fails", func() {
+				failed := serviceInstance
+				failed.LastOperation = models.LastOperationFields{Type: "delete", State: "failed"}
+				serviceRepo.FindInstanceByNameStub = nil
+				serviceRepo.FindInstanceByNameReturns(failed, nil)
+
+				Expect(runCommand("-f", "--wait", "my-service")).To(BeFalse())
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"The delete of service instance my-service failed"},
+				))
+			})
+		})
+
 		Context("when the service does not exist", func() {
 			BeforeEach(func() {
 				serviceRepo.FindInstanceByNameReturns(models.ServiceInstance{}, errors.NewModelNotFoundError("Service instance", "my-service"))
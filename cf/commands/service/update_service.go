@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry/cli/cf"
 	"github.com/cloudfoundry/cli/cf/actors/planbuilder"
@@ -24,6 +25,8 @@ type UpdateService struct {
 	config      coreconfig.Reader
 	serviceRepo api.ServiceRepository
 	planBuilder planbuilder.PlanBuilder
+
+	PollInterval time.Duration
 }
 
 func init() {
@@ -31,7 +34,7 @@ func init() {
 }
 
 func (cmd *UpdateService) MetaData() commandregistry.CommandMetadata {
-	baseUsage := T("CF_NAME update-service SERVICE_INSTANCE [-p NEW_PLAN] [-c PARAMETERS_AS_JSON] [-t TAGS]")
+	baseUsage := T("CF_NAME update-service SERVICE_INSTANCE [-p NEW_PLAN] [-c PARAMETERS_AS_JSON] [-t TAGS] [--wait [--timeout MINUTES]]")
 	paramsUsage := T(`   Optionally provide service-specific configuration parameters in a valid JSON object in-line.
    CF_NAME update-service -c '{"name":"value","name":"value"}'
 
@@ -52,6 +55,7 @@ func (cmd *UpdateService) MetaData() commandregistry.CommandMetadata {
 	fs["p"] = &flags.StringFlag{ShortName: "p", Usage: T("Change service plan for a service instance")}
 	fs["c"] = &flags.StringFlag{ShortName: "c", Usage: T("Valid JSON object containing service-specific configuration parameters, provided either in-line or in a file. For a list of supported configuration parameters, see documentation for the particular service offering.")}
 	fs["t"] = &flags.StringFlag{ShortName: "t", Usage: T("User provided tags")}
+	addWaitFlags(fs)
 
 	return commandregistry.CommandMetadata{
 		Name:        "update-service",
@@ -68,6 +72,7 @@ func (cmd *UpdateService) MetaData() commandregistry.CommandMetadata {
 			`CF_NAME update-service mydb -c '{"ram_gb":4}'`,
 			`CF_NAME update-service mydb -c ~/workspace/tmp/instance_config.json`,
 			`CF_NAME update-service mydb -t "list,of, tags"`,
+			`CF_NAME update-service mydb -p gold --wait`,
 		},
 		Flags: fs,
 	}
@@ -77,6 +82,7 @@ func (cmd *UpdateService) Requirements(requirementsFactory requirements.Factory,
 	if len(fc.Args()) != 1 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("update-service"))
 	}
+	checkWaitFlags(cmd.ui, fc, "update-service")
 
 	reqs := []requirements.Requirement{
 		requirementsFactory.NewLoginRequirement(),
@@ -95,6 +101,7 @@ func (cmd *UpdateService) SetDependency(deps commandregistry.Dependency, pluginC
 	cmd.config = deps.Config
 	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
 	cmd.planBuilder = deps.PlanBuilder
+	cmd.PollInterval = DefaultServicePollInterval
 	return cmd
 }
 
@@ -138,6 +145,16 @@ func (cmd *UpdateService) Execute(c flags.FlagContext) error {
 	if err != nil {
 		return err
 	}
+
+	if c.Bool("wait") {
+		err = newServiceOperationWaiter(cmd.ui, cmd.serviceRepo, c, cmd.PollInterval).Wait(serviceInstanceName, false)
+		if err != nil {
+			return err
+		}
+		cmd.ui.Ok()
+		return nil
+	}
+
 	err = printSuccessMessageForServiceInstance(serviceInstanceName, cmd.serviceRepo, cmd.ui)
 	if err != nil {
 		return err
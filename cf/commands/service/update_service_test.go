@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/blang/semver"
 	planbuilderfakes "github.com/cloudfoundry/cli/cf/actors/planbuilder/planbuilderfakes"
@@ -40,7 +41,9 @@ var _ = Describe("update-service command", func() {
 		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
 		deps.Config = config
 		deps.PlanBuilder = planBuilder
-		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("update-service").SetDependency(deps, pluginCall))
+		cmd := commandregistry.Commands.FindCommand("update-service").SetDependency(deps, pluginCall).(*service.UpdateService)
+		cmd.PollInterval = time.Millisecond
+		commandregistry.Commands.SetCommand(cmd)
 	}
 
 	BeforeEach(func() {
@@ -367,6 +370,38 @@ var _ = Describe("update-service command", func() {
 					))
 				})
 			})
+			Context("when --wait is given", func() {
+				BeforeEach(func() {
+					inProgress := models.ServiceInstance{}
+					inProgress.Name = "my-service-instance"
+					inProgress.GUID = "my-service-instance-guid"
+					inProgress.ServiceOffering.GUID = "murkydb-guid"
+					inProgress.LastOperation = models.LastOperationFields{Type: "update", State: "in progress", Description: "fake service instance description"}
+					succeeded := inProgress
+					succeeded.LastOperation = models.LastOperationFields{Type: "update", State: "succeeded"}
+
+					serviceRepo.FindInstanceByNameStub = func(name string) (models.ServiceInstance, error) {
+						if serviceRepo.FindInstanceByNameCallCount() < 3 {
+							return inProgress, nil
+						}
+						return succeeded, nil
+					}
+				})
+
+				It("waits for the update to finish", func() {
+					Expect(callUpdateService([]string{"-p", "flare", "--wait", "my-service-instance"})).To(BeTrue())
+
+					Expect(serviceRepo.FindInstanceByNameCallCount()).To(Equal(3))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Updating service", "my-service-instance"},
+						[]string{"Waiting for the update of service instance my-service-instance to finish..."},
+						[]string{"fake service instance description"},
+						[]string{"OK"},
+					))
+					Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Update in progress"}))
+				})
+			})
+
 			Context("when there is an err finding service plans", func() {
 				It("returns an error", func() {
 					planBuilder.GetPlansForServiceForOrgReturns(nil, errors.New("Error fetching plans"))
@@ -0,0 +1,238 @@
+package servicekey
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+	cfjson "github.com/cloudfoundry/cli/json"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+const defaultKeptServiceKeys = 1
+
+type RotateServiceKey struct {
+	ui                         terminal.UI
+	config                     coreconfig.Reader
+	serviceKeyRepo             api.ServiceKeyRepository
+	serviceInstanceRequirement requirements.ServiceInstanceRequirement
+}
+
+// versionedServiceKey is a key named SERVICE_KEY-vN, or SERVICE_KEY itself
+// as version 0.
+type versionedServiceKey struct {
+	models.ServiceKeyFields
+	version int
+}
+
+type versionedServiceKeys []versionedServiceKey
+
+func (keys versionedServiceKeys) Len() int           { return len(keys) }
+func (keys versionedServiceKeys) Swap(i, j int)      { keys[i], keys[j] = keys[j], keys[i] }
+func (keys versionedServiceKeys) Less(i, j int) bool { return keys[i].version < keys[j].version }
+
+func init() {
+	commandregistry.Register(&RotateServiceKey{})
+}
+
+func (cmd *RotateServiceKey) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["c"] = &flags.StringFlag{ShortName: "c", Usage: T("Valid JSON object containing service-specific configuration parameters, provided either in-line or in a file. For a list of supported configuration parameters, see documentation for the particular service offering.")}
+	fs["output"] = &flags.StringFlag{Name: "output", Usage: T("Write the credentials of the new key to the file instead of showing them")}
+	fs["keep"] = &flags.IntFlag{Name: "keep", Usage: T("Number of previous keys to keep (Default: 1)")}
+	fs["grace-period"] = &flags.IntFlag{Name: "grace-period", Usage: T("Seconds to wait before deleting the previous keys, instead of asking for confirmation")}
+	fs["f"] = &flags.BoolFlag{ShortName: "f", Usage: T("Delete the previous keys without confirmation or grace period")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "rotate-service-key",
+		Description: T("Create a new version of a service key and delete its older versions"),
+		Usage: []string{
+			T("CF_NAME rotate-service-key SERVICE_INSTANCE SERVICE_KEY [-c PARAMETERS_AS_JSON] [--output FILE] [--keep NUMBER] [--grace-period SECONDS | -f]"),
+			"\n\n",
+			T("The new key is named SERVICE_KEY-vN, where N is one more than the latest version. A key named SERVICE_KEY itself is taken as version 0. Once the new key is created, all versions but the new one and the latest kept ones are deleted."),
+		},
+		Examples: []string{
+			"CF_NAME rotate-service-key mydb mykey",
+			"CF_NAME rotate-service-key mydb mykey --output creds.json --grace-period 300",
+			"CF_NAME rotate-service-key mydb mykey --keep 0 -f",
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *RotateServiceKey) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 2 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires SERVICE_INSTANCE and SERVICE_KEY as arguments\n\n") + commandregistry.Commands.CommandUsage("rotate-service-key"))
+	}
+	if fc.IsSet("keep") && fc.Int("keep") < 0 {
+		cmd.ui.Failed(T("Incorrect Usage. --keep cannot be negative\n\n") + commandregistry.Commands.CommandUsage("rotate-service-key"))
+	}
+	if fc.IsSet("grace-period") && fc.Bool("f") {
+		cmd.ui.Failed(T("Incorrect Usage. --grace-period and -f cannot be used together\n\n") + commandregistry.Commands.CommandUsage("rotate-service-key"))
+	}
+
+	loginRequirement := requirementsFactory.NewLoginRequirement()
+	cmd.serviceInstanceRequirement = requirementsFactory.NewServiceInstanceRequirement(fc.Args()[0])
+	targetSpaceRequirement := requirementsFactory.NewTargetedSpaceRequirement()
+
+	reqs := []requirements.Requirement{loginRequirement, cmd.serviceInstanceRequirement, targetSpaceRequirement}
+	return reqs
+}
+
+func (cmd *RotateServiceKey) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.serviceKeyRepo = deps.RepoLocator.GetServiceKeyRepository()
+	return cmd
+}
+
+func (cmd *RotateServiceKey) Execute(c flags.FlagContext) error {
+	serviceInstance := cmd.serviceInstanceRequirement.GetServiceInstance()
+	serviceKeyName := c.Args()[1]
+
+	keep := defaultKeptServiceKeys
+	if c.IsSet("keep") {
+		keep = c.Int("keep")
+	}
+
+	paramsMap, err := cfjson.ParseJSONFromFileOrString(c.String("c"))
+	if err != nil {
+		return errors.New(T("Invalid configuration provided for -c flag. Please provide a valid JSON object or path to a file containing a valid JSON object."))
+	}
+
+	serviceKeys, err := cmd.serviceKeyRepo.ListServiceKeys(serviceInstance.GUID)
+	if err != nil {
+		return err
+	}
+
+	previousKeys := findServiceKeyVersions(serviceKeys, serviceKeyName)
+	newVersion := 1
+	if len(previousKeys) > 0 {
+		newVersion = previousKeys[len(previousKeys)-1].version + 1
+	}
+	newKeyName := serviceKeyName + "-v" + strconv.Itoa(newVersion)
+
+	cmd.ui.Say(T("Creating service key {{.ServiceKeyName}} for service instance {{.ServiceInstanceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"ServiceInstanceName": terminal.EntityNameColor(serviceInstance.Name),
+			"ServiceKeyName":      terminal.EntityNameColor(newKeyName),
+			"CurrentUser":         terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	err = cmd.serviceKeyRepo.CreateServiceKey(serviceInstance.GUID, newKeyName, paramsMap)
+	if err != nil {
+		return err
+	}
+
+	newKey, err := cmd.serviceKeyRepo.GetServiceKey(serviceInstance.GUID, newKeyName)
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	jsonBytes, err := json.MarshalIndent(newKey.Credentials, "", " ")
+	if err != nil {
+		return err
+	}
+
+	if path := c.String("output"); path != "" {
+		err = ioutil.WriteFile(path, append(jsonBytes, '\n'), 0600)
+		if err != nil {
+			return err
+		}
+		cmd.ui.Say(T("Credentials of service key {{.ServiceKeyName}} written to {{.Path}}",
+			map[string]interface{}{
+				"ServiceKeyName": terminal.EntityNameColor(newKeyName),
+				"Path":           terminal.EntityNameColor(path),
+			}))
+	} else {
+		cmd.ui.Say(string(jsonBytes))
+	}
+
+	if len(previousKeys) <= keep {
+		return nil
+	}
+	oldKeys := previousKeys[:len(previousKeys)-keep]
+
+	oldKeyNames := []string{}
+	for _, key := range oldKeys {
+		oldKeyNames = append(oldKeyNames, key.Name)
+	}
+	params := map[string]interface{}{
+		"ServiceKeyNames":     strings.Join(oldKeyNames, ", "),
+		"ServiceInstanceName": serviceInstance.Name,
+	}
+
+	cmd.ui.Say("")
+	switch {
+	case c.Bool("f"):
+	case c.IsSet("grace-period"):
+		gracePeriod := time.Duration(c.Int("grace-period")) * time.Second
+		params["GracePeriod"] = gracePeriod.String()
+		cmd.ui.Say(T("Waiting {{.GracePeriod}} before deleting service keys {{.ServiceKeyNames}}...", params))
+		time.Sleep(gracePeriod)
+	default:
+		if !cmd.ui.Confirm(T("Really delete service keys {{.ServiceKeyNames}} of service instance {{.ServiceInstanceName}}?", params)) {
+			cmd.ui.Say(T("Kept service keys {{.ServiceKeyNames}}", params))
+			return nil
+		}
+	}
+
+	for _, key := range oldKeys {
+		cmd.ui.Say(T("Deleting key {{.ServiceKeyName}} for service instance {{.ServiceInstanceName}} as {{.CurrentUser}}...",
+			map[string]interface{}{
+				"ServiceKeyName":      terminal.EntityNameColor(key.Name),
+				"ServiceInstanceName": terminal.EntityNameColor(serviceInstance.Name),
+				"CurrentUser":         terminal.EntityNameColor(cmd.config.Username()),
+			}))
+
+		err = cmd.serviceKeyRepo.DeleteServiceKey(key.GUID)
+		if err != nil {
+			return err
+		}
+		cmd.ui.Ok()
+	}
+
+	return nil
+}
+
+// findServiceKeyVersions returns the versions of the key, oldest first.
+func findServiceKeyVersions(serviceKeys []models.ServiceKey, serviceKeyName string) []versionedServiceKey {
+	versionPattern := regexp.MustCompile("^" + regexp.QuoteMeta(serviceKeyName) + `-v(\d+)$`)
+
+	versions := versionedServiceKeys{}
+	for _, key := range serviceKeys {
+		if key.Fields.Name == serviceKeyName {
+			versions = append(versions, versionedServiceKey{ServiceKeyFields: key.Fields})
+			continue
+		}
+
+		matches := versionPattern.FindStringSubmatch(key.Fields.Name)
+		if matches == nil {
+			continue
+		}
+		version, err := strconv.Atoi(matches[1])
+		if err != nil {
+			continue
+		}
+		versions = append(versions, versionedServiceKey{ServiceKeyFields: key.Fields, version: version})
+	}
+
+	sort.Sort(versions)
+	return versions
+}
@@ -0,0 +1,260 @@
+package servicekey_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rotate-service-key command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		config              coreconfig.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		serviceKeyRepo      *apifakes.FakeServiceKeyRepository
+		deps                commandregistry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.RepoLocator = deps.RepoLocator.SetServiceKeyRepository(serviceKeyRepo)
+		deps.Config = config
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("rotate-service-key").SetDependency(deps, pluginCall))
+	}
+
+	serviceKey := func(name string) models.ServiceKey {
+		return models.ServiceKey{
+			Fields: models.ServiceKeyFields{Name: name, GUID: name + "-guid"},
+		}
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepositoryWithDefaults()
+		serviceKeyRepo = new(apifakes.FakeServiceKeyRepository)
+
+		serviceInstance := models.ServiceInstance{}
+		serviceInstance.GUID = "fake-instance-guid"
+		serviceInstance.Name = "fake-service-instance"
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, ServiceInstanceNotFound: false}
+		requirementsFactory.ServiceInstance = serviceInstance
+
+		serviceKeyRepo.ListServiceKeysReturns([]models.ServiceKey{
+			serviceKey("fake-key-v2"),
+			serviceKey("other-key"),
+			serviceKey("fake-key"),
+			serviceKey("fake-key-v10"),
+			serviceKey("fake-key-vnext"),
+		}, nil)
+		serviceKeyRepo.GetServiceKeyStub = func(instanceGUID string, keyName string) (models.ServiceKey, error) {
+			key := serviceKey(keyName)
+			key.Credentials = map[string]interface{}{"username": "new-user", "password": "new-password"}
+			return key, nil
+		}
+	})
+
+	var callRotateServiceKey = func(args ...string) bool {
+		return testcmd.RunCLICommand("rotate-service-key", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	deletedKeyGUIDs := func() []string {
+		guids := []string{}
+		for i := 0; i < serviceKeyRepo.DeleteServiceKeyCallCount(); i++ {
+			guids = append(guids, serviceKeyRepo.DeleteServiceKeyArgsForCall(i))
+		}
+		return guids
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: false}
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key")).To(BeFalse())
+		})
+
+		It("requires two arguments to run", func() {
+			Expect(callRotateServiceKey()).To(BeFalse())
+			Expect(callRotateServiceKey("fake-arg-one")).To(BeFalse())
+			Expect(callRotateServiceKey("fake-arg-one", "fake-arg-two", "fake-arg-three")).To(BeFalse())
+		})
+
+		It("fails when service instance is not found", func() {
+			requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, ServiceInstanceNotFound: true}
+			Expect(callRotateServiceKey("non-exist-service-instance", "fake-key")).To(BeFalse())
+		})
+
+		It("fails when space is not targeted", func() {
+			requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: false}
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key")).To(BeFalse())
+		})
+
+		It("fails with usage when --keep is negative", func() {
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key", "--keep", "-1")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"--keep cannot be negative"}))
+		})
+
+		It("fails with usage when --grace-period is used with -f", func() {
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key", "--grace-period", "10", "-f")).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"--grace-period and -f cannot be used together"}))
+		})
+	})
+
+	Describe("requirements are satisfied", func() {
+		It("creates the next version of the key and shows its credentials", func() {
+			ui.Inputs = []string{"n"}
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key")).To(BeTrue())
+
+			Expect(serviceKeyRepo.ListServiceKeysArgsForCall(0)).To(Equal("fake-instance-guid"))
+			Expect(serviceKeyRepo.CreateServiceKeyCallCount()).To(Equal(1))
+			instanceGUID, keyName, params := serviceKeyRepo.CreateServiceKeyArgsForCall(0)
+			Expect(instanceGUID).To(Equal("fake-instance-guid"))
+			Expect(keyName).To(Equal("fake-key-v11"))
+			Expect(params).To(BeNil())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Creating service key", "fake-key-v11", "for service instance", "fake-service-instance", "as", "my-user"},
+				[]string{"OK"},
+				[]string{`"password": "new-password"`},
+			))
+		})
+
+		It("names the first version v1", func() {
+			serviceKeyRepo.ListServiceKeysReturns([]models.ServiceKey{serviceKey("other-key")}, nil)
+
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key")).To(BeTrue())
+
+			_, keyName, _ := serviceKeyRepo.CreateServiceKeyArgsForCall(0)
+			Expect(keyName).To(Equal("fake-key-v1"))
+			Expect(ui.Prompts).To(BeEmpty())
+			Expect(serviceKeyRepo.DeleteServiceKeyCallCount()).To(Equal(0))
+		})
+
+		It("passes the parameters given with -c", func() {
+			ui.Inputs = []string{"n"}
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key", "-c", `{"foo": "bar"}`)).To(BeTrue())
+
+			_, _, params := serviceKeyRepo.CreateServiceKeyArgsForCall(0)
+			Expect(params).To(Equal(map[string]interface{}{"foo": "bar"}))
+		})
+
+		It("fails when -c is not valid JSON", func() {
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key", "-c", `bad-json`)).To(BeFalse())
+
+			Expect(serviceKeyRepo.CreateServiceKeyCallCount()).To(Equal(0))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Invalid configuration provided for -c flag"},
+			))
+		})
+
+		It("deletes the older versions once confirmed, keeping the latest one", func() {
+			ui.Inputs = []string{"y"}
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key")).To(BeTrue())
+
+			Expect(ui.Prompts).To(ContainSubstrings(
+				[]string{"Really delete service keys fake-key, fake-key-v2 of service instance fake-service-instance?"},
+			))
+			Expect(deletedKeyGUIDs()).To(Equal([]string{"fake-key-guid", "fake-key-v2-guid"}))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Deleting key", "fake-key", "for service instance", "fake-service-instance"},
+				[]string{"OK"},
+				[]string{"Deleting key", "fake-key-v2", "for service instance", "fake-service-instance"},
+				[]string{"OK"},
+			))
+		})
+
+		It("keeps the older versions when not confirmed", func() {
+			ui.Inputs = []string{"n"}
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key")).To(BeTrue())
+
+			Expect(serviceKeyRepo.DeleteServiceKeyCallCount()).To(Equal(0))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Kept service keys fake-key, fake-key-v2"}))
+		})
+
+		It("keeps the number of previous versions given with --keep", func() {
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key", "--keep", "0", "-f")).To(BeTrue())
+
+			Expect(ui.Prompts).To(BeEmpty())
+			Expect(deletedKeyGUIDs()).To(Equal([]string{"fake-key-guid", "fake-key-v2-guid", "fake-key-v10-guid"}))
+		})
+
+		It("waits for the grace period instead of asking for confirmation", func() {
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key", "--grace-period", "0")).To(BeTrue())
+
+			Expect(ui.Prompts).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Waiting 0s before deleting service keys fake-key, fake-key-v2"}))
+			Expect(deletedKeyGUIDs()).To(Equal([]string{"fake-key-guid", "fake-key-v2-guid"}))
+		})
+
+		It("fails when an older version cannot be deleted", func() {
+			serviceKeyRepo.DeleteServiceKeyReturns(errors.New("delete failed"))
+
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key", "-f")).To(BeFalse())
+
+			Expect(serviceKeyRepo.DeleteServiceKeyCallCount()).To(Equal(1))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"delete failed"},
+			))
+		})
+
+		It("does not delete anything when the new key cannot be created", func() {
+			serviceKeyRepo.CreateServiceKeyReturns(errors.NewUnbindableServiceError())
+
+			Expect(callRotateServiceKey("fake-service-instance", "fake-key", "-f")).To(BeFalse())
+
+			Expect(serviceKeyRepo.DeleteServiceKeyCallCount()).To(Equal(0))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"This service doesn't support creation of keys."},
+			))
+		})
+
+		Context("when --output is given", func() {
+			var tempDir string
+
+			BeforeEach(func() {
+				var err error
+				tempDir, err = ioutil.TempDir("", "rotate-service-key")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(tempDir)
+			})
+
+			It("writes the credentials to the file instead of showing them", func() {
+				path := filepath.Join(tempDir, "creds.json")
+
+				Expect(callRotateServiceKey("fake-service-instance", "fake-key", "--output", path, "--keep", "5")).To(BeTrue())
+
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"new-password"}))
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"Credentials of service key", "fake-key-v11", "written to", path}))
+
+				contents, err := ioutil.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+				var credentials map[string]interface{}
+				Expect(json.Unmarshal(contents, &credentials)).To(Succeed())
+				Expect(credentials).To(Equal(map[string]interface{}{"username": "new-user", "password": "new-password"}))
+
+				info, err := os.Stat(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			})
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/trace"
 	"github.com/cloudfoundry/cli/flags"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
@@ -29,12 +30,13 @@ func init() {
 func (cmd *ServiceKey) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["guid"] = &flags.BoolFlag{Name: "guid", Usage: T("Retrieve and display the given service-key's guid.  All other output for the service is suppressed.")}
+	fs["show-secrets"] = &flags.BoolFlag{Name: "show-secrets", Usage: T("Show passwords, keys and other credentials instead of hiding them")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "service-key",
 		Description: T("Show service key info"),
 		Usage: []string{
-			T("CF_NAME service-key SERVICE_INSTANCE SERVICE_KEY"),
+			T("CF_NAME service-key SERVICE_INSTANCE SERVICE_KEY [--guid] [--show-secrets]"),
 		},
 		Examples: []string{
 			"CF_NAME service-key mydb mykey",
@@ -102,7 +104,16 @@ func (cmd *ServiceKey) Execute(c flags.FlagContext) error {
 			return nil
 		}
 
-		jsonBytes, err := json.MarshalIndent(serviceKey.Credentials, "", " ")
+		credentials := serviceKey.Credentials
+		if !c.Bool("show-secrets") {
+			masker, err := trace.NewSecretMasker(cmd.config.SecretPatterns())
+			if err != nil {
+				return err
+			}
+			credentials = masker.MaskVariables(credentials)
+		}
+
+		jsonBytes, err := json.MarshalIndent(credentials, "", " ")
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,257 @@
+package space
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/spacequotas"
+	"github.com/cloudfoundry/cli/cf/api/stacks"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/spacebundle"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type ExportSpace struct {
+	ui                 terminal.UI
+	config             coreconfig.Reader
+	spaceReq           requirements.SpaceRequirement
+	appSummaryRepo     api.AppSummaryRepository
+	stackRepo          stacks.StackRepository
+	serviceSummaryRepo api.ServiceSummaryRepository
+	userProvidedRepo   api.UserProvidedServiceInstanceRepository
+	routeRepo          api.RouteRepository
+	spaceQuotaRepo     spacequotas.SpaceQuotaRepository
+	manifest           manifest.AppManifest
+}
+
+func init() {
+	commandregistry.Register(&ExportSpace{})
+}
+
+func (cmd *ExportSpace) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["o"] = &flags.StringFlag{ShortName: "o", Usage: T("Directory to write the bundle to, defaults to a directory named after the space")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "export-space",
+		Description: T("Export the apps, services, routes, space quota and security groups of a space to a bundle"),
+		Usage: []string{
+			T("CF_NAME export-space SPACE [-o DIRECTORY]"),
+			"\n\n",
+			T("The parameters of service instances cannot be exported, add them to space.yml in the bundle before importing it if they are needed."),
+		},
+		Examples: []string{
+			"CF_NAME export-space production -o bundle/",
+			"CF_NAME import-space bundle/",
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *ExportSpace) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("export-space"))
+	}
+
+	cmd.spaceReq = requirementsFactory.NewSpaceRequirement(fc.Args()[0])
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedOrgRequirement(),
+		cmd.spaceReq,
+	}
+
+	return reqs
+}
+
+func (cmd *ExportSpace) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.stackRepo = deps.RepoLocator.GetStackRepository()
+	cmd.serviceSummaryRepo = deps.RepoLocator.GetServiceSummaryRepository()
+	cmd.userProvidedRepo = deps.RepoLocator.GetUserProvidedServiceInstanceRepository()
+	cmd.routeRepo = deps.RepoLocator.GetRouteRepository()
+	cmd.spaceQuotaRepo = deps.RepoLocator.GetSpaceQuotaRepository()
+	cmd.manifest = deps.AppManifest
+	return cmd
+}
+
+func (cmd *ExportSpace) Execute(c flags.FlagContext) error {
+	space := cmd.spaceReq.GetSpace()
+
+	dir := c.String("o")
+	if dir == "" {
+		dir = space.Name
+	}
+
+	cmd.ui.Say(T("Exporting space {{.SpaceName}} in org {{.OrgName}} as {{.Username}} to {{.Path}}...",
+		map[string]interface{}{
+			"SpaceName": terminal.EntityNameColor(space.Name),
+			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.config.Username()),
+			"Path":      terminal.EntityNameColor(dir),
+		}))
+
+	bundle := spacebundle.Bundle{Space: space.Name}
+
+	err := cmd.exportServices(space, &bundle)
+	if err != nil {
+		return err
+	}
+
+	err = cmd.exportRoutes(space, &bundle)
+	if err != nil {
+		return err
+	}
+
+	err = cmd.exportSpaceQuota(space, &bundle)
+	if err != nil {
+		return err
+	}
+
+	for _, securityGroup := range space.SecurityGroups {
+		bundle.SecurityGroups = append(bundle.SecurityGroups, securityGroup.Name)
+	}
+
+	err = spacebundle.Write(dir, bundle)
+	if err != nil {
+		return errors.New(T("Error writing bundle: ") + err.Error())
+	}
+
+	if len(space.Applications) > 0 {
+		err = cmd.exportApps(space, filepath.Join(dir, spacebundle.ManifestFile))
+		if err != nil {
+			return err
+		}
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("Exported {{.AppCount}} apps, {{.ServiceCount}} service instances and {{.RouteCount}} routes to {{.Path}}",
+		map[string]interface{}{
+			"AppCount":     len(space.Applications),
+			"ServiceCount": len(bundle.Services) + len(bundle.UserProvidedServices),
+			"RouteCount":   len(bundle.Routes),
+			"Path":         terminal.EntityNameColor(dir),
+		}))
+
+	if len(bundle.UserProvidedServices) > 0 {
+		cmd.ui.Warn(T("The bundle contains the credentials of the user provided service instances"))
+	}
+	if len(bundle.Services) > 0 {
+		cmd.ui.Say(T("TIP: The parameters of service instances cannot be exported, add them to {{.File}} before importing the bundle if they are needed",
+			map[string]interface{}{"File": spacebundle.SpaceFile}))
+	}
+
+	return nil
+}
+
+func (cmd *ExportSpace) exportApps(space models.Space, path string) error {
+	for _, appFields := range space.Applications {
+		app, err := cmd.appSummaryRepo.GetSummary(appFields.GUID)
+		if err != nil {
+			return errors.New(T("Error getting application summary: ") + err.Error())
+		}
+
+		stack, err := cmd.stackRepo.FindByGUID(app.StackGUID)
+		if err != nil {
+			return errors.New(T("Error retrieving stack: ") + err.Error())
+		}
+		app.Stack = &stack
+
+		err = manifest.AddApplication(cmd.manifest, app)
+		if err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.New(T("Error creating manifest file: ") + err.Error())
+	}
+	defer f.Close()
+
+	err = cmd.manifest.Save(f)
+	if err != nil {
+		return errors.New(T("Error creating manifest file: ") + err.Error())
+	}
+
+	return nil
+}
+
+func (cmd *ExportSpace) exportServices(space models.Space, bundle *spacebundle.Bundle) error {
+	instances, err := cmd.serviceSummaryRepo.GetSummariesInSpace(space.GUID)
+	if err != nil {
+		return err
+	}
+
+	for _, instance := range instances {
+		if !instance.IsUserProvided() {
+			bundle.Services = append(bundle.Services, spacebundle.Service{
+				Name:    instance.Name,
+				Service: instance.ServiceOffering.Label,
+				Plan:    instance.ServicePlan.Name,
+			})
+		}
+	}
+
+	return cmd.userProvidedRepo.ListUserProvidedServicesInSpace(space.GUID, func(instance models.ServiceInstanceFields) bool {
+		bundle.UserProvidedServices = append(bundle.UserProvidedServices, spacebundle.UserProvidedService{
+			Name:            instance.Name,
+			Credentials:     instance.Params,
+			SyslogDrainURL:  instance.SysLogDrainURL,
+			RouteServiceURL: instance.RouteServiceURL,
+			Tags:            instance.Tags,
+		})
+		return true
+	})
+}
+
+func (cmd *ExportSpace) exportRoutes(space models.Space, bundle *spacebundle.Bundle) error {
+	return cmd.routeRepo.ListRoutesInSpace(space.GUID, func(route models.Route) bool {
+		exported := spacebundle.Route{
+			Host:   route.Host,
+			Domain: route.Domain.Name,
+			Path:   route.Path,
+			Port:   route.Port,
+		}
+		for _, app := range route.Apps {
+			exported.Apps = append(exported.Apps, app.Name)
+		}
+
+		bundle.Routes = append(bundle.Routes, exported)
+		return true
+	})
+}
+
+func (cmd *ExportSpace) exportSpaceQuota(space models.Space, bundle *spacebundle.Bundle) error {
+	if space.SpaceQuotaGUID == "" {
+		return nil
+	}
+
+	quota, err := cmd.spaceQuotaRepo.FindByGUID(space.SpaceQuotaGUID)
+	if err != nil {
+		return err
+	}
+
+	bundle.SpaceQuota = &spacebundle.SpaceQuota{
+		Name:                    quota.Name,
+		MemoryLimit:             quota.MemoryLimit,
+		InstanceMemoryLimit:     quota.InstanceMemoryLimit,
+		RoutesLimit:             quota.RoutesLimit,
+		ServicesLimit:           quota.ServicesLimit,
+		NonBasicServicesAllowed: quota.NonBasicServicesAllowed,
+		AppInstanceLimit:        quota.AppInstanceLimit,
+		ReservedRoutePortsLimit: string(quota.ReservedRoutePortsLimit),
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package space_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/spacequotas/spacequotasfakes"
+	"github.com/cloudfoundry/cli/cf/api/stacks/stacksfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/spacebundle"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("export-space command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          coreconfig.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		appSummaryRepo      *apifakes.FakeAppSummaryRepository
+		stackRepo           *stacksfakes.FakeStackRepository
+		serviceSummaryRepo  *apifakes.FakeServiceSummaryRepository
+		userProvidedRepo    *apifakes.FakeUserProvidedServiceInstanceRepository
+		routeRepo           *apifakes.FakeRouteRepository
+		spaceQuotaRepo      *spacequotasfakes.FakeSpaceQuotaRepository
+		deps                commandregistry.Dependency
+
+		tmpDir    string
+		bundleDir string
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = configRepo
+		deps.AppManifest = manifest.NewGenerator()
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetStackRepository(stackRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceSummaryRepository(serviceSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetUserProvidedServiceInstanceRepository(userProvidedRepo)
+		deps.RepoLocator = deps.RepoLocator.SetRouteRepository(routeRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceQuotaRepository(spaceQuotaRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("export-space").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		appSummaryRepo = new(apifakes.FakeAppSummaryRepository)
+		stackRepo = new(stacksfakes.FakeStackRepository)
+		serviceSummaryRepo = new(apifakes.FakeServiceSummaryRepository)
+		userProvidedRepo = new(apifakes.FakeUserProvidedServiceInstanceRepository)
+		routeRepo = new(apifakes.FakeRouteRepository)
+		spaceQuotaRepo = new(spacequotasfakes.FakeSpaceQuotaRepository)
+
+		space := models.Space{
+			SpaceFields: models.SpaceFields{Name: "production", GUID: "production-guid"},
+			Applications: []models.ApplicationFields{
+				{Name: "my-app", GUID: "my-app-guid"},
+			},
+			SecurityGroups: []models.SecurityGroupFields{
+				{Name: "public-networks"},
+			},
+			SpaceQuotaGUID: "quota-guid",
+		}
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedOrgSuccess: true, Space: space}
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "export-space")
+		Expect(err).NotTo(HaveOccurred())
+		bundleDir = filepath.Join(tmpDir, "bundle")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("export-space", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("production")).To(BeFalse())
+		})
+
+		It("fails when an org is not targeted", func() {
+			requirementsFactory.TargetedOrgSuccess = false
+			Expect(runCommand("production")).To(BeFalse())
+		})
+
+		It("fails with usage when not given a space", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires an argument"},
+			))
+		})
+	})
+
+	Context("when the space can be read", func() {
+		BeforeEach(func() {
+			app := models.Application{}
+			app.Name = "my-app"
+			app.GUID = "my-app-guid"
+			app.Memory = 256
+			app.DiskQuota = 1024
+			app.InstanceCount = 2
+			app.StackGUID = "stack-guid"
+			app.EnvironmentVars = map[string]interface{}{"GREETING": "hello"}
+			app.Services = []models.ServicePlanSummary{{Name: "my-db"}, {Name: "my-api"}}
+			appSummaryRepo.GetSummaryReturns(app, nil)
+			stackRepo.FindByGUIDReturns(models.Stack{Name: "cflinuxfs2"}, nil)
+
+			managed := models.ServiceInstance{}
+			managed.Name = "my-db"
+			managed.ServicePlan = models.ServicePlanFields{Name: "100mb", GUID: "plan-guid"}
+			managed.ServiceOffering = models.ServiceOfferingFields{Label: "p-mysql"}
+			userProvided := models.ServiceInstance{}
+			userProvided.Name = "my-api"
+			serviceSummaryRepo.GetSummariesInSpaceReturns([]models.ServiceInstance{managed, userProvided}, nil)
+
+			userProvidedRepo.ListUserProvidedServicesInSpaceStub = func(spaceGUID string, cb func(models.ServiceInstanceFields) bool) error {
+				cb(models.ServiceInstanceFields{
+					Name:   "my-api",
+					Params: map[string]interface{}{"uri": "https://api.example.com"},
+					Tags:   []string{"api"},
+				})
+				return nil
+			}
+
+			routeRepo.ListRoutesInSpaceStub = func(spaceGUID string, cb func(models.Route) bool) error {
+				cb(models.Route{
+					Host:   "my-app",
+					Domain: models.DomainFields{Name: "example.com"},
+					Path:   "/api",
+					Apps:   []models.ApplicationFields{{Name: "my-app"}},
+				})
+				return nil
+			}
+
+			spaceQuotaRepo.FindByGUIDReturns(models.SpaceQuota{Name: "my-quota", MemoryLimit: 2048, AppInstanceLimit: -1}, nil)
+		})
+
+		It("writes the manifest and the description of the space", func() {
+			Expect(runCommand("production", "-o", bundleDir)).To(BeTrue())
+
+			Expect(appSummaryRepo.GetSummaryArgsForCall(0)).To(Equal("my-app-guid"))
+			Expect(stackRepo.FindByGUIDArgsForCall(0)).To(Equal("stack-guid"))
+			Expect(serviceSummaryRepo.GetSummariesInSpaceArgsForCall(0)).To(Equal("production-guid"))
+			upsSpaceGUID, _ := userProvidedRepo.ListUserProvidedServicesInSpaceArgsForCall(0)
+			Expect(upsSpaceGUID).To(Equal("production-guid"))
+			spaceGUID, _ := routeRepo.ListRoutesInSpaceArgsForCall(0)
+			Expect(spaceGUID).To(Equal("production-guid"))
+			Expect(spaceQuotaRepo.FindByGUIDArgsForCall(0)).To(Equal("quota-guid"))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Exporting space", "production", "my-org", "my-user", bundleDir},
+				[]string{"OK"},
+				[]string{"Exported 1 apps, 2 service instances and 1 routes"},
+				[]string{"credentials of the user provided service instances"},
+				[]string{"TIP", "parameters of service instances cannot be exported"},
+			))
+
+			bundle, err := spacebundle.Read(bundleDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bundle).To(Equal(spacebundle.Bundle{
+				Space:          "production",
+				SpaceQuota:     &spacebundle.SpaceQuota{Name: "my-quota", MemoryLimit: 2048, AppInstanceLimit: -1},
+				SecurityGroups: []string{"public-networks"},
+				Services: []spacebundle.Service{
+					{Name: "my-db", Service: "p-mysql", Plan: "100mb"},
+				},
+				UserProvidedServices: []spacebundle.UserProvidedService{
+					{Name: "my-api", Credentials: map[string]interface{}{"uri": "https://api.example.com"}, Tags: []string{"api"}},
+				},
+				Routes: []spacebundle.Route{
+					{Host: "my-app", Domain: "example.com", Path: "/api", Apps: []string{"my-app"}},
+				},
+			}))
+
+			m, err := manifest.NewManifestDiskRepository().ReadManifest(filepath.Join(bundleDir, spacebundle.ManifestFile))
+			Expect(err).NotTo(HaveOccurred())
+			apps, err := m.Applications()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apps).To(HaveLen(1))
+			Expect(*apps[0].Name).To(Equal("my-app"))
+			Expect(*apps[0].Memory).To(Equal(int64(256)))
+			Expect(*apps[0].InstanceCount).To(Equal(2))
+			Expect(*apps[0].StackName).To(Equal("cflinuxfs2"))
+			Expect(*apps[0].ServicesToBind).To(Equal([]string{"my-db", "my-api"}))
+			Expect(*apps[0].EnvironmentVars).To(Equal(map[string]interface{}{"GREETING": "hello"}))
+		})
+
+		It("fails when the services cannot be read", func() {
+			serviceSummaryRepo.GetSummariesInSpaceReturns(nil, errors.New("server error"))
+
+			Expect(runCommand("production", "-o", bundleDir)).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"server error"},
+			))
+
+			_, err := os.Stat(bundleDir)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,468 @@
+package space
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/applications"
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups"
+	securitygroupspaces "github.com/cloudfoundry/cli/cf/api/securitygroups/spaces"
+	"github.com/cloudfoundry/cli/cf/api/spacequotas"
+	"github.com/cloudfoundry/cli/cf/api/stacks"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/spacebundle"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type ImportSpace struct {
+	ui                  terminal.UI
+	config              coreconfig.Reader
+	manifestRepo        manifest.ManifestRepository
+	appRepo             applications.ApplicationRepository
+	stackRepo           stacks.StackRepository
+	serviceRepo         api.ServiceRepository
+	userProvidedRepo    api.UserProvidedServiceInstanceRepository
+	serviceBindingRepo  api.ServiceBindingRepository
+	domainRepo          api.DomainRepository
+	routeRepo           api.RouteRepository
+	spaceQuotaRepo      spacequotas.SpaceQuotaRepository
+	securityGroupRepo   securitygroups.SecurityGroupRepo
+	securityGroupBinder securitygroupspaces.SecurityGroupSpaceBinder
+}
+
+func init() {
+	commandregistry.Register(&ImportSpace{})
+}
+
+func (cmd *ImportSpace) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:        "import-space",
+		Description: T("Recreate the apps, services, routes, space quota and security groups of an exported space in the targeted space"),
+		Usage: []string{
+			T("CF_NAME import-space DIRECTORY"),
+			"\n\n",
+			T("Everything that already exists in the targeted space is kept, so the same bundle can be imported again. The apps are created without their code."),
+		},
+		Examples: []string{
+			"CF_NAME import-space bundle/",
+		},
+	}
+}
+
+func (cmd *ImportSpace) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("import-space"))
+	}
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+	}
+
+	return reqs
+}
+
+func (cmd *ImportSpace) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.manifestRepo = deps.ManifestRepo
+	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.stackRepo = deps.RepoLocator.GetStackRepository()
+	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
+	cmd.userProvidedRepo = deps.RepoLocator.GetUserProvidedServiceInstanceRepository()
+	cmd.serviceBindingRepo = deps.RepoLocator.GetServiceBindingRepository()
+	cmd.domainRepo = deps.RepoLocator.GetDomainRepository()
+	cmd.routeRepo = deps.RepoLocator.GetRouteRepository()
+	cmd.spaceQuotaRepo = deps.RepoLocator.GetSpaceQuotaRepository()
+	cmd.securityGroupRepo = deps.RepoLocator.GetSecurityGroupRepository()
+	cmd.securityGroupBinder = deps.RepoLocator.GetSecurityGroupSpaceBinder()
+	return cmd
+}
+
+func (cmd *ImportSpace) Execute(c flags.FlagContext) error {
+	dir := c.Args()[0]
+
+	bundle, err := spacebundle.Read(dir)
+	if err != nil {
+		return errors.New(T("Error reading bundle: ") + err.Error())
+	}
+
+	apps, err := cmd.readApps(dir)
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Say(T("Importing {{.Path}} into org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		map[string]interface{}{
+			"Path":      terminal.EntityNameColor(dir),
+			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.config.Username()),
+		}))
+	cmd.ui.Say("")
+
+	err = cmd.importSpaceQuota(bundle.SpaceQuota)
+	if err != nil {
+		return err
+	}
+
+	err = cmd.importSecurityGroups(bundle.SecurityGroups)
+	if err != nil {
+		return err
+	}
+
+	err = cmd.importUserProvidedServices(bundle.UserProvidedServices)
+	if err != nil {
+		return err
+	}
+
+	err = cmd.importServices(bundle.Services)
+	if err != nil {
+		return err
+	}
+
+	appGUIDs, err := cmd.importApps(apps)
+	if err != nil {
+		return err
+	}
+
+	err = cmd.importRoutes(bundle.Routes, appGUIDs)
+	if err != nil {
+		return err
+	}
+
+	err = cmd.bindServices(apps, appGUIDs)
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Ok()
+	if len(apps) > 0 {
+		cmd.ui.Say("")
+		cmd.ui.Say(T("TIP: Use '{{.Command}}' to push the code of each app",
+			map[string]interface{}{"Command": terminal.CommandColor(cf.Name + " push APP_NAME -p PATH")}))
+	}
+
+	return nil
+}
+
+func (cmd *ImportSpace) readApps(dir string) ([]models.AppParams, error) {
+	path := filepath.Join(dir, spacebundle.ManifestFile)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	m, err := cmd.manifestRepo.ReadManifest(path)
+	if err != nil {
+		return nil, errors.New(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+
+	apps, err := m.Applications()
+	if err != nil {
+		return nil, errors.New(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+
+	return apps, nil
+}
+
+func (cmd *ImportSpace) importSpaceQuota(bundleQuota *spacebundle.SpaceQuota) error {
+	if bundleQuota == nil {
+		return nil
+	}
+
+	quota, err := cmd.spaceQuotaRepo.FindByName(bundleQuota.Name)
+	switch err.(type) {
+	case nil:
+	case *cferrors.ModelNotFoundError:
+		cmd.ui.Say(T("Creating space quota {{.QuotaName}}...",
+			map[string]interface{}{"QuotaName": terminal.EntityNameColor(bundleQuota.Name)}))
+
+		err = cmd.spaceQuotaRepo.Create(models.SpaceQuota{
+			Name:                    bundleQuota.Name,
+			MemoryLimit:             bundleQuota.MemoryLimit,
+			InstanceMemoryLimit:     bundleQuota.InstanceMemoryLimit,
+			RoutesLimit:             bundleQuota.RoutesLimit,
+			ServicesLimit:           bundleQuota.ServicesLimit,
+			NonBasicServicesAllowed: bundleQuota.NonBasicServicesAllowed,
+			OrgGUID:                 cmd.config.OrganizationFields().GUID,
+			AppInstanceLimit:        bundleQuota.AppInstanceLimit,
+			ReservedRoutePortsLimit: json.Number(bundleQuota.ReservedRoutePortsLimit),
+		})
+		if err != nil {
+			return err
+		}
+
+		quota, err = cmd.spaceQuotaRepo.FindByName(bundleQuota.Name)
+		if err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	cmd.ui.Say(T("Assigning space quota {{.QuotaName}}...",
+		map[string]interface{}{"QuotaName": terminal.EntityNameColor(quota.Name)}))
+
+	return cmd.spaceQuotaRepo.AssociateSpaceWithQuota(cmd.config.SpaceFields().GUID, quota.GUID)
+}
+
+func (cmd *ImportSpace) importSecurityGroups(names []string) error {
+	for _, name := range names {
+		securityGroup, err := cmd.securityGroupRepo.Read(name)
+		switch err.(type) {
+		case nil:
+		case *cferrors.ModelNotFoundError:
+			cmd.ui.Warn(T("Security group {{.Name}} does not exist, skipping it",
+				map[string]interface{}{"Name": name}))
+			continue
+		default:
+			return err
+		}
+
+		cmd.ui.Say(T("Binding security group {{.Name}}...",
+			map[string]interface{}{"Name": terminal.EntityNameColor(name)}))
+
+		err = cmd.securityGroupBinder.BindSpace(securityGroup.GUID, cmd.config.SpaceFields().GUID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cmd *ImportSpace) importUserProvidedServices(services []spacebundle.UserProvidedService) error {
+	for _, service := range services {
+		instance, err := cmd.serviceRepo.FindInstanceByName(service.Name)
+		switch err.(type) {
+		case nil:
+			cmd.ui.Say(T("Updating user provided service {{.ServiceName}}...",
+				map[string]interface{}{"ServiceName": terminal.EntityNameColor(service.Name)}))
+		case *cferrors.ModelNotFoundError:
+			cmd.ui.Say(T("Creating user provided service {{.ServiceName}}...",
+				map[string]interface{}{"ServiceName": terminal.EntityNameColor(service.Name)}))
+
+			err = cmd.userProvidedRepo.Create(service.Name, service.SyslogDrainURL, service.RouteServiceURL, service.Credentials)
+			if err != nil {
+				return err
+			}
+
+			// the tags can only be set on an existing instance
+			if len(service.Tags) == 0 {
+				continue
+			}
+			instance, err = cmd.serviceRepo.FindInstanceByName(service.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		instance.Params = service.Credentials
+		instance.SysLogDrainURL = service.SyslogDrainURL
+		instance.RouteServiceURL = service.RouteServiceURL
+		instance.Tags = service.Tags
+		err = cmd.userProvidedRepo.Update(instance.ServiceInstanceFields)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cmd *ImportSpace) importServices(services []spacebundle.Service) error {
+	for _, service := range services {
+		_, err := cmd.serviceRepo.FindInstanceByName(service.Name)
+		switch err.(type) {
+		case nil:
+			cmd.ui.Say(T("Service instance {{.ServiceName}} already exists",
+				map[string]interface{}{"ServiceName": terminal.EntityNameColor(service.Name)}))
+			continue
+		case *cferrors.ModelNotFoundError:
+		default:
+			return err
+		}
+
+		cmd.ui.Say(T("Creating service instance {{.ServiceName}} of service {{.Service}} with plan {{.Plan}}...",
+			map[string]interface{}{
+				"ServiceName": terminal.EntityNameColor(service.Name),
+				"Service":     terminal.EntityNameColor(service.Service),
+				"Plan":        terminal.EntityNameColor(service.Plan),
+			}))
+
+		planGUID, err := cmd.serviceRepo.FindServicePlanByDescription(resources.ServicePlanDescription{
+			ServiceLabel:    service.Service,
+			ServicePlanName: service.Plan,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = cmd.serviceRepo.CreateServiceInstance(service.Name, planGUID, service.Parameters, service.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cmd *ImportSpace) importApps(apps []models.AppParams) (map[string]string, error) {
+	appGUIDs := map[string]string{}
+
+	for _, params := range apps {
+		if params.Name == nil {
+			return nil, errors.New(T("Error reading manifest file:\n{{.Err}}",
+				map[string]interface{}{"Err": T("an app has no name")}))
+		}
+		name := *params.Name
+
+		if params.StackName != nil {
+			stack, err := cmd.stackRepo.FindByName(*params.StackName)
+			if err != nil {
+				return nil, err
+			}
+			params.StackGUID = &stack.GUID
+		}
+
+		app, err := cmd.appRepo.Read(name)
+		switch err.(type) {
+		case nil:
+			cmd.ui.Say(T("Updating app {{.AppName}}...",
+				map[string]interface{}{"AppName": terminal.EntityNameColor(name)}))
+
+			app, err = cmd.appRepo.Update(app.GUID, params)
+		case *cferrors.ModelNotFoundError:
+			cmd.ui.Say(T("Creating app {{.AppName}}...",
+				map[string]interface{}{"AppName": terminal.EntityNameColor(name)}))
+
+			spaceGUID := cmd.config.SpaceFields().GUID
+			params.SpaceGUID = &spaceGUID
+			app, err = cmd.appRepo.Create(params)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		appGUIDs[name] = app.GUID
+	}
+
+	return appGUIDs, nil
+}
+
+func (cmd *ImportSpace) importRoutes(routes []spacebundle.Route, appGUIDs map[string]string) error {
+	for _, bundleRoute := range routes {
+		domain, err := cmd.domainRepo.FindByNameInOrg(bundleRoute.Domain, cmd.config.OrganizationFields().GUID)
+		if err != nil {
+			return err
+		}
+
+		url := (&models.RoutePresenter{
+			Host:   bundleRoute.Host,
+			Domain: bundleRoute.Domain,
+			Path:   bundleRoute.Path,
+			Port:   bundleRoute.Port,
+		}).URL()
+
+		route, err := cmd.routeRepo.Find(bundleRoute.Host, domain, bundleRoute.Path, bundleRoute.Port)
+		switch err.(type) {
+		case nil:
+			if route.Space.GUID != cmd.config.SpaceFields().GUID {
+				return errors.New(T("Route {{.URL}} already exists in space {{.SpaceName}}, so it cannot be mapped to apps of space {{.TargetSpace}}",
+					map[string]interface{}{
+						"URL":         url,
+						"SpaceName":   route.Space.Name,
+						"TargetSpace": cmd.config.SpaceFields().Name,
+					}))
+			}
+		case *cferrors.ModelNotFoundError:
+			cmd.ui.Say(T("Creating route {{.URL}}...",
+				map[string]interface{}{"URL": terminal.EntityNameColor(url)}))
+
+			route, err = cmd.routeRepo.CreateInSpace(bundleRoute.Host, bundleRoute.Path, domain.GUID, cmd.config.SpaceFields().GUID, bundleRoute.Port, false)
+			if err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		for _, appName := range bundleRoute.Apps {
+			appGUID, err := cmd.findAppGUID(appName, appGUIDs)
+			if err != nil {
+				return err
+			}
+
+			cmd.ui.Say(T("Mapping route {{.URL}} to app {{.AppName}}...",
+				map[string]interface{}{
+					"URL":     terminal.EntityNameColor(url),
+					"AppName": terminal.EntityNameColor(appName),
+				}))
+
+			err = cmd.routeRepo.Bind(route.GUID, appGUID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cmd *ImportSpace) bindServices(apps []models.AppParams, appGUIDs map[string]string) error {
+	for _, params := range apps {
+		if params.ServicesToBind == nil {
+			continue
+		}
+
+		for _, serviceName := range *params.ServicesToBind {
+			instance, err := cmd.serviceRepo.FindInstanceByName(serviceName)
+			if err != nil {
+				return err
+			}
+
+			cmd.ui.Say(T("Binding service {{.ServiceName}} to app {{.AppName}}...",
+				map[string]interface{}{
+					"ServiceName": terminal.EntityNameColor(serviceName),
+					"AppName":     terminal.EntityNameColor(*params.Name),
+				}))
+
+			err = cmd.serviceBindingRepo.Create(instance.GUID, appGUIDs[*params.Name], nil)
+			if httpErr, ok := err.(cferrors.HTTPError); ok && httpErr.ErrorCode() == cferrors.ServiceBindingAppServiceTaken {
+				err = nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cmd *ImportSpace) findAppGUID(name string, appGUIDs map[string]string) (string, error) {
+	if guid, ok := appGUIDs[name]; ok {
+		return guid, nil
+	}
+
+	app, err := cmd.appRepo.Read(name)
+	if err != nil {
+		return "", errors.New(T("Error mapping route to app {{.AppName}}: {{.Err}}",
+			map[string]interface{}{"AppName": name, "Err": err.Error()}))
+	}
+
+	appGUIDs[name] = app.GUID
+	return app.GUID, nil
+}
@@ -0,0 +1,348 @@
+package space_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/applications/applicationsfakes"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/securitygroupsfakes"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/spaces/spacesfakes"
+	"github.com/cloudfoundry/cli/cf/api/spacequotas/spacequotasfakes"
+	"github.com/cloudfoundry/cli/cf/api/stacks/stacksfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/spacebundle"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("import-space command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          coreconfig.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		appRepo             *applicationsfakes.FakeApplicationRepository
+		stackRepo           *stacksfakes.FakeStackRepository
+		serviceRepo         *apifakes.FakeServiceRepository
+		userProvidedRepo    *apifakes.FakeUserProvidedServiceInstanceRepository
+		serviceBindingRepo  *apifakes.FakeServiceBindingRepository
+		domainRepo          *apifakes.FakeDomainRepository
+		routeRepo           *apifakes.FakeRouteRepository
+		spaceQuotaRepo      *spacequotasfakes.FakeSpaceQuotaRepository
+		securityGroupRepo   *securitygroupsfakes.FakeSecurityGroupRepo
+		securityGroupBinder *spacesfakes.FakeSecurityGroupSpaceBinder
+		deps                commandregistry.Dependency
+
+		bundleDir string
+		instances map[string]models.ServiceInstance
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = configRepo
+		deps.ManifestRepo = manifest.NewManifestDiskRepository()
+		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetStackRepository(stackRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetUserProvidedServiceInstanceRepository(userProvidedRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceBindingRepository(serviceBindingRepo)
+		deps.RepoLocator = deps.RepoLocator.SetDomainRepository(domainRepo)
+		deps.RepoLocator = deps.RepoLocator.SetRouteRepository(routeRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceQuotaRepository(spaceQuotaRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSecurityGroupRepository(securityGroupRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSecurityGroupSpaceBinder(securityGroupBinder)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("import-space").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		configRepo.SetOrganizationFields(models.OrganizationFields{Name: "my-org", GUID: "my-org-guid"})
+		configRepo.SetSpaceFields(models.SpaceFields{Name: "staging", GUID: "staging-guid"})
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
+
+		appRepo = new(applicationsfakes.FakeApplicationRepository)
+		stackRepo = new(stacksfakes.FakeStackRepository)
+		serviceRepo = new(apifakes.FakeServiceRepository)
+		userProvidedRepo = new(apifakes.FakeUserProvidedServiceInstanceRepository)
+		serviceBindingRepo = new(apifakes.FakeServiceBindingRepository)
+		domainRepo = new(apifakes.FakeDomainRepository)
+		routeRepo = new(apifakes.FakeRouteRepository)
+		spaceQuotaRepo = new(spacequotasfakes.FakeSpaceQuotaRepository)
+		securityGroupRepo = new(securitygroupsfakes.FakeSecurityGroupRepo)
+		securityGroupBinder = new(spacesfakes.FakeSecurityGroupSpaceBinder)
+
+		instances = map[string]models.ServiceInstance{}
+		serviceRepo.FindInstanceByNameStub = func(name string) (models.ServiceInstance, error) {
+			instance, ok := instances[name]
+			if !ok {
+				return models.ServiceInstance{}, errors.NewModelNotFoundError("Service instance", name)
+			}
+			return instance, nil
+		}
+		addInstance := func(name string) {
+			instance := models.ServiceInstance{}
+			instance.Name = name
+			instance.GUID = name + "-guid"
+			instances[name] = instance
+		}
+		serviceRepo.CreateServiceInstanceStub = func(name, planGUID string, params map[string]interface{}, tags []string) error {
+			addInstance(name)
+			return nil
+		}
+		userProvidedRepo.CreateStub = func(name, drainURL string, routeServiceURL string, params map[string]interface{}) error {
+			addInstance(name)
+			return nil
+		}
+
+		stackRepo.FindByNameReturns(models.Stack{Name: "cflinuxfs2", GUID: "stack-guid"}, nil)
+		serviceRepo.FindServicePlanByDescriptionReturns("plan-guid", nil)
+		domainRepo.FindByNameInOrgReturns(models.DomainFields{Name: "example.com", GUID: "domain-guid"}, nil)
+		securityGroupRepo.ReadStub = func(name string) (models.SecurityGroup, error) {
+			if name == "missing-group" {
+				return models.SecurityGroup{}, errors.NewModelNotFoundError("security group", name)
+			}
+			return models.SecurityGroup{SecurityGroupFields: models.SecurityGroupFields{Name: name, GUID: name + "-guid"}}, nil
+		}
+
+		var err error
+		bundleDir, err = ioutil.TempDir("", "import-space")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = spacebundle.Write(bundleDir, spacebundle.Bundle{
+			Space:          "production",
+			SpaceQuota:     &spacebundle.SpaceQuota{Name: "my-quota", MemoryLimit: 2048, AppInstanceLimit: -1},
+			SecurityGroups: []string{"public-networks", "missing-group"},
+			Services: []spacebundle.Service{
+				{Name: "my-db", Service: "p-mysql", Plan: "100mb", Parameters: map[string]interface{}{"backups": true}},
+			},
+			UserProvidedServices: []spacebundle.UserProvidedService{
+				{Name: "my-api", Credentials: map[string]interface{}{"uri": "https://api.example.com"}, Tags: []string{"api"}},
+			},
+			Routes: []spacebundle.Route{
+				{Host: "my-app", Domain: "example.com", Path: "/api", Apps: []string{"my-app"}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(bundleDir, spacebundle.ManifestFile), []byte(`applications:
+- name: my-app
+  instances: 2
+  memory: 256M
+  disk_quota: 1024M
+  stack: cflinuxfs2
+  services:
+  - my-db
+  - my-api
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(bundleDir)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("import-space", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand(bundleDir)).To(BeFalse())
+		})
+
+		It("fails when a space is not targeted", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand(bundleDir)).To(BeFalse())
+		})
+
+		It("fails with usage when not given a directory", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires an argument"},
+			))
+		})
+	})
+
+	Context("when the targeted space is empty", func() {
+		BeforeEach(func() {
+			quotaCreated := false
+			spaceQuotaRepo.CreateStub = func(models.SpaceQuota) error {
+				quotaCreated = true
+				return nil
+			}
+			spaceQuotaRepo.FindByNameStub = func(name string) (models.SpaceQuota, error) {
+				if !quotaCreated {
+					return models.SpaceQuota{}, errors.NewModelNotFoundError("Space Quota", name)
+				}
+				return models.SpaceQuota{Name: name, GUID: "quota-guid"}, nil
+			}
+
+			appRepo.ReadReturns(models.Application{}, errors.NewModelNotFoundError("App", "my-app"))
+			appRepo.CreateStub = func(params models.AppParams) (models.Application, error) {
+				app := models.Application{}
+				app.Name = *params.Name
+				app.GUID = "my-app-guid"
+				return app, nil
+			}
+
+			routeRepo.FindReturns(models.Route{}, errors.NewModelNotFoundError("Route", "my-app.example.com/api"))
+			routeRepo.CreateInSpaceReturns(models.Route{GUID: "route-guid"}, nil)
+		})
+
+		It("creates everything in the bundle", func() {
+			Expect(runCommand(bundleDir)).To(BeTrue())
+
+			Expect(spaceQuotaRepo.CreateCallCount()).To(Equal(1))
+			quota := spaceQuotaRepo.CreateArgsForCall(0)
+			Expect(quota.Name).To(Equal("my-quota"))
+			Expect(quota.MemoryLimit).To(Equal(int64(2048)))
+			Expect(quota.OrgGUID).To(Equal("my-org-guid"))
+			spaceGUID, quotaGUID := spaceQuotaRepo.AssociateSpaceWithQuotaArgsForCall(0)
+			Expect(spaceGUID).To(Equal("staging-guid"))
+			Expect(quotaGUID).To(Equal("quota-guid"))
+
+			Expect(securityGroupBinder.BindSpaceCallCount()).To(Equal(1))
+			groupGUID, spaceGUID := securityGroupBinder.BindSpaceArgsForCall(0)
+			Expect(groupGUID).To(Equal("public-networks-guid"))
+			Expect(spaceGUID).To(Equal("staging-guid"))
+
+			name, _, _, credentials := userProvidedRepo.CreateArgsForCall(0)
+			Expect(name).To(Equal("my-api"))
+			Expect(credentials).To(Equal(map[string]interface{}{"uri": "https://api.example.com"}))
+			Expect(userProvidedRepo.UpdateCallCount()).To(Equal(1))
+			Expect(userProvidedRepo.UpdateArgsForCall(0).GUID).To(Equal("my-api-guid"))
+			Expect(userProvidedRepo.UpdateArgsForCall(0).Tags).To(Equal([]string{"api"}))
+
+			Expect(serviceRepo.FindServicePlanByDescriptionArgsForCall(0).ServiceLabel).To(Equal("p-mysql"))
+			Expect(serviceRepo.FindServicePlanByDescriptionArgsForCall(0).ServicePlanName).To(Equal("100mb"))
+			name, planGUID, params, _ := serviceRepo.CreateServiceInstanceArgsForCall(0)
+			Expect(name).To(Equal("my-db"))
+			Expect(planGUID).To(Equal("plan-guid"))
+			Expect(params).To(Equal(map[string]interface{}{"backups": true}))
+
+			params0 := appRepo.CreateArgsForCall(0)
+			Expect(*params0.Name).To(Equal("my-app"))
+			Expect(*params0.SpaceGUID).To(Equal("staging-guid"))
+			Expect(*params0.StackGUID).To(Equal("stack-guid"))
+			Expect(*params0.InstanceCount).To(Equal(2))
+
+			host, path, domainGUID, routeSpaceGUID, _, _ := routeRepo.CreateInSpaceArgsForCall(0)
+			Expect(host).To(Equal("my-app"))
+			Expect(path).To(Equal("/api"))
+			Expect(domainGUID).To(Equal("domain-guid"))
+			Expect(routeSpaceGUID).To(Equal("staging-guid"))
+			routeGUID, appGUID := routeRepo.BindArgsForCall(0)
+			Expect(routeGUID).To(Equal("route-guid"))
+			Expect(appGUID).To(Equal("my-app-guid"))
+
+			Expect(serviceBindingRepo.CreateCallCount()).To(Equal(2))
+			instanceGUID, appGUID, _ := serviceBindingRepo.CreateArgsForCall(0)
+			Expect(instanceGUID).To(Equal("my-db-guid"))
+			Expect(appGUID).To(Equal("my-app-guid"))
+			instanceGUID, _, _ = serviceBindingRepo.CreateArgsForCall(1)
+			Expect(instanceGUID).To(Equal("my-api-guid"))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Importing", bundleDir, "my-org", "staging", "my-user"},
+				[]string{"Creating space quota", "my-quota"},
+				[]string{"Binding security group", "public-networks"},
+				[]string{"Security group missing-group does not exist"},
+				[]string{"Creating user provided service", "my-api"},
+				[]string{"Creating service instance", "my-db", "p-mysql", "100mb"},
+				[]string{"Creating app", "my-app"},
+				[]string{"Creating route", "my-app.example.com/api"},
+				[]string{"Mapping route", "my-app.example.com/api", "my-app"},
+				[]string{"Binding service", "my-db", "my-app"},
+				[]string{"OK"},
+				[]string{"TIP", "push APP_NAME -p PATH"},
+			))
+		})
+	})
+
+	Context("when the bundle has already been imported", func() {
+		BeforeEach(func() {
+			for _, name := range []string{"my-db", "my-api"} {
+				instance := models.ServiceInstance{}
+				instance.Name = name
+				instance.GUID = name + "-guid"
+				instances[name] = instance
+			}
+
+			spaceQuotaRepo.FindByNameReturns(models.SpaceQuota{Name: "my-quota", GUID: "quota-guid"}, nil)
+
+			app := models.Application{}
+			app.Name = "my-app"
+			app.GUID = "my-app-guid"
+			appRepo.ReadReturns(app, nil)
+			appRepo.UpdateReturns(app, nil)
+
+			route := models.Route{GUID: "route-guid"}
+			route.Space = models.SpaceFields{GUID: "staging-guid", Name: "staging"}
+			routeRepo.FindReturns(route, nil)
+			serviceBindingRepo.CreateReturns(errors.NewHTTPError(400, errors.ServiceBindingAppServiceTaken, "The app space binding to service is taken"))
+		})
+
+		It("updates what can change and keeps the rest", func() {
+			Expect(runCommand(bundleDir)).To(BeTrue())
+
+			Expect(spaceQuotaRepo.CreateCallCount()).To(Equal(0))
+			Expect(spaceQuotaRepo.AssociateSpaceWithQuotaCallCount()).To(Equal(1))
+
+			Expect(userProvidedRepo.CreateCallCount()).To(Equal(0))
+			instanceFields := userProvidedRepo.UpdateArgsForCall(0)
+			Expect(instanceFields.GUID).To(Equal("my-api-guid"))
+			Expect(instanceFields.Params).To(Equal(map[string]interface{}{"uri": "https://api.example.com"}))
+			Expect(instanceFields.Tags).To(Equal([]string{"api"}))
+
+			Expect(serviceRepo.CreateServiceInstanceCallCount()).To(Equal(0))
+
+			Expect(appRepo.CreateCallCount()).To(Equal(0))
+			appGUID, _ := appRepo.UpdateArgsForCall(0)
+			Expect(appGUID).To(Equal("my-app-guid"))
+
+			Expect(routeRepo.CreateInSpaceCallCount()).To(Equal(0))
+			Expect(routeRepo.BindCallCount()).To(Equal(1))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Updating user provided service", "my-api"},
+				[]string{"Service instance", "my-db", "already exists"},
+				[]string{"Updating app", "my-app"},
+				[]string{"OK"},
+			))
+		})
+
+		It("fails when the route belongs to another space", func() {
+			route := models.Route{GUID: "route-guid"}
+			route.Space = models.SpaceFields{GUID: "other-space-guid", Name: "other-space"}
+			routeRepo.FindReturns(route, nil)
+
+			Expect(runCommand(bundleDir)).To(BeFalse())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Route my-app.example.com/api already exists in space other-space", "staging"},
+			))
+			Expect(routeRepo.BindCallCount()).To(Equal(0))
+		})
+	})
+
+	It("fails when the bundle cannot be read", func() {
+		Expect(runCommand(filepath.Join(bundleDir, "missing"))).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Error reading bundle"},
+		))
+	})
+})
@@ -23,17 +23,32 @@ func init() {
 }
 
 func (cmd *CreateUser) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["origin"] = &flags.StringFlag{Name: "origin", Usage: T("Origin of the user in UAA, such as ldap or the name of a SAML provider. Users of other origins than uaa have no password")}
+
 	return commandregistry.CommandMetadata{
 		Name:        "create-user",
 		Description: T("Create a new user"),
 		Usage: []string{
 			T("CF_NAME create-user USERNAME PASSWORD"),
+			"\n   ",
+			T("CF_NAME create-user USERNAME --origin ORIGIN"),
+		},
+		Examples: []string{
+			"CF_NAME create-user j.smith@example.com S3cr3t",
+			"CF_NAME create-user j.smith@example.com --origin ldap",
 		},
+		Flags: fs,
 	}
 }
 
 func (cmd *CreateUser) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
-	if len(fc.Args()) != 2 {
+	expectedArgs := 2
+	if isExternalOrigin(fc.String("origin")) {
+		expectedArgs = 1
+	}
+
+	if len(fc.Args()) != expectedArgs {
 		usage := commandregistry.Commands.CommandUsage("create-user")
 		cmd.ui.Failed(T("Incorrect Usage. Requires arguments\n\n") + usage)
 	}
@@ -54,7 +69,7 @@ func (cmd *CreateUser) SetDependency(deps commandregistry.Dependency, pluginCall
 
 func (cmd *CreateUser) Execute(c flags.FlagContext) error {
 	username := c.Args()[0]
-	password := c.Args()[1]
+	origin := c.String("origin")
 
 	cmd.ui.Say(T("Creating user {{.TargetUser}}...",
 		map[string]interface{}{
@@ -62,7 +77,12 @@ func (cmd *CreateUser) Execute(c flags.FlagContext) error {
 			"CurrentUser": terminal.EntityNameColor(cmd.config.Username()),
 		}))
 
-	err := cmd.userRepo.Create(username, password)
+	var err error
+	if isExternalOrigin(origin) {
+		err = cmd.userRepo.CreateWithOrigin(username, origin)
+	} else {
+		err = cmd.userRepo.Create(username, c.Args()[1])
+	}
 	switch err.(type) {
 	case nil:
 	case *errors.ModelAlreadyExistsError:
@@ -79,3 +99,9 @@ func (cmd *CreateUser) Execute(c flags.FlagContext) error {
 	cmd.ui.Say(T("\nTIP: Assign roles with '{{.CurrentUser}} set-org-role' and '{{.CurrentUser}} set-space-role'", map[string]interface{}{"CurrentUser": cf.Name}))
 	return nil
 }
+
+// isExternalOrigin tells whether the users of the origin authenticate with an
+// external identity provider rather than with a password stored in UAA.
+func isExternalOrigin(origin string) bool {
+	return origin != "" && origin != defaultOrigin
+}
@@ -60,6 +60,39 @@ var _ = Describe("Create user command", func() {
 		Expect(password).To(Equal("my-password"))
 	})
 
+	Context("when an origin is given", func() {
+		It("creates a user of that origin without a password", func() {
+			Expect(runCommand("my-user", "--origin", "ldap")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Creating user", "my-user"},
+				[]string{"OK"},
+			))
+
+			Expect(userRepo.CreateCallCount()).To(Equal(0))
+			userName, origin := userRepo.CreateWithOriginArgsForCall(0)
+			Expect(userName).To(Equal("my-user"))
+			Expect(origin).To(Equal("ldap"))
+		})
+
+		It("fails with usage when a password is also given", func() {
+			runCommand("my-user", "my-password", "--origin", "ldap")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage"},
+			))
+			Expect(userRepo.CreateWithOriginCallCount()).To(Equal(0))
+		})
+
+		It("requires a password for the uaa origin", func() {
+			runCommand("my-user", "--origin", "uaa")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage"},
+			))
+		})
+	})
+
 	Context("when creating the user returns an error", func() {
 		It("prints a warning when the given user already exists", func() {
 			userRepo.CreateReturns(errors.NewModelAlreadyExistsError("User", "my-user"))
@@ -0,0 +1,507 @@
+package user
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/organizations"
+	"github.com/cloudfoundry/cli/cf/api/spaces"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+const (
+	defaultOrigin     = "uaa"
+	importRoleWorkers = 5
+)
+
+var (
+	orgRoleNames   = []string{"OrgManager", "BillingManager", "OrgAuditor"}
+	spaceRoleNames = []string{"SpaceManager", "SpaceDeveloper", "SpaceAuditor"}
+)
+
+type roleAssignment struct {
+	row       int
+	username  string
+	origin    string
+	orgName   string
+	spaceName string
+	roleName  string
+	role      models.Role
+
+	userGUID  string
+	orgGUID   string
+	spaceGUID string
+}
+
+func (a roleAssignment) key() string {
+	return strings.Join([]string{a.userGUID, a.orgGUID, a.spaceGUID, a.roleName}, "/")
+}
+
+type roleChange struct {
+	roleAssignment
+	revoke bool
+	err    error
+}
+
+type ImportRoles struct {
+	ui        terminal.UI
+	config    coreconfig.Reader
+	userRepo  api.UserRepository
+	orgRepo   organizations.OrganizationRepository
+	spaceRepo spaces.SpaceRepository
+}
+
+func init() {
+	commandregistry.Register(&ImportRoles{})
+}
+
+func (cmd *ImportRoles) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["create-users"] = &flags.BoolFlag{Name: "create-users", Usage: T("Create the users that do not exist yet, for origins other than uaa")}
+	fs["revoke-missing"] = &flags.BoolFlag{Name: "revoke-missing", Usage: T("Revoke the org and space roles that are not in the file from the orgs that are in it")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "import-roles",
+		Description: T("Assign the org and space roles listed in a CSV file"),
+		Usage: []string{
+			T("CF_NAME import-roles FILE [--create-users] [--revoke-missing]"),
+			"\n\n",
+			T("Every row of the file has the columns user, origin, org, space and role. The origin defaults to uaa, and the space is left empty for org roles. A header row is skipped."),
+			"\n\n",
+			T("ROLES:\n"),
+			"   OrgManager, BillingManager, OrgAuditor, SpaceManager, SpaceDeveloper, SpaceAuditor",
+			"\n\n",
+			T("All the rows are validated before any role is assigned."),
+		},
+		Examples: []string{
+			"CF_NAME import-roles roles.csv --create-users",
+			"",
+			"   user,origin,org,space,role",
+			"   alice,ldap,my-org,,OrgManager",
+			"   bob,ldap,my-org,production,SpaceDeveloper",
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *ImportRoles) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("import-roles"))
+	}
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+
+	return reqs
+}
+
+func (cmd *ImportRoles) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.userRepo = deps.RepoLocator.GetUserRepository()
+	cmd.orgRepo = deps.RepoLocator.GetOrganizationRepository()
+	cmd.spaceRepo = deps.RepoLocator.GetSpaceRepository()
+	return cmd
+}
+
+func (cmd *ImportRoles) Execute(c flags.FlagContext) error {
+	path := c.Args()[0]
+
+	assignments, err := readRoleAssignments(path)
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Say(T("Importing {{.Count}} roles from {{.Path}} as {{.Username}}...",
+		map[string]interface{}{
+			"Count":    len(assignments),
+			"Path":     terminal.EntityNameColor(path),
+			"Username": terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	orgs, missingUsers, err := cmd.resolve(assignments, c.Bool("create-users"))
+	if err != nil {
+		return err
+	}
+
+	err = cmd.createUsers(assignments, missingUsers)
+	if err != nil {
+		return err
+	}
+
+	changes := []*roleChange{}
+	wanted := map[string]bool{}
+	for _, assignment := range assignments {
+		if wanted[assignment.key()] {
+			continue
+		}
+		wanted[assignment.key()] = true
+		changes = append(changes, &roleChange{roleAssignment: assignment})
+	}
+
+	if c.Bool("revoke-missing") {
+		revocations, err := cmd.revocations(orgs, wanted)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, revocations...)
+	}
+
+	cmd.applyChanges(changes)
+
+	table := cmd.ui.Table([]string{T("row"), T("action"), T("user"), T("org"), T("space"), T("role"), T("result")})
+	failed, assigned, revoked := 0, 0, 0
+	for _, change := range changes {
+		row, action := "-", T("revoke")
+		if !change.revoke {
+			row, action = strconv.Itoa(change.row), T("assign")
+		}
+
+		result := T("ok")
+		switch {
+		case change.err != nil:
+			failed++
+			result = terminal.FailureColor(change.err.Error())
+		case change.revoke:
+			revoked++
+		default:
+			assigned++
+		}
+
+		table.Add(row, action, change.username, change.orgName, change.spaceName, change.roleName, result)
+	}
+
+	cmd.ui.Say("")
+	table.Print()
+
+	if failed > 0 {
+		return errors.New(T("{{.Failed}} of {{.Total}} role changes failed",
+			map[string]interface{}{"Failed": failed, "Total": len(changes)}))
+	}
+
+	cmd.ui.Say("")
+	cmd.ui.Ok()
+	cmd.ui.Say(T("Assigned {{.Assigned}} roles and revoked {{.Revoked}} roles",
+		map[string]interface{}{"Assigned": assigned, "Revoked": revoked}))
+	return nil
+}
+
+func readRoleAssignments(path string) ([]roleAssignment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 5
+	reader.TrimLeadingSpace = true
+
+	assignments := []roleAssignment{}
+	problems := []string{}
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New(T("Error reading {{.Path}}: {{.Err}}", map[string]interface{}{"Path": path, "Err": err.Error()}))
+		}
+
+		if row == 1 && strings.EqualFold(record[0], "user") {
+			continue
+		}
+
+		assignment, err := parseRoleAssignment(row, record)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%d: %s", row, err.Error()))
+			continue
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	if len(problems) > 0 {
+		return nil, invalidRowsError(problems)
+	}
+
+	return assignments, nil
+}
+
+func parseRoleAssignment(row int, record []string) (roleAssignment, error) {
+	assignment := roleAssignment{
+		row:       row,
+		username:  strings.TrimSpace(record[0]),
+		origin:    strings.TrimSpace(record[1]),
+		orgName:   strings.TrimSpace(record[2]),
+		spaceName: strings.TrimSpace(record[3]),
+		roleName:  strings.TrimSpace(record[4]),
+	}
+	if assignment.origin == "" {
+		assignment.origin = defaultOrigin
+	}
+
+	if assignment.username == "" || assignment.orgName == "" {
+		return assignment, errors.New(T("the user and the org are required"))
+	}
+
+	role, err := models.RoleFromString(assignment.roleName)
+	if err != nil {
+		return assignment, errors.New(T("unknown role {{.Role}}", map[string]interface{}{"Role": assignment.roleName}))
+	}
+	assignment.role = role
+
+	isSpaceRole := false
+	for _, name := range spaceRoleNames {
+		if name == assignment.roleName {
+			isSpaceRole = true
+		}
+	}
+
+	if isSpaceRole && assignment.spaceName == "" {
+		return assignment, errors.New(T("the space role {{.Role}} needs a space", map[string]interface{}{"Role": assignment.roleName}))
+	}
+	if !isSpaceRole && assignment.spaceName != "" {
+		return assignment, errors.New(T("the org role {{.Role}} cannot be given in a space", map[string]interface{}{"Role": assignment.roleName}))
+	}
+
+	return assignment, nil
+}
+
+// resolve looks up the orgs, spaces and users of every row, and returns the
+// orgs in the file and the users that have to be created.
+func (cmd *ImportRoles) resolve(assignments []roleAssignment, createUsers bool) ([]models.Organization, map[string]bool, error) {
+	orgs := []models.Organization{}
+	orgsByName := map[string]models.Organization{}
+	orgErrors := map[string]error{}
+	spaceGUIDs := map[string]string{}
+	spaceErrors := map[string]error{}
+	userGUIDs := map[string]string{}
+	userErrors := map[string]error{}
+	missingUsers := map[string]bool{}
+
+	problems := []string{}
+	for i := range assignments {
+		assignment := &assignments[i]
+
+		if _, found := orgsByName[assignment.orgName]; !found && orgErrors[assignment.orgName] == nil {
+			org, err := cmd.orgRepo.FindByName(assignment.orgName)
+			if err != nil {
+				orgErrors[assignment.orgName] = err
+			} else {
+				orgs = append(orgs, org)
+				orgsByName[assignment.orgName] = org
+			}
+		}
+		if err := orgErrors[assignment.orgName]; err != nil {
+			problems = append(problems, fmt.Sprintf("%d: %s", assignment.row, err.Error()))
+			continue
+		}
+		assignment.orgGUID = orgsByName[assignment.orgName].GUID
+
+		if assignment.spaceName != "" {
+			spaceKey := assignment.orgName + "/" + assignment.spaceName
+			if _, found := spaceGUIDs[spaceKey]; !found && spaceErrors[spaceKey] == nil {
+				space, err := cmd.spaceRepo.FindByNameInOrg(assignment.spaceName, assignment.orgGUID)
+				if err != nil {
+					spaceErrors[spaceKey] = err
+				} else {
+					spaceGUIDs[spaceKey] = space.GUID
+				}
+			}
+			if err := spaceErrors[spaceKey]; err != nil {
+				problems = append(problems, fmt.Sprintf("%d: %s", assignment.row, err.Error()))
+				continue
+			}
+			assignment.spaceGUID = spaceGUIDs[spaceKey]
+		}
+
+		userKey := assignment.origin + "/" + assignment.username
+		if _, found := userGUIDs[userKey]; !found && userErrors[userKey] == nil && !missingUsers[userKey] {
+			user, err := cmd.userRepo.FindByUsernameAndOrigin(assignment.username, assignment.origin)
+			switch err.(type) {
+			case nil:
+				userGUIDs[userKey] = user.GUID
+			case *cferrors.ModelNotFoundError:
+				switch {
+				case !createUsers:
+					userErrors[userKey] = errors.New(T("user {{.Username}} of origin {{.Origin}} not found, use --create-users to create it",
+						map[string]interface{}{"Username": assignment.username, "Origin": assignment.origin}))
+				case assignment.origin == defaultOrigin:
+					userErrors[userKey] = errors.New(T("user {{.Username}} not found, users of origin uaa need a password and have to be created with create-user",
+						map[string]interface{}{"Username": assignment.username}))
+				default:
+					missingUsers[userKey] = true
+				}
+			default:
+				userErrors[userKey] = err
+			}
+		}
+		if err := userErrors[userKey]; err != nil {
+			problems = append(problems, fmt.Sprintf("%d: %s", assignment.row, err.Error()))
+			continue
+		}
+		assignment.userGUID = userGUIDs[userKey]
+	}
+
+	if len(problems) > 0 {
+		return nil, nil, invalidRowsError(problems)
+	}
+
+	return orgs, missingUsers, nil
+}
+
+func (cmd *ImportRoles) createUsers(assignments []roleAssignment, missingUsers map[string]bool) error {
+	userGUIDs := map[string]string{}
+	for i := range assignments {
+		assignment := &assignments[i]
+		userKey := assignment.origin + "/" + assignment.username
+		if !missingUsers[userKey] {
+			continue
+		}
+
+		if _, created := userGUIDs[userKey]; !created {
+			cmd.ui.Say(T("Creating user {{.Username}} of origin {{.Origin}}...",
+				map[string]interface{}{
+					"Username": terminal.EntityNameColor(assignment.username),
+					"Origin":   terminal.EntityNameColor(assignment.origin),
+				}))
+
+			err := cmd.userRepo.CreateWithOrigin(assignment.username, assignment.origin)
+			if err != nil {
+				return err
+			}
+
+			user, err := cmd.userRepo.FindByUsernameAndOrigin(assignment.username, assignment.origin)
+			if err != nil {
+				return err
+			}
+			userGUIDs[userKey] = user.GUID
+		}
+
+		assignment.userGUID = userGUIDs[userKey]
+	}
+
+	return nil
+}
+
+// revocations returns the roles in the given orgs and their spaces that are
+// not wanted.
+func (cmd *ImportRoles) revocations(orgs []models.Organization, wanted map[string]bool) ([]*roleChange, error) {
+	changes := []*roleChange{}
+
+	for _, org := range orgs {
+		for _, roleName := range orgRoleNames {
+			role, _ := models.RoleFromString(roleName)
+			users, err := cmd.userRepo.ListUsersInOrgForRoleWithNoUAA(org.GUID, role)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, user := range users {
+				change := &roleChange{revoke: true, roleAssignment: roleAssignment{
+					username: user.Username,
+					orgName:  org.Name,
+					roleName: roleName,
+					role:     role,
+					userGUID: user.GUID,
+					orgGUID:  org.GUID,
+				}}
+				if !wanted[change.key()] {
+					changes = append(changes, change)
+				}
+			}
+		}
+
+		// the spaces inlined in the org are capped, so they are listed page
+		// by page
+		spaces := []models.Space{}
+		err := cmd.spaceRepo.ListSpacesFromOrg(org.GUID, func(space models.Space) bool {
+			spaces = append(spaces, space)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, space := range spaces {
+			for _, roleName := range spaceRoleNames {
+				role, _ := models.RoleFromString(roleName)
+				users, err := cmd.userRepo.ListUsersInSpaceForRoleWithNoUAA(space.GUID, role)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, user := range users {
+					change := &roleChange{revoke: true, roleAssignment: roleAssignment{
+						username:  user.Username,
+						orgName:   org.Name,
+						spaceName: space.Name,
+						roleName:  roleName,
+						role:      role,
+						userGUID:  user.GUID,
+						orgGUID:   org.GUID,
+						spaceGUID: space.GUID,
+					}}
+					if !wanted[change.key()] {
+						changes = append(changes, change)
+					}
+				}
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+func (cmd *ImportRoles) applyChanges(changes []*roleChange) {
+	work := make(chan *roleChange)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < importRoleWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for change := range work {
+				change.err = cmd.applyChange(change)
+			}
+		}()
+	}
+
+	for _, change := range changes {
+		work <- change
+	}
+	close(work)
+	wg.Wait()
+}
+
+func (cmd *ImportRoles) applyChange(change *roleChange) error {
+	switch {
+	case change.spaceGUID == "" && change.revoke:
+		return cmd.userRepo.UnsetOrgRoleByGUID(change.userGUID, change.orgGUID, change.role)
+	case change.spaceGUID == "":
+		return cmd.userRepo.SetOrgRoleByGUID(change.userGUID, change.orgGUID, change.role)
+	case change.revoke:
+		return cmd.userRepo.UnsetSpaceRoleByGUID(change.userGUID, change.spaceGUID, change.role)
+	default:
+		return cmd.userRepo.SetSpaceRoleByGUID(change.userGUID, change.spaceGUID, change.orgGUID, change.role)
+	}
+}
+
+func invalidRowsError(problems []string) error {
+	return errors.New(T("The following rows are invalid, no roles have been changed:") + "\n" + strings.Join(problems, "\n"))
+}
@@ -0,0 +1,296 @@
+package user_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/organizations/organizationsfakes"
+	"github.com/cloudfoundry/cli/cf/api/spaces/spacesfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("import-roles command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		config              coreconfig.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		userRepo            *apifakes.FakeUserRepository
+		orgRepo             *organizationsfakes.FakeOrganizationRepository
+		spaceRepo           *spacesfakes.FakeSpaceRepository
+		deps                commandregistry.Dependency
+
+		dir  string
+		path string
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = config
+		deps.RepoLocator = deps.RepoLocator.SetUserRepository(userRepo)
+		deps.RepoLocator = deps.RepoLocator.SetOrganizationRepository(orgRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceRepository(spaceRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("import-roles").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		userRepo = new(apifakes.FakeUserRepository)
+		orgRepo = new(organizationsfakes.FakeOrganizationRepository)
+		spaceRepo = new(spacesfakes.FakeSpaceRepository)
+
+		org := models.Organization{}
+		org.GUID = "my-org-guid"
+		org.Name = "my-org"
+		org.Spaces = []models.SpaceFields{{GUID: "production-guid", Name: "production"}}
+		orgRepo.FindByNameStub = func(name string) (models.Organization, error) {
+			if name != "my-org" {
+				return models.Organization{}, cferrors.NewModelNotFoundError("Organization", name)
+			}
+			return org, nil
+		}
+
+		spaceRepo.FindByNameInOrgStub = func(name string, orgGUID string) (models.Space, error) {
+			if name != "production" {
+				return models.Space{}, cferrors.NewModelNotFoundError("Space", name)
+			}
+			return models.Space{SpaceFields: models.SpaceFields{GUID: "production-guid", Name: name}}, nil
+		}
+		// staging is only returned when paging through the spaces of the org
+		spaceRepo.ListSpacesFromOrgStub = func(orgGUID string, callback func(models.Space) bool) error {
+			Expect(orgGUID).To(Equal("my-org-guid"))
+			for _, name := range []string{"production", "staging"} {
+				if !callback(models.Space{SpaceFields: models.SpaceFields{GUID: name + "-guid", Name: name}}) {
+					break
+				}
+			}
+			return nil
+		}
+
+		userRepo.FindByUsernameAndOriginStub = func(username string, origin string) (models.UserFields, error) {
+			if username == "carol" && userRepo.CreateWithOriginCallCount() == 0 {
+				return models.UserFields{}, cferrors.NewModelNotFoundError("User", username)
+			}
+			return models.UserFields{GUID: username + "-guid", Username: username}, nil
+		}
+
+		var err error
+		dir, err = ioutil.TempDir("", "import-roles")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "roles.csv")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(contents string) {
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("import-roles", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand(path)).To(BeFalse())
+		})
+
+		It("fails with usage when not given a file", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires an argument"},
+			))
+		})
+	})
+
+	It("assigns the org and space roles of every row", func() {
+		write("user,origin,org,space,role\nalice,ldap,my-org,,OrgManager\nbob,,my-org,production,SpaceDeveloper\nbob,,my-org,production,SpaceDeveloper\n")
+
+		Expect(runCommand(path)).To(BeTrue())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Importing 3 roles from", path, "my-user"},
+			[]string{"row", "action", "user", "org", "space", "role", "result"},
+			[]string{"2", "assign", "alice", "my-org", "OrgManager", "ok"},
+			[]string{"3", "assign", "bob", "my-org", "production", "SpaceDeveloper", "ok"},
+			[]string{"OK"},
+			[]string{"Assigned 2 roles and revoked 0 roles"},
+		))
+
+		username, origin := userRepo.FindByUsernameAndOriginArgsForCall(0)
+		Expect(username).To(Equal("alice"))
+		Expect(origin).To(Equal("ldap"))
+		_, origin = userRepo.FindByUsernameAndOriginArgsForCall(1)
+		Expect(origin).To(Equal("uaa"))
+
+		Expect(userRepo.SetOrgRoleByGUIDCallCount()).To(Equal(1))
+		userGUID, orgGUID, role := userRepo.SetOrgRoleByGUIDArgsForCall(0)
+		Expect(userGUID).To(Equal("alice-guid"))
+		Expect(orgGUID).To(Equal("my-org-guid"))
+		Expect(role).To(Equal(models.RoleOrgManager))
+
+		Expect(userRepo.SetSpaceRoleByGUIDCallCount()).To(Equal(1))
+		userGUID, spaceGUID, orgGUID, role := userRepo.SetSpaceRoleByGUIDArgsForCall(0)
+		Expect(userGUID).To(Equal("bob-guid"))
+		Expect(spaceGUID).To(Equal("production-guid"))
+		Expect(orgGUID).To(Equal("my-org-guid"))
+		Expect(role).To(Equal(models.RoleSpaceDeveloper))
+	})
+
+	It("validates every row before changing anything", func() {
+		write("alice,ldap,my-org,production,OrgManager\nbob,,my-org,,SpaceDeveloper\ndave,,my-org,,Owner\nerin,,other-org,,OrgAuditor\nfrank,,my-org,staging,SpaceAuditor\nalice,ldap,my-org,,OrgAuditor\n")
+
+		Expect(runCommand(path)).To(BeFalse())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"The following rows are invalid"},
+			[]string{"1: the org role OrgManager cannot be given in a space"},
+			[]string{"2: the space role SpaceDeveloper needs a space"},
+			[]string{"3: unknown role Owner"},
+		))
+		Expect(orgRepo.FindByNameCallCount()).To(Equal(0))
+		Expect(userRepo.SetOrgRoleByGUIDCallCount()).To(Equal(0))
+	})
+
+	It("reports the rows whose org, space or user cannot be found", func() {
+		write("erin,,other-org,,OrgAuditor\nfrank,,my-org,staging,SpaceAuditor\ncarol,ldap,my-org,,OrgAuditor\nalice,ldap,my-org,,OrgAuditor\n")
+
+		Expect(runCommand(path)).To(BeFalse())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"1:", "other-org", "not found"},
+			[]string{"2:", "staging", "not found"},
+			[]string{"3: user carol of origin ldap not found, use --create-users to create it"},
+		))
+		Expect(userRepo.SetOrgRoleByGUIDCallCount()).To(Equal(0))
+	})
+
+	It("creates the missing users with --create-users", func() {
+		write("carol,ldap,my-org,,OrgAuditor\ncarol,ldap,my-org,production,SpaceAuditor\n")
+
+		Expect(runCommand(path, "--create-users")).To(BeTrue())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Creating user", "carol", "ldap"},
+			[]string{"Assigned 2 roles"},
+		))
+		Expect(userRepo.CreateWithOriginCallCount()).To(Equal(1))
+		username, origin := userRepo.CreateWithOriginArgsForCall(0)
+		Expect(username).To(Equal("carol"))
+		Expect(origin).To(Equal("ldap"))
+
+		userGUID, _, _ := userRepo.SetOrgRoleByGUIDArgsForCall(0)
+		Expect(userGUID).To(Equal("carol-guid"))
+	})
+
+	It("does not create users of origin uaa", func() {
+		write("carol,uaa,my-org,,OrgAuditor\n")
+
+		Expect(runCommand(path, "--create-users")).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"1: user carol not found", "create-user"},
+		))
+		Expect(userRepo.CreateWithOriginCallCount()).To(Equal(0))
+	})
+
+	It("revokes the roles that are not listed with --revoke-missing", func() {
+		write("alice,ldap,my-org,,OrgManager\n")
+		userRepo.ListUsersInOrgForRoleWithNoUAAStub = func(orgGUID string, role models.Role) ([]models.UserFields, error) {
+			if role == models.RoleOrgManager {
+				return []models.UserFields{{GUID: "alice-guid", Username: "alice"}, {GUID: "mallory-guid", Username: "mallory"}}, nil
+			}
+			return nil, nil
+		}
+		userRepo.ListUsersInSpaceForRoleWithNoUAAStub = func(spaceGUID string, role models.Role) ([]models.UserFields, error) {
+			if spaceGUID == "production-guid" && role == models.RoleSpaceDeveloper {
+				return []models.UserFields{{GUID: "bob-guid", Username: "bob"}}, nil
+			}
+			return nil, nil
+		}
+
+		Expect(runCommand(path, "--revoke-missing")).To(BeTrue())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"1", "assign", "alice", "OrgManager", "ok"},
+			[]string{"-", "revoke", "mallory", "my-org", "OrgManager", "ok"},
+			[]string{"-", "revoke", "bob", "my-org", "production", "SpaceDeveloper", "ok"},
+			[]string{"Assigned 1 roles and revoked 2 roles"},
+		))
+
+		userGUID, orgGUID, role := userRepo.UnsetOrgRoleByGUIDArgsForCall(0)
+		Expect(userGUID).To(Equal("mallory-guid"))
+		Expect(orgGUID).To(Equal("my-org-guid"))
+		Expect(role).To(Equal(models.RoleOrgManager))
+
+		userGUID, spaceGUID, role := userRepo.UnsetSpaceRoleByGUIDArgsForCall(0)
+		Expect(userGUID).To(Equal("bob-guid"))
+		Expect(spaceGUID).To(Equal("production-guid"))
+		Expect(role).To(Equal(models.RoleSpaceDeveloper))
+	})
+
+	It("revokes the roles in the spaces that are not inlined in the org with --revoke-missing", func() {
+		write("alice,ldap,my-org,,OrgManager\n")
+		userRepo.ListUsersInSpaceForRoleWithNoUAAStub = func(spaceGUID string, role models.Role) ([]models.UserFields, error) {
+			if spaceGUID == "staging-guid" && role == models.RoleSpaceAuditor {
+				return []models.UserFields{{GUID: "eve-guid", Username: "eve"}}, nil
+			}
+			return nil, nil
+		}
+
+		Expect(runCommand(path, "--revoke-missing")).To(BeTrue())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"-", "revoke", "eve", "my-org", "staging", "SpaceAuditor", "ok"},
+		))
+		userGUID, spaceGUID, role := userRepo.UnsetSpaceRoleByGUIDArgsForCall(0)
+		Expect(userGUID).To(Equal("eve-guid"))
+		Expect(spaceGUID).To(Equal("staging-guid"))
+		Expect(role).To(Equal(models.RoleSpaceAuditor))
+	})
+
+	It("fails when the spaces of an org cannot be listed with --revoke-missing", func() {
+		write("alice,ldap,my-org,,OrgManager\n")
+		spaceRepo.ListSpacesFromOrgStub = nil
+		spaceRepo.ListSpacesFromOrgReturns(errors.New("server error"))
+
+		Expect(runCommand(path, "--revoke-missing")).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"server error"},
+		))
+		Expect(userRepo.SetOrgRoleByGUIDCallCount()).To(Equal(0))
+	})
+
+	It("reports the rows that failed", func() {
+		write("alice,ldap,my-org,,OrgManager\nbob,,my-org,production,SpaceDeveloper\n")
+		userRepo.SetOrgRoleByGUIDReturns(errors.New("server error"))
+
+		Expect(runCommand(path)).To(BeFalse())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"1", "assign", "alice", "OrgManager", "server error"},
+			[]string{"2", "assign", "bob", "SpaceDeveloper", "ok"},
+			[]string{"FAILED"},
+			[]string{"1 of 2 role changes failed"},
+		))
+	})
+})
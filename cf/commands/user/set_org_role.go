@@ -1,6 +1,7 @@
 package user
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/cloudfoundry/cli/cf"
@@ -36,16 +37,20 @@ func init() {
 }
 
 func (cmd *SetOrgRole) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["origin"] = &flags.StringFlag{Name: "origin", Usage: T("Origin of the user, for usernames that exist in more than one identity provider")}
+
 	return commandregistry.CommandMetadata{
 		Name:        "set-org-role",
 		Description: T("Assign an org role to a user"),
 		Usage: []string{
-			T("CF_NAME set-org-role USERNAME ORG ROLE\n\n"),
+			T("CF_NAME set-org-role USERNAME ORG ROLE [--origin ORIGIN]\n\n"),
 			T("ROLES:\n"),
 			fmt.Sprintf("   'OrgManager' - %s", T("Invite and manage users, select and change plans, and set spending limits\n")),
 			fmt.Sprintf("   'BillingManager' - %s", T("Create and manage the billing account and payment info\n")),
 			fmt.Sprintf("   'OrgAuditor' - %s", T("Read-only access to org info and reports\n")),
 		},
+		Flags: fs,
 	}
 }
 
@@ -54,15 +59,20 @@ func (cmd *SetOrgRole) Requirements(requirementsFactory requirements.Factory, fc
 		cmd.ui.Failed(T("Incorrect Usage. Requires USERNAME, ORG, ROLE as arguments\n\n") + commandregistry.Commands.CommandUsage("set-org-role"))
 	}
 
-	var wantGUID bool
-	if cmd.config.IsMinAPIVersion(cf.SetRolesByUsernameMinimumAPIVersion) {
-		setRolesByUsernameFlag, err := cmd.flagRepo.FindByName("set_roles_by_username")
-		wantGUID = (err != nil || !setRolesByUsernameFlag.Enabled)
+	if fc.String("origin") != "" {
+		cmd.userReq = requirementsFactory.NewUserRequirementWithOrigin(fc.Args()[0], fc.String("origin"))
 	} else {
-		wantGUID = true
+		var wantGUID bool
+		if cmd.config.IsMinAPIVersion(cf.SetRolesByUsernameMinimumAPIVersion) {
+			setRolesByUsernameFlag, err := cmd.flagRepo.FindByName("set_roles_by_username")
+			wantGUID = (err != nil || !setRolesByUsernameFlag.Enabled)
+		} else {
+			wantGUID = true
+		}
+
+		cmd.userReq = requirementsFactory.NewUserRequirement(fc.Args()[0], wantGUID)
 	}
 
-	cmd.userReq = requirementsFactory.NewUserRequirement(fc.Args()[0], wantGUID)
 	cmd.orgReq = requirementsFactory.NewOrganizationRequirement(fc.Args()[1])
 
 	reqs := []requirements.Requirement{
@@ -91,6 +101,13 @@ func (cmd *SetOrgRole) Execute(c flags.FlagContext) error {
 		return err
 	}
 
+	if c.String("origin") == "" && user.GUID != "" {
+		err = checkUsernameInOneOrigin(cmd.userRepo, user.Username)
+		if err != nil {
+			return err
+		}
+	}
+
 	cmd.ui.Say(T("Assigning role {{.Role}} to user {{.TargetUser}} in org {{.TargetOrg}} as {{.CurrentUser}}...",
 		map[string]interface{}{
 			"Role":        terminal.EntityNameColor(roleStr),
@@ -115,3 +132,20 @@ func (cmd *SetOrgRole) SetOrgRole(orgGUID string, role models.Role, userGUID, us
 
 	return cmd.userRepo.SetOrgRoleByUsername(userName, orgGUID, role)
 }
+
+// checkUsernameInOneOrigin fails when the username exists in more than one
+// origin, as the role would be given to whichever of the users the UAA
+// lists first.
+func checkUsernameInOneOrigin(userRepo api.UserRepository, username string) error {
+	users, err := userRepo.FindAllByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	if len(users) > 1 {
+		return errors.New(T("The username {{.Username}} exists in more than one origin, use --origin to choose one",
+			map[string]interface{}{"Username": username}))
+	}
+
+	return nil
+}
@@ -39,16 +39,20 @@ func init() {
 }
 
 func (cmd *SetSpaceRole) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["origin"] = &flags.StringFlag{Name: "origin", Usage: T("Origin of the user, for usernames that exist in more than one identity provider")}
+
 	return commandregistry.CommandMetadata{
 		Name:        "set-space-role",
 		Description: T("Assign a space role to a user"),
 		Usage: []string{
-			T("CF_NAME set-space-role USERNAME ORG SPACE ROLE\n\n"),
+			T("CF_NAME set-space-role USERNAME ORG SPACE ROLE [--origin ORIGIN]\n\n"),
 			T("ROLES:\n"),
 			fmt.Sprintf("   'SpaceManager' - %s", T("Invite and manage users, and enable features for a given space\n")),
 			fmt.Sprintf("   'SpaceDeveloper' - %s", T("Create and manage apps and services, and see logs and reports\n")),
 			fmt.Sprintf("   'SpaceAuditor' - %s", T("View logs, reports, and settings on this space\n")),
 		},
+		Flags: fs,
 	}
 }
 
@@ -57,15 +61,20 @@ func (cmd *SetSpaceRole) Requirements(requirementsFactory requirements.Factory,
 		cmd.ui.Failed(T("Incorrect Usage. Requires USERNAME, ORG, SPACE, ROLE as arguments\n\n") + commandregistry.Commands.CommandUsage("set-space-role"))
 	}
 
-	var wantGUID bool
-	if cmd.config.IsMinAPIVersion(cf.SetRolesByUsernameMinimumAPIVersion) {
-		setRolesByUsernameFlag, err := cmd.flagRepo.FindByName("set_roles_by_username")
-		wantGUID = (err != nil || !setRolesByUsernameFlag.Enabled)
+	if fc.String("origin") != "" {
+		cmd.userReq = requirementsFactory.NewUserRequirementWithOrigin(fc.Args()[0], fc.String("origin"))
 	} else {
-		wantGUID = true
+		var wantGUID bool
+		if cmd.config.IsMinAPIVersion(cf.SetRolesByUsernameMinimumAPIVersion) {
+			setRolesByUsernameFlag, err := cmd.flagRepo.FindByName("set_roles_by_username")
+			wantGUID = (err != nil || !setRolesByUsernameFlag.Enabled)
+		} else {
+			wantGUID = true
+		}
+
+		cmd.userReq = requirementsFactory.NewUserRequirement(fc.Args()[0], wantGUID)
 	}
 
-	cmd.userReq = requirementsFactory.NewUserRequirement(fc.Args()[0], wantGUID)
 	cmd.orgReq = requirementsFactory.NewOrganizationRequirement(fc.Args()[1])
 
 	reqs := []requirements.Requirement{
@@ -97,6 +106,13 @@ func (cmd *SetSpaceRole) Execute(c flags.FlagContext) error {
 	userFields := cmd.userReq.GetUser()
 	org := cmd.orgReq.GetOrganization()
 
+	if c.String("origin") == "" && userFields.GUID != "" {
+		err = checkUsernameInOneOrigin(cmd.userRepo, userFields.Username)
+		if err != nil {
+			return err
+		}
+	}
+
 	space, err := cmd.spaceRepo.FindByNameInOrg(spaceName, org.GUID)
 	if err != nil {
 		return err
@@ -186,6 +186,25 @@ var _ = Describe("SetSpaceRole", func() {
 				})
 			})
 		})
+
+		Context("when an origin is given", func() {
+			BeforeEach(func() {
+				flagContext = flags.NewFlagContext(cmd.MetaData().Flags)
+				flagContext.Parse("the-user-name", "the-org-name", "the-space-name", "SpaceManager", "--origin", "ldap")
+				factory.NewUserRequirementWithOriginReturns(userRequirement)
+			})
+
+			It("returns a UserRequirement for the user of that origin", func() {
+				actualRequirements := cmd.Requirements(factory, flagContext)
+				Expect(factory.NewUserRequirementCallCount()).To(Equal(0))
+				Expect(factory.NewUserRequirementWithOriginCallCount()).To(Equal(1))
+				actualUsername, actualOrigin := factory.NewUserRequirementWithOriginArgsForCall(0)
+				Expect(actualUsername).To(Equal("the-user-name"))
+				Expect(actualOrigin).To(Equal("ldap"))
+
+				Expect(actualRequirements).To(ContainElement(userRequirement))
+			})
+		})
 	})
 
 	Describe("Execute", func() {
@@ -256,6 +275,20 @@ var _ = Describe("SetSpaceRole", func() {
 					Expect(actualRole).To(Equal(models.RoleSpaceManager))
 				})
 
+				Context("when the username exists in more than one origin", func() {
+					BeforeEach(func() {
+						userRepo.FindAllByUsernameReturns([]models.UserFields{
+							{GUID: "the-user-guid", Username: "the-user-name"},
+							{GUID: "the-ldap-user-guid", Username: "the-user-name"},
+						}, nil)
+					})
+
+					It("asks for the origin instead of setting the role", func() {
+						Expect(err).To(MatchError("The username the-user-name exists in more than one origin, use --origin to choose one"))
+						Expect(userRepo.SetSpaceRoleByGUIDCallCount()).To(BeZero())
+					})
+				})
+
 				Context("when the call to CC fails", func() {
 					BeforeEach(func() {
 						userRepo.SetSpaceRoleByGUIDReturns(errors.New("user-repo-error"))
@@ -0,0 +1,244 @@
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/organizations"
+	"github.com/cloudfoundry/cli/cf/api/spaces"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type userRole struct {
+	Org   string `json:"org"`
+	Space string `json:"space,omitempty"`
+	Role  string `json:"role"`
+}
+
+type UserRoles struct {
+	ui        terminal.UI
+	config    coreconfig.Reader
+	userRepo  api.UserRepository
+	orgRepo   organizations.OrganizationRepository
+	spaceRepo spaces.SpaceRepository
+}
+
+func init() {
+	commandregistry.Register(&UserRoles{})
+}
+
+func (cmd *UserRoles) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["json"] = &flags.BoolFlag{Name: "json", Usage: T("Show the roles as JSON")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "user-roles",
+		Description: T("Show the org and space roles of a user"),
+		Usage: []string{
+			T("CF_NAME user-roles USERNAME [--json]"),
+			"\n\n",
+			T("Admins see the roles in every org. Other users see the roles in the orgs that are visible to them."),
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *UserRoles) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + commandregistry.Commands.CommandUsage("user-roles"))
+	}
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+
+	return reqs
+}
+
+func (cmd *UserRoles) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.userRepo = deps.RepoLocator.GetUserRepository()
+	cmd.orgRepo = deps.RepoLocator.GetOrganizationRepository()
+	cmd.spaceRepo = deps.RepoLocator.GetSpaceRepository()
+	return cmd
+}
+
+func (cmd *UserRoles) Execute(c flags.FlagContext) error {
+	username := c.Args()[0]
+
+	if !c.Bool("json") {
+		cmd.ui.Say(T("Getting roles of user {{.TargetUser}} as {{.CurrentUser}}...",
+			map[string]interface{}{
+				"TargetUser":  terminal.EntityNameColor(username),
+				"CurrentUser": terminal.EntityNameColor(cmd.config.Username()),
+			}))
+	}
+
+	userGUID, err := cmd.findUserGUID(username)
+	if err != nil {
+		return err
+	}
+
+	var roles []userRole
+	if userGUID != "" {
+		roles, err = cmd.rolesOfUser(userGUID)
+	}
+	if userGUID == "" || isForbidden(err) {
+		roles, err = cmd.rolesInVisibleOrgs(userGUID, username)
+	}
+	if err != nil {
+		return err
+	}
+
+	sort.Stable(userRolesByOrgAndSpace(roles))
+
+	if c.Bool("json") {
+		jsonBytes, err := json.MarshalIndent(roles, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.ui.Say("%s", string(jsonBytes))
+		return nil
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	if len(roles) == 0 {
+		cmd.ui.Say(T("No roles found"))
+		return nil
+	}
+
+	table := cmd.ui.Table([]string{T("org"), T("space"), T("role")})
+	for _, role := range roles {
+		table.Add(role.Org, role.Space, role.Role)
+	}
+	table.Print()
+	return nil
+}
+
+// findUserGUID returns an empty GUID when UAA does not let the current user
+// look up other users, in which case the roles are matched by username.
+func (cmd *UserRoles) findUserGUID(username string) (string, error) {
+	if username == cmd.config.Username() {
+		return cmd.config.UserGUID(), nil
+	}
+
+	user, err := cmd.userRepo.FindByUsername(username)
+	switch err.(type) {
+	case nil:
+		return user.GUID, nil
+	case *cferrors.AccessDeniedError:
+		return "", nil
+	default:
+		return "", err
+	}
+}
+
+func (cmd *UserRoles) rolesOfUser(userGUID string) ([]userRole, error) {
+	roles := []userRole{}
+
+	for _, roleName := range orgRoleNames {
+		role, _ := models.RoleFromString(roleName)
+		orgs, err := cmd.userRepo.ListOrgsForUserWithRole(userGUID, role)
+		if err != nil {
+			return nil, err
+		}
+		for _, org := range orgs {
+			roles = append(roles, userRole{Org: org.Name, Role: roleName})
+		}
+	}
+
+	for _, roleName := range spaceRoleNames {
+		role, _ := models.RoleFromString(roleName)
+		spaces, err := cmd.userRepo.ListSpacesForUserWithRole(userGUID, role)
+		if err != nil {
+			return nil, err
+		}
+		for _, space := range spaces {
+			roles = append(roles, userRole{Org: space.Organization.Name, Space: space.Name, Role: roleName})
+		}
+	}
+
+	return roles, nil
+}
+
+func (cmd *UserRoles) rolesInVisibleOrgs(userGUID string, username string) ([]userRole, error) {
+	matches := func(users []models.UserFields) bool {
+		for _, user := range users {
+			if (userGUID != "" && user.GUID == userGUID) || (userGUID == "" && user.Username == username) {
+				return true
+			}
+		}
+		return false
+	}
+
+	orgs, err := cmd.orgRepo.ListOrgs(0)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []userRole{}
+	for _, org := range orgs {
+		for _, roleName := range orgRoleNames {
+			role, _ := models.RoleFromString(roleName)
+			users, err := cmd.userRepo.ListUsersInOrgForRoleWithNoUAA(org.GUID, role)
+			if err != nil {
+				return nil, err
+			}
+			if matches(users) {
+				roles = append(roles, userRole{Org: org.Name, Role: roleName})
+			}
+		}
+
+		spaces := []models.Space{}
+		err = cmd.spaceRepo.ListSpacesFromOrg(org.GUID, func(space models.Space) bool {
+			spaces = append(spaces, space)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, space := range spaces {
+			for _, roleName := range spaceRoleNames {
+				role, _ := models.RoleFromString(roleName)
+				users, err := cmd.userRepo.ListUsersInSpaceForRoleWithNoUAA(space.GUID, role)
+				if err != nil {
+					return nil, err
+				}
+				if matches(users) {
+					roles = append(roles, userRole{Org: org.Name, Space: space.Name, Role: roleName})
+				}
+			}
+		}
+	}
+
+	return roles, nil
+}
+
+func isForbidden(err error) bool {
+	httpErr, ok := err.(cferrors.HTTPError)
+	return ok && httpErr.StatusCode() == http.StatusForbidden
+}
+
+type userRolesByOrgAndSpace []userRole
+
+func (roles userRolesByOrgAndSpace) Len() int      { return len(roles) }
+func (roles userRolesByOrgAndSpace) Swap(i, j int) { roles[i], roles[j] = roles[j], roles[i] }
+func (roles userRolesByOrgAndSpace) Less(i, j int) bool {
+	if roles[i].Org != roles[j].Org {
+		return roles[i].Org < roles[j].Org
+	}
+	return roles[i].Space < roles[j].Space
+}
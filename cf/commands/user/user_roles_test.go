@@ -0,0 +1,205 @@
+package user_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/organizations/organizationsfakes"
+	"github.com/cloudfoundry/cli/cf/api/spaces/spacesfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("user-roles command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		config              coreconfig.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		userRepo            *apifakes.FakeUserRepository
+		orgRepo             *organizationsfakes.FakeOrganizationRepository
+		spaceRepo           *spacesfakes.FakeSpaceRepository
+		deps                commandregistry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = config
+		deps.RepoLocator = deps.RepoLocator.SetUserRepository(userRepo)
+		deps.RepoLocator = deps.RepoLocator.SetOrganizationRepository(orgRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceRepository(spaceRepo)
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("user-roles").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		userRepo = new(apifakes.FakeUserRepository)
+		orgRepo = new(organizationsfakes.FakeOrganizationRepository)
+		spaceRepo = new(spacesfakes.FakeSpaceRepository)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("user-roles", args, requirementsFactory, updateCommandDependency, false, ui)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("alice")).To(BeFalse())
+		})
+
+		It("fails with usage when not given a user", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires an argument"},
+			))
+		})
+	})
+
+	Context("when the roles can be read from the user", func() {
+		BeforeEach(func() {
+			userRepo.FindByUsernameReturns(models.UserFields{GUID: "alice-guid", Username: "alice"}, nil)
+			userRepo.ListOrgsForUserWithRoleStub = func(userGUID string, role models.Role) ([]models.OrganizationFields, error) {
+				if role == models.RoleOrgManager {
+					return []models.OrganizationFields{{GUID: "org-b-guid", Name: "org-b"}}, nil
+				}
+				return nil, nil
+			}
+			userRepo.ListSpacesForUserWithRoleStub = func(userGUID string, role models.Role) ([]models.Space, error) {
+				if role == models.RoleSpaceDeveloper {
+					space := models.Space{}
+					space.Name = "production"
+					space.Organization = models.OrganizationFields{Name: "org-a"}
+					return []models.Space{space}, nil
+				}
+				return nil, nil
+			}
+		})
+
+		It("lists the org and space roles sorted by org", func() {
+			Expect(runCommand("alice")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Getting roles of user", "alice", "my-user"},
+				[]string{"OK"},
+				[]string{"org", "space", "role"},
+				[]string{"org-a", "production", "SpaceDeveloper"},
+				[]string{"org-b", "OrgManager"},
+			))
+
+			Expect(userRepo.FindByUsernameArgsForCall(0)).To(Equal("alice"))
+			userGUID, _ := userRepo.ListOrgsForUserWithRoleArgsForCall(0)
+			Expect(userGUID).To(Equal("alice-guid"))
+			Expect(orgRepo.ListOrgsCallCount()).To(Equal(0))
+		})
+
+		It("prints the roles as JSON", func() {
+			Expect(runCommand("alice", "--json")).To(BeTrue())
+
+			var roles []map[string]string
+			Expect(json.Unmarshal([]byte(strings.Join(ui.Outputs, "\n")), &roles)).To(Succeed())
+			Expect(roles).To(Equal([]map[string]string{
+				{"org": "org-a", "space": "production", "role": "SpaceDeveloper"},
+				{"org": "org-b", "role": "OrgManager"},
+			}))
+		})
+
+		It("uses the GUID of the current user without asking UAA", func() {
+			Expect(runCommand("my-user")).To(BeTrue())
+
+			Expect(userRepo.FindByUsernameCallCount()).To(Equal(0))
+			userGUID, _ := userRepo.ListOrgsForUserWithRoleArgsForCall(0)
+			Expect(userGUID).To(Equal(config.UserGUID()))
+		})
+	})
+
+	Context("when the roles of the user cannot be read", func() {
+		BeforeEach(func() {
+			userRepo.FindByUsernameReturns(models.UserFields{}, cferrors.NewAccessDeniedError())
+
+			org := models.Organization{}
+			org.GUID = "my-org-guid"
+			org.Name = "my-org"
+			orgRepo.ListOrgsReturns([]models.Organization{org}, nil)
+			spaceRepo.ListSpacesFromOrgStub = func(orgGUID string, callback func(models.Space) bool) error {
+				Expect(orgGUID).To(Equal("my-org-guid"))
+				for _, name := range []string{"production", "staging"} {
+					if !callback(models.Space{SpaceFields: models.SpaceFields{GUID: name + "-guid", Name: name}}) {
+						break
+					}
+				}
+				return nil
+			}
+
+			userRepo.ListUsersInOrgForRoleWithNoUAAStub = func(orgGUID string, role models.Role) ([]models.UserFields, error) {
+				if role == models.RoleOrgAuditor {
+					return []models.UserFields{{GUID: "alice-guid", Username: "alice"}}, nil
+				}
+				return []models.UserFields{{GUID: "bob-guid", Username: "bob"}}, nil
+			}
+			userRepo.ListUsersInSpaceForRoleWithNoUAAStub = func(spaceGUID string, role models.Role) ([]models.UserFields, error) {
+				if spaceGUID == "production-guid" && role == models.RoleSpaceManager {
+					return []models.UserFields{{GUID: "alice-guid", Username: "alice"}}, nil
+				}
+				if spaceGUID == "staging-guid" && role == models.RoleSpaceAuditor {
+					return []models.UserFields{{GUID: "alice-guid", Username: "alice"}}, nil
+				}
+				return nil, nil
+			}
+		})
+
+		It("looks for the user in the visible orgs", func() {
+			Expect(runCommand("alice")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"my-org", "OrgAuditor"},
+				[]string{"my-org", "production", "SpaceManager"},
+				[]string{"my-org", "staging", "SpaceAuditor"},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"OrgManager"}))
+			Expect(userRepo.ListOrgsForUserWithRoleCallCount()).To(Equal(0))
+			Expect(orgRepo.FindByNameCallCount()).To(Equal(0))
+		})
+
+		It("falls back to the visible orgs when CC forbids reading the roles of the user", func() {
+			userRepo.FindByUsernameReturns(models.UserFields{GUID: "alice-guid", Username: "alice"}, nil)
+			userRepo.ListOrgsForUserWithRoleReturns(nil, cferrors.NewHTTPError(403, "10003", "You are not authorized to perform the requested action"))
+
+			Expect(runCommand("alice")).To(BeTrue())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"my-org", "OrgAuditor"},
+			))
+		})
+
+		It("says when the user has no roles", func() {
+			userRepo.ListUsersInOrgForRoleWithNoUAAStub = nil
+			userRepo.ListUsersInSpaceForRoleWithNoUAAStub = nil
+
+			Expect(runCommand("alice")).To(BeTrue())
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"No roles found"}))
+		})
+	})
+
+	It("fails when the user does not exist", func() {
+		userRepo.FindByUsernameReturns(models.UserFields{}, cferrors.NewModelNotFoundError("User", "alice"))
+
+		Expect(runCommand("alice")).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"alice", "not found"},
+		))
+	})
+})
@@ -40,6 +40,8 @@ type Data struct {
 	PluginRepos              []models.PluginRepo
 	MinCLIVersion            string
 	MinRecommendedCLIVersion string
+	SSHRecordSpaces          []string
+	SecretPatterns           []string
 }
 
 func NewData() (data *Data) {
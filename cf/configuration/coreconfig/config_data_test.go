@@ -52,7 +52,9 @@ var _ = Describe("V3 Config files", func() {
 		}
 		],
 		"MinCLIVersion": "6.0.0",
-		"MinRecommendedCLIVersion": "6.9.0"
+		"MinRecommendedCLIVersion": "6.9.0",
+		"SSHRecordSpaces": ["the-org/the-space"],
+		"SecretPatterns": ["(?i)^db_"]
 	}`
 
 	// V2 by virtue of ConfigVersion only
@@ -135,6 +137,8 @@ var _ = Describe("V3 Config files", func() {
 						URL:  "http://repo.com",
 					},
 				},
+				SSHRecordSpaces: []string{"the-org/the-space"},
+				SecretPatterns:  []string{"(?i)^db_"},
 			}
 
 			jsonData, err := data.JSONMarshalV3()
@@ -185,6 +189,8 @@ var _ = Describe("V3 Config files", func() {
 						URL:  "http://repo.com",
 					},
 				},
+				SSHRecordSpaces: []string{"the-org/the-space"},
+				SecretPatterns:  []string{"(?i)^db_"},
 			}
 
 			actualData := coreconfig.NewData()
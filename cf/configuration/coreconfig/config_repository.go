@@ -91,6 +91,9 @@ type Reader interface {
 	Locale() string
 
 	PluginRepos() []models.PluginRepo
+
+	SSHRecordSpaces() []string
+	SecretPatterns() []string
 }
 
 //go:generate counterfeiter . ReadWriter
@@ -119,6 +122,8 @@ type ReadWriter interface {
 	SetLocale(string)
 	SetPluginRepo(models.PluginRepo)
 	UnSetPluginRepo(int)
+	SetSSHRecordSpaces([]string)
+	SetSecretPatterns([]string)
 }
 
 //go:generate counterfeiter . Repository
@@ -402,6 +407,20 @@ func (c *ConfigRepository) PluginRepos() (repos []models.PluginRepo) {
 	return
 }
 
+func (c *ConfigRepository) SSHRecordSpaces() (spaces []string) {
+	c.read(func() {
+		spaces = c.data.SSHRecordSpaces
+	})
+	return
+}
+
+func (c *ConfigRepository) SecretPatterns() (patterns []string) {
+	c.read(func() {
+		patterns = c.data.SecretPatterns
+	})
+	return
+}
+
 // SETTERS
 
 func (c *ConfigRepository) ClearSession() {
@@ -538,3 +557,15 @@ func (c *ConfigRepository) UnSetPluginRepo(index int) {
 		c.data.PluginRepos = append(c.data.PluginRepos[:index], c.data.PluginRepos[index+1:]...)
 	})
 }
+
+func (c *ConfigRepository) SetSSHRecordSpaces(spaces []string) {
+	c.write(func() {
+		c.data.SSHRecordSpaces = spaces
+	})
+}
+
+func (c *ConfigRepository) SetSecretPatterns(patterns []string) {
+	c.write(func() {
+		c.data.SecretPatterns = patterns
+	})
+}
@@ -107,6 +107,12 @@ var _ = Describe("Configuration Repository", func() {
 		Expect(config.PluginRepos()[0].Name).To(Equal("repo"))
 		Expect(config.PluginRepos()[0].URL).To(Equal("nowhere.com"))
 
+		config.SetSSHRecordSpaces([]string{"the-org/the-space"})
+		Expect(config.SSHRecordSpaces()).To(Equal([]string{"the-org/the-space"}))
+
+		config.SetSecretPatterns([]string{"(?i)^db_"})
+		Expect(config.SecretPatterns()).To(Equal([]string{"(?i)^db_"}))
+
 		s, _ := semver.Make("3.1")
 		Expect(config.IsMinAPIVersion(s)).To(Equal(false))
 
@@ -188,6 +188,18 @@ type FakeReadWriter struct {
 	pluginReposReturns     struct {
 		result1 []models.PluginRepo
 	}
+	SSHRecordSpacesStub        func() []string
+	sSHRecordSpacesMutex       sync.RWMutex
+	sSHRecordSpacesArgsForCall []struct{}
+	sSHRecordSpacesReturns     struct {
+		result1 []string
+	}
+	SecretPatternsStub        func() []string
+	secretPatternsMutex       sync.RWMutex
+	secretPatternsArgsForCall []struct{}
+	secretPatternsReturns     struct {
+		result1 []string
+	}
 	ClearSessionStub          func()
 	clearSessionMutex         sync.RWMutex
 	clearSessionArgsForCall   []struct{}
@@ -296,6 +308,16 @@ type FakeReadWriter struct {
 	unSetPluginRepoArgsForCall []struct {
 		arg1 int
 	}
+	SetSSHRecordSpacesStub        func([]string)
+	setSSHRecordSpacesMutex       sync.RWMutex
+	setSSHRecordSpacesArgsForCall []struct {
+		arg1 []string
+	}
+	SetSecretPatternsStub        func([]string)
+	setSecretPatternsMutex       sync.RWMutex
+	setSecretPatternsArgsForCall []struct {
+		arg1 []string
+	}
 }
 
 func (fake *FakeReadWriter) APIEndpoint() string {
@@ -1010,6 +1032,54 @@ func (fake *FakeReadWriter) PluginReposReturns(result1 []models.PluginRepo) {
 	}{result1}
 }
 
+func (fake *FakeReadWriter) SSHRecordSpaces() []string {
+	fake.sSHRecordSpacesMutex.Lock()
+	fake.sSHRecordSpacesArgsForCall = append(fake.sSHRecordSpacesArgsForCall, struct{}{})
+	fake.sSHRecordSpacesMutex.Unlock()
+	if fake.SSHRecordSpacesStub != nil {
+		return fake.SSHRecordSpacesStub()
+	} else {
+		return fake.sSHRecordSpacesReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) SSHRecordSpacesCallCount() int {
+	fake.sSHRecordSpacesMutex.RLock()
+	defer fake.sSHRecordSpacesMutex.RUnlock()
+	return len(fake.sSHRecordSpacesArgsForCall)
+}
+
+func (fake *FakeReadWriter) SSHRecordSpacesReturns(result1 []string) {
+	fake.SSHRecordSpacesStub = nil
+	fake.sSHRecordSpacesReturns = struct {
+		result1 []string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) SecretPatterns() []string {
+	fake.secretPatternsMutex.Lock()
+	fake.secretPatternsArgsForCall = append(fake.secretPatternsArgsForCall, struct{}{})
+	fake.secretPatternsMutex.Unlock()
+	if fake.SecretPatternsStub != nil {
+		return fake.SecretPatternsStub()
+	} else {
+		return fake.secretPatternsReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) SecretPatternsCallCount() int {
+	fake.secretPatternsMutex.RLock()
+	defer fake.secretPatternsMutex.RUnlock()
+	return len(fake.secretPatternsArgsForCall)
+}
+
+func (fake *FakeReadWriter) SecretPatternsReturns(result1 []string) {
+	fake.SecretPatternsStub = nil
+	fake.secretPatternsReturns = struct {
+		result1 []string
+	}{result1}
+}
+
 func (fake *FakeReadWriter) ClearSession() {
 	fake.clearSessionMutex.Lock()
 	fake.clearSessionArgsForCall = append(fake.clearSessionArgsForCall, struct{}{})
@@ -1508,4 +1578,50 @@ func (fake *FakeReadWriter) UnSetPluginRepoArgsForCall(i int) int {
 	return fake.unSetPluginRepoArgsForCall[i].arg1
 }
 
+func (fake *FakeReadWriter) SetSSHRecordSpaces(arg1 []string) {
+	fake.setSSHRecordSpacesMutex.Lock()
+	fake.setSSHRecordSpacesArgsForCall = append(fake.setSSHRecordSpacesArgsForCall, struct {
+		arg1 []string
+	}{arg1})
+	fake.setSSHRecordSpacesMutex.Unlock()
+	if fake.SetSSHRecordSpacesStub != nil {
+		fake.SetSSHRecordSpacesStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetSSHRecordSpacesCallCount() int {
+	fake.setSSHRecordSpacesMutex.RLock()
+	defer fake.setSSHRecordSpacesMutex.RUnlock()
+	return len(fake.setSSHRecordSpacesArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetSSHRecordSpacesArgsForCall(i int) []string {
+	fake.setSSHRecordSpacesMutex.RLock()
+	defer fake.setSSHRecordSpacesMutex.RUnlock()
+	return fake.setSSHRecordSpacesArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) SetSecretPatterns(arg1 []string) {
+	fake.setSecretPatternsMutex.Lock()
+	fake.setSecretPatternsArgsForCall = append(fake.setSecretPatternsArgsForCall, struct {
+		arg1 []string
+	}{arg1})
+	fake.setSecretPatternsMutex.Unlock()
+	if fake.SetSecretPatternsStub != nil {
+		fake.SetSecretPatternsStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetSecretPatternsCallCount() int {
+	fake.setSecretPatternsMutex.RLock()
+	defer fake.setSecretPatternsMutex.RUnlock()
+	return len(fake.setSecretPatternsArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetSecretPatternsArgsForCall(i int) []string {
+	fake.setSecretPatternsMutex.RLock()
+	defer fake.setSecretPatternsMutex.RUnlock()
+	return fake.setSecretPatternsArgsForCall[i].arg1
+}
+
 var _ coreconfig.ReadWriter = new(FakeReadWriter)
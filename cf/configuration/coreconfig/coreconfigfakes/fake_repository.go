@@ -188,6 +188,18 @@ type FakeRepository struct {
 	pluginReposReturns     struct {
 		result1 []models.PluginRepo
 	}
+	SSHRecordSpacesStub        func() []string
+	sSHRecordSpacesMutex       sync.RWMutex
+	sSHRecordSpacesArgsForCall []struct{}
+	sSHRecordSpacesReturns     struct {
+		result1 []string
+	}
+	SecretPatternsStub        func() []string
+	secretPatternsMutex       sync.RWMutex
+	secretPatternsArgsForCall []struct{}
+	secretPatternsReturns     struct {
+		result1 []string
+	}
 	ClearSessionStub          func()
 	clearSessionMutex         sync.RWMutex
 	clearSessionArgsForCall   []struct{}
@@ -296,6 +308,16 @@ type FakeRepository struct {
 	unSetPluginRepoArgsForCall []struct {
 		arg1 int
 	}
+	SetSSHRecordSpacesStub        func([]string)
+	setSSHRecordSpacesMutex       sync.RWMutex
+	setSSHRecordSpacesArgsForCall []struct {
+		arg1 []string
+	}
+	SetSecretPatternsStub        func([]string)
+	setSecretPatternsMutex       sync.RWMutex
+	setSecretPatternsArgsForCall []struct {
+		arg1 []string
+	}
 	CloseStub        func()
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct{}
@@ -1013,6 +1035,54 @@ func (fake *FakeRepository) PluginReposReturns(result1 []models.PluginRepo) {
 	}{result1}
 }
 
+func (fake *FakeRepository) SSHRecordSpaces() []string {
+	fake.sSHRecordSpacesMutex.Lock()
+	fake.sSHRecordSpacesArgsForCall = append(fake.sSHRecordSpacesArgsForCall, struct{}{})
+	fake.sSHRecordSpacesMutex.Unlock()
+	if fake.SSHRecordSpacesStub != nil {
+		return fake.SSHRecordSpacesStub()
+	} else {
+		return fake.sSHRecordSpacesReturns.result1
+	}
+}
+
+func (fake *FakeRepository) SSHRecordSpacesCallCount() int {
+	fake.sSHRecordSpacesMutex.RLock()
+	defer fake.sSHRecordSpacesMutex.RUnlock()
+	return len(fake.sSHRecordSpacesArgsForCall)
+}
+
+func (fake *FakeRepository) SSHRecordSpacesReturns(result1 []string) {
+	fake.SSHRecordSpacesStub = nil
+	fake.sSHRecordSpacesReturns = struct {
+		result1 []string
+	}{result1}
+}
+
+func (fake *FakeRepository) SecretPatterns() []string {
+	fake.secretPatternsMutex.Lock()
+	fake.secretPatternsArgsForCall = append(fake.secretPatternsArgsForCall, struct{}{})
+	fake.secretPatternsMutex.Unlock()
+	if fake.SecretPatternsStub != nil {
+		return fake.SecretPatternsStub()
+	} else {
+		return fake.secretPatternsReturns.result1
+	}
+}
+
+func (fake *FakeRepository) SecretPatternsCallCount() int {
+	fake.secretPatternsMutex.RLock()
+	defer fake.secretPatternsMutex.RUnlock()
+	return len(fake.secretPatternsArgsForCall)
+}
+
+func (fake *FakeRepository) SecretPatternsReturns(result1 []string) {
+	fake.SecretPatternsStub = nil
+	fake.secretPatternsReturns = struct {
+		result1 []string
+	}{result1}
+}
+
 func (fake *FakeRepository) ClearSession() {
 	fake.clearSessionMutex.Lock()
 	fake.clearSessionArgsForCall = append(fake.clearSessionArgsForCall, struct{}{})
@@ -1511,6 +1581,52 @@ func (fake *FakeRepository) UnSetPluginRepoArgsForCall(i int) int {
 	return fake.unSetPluginRepoArgsForCall[i].arg1
 }
 
+func (fake *FakeRepository) SetSSHRecordSpaces(arg1 []string) {
+	fake.setSSHRecordSpacesMutex.Lock()
+	fake.setSSHRecordSpacesArgsForCall = append(fake.setSSHRecordSpacesArgsForCall, struct {
+		arg1 []string
+	}{arg1})
+	fake.setSSHRecordSpacesMutex.Unlock()
+	if fake.SetSSHRecordSpacesStub != nil {
+		fake.SetSSHRecordSpacesStub(arg1)
+	}
+}
+
+func (fake *FakeRepository) SetSSHRecordSpacesCallCount() int {
+	fake.setSSHRecordSpacesMutex.RLock()
+	defer fake.setSSHRecordSpacesMutex.RUnlock()
+	return len(fake.setSSHRecordSpacesArgsForCall)
+}
+
+func (fake *FakeRepository) SetSSHRecordSpacesArgsForCall(i int) []string {
+	fake.setSSHRecordSpacesMutex.RLock()
+	defer fake.setSSHRecordSpacesMutex.RUnlock()
+	return fake.setSSHRecordSpacesArgsForCall[i].arg1
+}
+
+func (fake *FakeRepository) SetSecretPatterns(arg1 []string) {
+	fake.setSecretPatternsMutex.Lock()
+	fake.setSecretPatternsArgsForCall = append(fake.setSecretPatternsArgsForCall, struct {
+		arg1 []string
+	}{arg1})
+	fake.setSecretPatternsMutex.Unlock()
+	if fake.SetSecretPatternsStub != nil {
+		fake.SetSecretPatternsStub(arg1)
+	}
+}
+
+func (fake *FakeRepository) SetSecretPatternsCallCount() int {
+	fake.setSecretPatternsMutex.RLock()
+	defer fake.setSecretPatternsMutex.RUnlock()
+	return len(fake.setSecretPatternsArgsForCall)
+}
+
+func (fake *FakeRepository) SetSecretPatternsArgsForCall(i int) []string {
+	fake.setSecretPatternsMutex.RLock()
+	defer fake.setSecretPatternsMutex.RUnlock()
+	return fake.setSecretPatternsArgsForCall[i].arg1
+}
+
 func (fake *FakeRepository) Close() {
 	fake.closeMutex.Lock()
 	fake.closeArgsForCall = append(fake.closeArgsForCall, struct{}{})
@@ -0,0 +1,215 @@
+// Package envfile reads and writes sets of environment variables in the
+// dotenv and JSON formats.
+package envfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatDotenv = "dotenv"
+	FormatJSON   = "json"
+)
+
+var (
+	variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+	unquotedPattern     = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+)
+
+// Parse reads the variables of a JSON object, or of a dotenv file when the
+// contents do not start with '{'.
+func Parse(contents []byte) (map[string]interface{}, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(contents), []byte("{")) {
+		return parseJSON(contents)
+	}
+	return parseDotenv(contents)
+}
+
+func parseJSON(contents []byte) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&vars)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid JSON: %s", err.Error())
+	}
+
+	return vars, nil
+}
+
+func parseDotenv(contents []byte) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		separator := strings.Index(line, "=")
+		if separator < 0 {
+			return nil, fmt.Errorf("Missing '=' on line %d", lineNumber)
+		}
+
+		name := strings.TrimSpace(line[:separator])
+		if !variableNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("Invalid variable name '%s' on line %d", name, lineNumber)
+		}
+
+		value, err := parseDotenvValue(strings.TrimSpace(line[separator+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("%s on line %d", err.Error(), lineNumber)
+		}
+
+		vars[name] = value
+	}
+
+	return vars, scanner.Err()
+}
+
+func parseDotenvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch value[0] {
+	case '\'':
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return "", errors.New("Unterminated quoted value")
+		}
+		return value[1 : end+1], nil
+	case '"':
+		return parseDoubleQuotedValue(value[1:])
+	}
+
+	// an unquoted value ends at a comment
+	if comment := strings.Index(value, " #"); comment >= 0 {
+		value = strings.TrimSpace(value[:comment])
+	}
+	return value, nil
+}
+
+func parseDoubleQuotedValue(value string) (string, error) {
+	result := &bytes.Buffer{}
+
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '"':
+			return result.String(), nil
+		case '\\':
+			if i+1 == len(value) {
+				return "", errors.New("Unterminated quoted value")
+			}
+			i++
+			switch value[i] {
+			case 'n':
+				result.WriteByte('\n')
+			case 'r':
+				result.WriteByte('\r')
+			case 't':
+				result.WriteByte('\t')
+			default:
+				result.WriteByte(value[i])
+			}
+		default:
+			result.WriteByte(value[i])
+		}
+	}
+
+	return "", errors.New("Unterminated quoted value")
+}
+
+// Marshal writes the variables in the given format, sorted by name. Values
+// that are not strings are written as JSON in the dotenv format.
+func Marshal(vars map[string]interface{}, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(vars, "", "  ")
+	case FormatDotenv:
+		return marshalDotenv(vars)
+	default:
+		return nil, fmt.Errorf("Unknown format '%s', expected %s or %s", format, FormatDotenv, FormatJSON)
+	}
+}
+
+func marshalDotenv(vars map[string]interface{}) ([]byte, error) {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &bytes.Buffer{}
+	for _, name := range names {
+		value, err := stringValue(vars[name])
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(result, "%s=%s\n", name, quoteDotenvValue(value))
+	}
+
+	return result.Bytes(), nil
+}
+
+// Environ returns the variables as NAME=value strings, sorted by name, in
+// the form used by os/exec. Values that are not strings are written as JSON.
+func Environ(vars map[string]interface{}) ([]string, error) {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	environ := make([]string, 0, len(vars))
+	for _, name := range names {
+		value, err := stringValue(vars[name])
+		if err != nil {
+			return nil, err
+		}
+		environ = append(environ, name+"="+value)
+	}
+
+	return environ, nil
+}
+
+func stringValue(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func quoteDotenvValue(value string) string {
+	if unquotedPattern.MatchString(value) {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// Equal reports whether two values would be stored the same way.
+func Equal(a, b interface{}) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aJSON, bJSON)
+}
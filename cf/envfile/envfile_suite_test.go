@@ -0,0 +1,13 @@
+package envfile_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestEnvfile(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Envfile Suite")
+}
@@ -0,0 +1,125 @@
+package envfile_test
+
+import (
+	"encoding/json"
+
+	"github.com/cloudfoundry/cli/cf/envfile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("envfile", func() {
+	Describe("Parse", func() {
+		It("parses dotenv files", func() {
+			vars, err := envfile.Parse([]byte(`
+# database settings
+DB_HOST=db.example.com
+export DB_PORT=5432
+EMPTY=
+COMMENTED=value # trailing comment
+SINGLE='single $quoted # not a comment'
+DOUBLE="line one\nline \"two\""
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).To(Equal(map[string]interface{}{
+				"DB_HOST":   "db.example.com",
+				"DB_PORT":   "5432",
+				"EMPTY":     "",
+				"COMMENTED": "value",
+				"SINGLE":    "single $quoted # not a comment",
+				"DOUBLE":    "line one\nline \"two\"",
+			}))
+		})
+
+		It("parses JSON objects, keeping their values", func() {
+			vars, err := envfile.Parse([]byte(`{"NAME": "value", "COUNT": 12345678901234567890, "ENABLED": true}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).To(Equal(map[string]interface{}{
+				"NAME":    "value",
+				"COUNT":   json.Number("12345678901234567890"),
+				"ENABLED": true,
+			}))
+		})
+
+		It("fails on lines without a value", func() {
+			_, err := envfile.Parse([]byte("A=1\nB\n"))
+			Expect(err).To(MatchError("Missing '=' on line 2"))
+		})
+
+		It("fails on invalid variable names", func() {
+			_, err := envfile.Parse([]byte("1A=1\n"))
+			Expect(err).To(MatchError("Invalid variable name '1A' on line 1"))
+		})
+
+		It("fails on unterminated quotes", func() {
+			_, err := envfile.Parse([]byte(`A="open`))
+			Expect(err).To(MatchError("Unterminated quoted value on line 1"))
+		})
+
+		It("fails on invalid JSON", func() {
+			_, err := envfile.Parse([]byte(`{"A": }`))
+			Expect(err).To(MatchError(ContainSubstring("Invalid JSON")))
+		})
+	})
+
+	Describe("Environ", func() {
+		It("returns the variables as sorted NAME=value strings", func() {
+			environ, err := envfile.Environ(map[string]interface{}{
+				"PORT":          8080,
+				"VCAP_SERVICES": map[string]interface{}{"user-provided": []interface{}{}},
+				"GREETING":      "hello world",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(environ).To(Equal([]string{
+				"GREETING=hello world",
+				"PORT=8080",
+				`VCAP_SERVICES={"user-provided":[]}`,
+			}))
+		})
+	})
+
+	Describe("Marshal", func() {
+		var vars map[string]interface{}
+
+		BeforeEach(func() {
+			vars = map[string]interface{}{
+				"URL":     "https://example.com/path?a=1",
+				"MESSAGE": "hello \"world\"\n",
+				"COUNT":   float64(3),
+			}
+		})
+
+		It("writes dotenv files that parse back to the same strings", func() {
+			contents, err := envfile.Marshal(vars, envfile.FormatDotenv)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("COUNT=3\nMESSAGE=\"hello \\\"world\\\"\\n\"\nURL=\"https://example.com/path?a=1\"\n"))
+
+			parsed, err := envfile.Parse(contents)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(map[string]interface{}{
+				"URL":     "https://example.com/path?a=1",
+				"MESSAGE": "hello \"world\"\n",
+				"COUNT":   "3",
+			}))
+		})
+
+		It("writes JSON objects", func() {
+			contents, err := envfile.Marshal(vars, envfile.FormatJSON)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contents).To(MatchJSON(`{"URL": "https://example.com/path?a=1", "MESSAGE": "hello \"world\"\n", "COUNT": 3}`))
+		})
+
+		It("fails on unknown formats", func() {
+			_, err := envfile.Marshal(vars, "yaml")
+			Expect(err).To(MatchError("Unknown format 'yaml', expected dotenv or json"))
+		})
+	})
+
+	Describe("Equal", func() {
+		It("compares values the way they are stored", func() {
+			Expect(envfile.Equal(float64(3), json.Number("3"))).To(BeTrue())
+			Expect(envfile.Equal("3", json.Number("3"))).To(BeFalse())
+		})
+	})
+})
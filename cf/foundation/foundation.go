@@ -0,0 +1,197 @@
+// Package foundation reads the declarative description of the orgs, spaces,
+// quotas, user roles, security group bindings and feature flags of a Cloud
+// Foundry installation, and plans the changes the apply command makes to
+// converge it.
+package foundation
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"gopkg.in/yaml.v2"
+)
+
+// Foundation is the content of a foundation file. The role lists of orgs and
+// spaces, and the security groups of spaces, are only managed when they are
+// given in the file: a missing list leaves the current assignments alone,
+// while an empty list removes them with --prune.
+type Foundation struct {
+	Quotas       []Quota         `yaml:"quotas,omitempty"`
+	FeatureFlags map[string]bool `yaml:"feature_flags,omitempty"`
+	Orgs         []Org           `yaml:"orgs,omitempty"`
+}
+
+type Quota struct {
+	Name                    string `yaml:"name"`
+	MemoryLimit             string `yaml:"memory_limit,omitempty"`
+	InstanceMemoryLimit     string `yaml:"instance_memory_limit,omitempty"`
+	RoutesLimit             int    `yaml:"total_routes,omitempty"`
+	ServicesLimit           int    `yaml:"total_services,omitempty"`
+	AppInstanceLimit        *int   `yaml:"app_instance_limit,omitempty"`
+	NonBasicServicesAllowed bool   `yaml:"non_basic_services_allowed,omitempty"`
+	ReservedRoutePorts      string `yaml:"total_reserved_route_ports,omitempty"`
+}
+
+type Org struct {
+	Name            string   `yaml:"name"`
+	Quota           string   `yaml:"quota,omitempty"`
+	Managers        []string `yaml:"managers,omitempty"`
+	BillingManagers []string `yaml:"billing_managers,omitempty"`
+	Auditors        []string `yaml:"auditors,omitempty"`
+	SpaceQuotas     []Quota  `yaml:"space_quotas,omitempty"`
+	Spaces          []Space  `yaml:"spaces,omitempty"`
+}
+
+type Space struct {
+	Name           string   `yaml:"name"`
+	SpaceQuota     string   `yaml:"space_quota,omitempty"`
+	AllowSSH       *bool    `yaml:"allow_ssh,omitempty"`
+	Managers       []string `yaml:"managers,omitempty"`
+	Developers     []string `yaml:"developers,omitempty"`
+	Auditors       []string `yaml:"auditors,omitempty"`
+	SecurityGroups []string `yaml:"security_groups,omitempty"`
+}
+
+// Read reads and validates the foundation file at path.
+func Read(path string) (Foundation, error) {
+	var f Foundation
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+
+	err = yaml.Unmarshal(contents, &f)
+	if err != nil {
+		return f, err
+	}
+
+	return f, f.Validate()
+}
+
+// Validate checks that every item has a name which is unique among its
+// siblings, and that every quota can be converted.
+func (f Foundation) Validate() error {
+	names := map[string]bool{}
+	for _, quota := range f.Quotas {
+		if err := checkName(names, T("quota"), quota.Name); err != nil {
+			return err
+		}
+		if _, err := quota.ToQuotaFields(); err != nil {
+			return err
+		}
+	}
+
+	orgNames := map[string]bool{}
+	for _, org := range f.Orgs {
+		if err := checkName(orgNames, T("org"), org.Name); err != nil {
+			return err
+		}
+
+		spaceQuotaNames := map[string]bool{}
+		for _, quota := range org.SpaceQuotas {
+			if err := checkName(spaceQuotaNames, T("space quota"), quota.Name); err != nil {
+				return err
+			}
+			if _, err := quota.ToSpaceQuota(); err != nil {
+				return err
+			}
+		}
+
+		spaceNames := map[string]bool{}
+		for _, space := range org.Spaces {
+			if err := checkName(spaceNames, T("space"), space.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkName(names map[string]bool, kind string, name string) error {
+	if name == "" {
+		return errors.New(T("Every {{.Kind}} needs a name", map[string]interface{}{"Kind": kind}))
+	}
+	if names[name] {
+		return errors.New(T("The {{.Kind}} {{.Name}} is declared more than once", map[string]interface{}{"Kind": kind, "Name": name}))
+	}
+	names[name] = true
+	return nil
+}
+
+// ToQuotaFields returns the org quota definition, with the same defaults as
+// create-quota for the limits that are not given.
+func (q Quota) ToQuotaFields() (models.QuotaFields, error) {
+	memoryLimit, instanceMemoryLimit, err := q.memoryLimits()
+	if err != nil {
+		return models.QuotaFields{}, err
+	}
+
+	return models.QuotaFields{
+		Name:                    q.Name,
+		MemoryLimit:             memoryLimit,
+		InstanceMemoryLimit:     instanceMemoryLimit,
+		RoutesLimit:             q.RoutesLimit,
+		ServicesLimit:           q.ServicesLimit,
+		NonBasicServicesAllowed: q.NonBasicServicesAllowed,
+		AppInstanceLimit:        q.appInstanceLimit(),
+		ReservedRoutePorts:      json.Number(q.ReservedRoutePorts),
+	}, nil
+}
+
+// ToSpaceQuota returns the space quota definition, with the same defaults
+// as create-space-quota for the limits that are not given.
+func (q Quota) ToSpaceQuota() (models.SpaceQuota, error) {
+	memoryLimit, instanceMemoryLimit, err := q.memoryLimits()
+	if err != nil {
+		return models.SpaceQuota{}, err
+	}
+
+	return models.SpaceQuota{
+		Name:                    q.Name,
+		MemoryLimit:             memoryLimit,
+		InstanceMemoryLimit:     instanceMemoryLimit,
+		RoutesLimit:             q.RoutesLimit,
+		ServicesLimit:           q.ServicesLimit,
+		NonBasicServicesAllowed: q.NonBasicServicesAllowed,
+		AppInstanceLimit:        q.appInstanceLimit(),
+		ReservedRoutePortsLimit: json.Number(q.ReservedRoutePorts),
+	}, nil
+}
+
+func (q Quota) memoryLimits() (int64, int64, error) {
+	var memoryLimit int64
+	if q.MemoryLimit != "" {
+		var err error
+		memoryLimit, err = formatters.ToMegabytes(q.MemoryLimit)
+		if err != nil {
+			return 0, 0, errors.New(T("Invalid memory limit of quota {{.QuotaName}}: {{.MemoryLimit}}",
+				map[string]interface{}{"QuotaName": q.Name, "MemoryLimit": q.MemoryLimit}))
+		}
+	}
+
+	instanceMemoryLimit := int64(-1)
+	if q.InstanceMemoryLimit != "" && q.InstanceMemoryLimit != "-1" {
+		var err error
+		instanceMemoryLimit, err = formatters.ToMegabytes(q.InstanceMemoryLimit)
+		if err != nil {
+			return 0, 0, errors.New(T("Invalid instance memory limit of quota {{.QuotaName}}: {{.MemoryLimit}}",
+				map[string]interface{}{"QuotaName": q.Name, "MemoryLimit": q.InstanceMemoryLimit}))
+		}
+	}
+
+	return memoryLimit, instanceMemoryLimit, nil
+}
+
+func (q Quota) appInstanceLimit() int {
+	if q.AppInstanceLimit == nil {
+		return resources.UnlimitedAppInstances
+	}
+	return *q.AppInstanceLimit
+}
@@ -0,0 +1,151 @@
+package foundation_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/foundation"
+	"github.com/cloudfoundry/cli/cf/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("foundation", func() {
+	var (
+		dir  string
+		path string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "foundation")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "foundation.yml")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(contents string) {
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+	}
+
+	Describe("Read", func() {
+		It("reads the quotas, feature flags, orgs and spaces", func() {
+			write(`
+quotas:
+- name: small
+  memory_limit: 2G
+  total_routes: 10
+feature_flags:
+  diego_docker: true
+orgs:
+- name: my-org
+  quota: small
+  managers: [alice]
+  auditors: []
+  space_quotas:
+  - name: tiny
+    memory_limit: 512M
+  spaces:
+  - name: production
+    space_quota: tiny
+    allow_ssh: false
+    developers: [bob]
+    security_groups: [public-networks]
+`)
+
+			f, err := foundation.Read(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			allowSSH := false
+			Expect(f).To(Equal(foundation.Foundation{
+				Quotas:       []foundation.Quota{{Name: "small", MemoryLimit: "2G", RoutesLimit: 10}},
+				FeatureFlags: map[string]bool{"diego_docker": true},
+				Orgs: []foundation.Org{
+					{
+						Name:        "my-org",
+						Quota:       "small",
+						Managers:    []string{"alice"},
+						Auditors:    []string{},
+						SpaceQuotas: []foundation.Quota{{Name: "tiny", MemoryLimit: "512M"}},
+						Spaces: []foundation.Space{
+							{
+								Name:           "production",
+								SpaceQuota:     "tiny",
+								AllowSSH:       &allowSSH,
+								Developers:     []string{"bob"},
+								SecurityGroups: []string{"public-networks"},
+							},
+						},
+					},
+				},
+			}))
+		})
+
+		It("fails when a name is missing", func() {
+			write("orgs:\n- quota: small\n")
+
+			_, err := foundation.Read(path)
+			Expect(err).To(MatchError("Every org needs a name"))
+		})
+
+		It("fails when a space is declared twice in an org", func() {
+			write("orgs:\n- name: my-org\n  spaces:\n  - name: dev\n  - name: dev\n")
+
+			_, err := foundation.Read(path)
+			Expect(err).To(MatchError("The space dev is declared more than once"))
+		})
+
+		It("fails when a memory limit is invalid", func() {
+			write("quotas:\n- name: small\n  memory_limit: lots\n")
+
+			_, err := foundation.Read(path)
+			Expect(err).To(MatchError("Invalid memory limit of quota small: lots"))
+		})
+
+		It("fails when the file does not exist", func() {
+			_, err := foundation.Read(filepath.Join(dir, "missing.yml"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ToQuotaFields", func() {
+		It("uses the defaults of create-quota", func() {
+			quota, err := foundation.Quota{Name: "small"}.ToQuotaFields()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quota).To(Equal(models.QuotaFields{
+				Name:                "small",
+				InstanceMemoryLimit: -1,
+				AppInstanceLimit:    -1,
+			}))
+		})
+
+		It("converts the given limits", func() {
+			appInstances := 25
+			quota, err := foundation.Quota{
+				Name:                    "big",
+				MemoryLimit:             "10G",
+				InstanceMemoryLimit:     "1G",
+				ServicesLimit:           5,
+				AppInstanceLimit:        &appInstances,
+				NonBasicServicesAllowed: true,
+				ReservedRoutePorts:      "4",
+			}.ToQuotaFields()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quota).To(Equal(models.QuotaFields{
+				Name:                    "big",
+				MemoryLimit:             10240,
+				InstanceMemoryLimit:     1024,
+				ServicesLimit:           5,
+				AppInstanceLimit:        25,
+				NonBasicServicesAllowed: true,
+				ReservedRoutePorts:      json.Number("4"),
+			}))
+		})
+	})
+})
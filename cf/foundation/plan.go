@@ -0,0 +1,670 @@
+package foundation
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/featureflags"
+	"github.com/cloudfoundry/cli/cf/api/organizations"
+	"github.com/cloudfoundry/cli/cf/api/quotas"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups"
+	securitygroupspaces "github.com/cloudfoundry/cli/cf/api/securitygroups/spaces"
+	"github.com/cloudfoundry/cli/cf/api/spacequotas"
+	"github.com/cloudfoundry/cli/cf/api/spaces"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+)
+
+// The actions of the changes in a plan.
+const (
+	CreateAction = "+"
+	UpdateAction = "~"
+	DeleteAction = "-"
+)
+
+// Change is a single step of a plan. The changes of a plan must be applied
+// in order, as later changes use the GUIDs of the items created by earlier
+// ones.
+type Change struct {
+	Action      string
+	Description string
+	Apply       func() error
+}
+
+type planRole struct {
+	role  models.Role
+	name  string
+	users []string
+}
+
+// Planner computes the changes that converge the foundation. The GUIDs of
+// existing items are looked up while planning; the GUIDs of the items that
+// are created are filled in by their changes.
+type Planner struct {
+	quotaRepo           quotas.QuotaRepository
+	flagRepo            featureflags.FeatureFlagRepository
+	orgRepo             organizations.OrganizationRepository
+	spaceRepo           spaces.SpaceRepository
+	spaceQuotaRepo      spacequotas.SpaceQuotaRepository
+	securityGroupRepo   securitygroups.SecurityGroupRepo
+	securityGroupBinder securitygroupspaces.SecurityGroupSpaceBinder
+	userRepo            api.UserRepository
+
+	prune   bool
+	changes []Change
+
+	quotaGUIDs         map[string]string
+	orgGUIDs           map[string]string
+	spaceQuotaGUIDs    map[string]string
+	spaceGUIDs         map[string]string
+	securityGroupGUIDs map[string]string
+}
+
+func NewPlanner(
+	quotaRepo quotas.QuotaRepository,
+	flagRepo featureflags.FeatureFlagRepository,
+	orgRepo organizations.OrganizationRepository,
+	spaceRepo spaces.SpaceRepository,
+	spaceQuotaRepo spacequotas.SpaceQuotaRepository,
+	securityGroupRepo securitygroups.SecurityGroupRepo,
+	securityGroupBinder securitygroupspaces.SecurityGroupSpaceBinder,
+	userRepo api.UserRepository,
+	prune bool,
+) *Planner {
+	return &Planner{
+		quotaRepo:           quotaRepo,
+		flagRepo:            flagRepo,
+		orgRepo:             orgRepo,
+		spaceRepo:           spaceRepo,
+		spaceQuotaRepo:      spaceQuotaRepo,
+		securityGroupRepo:   securityGroupRepo,
+		securityGroupBinder: securityGroupBinder,
+		userRepo:            userRepo,
+		prune:               prune,
+		quotaGUIDs:          map[string]string{},
+		orgGUIDs:            map[string]string{},
+		spaceQuotaGUIDs:     map[string]string{},
+		spaceGUIDs:          map[string]string{},
+		securityGroupGUIDs:  map[string]string{},
+	}
+}
+
+func (p *Planner) add(action string, description string, apply func() error) {
+	p.changes = append(p.changes, Change{Action: action, Description: description, Apply: apply})
+}
+
+// Plan returns the changes that make the current state match f. A planner
+// computes a single plan.
+func (p *Planner) Plan(f Foundation) ([]Change, error) {
+	err := p.planQuotas(f.Quotas)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.planFeatureFlags(f.FeatureFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, org := range f.Orgs {
+		err = p.planOrg(org)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p.changes, nil
+}
+
+func (p *Planner) planQuotas(quotas []Quota) error {
+	for _, quota := range quotas {
+		desired, err := quota.ToQuotaFields()
+		if err != nil {
+			return err
+		}
+
+		current, err := p.quotaRepo.FindByName(quota.Name)
+		switch err.(type) {
+		case nil:
+			p.quotaGUIDs[quota.Name] = current.GUID
+			desired.GUID = current.GUID
+			if desired.ReservedRoutePorts == "" {
+				desired.ReservedRoutePorts = current.ReservedRoutePorts
+			}
+			if desired != current {
+				p.add(UpdateAction, T("update quota {{.Name}}", map[string]interface{}{"Name": quota.Name}), func() error {
+					return p.quotaRepo.Update(desired)
+				})
+			}
+		case *cferrors.ModelNotFoundError:
+			name := quota.Name
+			p.add(CreateAction, T("create quota {{.Name}}", map[string]interface{}{"Name": name}), func() error {
+				err := p.quotaRepo.Create(desired)
+				if err != nil {
+					return err
+				}
+				created, err := p.quotaRepo.FindByName(name)
+				if err != nil {
+					return err
+				}
+				p.quotaGUIDs[name] = created.GUID
+				return nil
+			})
+			p.quotaGUIDs[name] = ""
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Planner) planFeatureFlags(desired map[string]bool) error {
+	if len(desired) == 0 {
+		return nil
+	}
+
+	flags, err := p.flagRepo.List()
+	if err != nil {
+		return err
+	}
+
+	current := map[string]bool{}
+	for _, flag := range flags {
+		current[flag.Name] = flag.Enabled
+	}
+
+	names := []string{}
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		enabled, found := current[name]
+		if !found {
+			return errors.New(T("Unknown feature flag {{.Name}}", map[string]interface{}{"Name": name}))
+		}
+		if enabled == desired[name] {
+			continue
+		}
+
+		name, enabled := name, desired[name]
+		description := T("disable feature flag {{.Name}}", map[string]interface{}{"Name": name})
+		if enabled {
+			description = T("enable feature flag {{.Name}}", map[string]interface{}{"Name": name})
+		}
+		p.add(UpdateAction, description, func() error {
+			return p.flagRepo.Update(name, enabled)
+		})
+	}
+
+	return nil
+}
+
+func (p *Planner) planOrg(org Org) error {
+	if org.Quota != "" {
+		err := p.checkQuota(org.Quota)
+		if err != nil {
+			return err
+		}
+	}
+
+	current, err := p.orgRepo.FindByName(org.Name)
+	switch err.(type) {
+	case nil:
+		p.orgGUIDs[org.Name] = current.GUID
+		if org.Quota != "" && current.QuotaDefinition.Name != org.Quota {
+			p.add(UpdateAction, T("assign quota {{.QuotaName}} to org {{.OrgName}}",
+				map[string]interface{}{"QuotaName": org.Quota, "OrgName": org.Name}), func() error {
+				return p.quotaRepo.AssignQuotaToOrg(p.orgGUIDs[org.Name], p.quotaGUIDs[org.Quota])
+			})
+		}
+	case *cferrors.ModelNotFoundError:
+		p.add(CreateAction, T("create org {{.OrgName}}", map[string]interface{}{"OrgName": org.Name}), func() error {
+			newOrg := models.Organization{}
+			newOrg.Name = org.Name
+			newOrg.QuotaDefinition.GUID = p.quotaGUIDs[org.Quota]
+			err := p.orgRepo.Create(newOrg)
+			if err != nil {
+				return err
+			}
+			created, err := p.orgRepo.FindByName(org.Name)
+			if err != nil {
+				return err
+			}
+			p.orgGUIDs[org.Name] = created.GUID
+			return nil
+		})
+	default:
+		return err
+	}
+
+	// the spaces and space quotas inlined in the org are capped, so they
+	// are listed page by page
+	currentSpaceQuotas := map[string]models.SpaceQuota{}
+	currentSpaces := map[string]models.Space{}
+	if current.GUID != "" {
+		quotas, err := p.spaceQuotaRepo.FindByOrg(current.GUID)
+		if err != nil {
+			return err
+		}
+		for _, quota := range quotas {
+			currentSpaceQuotas[quota.Name] = quota
+		}
+
+		err = p.spaceRepo.ListSpacesFromOrg(current.GUID, func(space models.Space) bool {
+			currentSpaces[space.Name] = space
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = p.planSpaceQuotas(org, currentSpaceQuotas)
+	if err != nil {
+		return err
+	}
+
+	roles := []planRole{
+		{role: models.RoleOrgManager, name: "OrgManager", users: org.Managers},
+		{role: models.RoleBillingManager, name: "BillingManager", users: org.BillingManagers},
+		{role: models.RoleOrgAuditor, name: "OrgAuditor", users: org.Auditors},
+	}
+	for _, role := range roles {
+		err = p.planOrgRole(org, current.GUID, role)
+		if err != nil {
+			return err
+		}
+	}
+
+	desiredSpaces := map[string]bool{}
+	for _, space := range org.Spaces {
+		desiredSpaces[space.Name] = true
+		existing, exists := currentSpaces[space.Name]
+		err = p.planSpace(org, space, existing, exists)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !p.prune {
+		return nil
+	}
+
+	spaceNames := []string{}
+	for name := range currentSpaces {
+		spaceNames = append(spaceNames, name)
+	}
+	sort.Strings(spaceNames)
+
+	for _, name := range spaceNames {
+		if desiredSpaces[name] {
+			continue
+		}
+		spaceGUID := currentSpaces[name].GUID
+		p.add(DeleteAction, T("delete space {{.SpaceName}} in org {{.OrgName}}",
+			map[string]interface{}{"SpaceName": name, "OrgName": org.Name}), func() error {
+			return p.spaceRepo.Delete(spaceGUID)
+		})
+	}
+
+	desiredSpaceQuotas := map[string]bool{}
+	for _, quota := range org.SpaceQuotas {
+		desiredSpaceQuotas[quota.Name] = true
+	}
+	quotaNames := []string{}
+	for name := range currentSpaceQuotas {
+		quotaNames = append(quotaNames, name)
+	}
+	sort.Strings(quotaNames)
+
+	for _, name := range quotaNames {
+		if desiredSpaceQuotas[name] {
+			continue
+		}
+		quotaGUID := currentSpaceQuotas[name].GUID
+		p.add(DeleteAction, T("delete space quota {{.QuotaName}} in org {{.OrgName}}",
+			map[string]interface{}{"QuotaName": name, "OrgName": org.Name}), func() error {
+			return p.spaceQuotaRepo.Delete(quotaGUID)
+		})
+	}
+
+	return nil
+}
+
+func (p *Planner) planSpaceQuotas(org Org, current map[string]models.SpaceQuota) error {
+	for name, quota := range current {
+		p.spaceQuotaGUIDs[org.Name+"/"+name] = quota.GUID
+	}
+
+	for _, quota := range org.SpaceQuotas {
+		desired, err := quota.ToSpaceQuota()
+		if err != nil {
+			return err
+		}
+
+		key := org.Name + "/" + quota.Name
+		existing, found := current[quota.Name]
+		if found {
+			desired.GUID = existing.GUID
+			desired.OrgGUID = existing.OrgGUID
+			if desired.ReservedRoutePortsLimit == "" {
+				desired.ReservedRoutePortsLimit = existing.ReservedRoutePortsLimit
+			}
+			if desired != existing {
+				p.add(UpdateAction, T("update space quota {{.QuotaName}} in org {{.OrgName}}",
+					map[string]interface{}{"QuotaName": quota.Name, "OrgName": org.Name}), func() error {
+					return p.spaceQuotaRepo.Update(desired)
+				})
+			}
+			continue
+		}
+
+		name := quota.Name
+		p.spaceQuotaGUIDs[key] = ""
+		p.add(CreateAction, T("create space quota {{.QuotaName}} in org {{.OrgName}}",
+			map[string]interface{}{"QuotaName": name, "OrgName": org.Name}), func() error {
+			desired.OrgGUID = p.orgGUIDs[org.Name]
+			err := p.spaceQuotaRepo.Create(desired)
+			if err != nil {
+				return err
+			}
+			created, err := p.spaceQuotaRepo.FindByNameAndOrgGUID(name, desired.OrgGUID)
+			if err != nil {
+				return err
+			}
+			p.spaceQuotaGUIDs[key] = created.GUID
+			return nil
+		})
+	}
+
+	return nil
+}
+
+func (p *Planner) planOrgRole(org Org, orgGUID string, role planRole) error {
+	if role.users == nil {
+		return nil
+	}
+
+	current := []string{}
+	if orgGUID != "" {
+		users, err := p.userRepo.ListUsersInOrgForRoleWithNoUAA(orgGUID, role.role)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			current = append(current, user.Username)
+		}
+	}
+
+	missing, extra := diffUsernames(current, role.users)
+	for _, username := range missing {
+		username := username
+		p.add(CreateAction, T("assign role {{.Role}} to {{.Username}} in org {{.OrgName}}",
+			map[string]interface{}{"Role": role.name, "Username": username, "OrgName": org.Name}), func() error {
+			return p.userRepo.SetOrgRoleByUsername(username, p.orgGUIDs[org.Name], role.role)
+		})
+	}
+
+	if !p.prune {
+		return nil
+	}
+
+	for _, username := range extra {
+		username := username
+		p.add(DeleteAction, T("remove role {{.Role}} from {{.Username}} in org {{.OrgName}}",
+			map[string]interface{}{"Role": role.name, "Username": username, "OrgName": org.Name}), func() error {
+			return p.userRepo.UnsetOrgRoleByUsername(username, orgGUID, role.role)
+		})
+	}
+
+	return nil
+}
+
+func (p *Planner) planSpace(org Org, space Space, current models.Space, exists bool) error {
+	key := org.Name + "/" + space.Name
+
+	if space.SpaceQuota != "" {
+		if _, found := p.spaceQuotaGUIDs[org.Name+"/"+space.SpaceQuota]; !found {
+			return errors.New(T("Space quota {{.QuotaName}} not found in org {{.OrgName}}",
+				map[string]interface{}{"QuotaName": space.SpaceQuota, "OrgName": org.Name}))
+		}
+	}
+
+	for _, name := range space.SecurityGroups {
+		err := p.checkSecurityGroup(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	names := map[string]interface{}{"SpaceName": space.Name, "OrgName": org.Name}
+
+	if exists {
+		p.spaceGUIDs[key] = current.GUID
+
+		p.planSpaceQuotaAssignment(org, space, current)
+
+		if space.AllowSSH != nil && *space.AllowSSH != current.AllowSSH {
+			p.planAllowSSH(key, space, names)
+		}
+	} else {
+		p.add(CreateAction, T("create space {{.SpaceName}} in org {{.OrgName}}", names), func() error {
+			created, err := p.spaceRepo.Create(space.Name, p.orgGUIDs[org.Name], p.spaceQuotaGUIDs[org.Name+"/"+space.SpaceQuota])
+			if err != nil {
+				return err
+			}
+			p.spaceGUIDs[key] = created.GUID
+			return nil
+		})
+
+		// new spaces allow SSH unless told otherwise
+		if space.AllowSSH != nil && !*space.AllowSSH {
+			p.planAllowSSH(key, space, names)
+		}
+	}
+
+	roles := []planRole{
+		{role: models.RoleSpaceManager, name: "SpaceManager", users: space.Managers},
+		{role: models.RoleSpaceDeveloper, name: "SpaceDeveloper", users: space.Developers},
+		{role: models.RoleSpaceAuditor, name: "SpaceAuditor", users: space.Auditors},
+	}
+	for _, role := range roles {
+		err := p.planSpaceRole(org, space, current.GUID, role)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.planSecurityGroups(key, space, current, names)
+
+	return nil
+}
+
+func (p *Planner) planSpaceQuotaAssignment(org Org, space Space, current models.Space) {
+	key := org.Name + "/" + space.Name
+
+	if space.SpaceQuota == "" {
+		if p.prune && current.SpaceQuotaGUID != "" {
+			p.add(DeleteAction, T("unassign the space quota of space {{.SpaceName}} in org {{.OrgName}}",
+				map[string]interface{}{"SpaceName": space.Name, "OrgName": org.Name}), func() error {
+				return p.spaceQuotaRepo.UnassignQuotaFromSpace(current.GUID, current.SpaceQuotaGUID)
+			})
+		}
+		return
+	}
+
+	quotaKey := org.Name + "/" + space.SpaceQuota
+	if p.spaceQuotaGUIDs[quotaKey] != "" && p.spaceQuotaGUIDs[quotaKey] == current.SpaceQuotaGUID {
+		return
+	}
+
+	p.add(UpdateAction, T("assign space quota {{.QuotaName}} to space {{.SpaceName}} in org {{.OrgName}}",
+		map[string]interface{}{"QuotaName": space.SpaceQuota, "SpaceName": space.Name, "OrgName": org.Name}), func() error {
+		return p.spaceQuotaRepo.AssociateSpaceWithQuota(p.spaceGUIDs[key], p.spaceQuotaGUIDs[quotaKey])
+	})
+}
+
+func (p *Planner) planAllowSSH(key string, space Space, names map[string]interface{}) {
+	allow := *space.AllowSSH
+	description := T("disallow SSH in space {{.SpaceName}} in org {{.OrgName}}", names)
+	if allow {
+		description = T("allow SSH in space {{.SpaceName}} in org {{.OrgName}}", names)
+	}
+
+	p.add(UpdateAction, description, func() error {
+		return p.spaceRepo.SetAllowSSH(p.spaceGUIDs[key], allow)
+	})
+}
+
+func (p *Planner) planSpaceRole(org Org, space Space, spaceGUID string, role planRole) error {
+	if role.users == nil {
+		return nil
+	}
+
+	key := org.Name + "/" + space.Name
+
+	current := []string{}
+	if spaceGUID != "" {
+		users, err := p.userRepo.ListUsersInSpaceForRoleWithNoUAA(spaceGUID, role.role)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			current = append(current, user.Username)
+		}
+	}
+
+	missing, extra := diffUsernames(current, role.users)
+	for _, username := range missing {
+		username := username
+		p.add(CreateAction, T("assign role {{.Role}} to {{.Username}} in space {{.SpaceName}} in org {{.OrgName}}",
+			map[string]interface{}{"Role": role.name, "Username": username, "SpaceName": space.Name, "OrgName": org.Name}), func() error {
+			return p.userRepo.SetSpaceRoleByUsername(username, p.spaceGUIDs[key], p.orgGUIDs[org.Name], role.role)
+		})
+	}
+
+	if !p.prune {
+		return nil
+	}
+
+	for _, username := range extra {
+		username := username
+		p.add(DeleteAction, T("remove role {{.Role}} from {{.Username}} in space {{.SpaceName}} in org {{.OrgName}}",
+			map[string]interface{}{"Role": role.name, "Username": username, "SpaceName": space.Name, "OrgName": org.Name}), func() error {
+			return p.userRepo.UnsetSpaceRoleByUsername(username, spaceGUID, role.role)
+		})
+	}
+
+	return nil
+}
+
+func (p *Planner) planSecurityGroups(key string, space Space, current models.Space, names map[string]interface{}) {
+	if space.SecurityGroups == nil {
+		return
+	}
+
+	bound := map[string]string{}
+	for _, group := range current.SecurityGroups {
+		bound[group.Name] = group.GUID
+	}
+
+	desired := map[string]bool{}
+	for _, name := range space.SecurityGroups {
+		desired[name] = true
+		if _, found := bound[name]; found {
+			continue
+		}
+
+		groupGUID := p.securityGroupGUIDs[name]
+		p.add(CreateAction, T("bind security group {{.SecurityGroupName}} to space {{.SpaceName}} in org {{.OrgName}}",
+			withName(names, "SecurityGroupName", name)), func() error {
+			return p.securityGroupBinder.BindSpace(groupGUID, p.spaceGUIDs[key])
+		})
+	}
+
+	if !p.prune {
+		return
+	}
+
+	for _, group := range current.SecurityGroups {
+		if desired[group.Name] {
+			continue
+		}
+
+		groupGUID := group.GUID
+		p.add(DeleteAction, T("unbind security group {{.SecurityGroupName}} from space {{.SpaceName}} in org {{.OrgName}}",
+			withName(names, "SecurityGroupName", group.Name)), func() error {
+			return p.securityGroupBinder.UnbindSpace(groupGUID, current.GUID)
+		})
+	}
+}
+
+// checkQuota makes sure that a quota referenced by an org is either in the
+// file or exists.
+func (p *Planner) checkQuota(name string) error {
+	if _, found := p.quotaGUIDs[name]; found {
+		return nil
+	}
+
+	quota, err := p.quotaRepo.FindByName(name)
+	if err != nil {
+		return err
+	}
+
+	p.quotaGUIDs[name] = quota.GUID
+	return nil
+}
+
+func (p *Planner) checkSecurityGroup(name string) error {
+	if _, found := p.securityGroupGUIDs[name]; found {
+		return nil
+	}
+
+	group, err := p.securityGroupRepo.Read(name)
+	if err != nil {
+		return err
+	}
+
+	p.securityGroupGUIDs[name] = group.GUID
+	return nil
+}
+
+func withName(names map[string]interface{}, key string, value string) map[string]interface{} {
+	result := map[string]interface{}{key: value}
+	for k, v := range names {
+		result[k] = v
+	}
+	return result
+}
+
+func diffUsernames(current []string, desired []string) ([]string, []string) {
+	currentSet := map[string]bool{}
+	for _, username := range current {
+		currentSet[username] = true
+	}
+	desiredSet := map[string]bool{}
+	for _, username := range desired {
+		desiredSet[username] = true
+	}
+
+	missing := []string{}
+	for _, username := range desired {
+		if !currentSet[username] {
+			missing = append(missing, username)
+		}
+	}
+
+	extra := []string{}
+	for _, username := range current {
+		if !desiredSet[username] {
+			extra = append(extra, username)
+		}
+	}
+
+	return missing, extra
+}
@@ -0,0 +1,123 @@
+package foundation_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/featureflags/featureflagsfakes"
+	"github.com/cloudfoundry/cli/cf/api/organizations/organizationsfakes"
+	"github.com/cloudfoundry/cli/cf/api/quotas/quotasfakes"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/securitygroupsfakes"
+	securitygroupspacesfakes "github.com/cloudfoundry/cli/cf/api/securitygroups/spaces/spacesfakes"
+	"github.com/cloudfoundry/cli/cf/api/spacequotas/spacequotasfakes"
+	"github.com/cloudfoundry/cli/cf/api/spaces/spacesfakes"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/foundation"
+	"github.com/cloudfoundry/cli/cf/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Planner", func() {
+	var (
+		orgRepo        *organizationsfakes.FakeOrganizationRepository
+		spaceRepo      *spacesfakes.FakeSpaceRepository
+		spaceQuotaRepo *spacequotasfakes.FakeSpaceQuotaRepository
+		planner        *foundation.Planner
+		spaceNames     []string
+	)
+
+	BeforeEach(func() {
+		orgRepo = new(organizationsfakes.FakeOrganizationRepository)
+		spaceRepo = new(spacesfakes.FakeSpaceRepository)
+		spaceQuotaRepo = new(spacequotasfakes.FakeSpaceQuotaRepository)
+		planner = foundation.NewPlanner(
+			new(quotasfakes.FakeQuotaRepository),
+			new(featureflagsfakes.FakeFeatureFlagRepository),
+			orgRepo,
+			spaceRepo,
+			spaceQuotaRepo,
+			new(securitygroupsfakes.FakeSecurityGroupRepo),
+			new(securitygroupspacesfakes.FakeSecurityGroupSpaceBinder),
+			new(apifakes.FakeUserRepository),
+			true,
+		)
+
+		spaceNames = []string{}
+		for i := 1; i <= 60; i++ {
+			spaceNames = append(spaceNames, fmt.Sprintf("space-%02d", i))
+		}
+
+		// the server caps the inlined spaces, so only the listing has them all
+		org := models.Organization{}
+		org.GUID = "my-org-guid"
+		org.Name = "my-org"
+		for _, name := range spaceNames[:50] {
+			org.Spaces = append(org.Spaces, models.SpaceFields{GUID: name + "-guid", Name: name})
+		}
+		orgRepo.FindByNameReturns(org, nil)
+
+		spaceRepo.ListSpacesFromOrgStub = func(orgGUID string, callback func(models.Space) bool) error {
+			for _, name := range spaceNames {
+				space := models.Space{}
+				space.GUID = name + "-guid"
+				space.Name = name
+				if !callback(space) {
+					break
+				}
+			}
+			return nil
+		}
+	})
+
+	It("plans against every space of the org", func() {
+		desired := foundation.Org{Name: "my-org"}
+		for _, name := range spaceNames {
+			desired.Spaces = append(desired.Spaces, foundation.Space{Name: name})
+		}
+
+		changes, err := planner.Plan(foundation.Foundation{Orgs: []foundation.Org{desired}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+
+		orgGUID, _ := spaceRepo.ListSpacesFromOrgArgsForCall(0)
+		Expect(orgGUID).To(Equal("my-org-guid"))
+		Expect(spaceQuotaRepo.FindByOrgArgsForCall(0)).To(Equal("my-org-guid"))
+	})
+
+	It("only deletes the spaces that are not in the file", func() {
+		desired := foundation.Org{
+			Name:   "my-org",
+			Spaces: []foundation.Space{{Name: "space-55"}},
+		}
+
+		changes, err := planner.Plan(foundation.Foundation{Orgs: []foundation.Org{desired}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(HaveLen(59))
+		for _, change := range changes {
+			Expect(change.Action).To(Equal(foundation.DeleteAction))
+			Expect(change.Description).NotTo(ContainSubstring("space-55"))
+		}
+	})
+
+	It("does not list the spaces of an org that is created", func() {
+		orgRepo.FindByNameReturns(models.Organization{}, cferrors.NewModelNotFoundError("Organization", "new-org"))
+
+		changes, err := planner.Plan(foundation.Foundation{Orgs: []foundation.Org{{Name: "new-org"}}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Description).To(Equal("create org new-org"))
+		Expect(spaceRepo.ListSpacesFromOrgCallCount()).To(Equal(0))
+		Expect(spaceQuotaRepo.FindByOrgCallCount()).To(Equal(0))
+	})
+
+	It("fails when the spaces cannot be listed", func() {
+		spaceRepo.ListSpacesFromOrgStub = nil
+		spaceRepo.ListSpacesFromOrgReturns(errors.New("server error"))
+
+		_, err := planner.Plan(foundation.Foundation{Orgs: []foundation.Org{{Name: "my-org"}}})
+		Expect(err).To(MatchError("server error"))
+	})
+})
@@ -135,11 +135,13 @@ func newAppPresenter() appPresenter {
 					presentCommand("env"),
 					presentCommand("set-env"),
 					presentCommand("unset-env"),
+					presentCommand("local-env"),
 				}, {
 					presentCommand("stacks"),
 					presentCommand("stack"),
 				}, {
 					presentCommand("copy-source"),
+					presentCommand("download-droplet"),
 				}, {
 					presentCommand("create-app-manifest"),
 				}, {
@@ -149,6 +151,7 @@ func newAppPresenter() appPresenter {
 					presentCommand("disable-ssh"),
 					presentCommand("ssh-enabled"),
 					presentCommand("ssh"),
+					presentCommand("scp"),
 				},
 			},
 		}, {
@@ -168,6 +171,7 @@ func newAppPresenter() appPresenter {
 					presentCommand("service-keys"),
 					presentCommand("service-key"),
 					presentCommand("delete-service-key"),
+					presentCommand("rotate-service-key"),
 				}, {
 					presentCommand("bind-service"),
 					presentCommand("unbind-service"),
@@ -205,6 +209,9 @@ func newAppPresenter() appPresenter {
 					presentCommand("allow-space-ssh"),
 					presentCommand("disallow-space-ssh"),
 					presentCommand("space-ssh-allowed"),
+				}, {
+					presentCommand("export-space"),
+					presentCommand("import-space"),
 				},
 			},
 		}, {
@@ -259,6 +266,9 @@ func newAppPresenter() appPresenter {
 					presentCommand("space-users"),
 					presentCommand("set-space-role"),
 					presentCommand("unset-space-role"),
+				}, {
+					presentCommand("user-roles"),
+					presentCommand("import-roles"),
 				},
 			},
 		}, {
@@ -267,6 +277,8 @@ func newAppPresenter() appPresenter {
 				{
 					presentCommand("quotas"),
 					presentCommand("quota"),
+					presentCommand("quota-usage"),
+					presentCommand("usage-report"),
 					presentCommand("set-quota"),
 				}, {
 					presentCommand("create-quota"),
@@ -277,6 +289,9 @@ func newAppPresenter() appPresenter {
 					presentCommand("share-private-domain"),
 					presentCommand("unshare-private-domain"),
 				},
+				{
+					presentCommand("apply"),
+				},
 			},
 		}, {
 			Name: T("SPACE ADMIN"),
@@ -334,6 +349,9 @@ func newAppPresenter() appPresenter {
 					presentCommand("bind-running-security-group"),
 					presentCommand("running-security-groups"),
 					presentCommand("unbind-running-security-group"),
+				}, {
+					presentCommand("security-group-check"),
+					presentCommand("lint-security-groups"),
 				},
 			},
 		}, {
@@ -365,6 +383,9 @@ func newAppPresenter() appPresenter {
 					presentCommand("config"),
 					presentCommand("oauth-token"),
 					presentCommand("ssh-code"),
+					presentCommand("ssh-config"),
+					presentCommand("ssh-proxy"),
+					presentCommand("ssh-replay"),
 				},
 			},
 		}, {
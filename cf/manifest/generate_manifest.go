@@ -3,6 +3,7 @@ package manifest
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/cloudfoundry/cli/cf/models"
 
@@ -132,6 +133,75 @@ func (m *appManifest) GetContents() []models.Application {
 	return m.contents
 }
 
+// AddApplication adds the current settings of a pushed app to the manifest.
+func AddApplication(m AppManifest, app models.Application) error {
+	m.Memory(app.Name, app.Memory)
+	m.Instances(app.Name, app.InstanceCount)
+	m.Stack(app.Name, app.Stack.Name)
+
+	if len(app.AppPorts) > 0 {
+		m.AppPorts(app.Name, app.AppPorts)
+	}
+
+	if app.Command != "" {
+		m.StartCommand(app.Name, app.Command)
+	}
+
+	if app.BuildpackURL != "" {
+		m.BuildpackURL(app.Name, app.BuildpackURL)
+	}
+
+	if len(app.Services) > 0 {
+		for _, service := range app.Services {
+			m.Service(app.Name, service.Name)
+		}
+	}
+
+	if app.HealthCheckTimeout > 0 {
+		m.HealthCheckTimeout(app.Name, app.HealthCheckTimeout)
+	}
+
+	if len(app.EnvironmentVars) > 0 {
+		sorted := sortEnvVar(app.EnvironmentVars)
+		for _, envVarKey := range sorted {
+			switch app.EnvironmentVars[envVarKey].(type) {
+			default:
+				return errors.New(T("Failed to create manifest, unable to parse environment variable: ") + envVarKey)
+			case float64:
+				//json.Unmarshal turn all numbers to float64
+				value := int(app.EnvironmentVars[envVarKey].(float64))
+				m.EnvironmentVars(app.Name, envVarKey, fmt.Sprintf("%d", value))
+			case bool:
+				m.EnvironmentVars(app.Name, envVarKey, fmt.Sprintf("%t", app.EnvironmentVars[envVarKey].(bool)))
+			case string:
+				m.EnvironmentVars(app.Name, envVarKey, app.EnvironmentVars[envVarKey].(string))
+			}
+		}
+	}
+
+	if len(app.Routes) > 0 {
+		for i := 0; i < len(app.Routes); i++ {
+			m.Domain(app.Name, app.Routes[i].Host, app.Routes[i].Domain.Name)
+		}
+	}
+
+	if app.DiskQuota != 0 {
+		m.DiskQuota(app.Name, app.DiskQuota)
+	}
+
+	return nil
+}
+
+func sortEnvVar(vars map[string]interface{}) []string {
+	var varsAry []string
+	for k := range vars {
+		varsAry = append(varsAry, k)
+	}
+	sort.Strings(varsAry)
+
+	return varsAry
+}
+
 func generateAppMap(app models.Application) (ManifestApplication, error) {
 	if app.Stack == nil {
 		return ManifestApplication{}, errors.New(T("required attribute 'stack' missing"))
@@ -201,6 +201,7 @@ func mapToAppParams(basePath string, yamlMap generic.Map) (models.AppParams, err
 	appParams.EnvironmentVars = envVarOrEmptyMap(yamlMap, &errs)
 	appParams.HealthCheckType = stringVal(yamlMap, "health-check-type", &errs)
 	appParams.AppPorts = intSliceVal(yamlMap, "app-ports", &errs)
+	appParams.Processes = processesVal(yamlMap, &errs)
 
 	if appParams.Path != nil {
 		path := *appParams.Path
@@ -424,6 +425,60 @@ func intSliceVal(yamlMap generic.Map, key string, errs *[]error) *[]int {
 	return &intSlice
 }
 
+func processesVal(yamlMap generic.Map, errs *[]error) *[]models.ProcessParams {
+	key := "processes"
+	if !yamlMap.Has(key) {
+		return nil
+	}
+
+	processMaps, ok := yamlMap.Get(key).([]interface{})
+	if !ok {
+		*errs = append(*errs, errors.New(T("Expected {{.PropertyName}} to be a list", map[string]interface{}{"PropertyName": key})))
+		return nil
+	}
+
+	seenTypes := map[string]bool{}
+	processes := []models.ProcessParams{}
+	for _, processData := range processMaps {
+		if !generic.IsMappable(processData) {
+			*errs = append(*errs, errors.New(T("Expected process to be a list of key/value pairs\nError occurred in manifest near:\n'{{.YmlSnippet}}'",
+				map[string]interface{}{"YmlSnippet": processData})))
+			continue
+		}
+
+		processMap := generic.NewMap(processData)
+		processType := stringVal(processMap, "type", errs)
+		if processType == nil || *processType == "" {
+			*errs = append(*errs, errors.New(T("Each process must have a type")))
+			continue
+		}
+
+		if seenTypes[*processType] {
+			*errs = append(*errs, errors.New(T("Process type '{{.ProcessType}}' is defined more than once", map[string]interface{}{"ProcessType": *processType})))
+			continue
+		}
+		seenTypes[*processType] = true
+
+		process := models.ProcessParams{
+			Type:            *processType,
+			Command:         stringValOrDefault(processMap, "command", errs),
+			InstanceCount:   intVal(processMap, "instances", errs),
+			Memory:          bytesVal(processMap, "memory", errs),
+			DiskQuota:       bytesVal(processMap, "disk_quota", errs),
+			HealthCheckType: stringVal(processMap, "health-check-type", errs),
+		}
+
+		if process.InstanceCount != nil && *process.InstanceCount < 0 {
+			*errs = append(*errs, errors.New(T("Invalid instance count for process '{{.ProcessType}}': {{.InstanceCount}}",
+				map[string]interface{}{"ProcessType": *processType, "InstanceCount": *process.InstanceCount})))
+		}
+
+		processes = append(processes, process)
+	}
+
+	return &processes
+}
+
 func envVarOrEmptyMap(yamlMap generic.Map, errs *[]error) *map[string]interface{} {
 	key := "env"
 	switch envVars := yamlMap.Get(key).(type) {
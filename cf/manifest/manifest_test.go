@@ -507,4 +507,108 @@ var _ = Describe("Manifests", func() {
 			Expect(*app[0].ServicesToBind).To(Equal([]string{"service-1", "service-2"}))
 		})
 	})
+
+	Describe("parsing processes", func() {
+		It("parses each process type with its own settings", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"name": "my-app",
+						"processes": []interface{}{
+							map[interface{}]interface{}{
+								"type":              "web",
+								"instances":         2,
+								"health-check-type": "port",
+							},
+							map[interface{}]interface{}{
+								"type":       "worker",
+								"command":    "bundle exec rake work",
+								"instances":  3,
+								"memory":     "512M",
+								"disk_quota": "1G",
+							},
+						},
+					},
+				},
+			}))
+
+			apps, err := m.Applications()
+			Expect(err).NotTo(HaveOccurred())
+
+			processes := *apps[0].Processes
+			Expect(processes).To(HaveLen(2))
+
+			Expect(processes[0].Type).To(Equal("web"))
+			Expect(*processes[0].InstanceCount).To(Equal(2))
+			Expect(*processes[0].HealthCheckType).To(Equal("port"))
+			Expect(processes[0].Command).To(BeNil())
+			Expect(processes[0].Memory).To(BeNil())
+
+			Expect(processes[1].Type).To(Equal("worker"))
+			Expect(*processes[1].Command).To(Equal("bundle exec rake work"))
+			Expect(*processes[1].InstanceCount).To(Equal(3))
+			Expect(*processes[1].Memory).To(Equal(int64(512)))
+			Expect(*processes[1].DiskQuota).To(Equal(int64(1024)))
+		})
+
+		It("handles omitted field", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{},
+				},
+			}))
+
+			apps, err := m.Applications()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(apps[0].Processes).To(BeNil())
+		})
+
+		It("returns an error when a process has no type", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"processes": []interface{}{
+							map[interface{}]interface{}{"instances": 1},
+						},
+					},
+				},
+			}))
+
+			_, err := m.Applications()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Each process must have a type"))
+		})
+
+		It("returns an error when a process type is defined twice", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"processes": []interface{}{
+							map[interface{}]interface{}{"type": "worker"},
+							map[interface{}]interface{}{"type": "worker"},
+						},
+					},
+				},
+			}))
+
+			_, err := m.Applications()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Process type 'worker' is defined more than once"))
+		})
+
+		It("returns an error when processes is not a list", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"processes": "worker",
+					},
+				},
+			}))
+
+			_, err := m.Applications()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected processes to be a list"))
+		})
+	})
 })
@@ -9,4 +9,7 @@ type EventFields struct {
 	Description string
 	Actor       string
 	ActorName   string
+	Actee       string
+	ActeeType   string
+	ActeeName   string
 }
@@ -102,6 +102,7 @@ type AppParams struct {
 	State              *string
 	PackageUpdatedAt   *time.Time
 	AppPorts           *[]int
+	Processes          *[]ProcessParams
 }
 
 func (app *AppParams) Merge(other *AppParams) {
@@ -153,6 +154,9 @@ func (app *AppParams) Merge(other *AppParams) {
 	if other.Path != nil {
 		app.Path = other.Path
 	}
+	if other.Processes != nil {
+		app.Processes = other.Processes
+	}
 	if other.RoutePath != nil {
 		app.RoutePath = other.RoutePath
 	}
@@ -0,0 +1,20 @@
+package models
+
+const WebProcessType = "web"
+
+type ProcessParams struct {
+	Type            string
+	Command         *string
+	InstanceCount   *int
+	Memory          *int64
+	DiskQuota       *int64
+	HealthCheckType *string
+}
+
+func (params ProcessParams) HasScale() bool {
+	return params.InstanceCount != nil || params.Memory != nil || params.DiskQuota != nil
+}
+
+func (params ProcessParams) HasUpdate() bool {
+	return params.Command != nil || params.HealthCheckType != nil
+}
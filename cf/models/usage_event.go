@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type AppUsageEvent struct {
+	GUID                  string
+	CreatedAt             time.Time
+	State                 string
+	AppGUID               string
+	AppName               string
+	SpaceGUID             string
+	SpaceName             string
+	OrgGUID               string
+	InstanceCount         int
+	MemoryInMBPerInstance int64
+}
+
+type ServiceUsageEvent struct {
+	GUID                string
+	CreatedAt           time.Time
+	State               string
+	ServiceInstanceGUID string
+	ServiceInstanceName string
+	ServiceInstanceType string
+	SpaceGUID           string
+	SpaceName           string
+	OrgGUID             string
+}
@@ -40,10 +40,25 @@ func (p RequestDumper) DumpRequest(req *http.Request) {
 }
 
 func (p RequestDumper) DumpResponse(res *http.Response) {
-	dumpedResponse, err := httputil.DumpResponse(res, true)
+	shouldDisplayBody := !isBinaryContentType(res.Header.Get("Content-Type"))
+	dumpedResponse, err := httputil.DumpResponse(res, shouldDisplayBody)
 	if err != nil {
 		p.printer.Printf(T("Error dumping response\n{{.Err}}\n", map[string]interface{}{"Err": err}))
 	} else {
 		p.printer.Printf("\n%s [%s]\n%s\n", terminal.HeaderColor(T("RESPONSE:")), time.Now().Format(time.RFC3339), trace.Sanitize(string(dumpedResponse)))
+		if !shouldDisplayBody {
+			p.printer.Println(T("[BINARY CONTENT HIDDEN]"))
+		}
+	}
+}
+
+// isBinaryContentType tells whether a body is an archive or an opaque
+// stream, such as a droplet, which is neither read into memory nor printed.
+func isBinaryContentType(contentType string) bool {
+	for _, binaryType := range []string{"octet-stream", "zip", "x-tar", "x-compressed"} {
+		if strings.Contains(contentType, binaryType) {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,94 @@
+package net_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/trace/tracefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	return n, err
+}
+
+func (r *countingReader) Close() error {
+	return nil
+}
+
+var _ = Describe("RequestDumper", func() {
+	var (
+		printer *tracefakes.FakePrinter
+		dumper  RequestDumper
+	)
+
+	BeforeEach(func() {
+		printer = new(tracefakes.FakePrinter)
+		dumper = NewRequestDumper(printer)
+	})
+
+	printed := func() string {
+		output := ""
+		for i := 0; i < printer.PrintfCallCount(); i++ {
+			format, args := printer.PrintfArgsForCall(i)
+			output += fmt.Sprintf(format, args...)
+		}
+		for i := 0; i < printer.PrintlnCallCount(); i++ {
+			output += fmt.Sprintln(printer.PrintlnArgsForCall(i)...)
+		}
+		return output
+	}
+
+	Describe("DumpResponse", func() {
+		It("prints the body of text responses and leaves it readable", func() {
+			res := &http.Response{
+				StatusCode: http.StatusOK,
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"name":"my-app"}`)),
+			}
+
+			dumper.DumpResponse(res)
+
+			Expect(printed()).To(ContainSubstring(`{"name":"my-app"}`))
+			body, err := ioutil.ReadAll(res.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(Equal(`{"name":"my-app"}`))
+		})
+
+		It("neither reads nor prints the body of binary responses", func() {
+			body := &countingReader{reader: io.LimitReader(strings.NewReader(strings.Repeat("droplet", 1<<20)), 7<<20)}
+			res := &http.Response{
+				StatusCode: http.StatusOK,
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     http.Header{"Content-Type": []string{"application/octet-stream"}},
+				Body:       body,
+			}
+
+			dumper.DumpResponse(res)
+
+			Expect(body.read).To(BeZero())
+			Expect(printed()).NotTo(ContainSubstring("droplet"))
+			Expect(printed()).To(ContainSubstring("[BINARY CONTENT HIDDEN]"))
+
+			copied, err := io.Copy(ioutil.Discard, res.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied).To(Equal(int64(7 << 20)))
+		})
+	})
+})
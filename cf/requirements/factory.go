@@ -23,6 +23,7 @@ type Factory interface {
 	NewOrganizationRequirement(name string) OrganizationRequirement
 	NewDomainRequirement(name string) DomainRequirement
 	NewUserRequirement(username string, wantGUID bool) UserRequirement
+	NewUserRequirementWithOrigin(username string, origin string) UserRequirement
 	NewBuildpackRequirement(buildpack string) BuildpackRequirement
 	NewAPIEndpointRequirement() Requirement
 	NewMinAPIVersionRequirement(commandName string, requiredVersion semver.Version) Requirement
@@ -126,6 +127,14 @@ func (f apiRequirementFactory) NewUserRequirement(username string, wantGUID bool
 	)
 }
 
+func (f apiRequirementFactory) NewUserRequirementWithOrigin(username string, origin string) UserRequirement {
+	return NewUserRequirementWithOrigin(
+		username,
+		origin,
+		f.repoLocator.GetUserRepository(),
+	)
+}
+
 func (f apiRequirementFactory) NewBuildpackRequirement(buildpack string) BuildpackRequirement {
 	return NewBuildpackRequirement(
 		buildpack,
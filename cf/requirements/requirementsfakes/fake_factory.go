@@ -98,6 +98,15 @@ type FakeFactory struct {
 	newUserRequirementReturns struct {
 		result1 requirements.UserRequirement
 	}
+	NewUserRequirementWithOriginStub        func(username string, origin string) requirements.UserRequirement
+	newUserRequirementWithOriginMutex       sync.RWMutex
+	newUserRequirementWithOriginArgsForCall []struct {
+		username string
+		origin   string
+	}
+	newUserRequirementWithOriginReturns struct {
+		result1 requirements.UserRequirement
+	}
 	NewBuildpackRequirementStub        func(buildpack string) requirements.BuildpackRequirement
 	newBuildpackRequirementMutex       sync.RWMutex
 	newBuildpackRequirementArgsForCall []struct {
@@ -495,6 +504,39 @@ func (fake *FakeFactory) NewUserRequirementReturns(result1 requirements.UserRequ
 	}{result1}
 }
 
+func (fake *FakeFactory) NewUserRequirementWithOrigin(username string, origin string) requirements.UserRequirement {
+	fake.newUserRequirementWithOriginMutex.Lock()
+	fake.newUserRequirementWithOriginArgsForCall = append(fake.newUserRequirementWithOriginArgsForCall, struct {
+		username string
+		origin   string
+	}{username, origin})
+	fake.newUserRequirementWithOriginMutex.Unlock()
+	if fake.NewUserRequirementWithOriginStub != nil {
+		return fake.NewUserRequirementWithOriginStub(username, origin)
+	} else {
+		return fake.newUserRequirementWithOriginReturns.result1
+	}
+}
+
+func (fake *FakeFactory) NewUserRequirementWithOriginCallCount() int {
+	fake.newUserRequirementWithOriginMutex.RLock()
+	defer fake.newUserRequirementWithOriginMutex.RUnlock()
+	return len(fake.newUserRequirementWithOriginArgsForCall)
+}
+
+func (fake *FakeFactory) NewUserRequirementWithOriginArgsForCall(i int) (string, string) {
+	fake.newUserRequirementWithOriginMutex.RLock()
+	defer fake.newUserRequirementWithOriginMutex.RUnlock()
+	return fake.newUserRequirementWithOriginArgsForCall[i].username, fake.newUserRequirementWithOriginArgsForCall[i].origin
+}
+
+func (fake *FakeFactory) NewUserRequirementWithOriginReturns(result1 requirements.UserRequirement) {
+	fake.NewUserRequirementWithOriginStub = nil
+	fake.newUserRequirementWithOriginReturns = struct {
+		result1 requirements.UserRequirement
+	}{result1}
+}
+
 func (fake *FakeFactory) NewBuildpackRequirement(buildpack string) requirements.BuildpackRequirement {
 	fake.newBuildpackRequirementMutex.Lock()
 	fake.newBuildpackRequirementArgsForCall = append(fake.newBuildpackRequirementArgsForCall, struct {
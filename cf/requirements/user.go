@@ -14,6 +14,7 @@ type UserRequirement interface {
 
 type userAPIRequirement struct {
 	username string
+	origin   string
 	userRepo api.UserRepository
 	wantGUID bool
 
@@ -33,8 +34,27 @@ func NewUserRequirement(
 	return req
 }
 
+// NewUserRequirementWithOrigin looks up the user of the given origin, for
+// usernames that exist in more than one identity provider.
+func NewUserRequirementWithOrigin(
+	username string,
+	origin string,
+	userRepo api.UserRepository,
+) *userAPIRequirement {
+	req := NewUserRequirement(username, userRepo, true)
+	req.origin = origin
+
+	return req
+}
+
 func (req *userAPIRequirement) Execute() error {
-	if req.wantGUID {
+	if req.origin != "" {
+		var err error
+		req.user, err = req.userRepo.FindByUsernameAndOrigin(req.username, req.origin)
+		if err != nil {
+			return err
+		}
+	} else if req.wantGUID {
 		var err error
 		req.user, err = req.userRepo.FindByUsername(req.username)
 		if err != nil {
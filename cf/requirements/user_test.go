@@ -83,5 +83,23 @@ var _ = Describe("UserRequirement", func() {
 				Expect(userRequirement.GetUser()).To(Equal(expectedUser))
 			})
 		})
+
+		Context("when an origin is given", func() {
+			BeforeEach(func() {
+				userRequirement = requirements.NewUserRequirementWithOrigin("the-username", "ldap", userRepo)
+				userRepo.FindByUsernameAndOriginReturns(models.UserFields{Username: "the-username", GUID: "the-guid"}, nil)
+			})
+
+			It("finds the user of that origin", func() {
+				err := userRequirement.Execute()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(userRepo.FindByUsernameCallCount()).To(Equal(0))
+
+				username, origin := userRepo.FindByUsernameAndOriginArgsForCall(0)
+				Expect(username).To(Equal("the-username"))
+				Expect(origin).To(Equal("ldap"))
+				Expect(userRequirement.GetUser()).To(Equal(models.UserFields{Username: "the-username", GUID: "the-guid"}))
+			})
+		})
 	})
 })
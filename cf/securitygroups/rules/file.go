@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"gopkg.in/yaml.v2"
+)
+
+// ReadFile reads the rules of a security group from a file holding a single
+// array of rules, in YAML when the file name ends in .yml or .yaml and in JSON
+// otherwise. The problems found in the rules name the line on which the rule
+// starts. They fail a YAML file, while the rules of a JSON file are returned
+// unchanged for CC to validate, with the problems as warnings.
+func ReadFile(path string) (rules []map[string]interface{}, warnings []string, err error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rules = []map[string]interface{}{}
+	var lines []int
+	var strict bool
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		if err = yaml.Unmarshal(contents, &rules); err != nil {
+			return nil, nil, errors.New(T("Incorrect yaml format: file: {{.File}}\n{{.Err}}",
+				map[string]interface{}{"File": path, "Err": err.Error()}))
+		}
+		lines = yamlItemLines(string(contents))
+		strict = true
+	default:
+		if err = json.Unmarshal(contents, &rules); err != nil {
+			return nil, nil, errors.New(T(`Incorrect json format: file: {{.JSONFile}}
+
+Valid json file example:
+[
+  {
+    "protocol": "tcp",
+    "destination": "10.244.1.18",
+    "ports": "3306"
+  }
+]`, map[string]interface{}{"JSONFile": path}))
+		}
+		lines = jsonItemLines(string(contents))
+	}
+
+	problems := []string{}
+	for index, rule := range rules {
+		if err := Validate(rule); err != nil {
+			params := map[string]interface{}{"Index": index + 1, "Err": err.Error()}
+			if index < len(lines) {
+				params["Line"] = lines[index]
+				problems = append(problems, T("line {{.Line}}: rule #{{.Index}}: {{.Err}}", params))
+			} else {
+				problems = append(problems, T("rule #{{.Index}}: {{.Err}}", params))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return rules, nil, nil
+	}
+
+	params := map[string]interface{}{"File": path, "Problems": "   " + strings.Join(problems, "\n   ")}
+	if strict {
+		return nil, nil, errors.New(T("The rules in {{.File}} are invalid:\n{{.Problems}}", params))
+	}
+	return rules, []string{T("The rules in {{.File}} might be invalid:\n{{.Problems}}", params)}, nil
+}
+
+// jsonItemLines returns the line on which each object of the top level
+// array starts.
+func jsonItemLines(contents string) []int {
+	lines := []int{}
+	line, depth := 1, 0
+	inString, escaped := false, false
+
+	for _, char := range contents {
+		switch {
+		case char == '\n':
+			line++
+		case inString && escaped:
+			escaped = false
+		case inString && char == '\\':
+			escaped = true
+		case char == '"':
+			inString = !inString
+		case inString:
+		case char == '[' || char == '{':
+			if depth == 1 && char == '{' {
+				lines = append(lines, line)
+			}
+			depth++
+		case char == ']' || char == '}':
+			depth--
+		}
+	}
+
+	return lines
+}
+
+// yamlItemLines returns the line on which each item of the top level
+// sequence starts, which is the least indented line starting with a dash.
+func yamlItemLines(contents string) []int {
+	lines := []int{}
+	itemIndent := -1
+
+	for number, line := range strings.Split(contents, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed != "-" && !strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+
+		indent := len(line) - len(trimmed)
+		if itemIndent == -1 || indent < itemIndent {
+			itemIndent = indent
+			lines = []int{}
+		}
+		if indent == itemIndent {
+			lines = append(lines, number+1)
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,59 @@
+package rules_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/securitygroups/rules"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReadFile", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "security-group-rules")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(name string, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		return path
+	}
+
+	It("fails on the invalid rules of a YAML file", func() {
+		path := write("rules.yml", `
+- protocol: udp
+  destination: 10.0.0.2
+  port: "53"
+`)
+
+		_, _, err := rules.ReadFile(path)
+		Expect(err).To(MatchError(ContainSubstring("line 2: rule #1: unknown field port")))
+	})
+
+	It("returns the rules of a JSON file unchanged and warns about the invalid ones", func() {
+		path := write("rules.json", `[
+  {"protocol": "udp", "destination": "10.0.0.2", "ports": "53"},
+  {"protocol": "udp", "destination": "10.0.0.2", "port": "53"}
+]`)
+
+		groupRules, warnings, err := rules.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groupRules).To(Equal([]map[string]interface{}{
+			{"protocol": "udp", "destination": "10.0.0.2", "ports": "53"},
+			{"protocol": "udp", "destination": "10.0.0.2", "port": "53"},
+		}))
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("line 3: rule #2: unknown field port"))
+	})
+})
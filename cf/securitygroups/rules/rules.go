@@ -0,0 +1,364 @@
+// Package rules parses the rules of security groups and tells which traffic
+// they allow.
+package rules
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+// AnyICMP is the type or code of icmp rules that allow every type or code.
+const AnyICMP = -1
+
+// Rule is a single rule of a security group, in the form that CC accepts and
+// returns it.
+type Rule struct {
+	Protocol    string
+	Destination string
+	Ports       string
+	Type        int
+	Code        int
+
+	addresses []valueRange
+	ports     []valueRange
+}
+
+var knownFields = map[string]bool{
+	"protocol":    true,
+	"destination": true,
+	"ports":       true,
+	"type":        true,
+	"code":        true,
+	"log":         true,
+	"description": true,
+}
+
+// valueRange is an inclusive range of IPv4 addresses or of ports.
+type valueRange struct {
+	first, last uint32
+}
+
+// Connection is the traffic that an app instance tries to send.
+type Connection struct {
+	Protocol string
+	IP       net.IP
+	Port     int
+	Type     int
+	Code     int
+}
+
+func (conn Connection) String() string {
+	if conn.Protocol == "icmp" {
+		return fmt.Sprintf("%s icmp type %d code %d", conn.IP, conn.Type, conn.Code)
+	}
+	return fmt.Sprintf("%s:%d/%s", conn.IP, conn.Port, conn.Protocol)
+}
+
+// Parse reads a rule as it is decoded from the JSON of a security group.
+func Parse(rule map[string]interface{}) (Rule, error) {
+	parsed := Rule{Type: AnyICMP, Code: AnyICMP}
+
+	var ok bool
+	if parsed.Protocol, ok = rule["protocol"].(string); !ok {
+		return parsed, errors.New(T("the rule has no protocol"))
+	}
+	if parsed.Destination, ok = rule["destination"].(string); !ok {
+		return parsed, errors.New(T("the rule has no destination"))
+	}
+
+	var err error
+	switch parsed.Protocol {
+	case "tcp", "udp":
+		if parsed.Ports, ok = rule["ports"].(string); !ok {
+			return parsed, errors.New(T("the {{.Protocol}} rule has no ports", map[string]interface{}{"Protocol": parsed.Protocol}))
+		}
+		if parsed.ports, err = parsePorts(parsed.Ports); err != nil {
+			return parsed, err
+		}
+	case "icmp":
+		if parsed.Type, err = icmpField(rule, "type"); err != nil {
+			return parsed, err
+		}
+		if parsed.Code, err = icmpField(rule, "code"); err != nil {
+			return parsed, err
+		}
+	case "all":
+	default:
+		return parsed, errors.New(T("unknown protocol {{.Protocol}}", map[string]interface{}{"Protocol": parsed.Protocol}))
+	}
+
+	if parsed.addresses, err = parseDestination(parsed.Destination); err != nil {
+		return parsed, err
+	}
+
+	return parsed, nil
+}
+
+// Validate is stricter than Parse: it rejects the fields that CC does not know
+// or would ignore.
+func Validate(rule map[string]interface{}) error {
+	for field := range rule {
+		if !knownFields[field] {
+			return errors.New(T("unknown field {{.Field}}", map[string]interface{}{"Field": field}))
+		}
+	}
+
+	parsed, err := Parse(rule)
+	if err != nil {
+		return err
+	}
+
+	_, hasPorts := rule["ports"]
+	if hasPorts && parsed.Protocol != "tcp" && parsed.Protocol != "udp" {
+		return errors.New(T("ports are only allowed in tcp and udp rules"))
+	}
+
+	_, hasType := rule["type"]
+	_, hasCode := rule["code"]
+	if parsed.Protocol == "icmp" && (!hasType || !hasCode) {
+		return errors.New(T("the icmp rule needs a type and a code"))
+	}
+	if parsed.Protocol != "icmp" && (hasType || hasCode) {
+		return errors.New(T("type and code are only allowed in icmp rules"))
+	}
+
+	if log, found := rule["log"]; found {
+		if _, ok := log.(bool); !ok {
+			return errors.New(T("log must be true or false"))
+		}
+	}
+
+	if description, found := rule["description"]; found {
+		if _, ok := description.(string); !ok {
+			return errors.New(T("description must be a string"))
+		}
+	}
+
+	return nil
+}
+
+func icmpField(rule map[string]interface{}, name string) (int, error) {
+	value, found := rule[name]
+	if !found {
+		return AnyICMP, nil
+	}
+
+	switch value := value.(type) {
+	case float64:
+		return int(value), nil
+	case int:
+		return value, nil
+	default:
+		return 0, errors.New(T("the icmp {{.Field}} must be a number", map[string]interface{}{"Field": name}))
+	}
+}
+
+func (rule Rule) String() string {
+	switch rule.Protocol {
+	case "tcp", "udp":
+		return fmt.Sprintf("%s %s:%s", rule.Protocol, rule.Destination, rule.Ports)
+	case "icmp":
+		return fmt.Sprintf("icmp %s type %d code %d", rule.Destination, rule.Type, rule.Code)
+	default:
+		return fmt.Sprintf("%s %s", rule.Protocol, rule.Destination)
+	}
+}
+
+// Allows tells whether the rule lets the connection through.
+func (rule Rule) Allows(conn Connection) bool {
+	if rule.Protocol != "all" && rule.Protocol != conn.Protocol {
+		return false
+	}
+
+	address := conn.IP.To4()
+	if address == nil || !rangesContain(rule.addresses, valueRange{ipToUint(address), ipToUint(address)}) {
+		return false
+	}
+
+	switch rule.Protocol {
+	case "tcp", "udp":
+		return rangesContain(rule.ports, valueRange{uint32(conn.Port), uint32(conn.Port)})
+	case "icmp":
+		return icmpCovers(rule.Type, conn.Type) && icmpCovers(rule.Code, conn.Code)
+	default:
+		return true
+	}
+}
+
+// Covers tells whether the rule allows all the traffic that the other rule
+// allows.
+func (rule Rule) Covers(other Rule) bool {
+	if rule.Protocol != "all" && rule.Protocol != other.Protocol {
+		return false
+	}
+
+	for _, addresses := range other.addresses {
+		if !rangesContain(rule.addresses, addresses) {
+			return false
+		}
+	}
+
+	switch rule.Protocol {
+	case "tcp", "udp":
+		for _, ports := range other.ports {
+			if !rangesContain(rule.ports, ports) {
+				return false
+			}
+		}
+		return true
+	case "icmp":
+		return icmpCovers(rule.Type, other.Type) && icmpCovers(rule.Code, other.Code)
+	default:
+		return true
+	}
+}
+
+// IsOverlyBroad tells whether the rule opens all ports to every address.
+func (rule Rule) IsOverlyBroad() bool {
+	if !rangesContain(rule.addresses, valueRange{0, math.MaxUint32}) {
+		return false
+	}
+
+	switch rule.Protocol {
+	case "all":
+		return true
+	case "tcp", "udp":
+		return rangesContain(rule.ports, valueRange{1, 65535})
+	default:
+		return false
+	}
+}
+
+func icmpCovers(ruleValue int, value int) bool {
+	return ruleValue == AnyICMP || ruleValue == value
+}
+
+// rangesContain tells whether the union of the ranges contains the whole of
+// the given range.
+func rangesContain(ranges []valueRange, contained valueRange) bool {
+	for _, merged := range mergeRanges(ranges) {
+		if merged.first <= contained.first && contained.last <= merged.last {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeRanges(ranges []valueRange) []valueRange {
+	sorted := make([]valueRange, len(ranges))
+	copy(sorted, ranges)
+	sort.Sort(valueRangesByFirst(sorted))
+
+	merged := []valueRange{}
+	for _, current := range sorted {
+		last := len(merged) - 1
+		if last >= 0 && (merged[last].last == math.MaxUint32 || current.first <= merged[last].last+1) {
+			if current.last > merged[last].last {
+				merged[last].last = current.last
+			}
+			continue
+		}
+		merged = append(merged, current)
+	}
+	return merged
+}
+
+type valueRangesByFirst []valueRange
+
+func (ranges valueRangesByFirst) Len() int           { return len(ranges) }
+func (ranges valueRangesByFirst) Swap(i, j int)      { ranges[i], ranges[j] = ranges[j], ranges[i] }
+func (ranges valueRangesByFirst) Less(i, j int) bool { return ranges[i].first < ranges[j].first }
+
+// parseDestination accepts the destinations CC does: a single address, a
+// CIDR, a range of addresses, or a comma separated list of those.
+func parseDestination(destination string) ([]valueRange, error) {
+	ranges := []valueRange{}
+
+	for _, part := range strings.Split(destination, ",") {
+		part = strings.TrimSpace(part)
+
+		switch {
+		case strings.Contains(part, "/"):
+			_, network, err := net.ParseCIDR(part)
+			if err != nil || network.IP.To4() == nil {
+				return nil, invalidDestinationError(part)
+			}
+			first := ipToUint(network.IP.To4())
+			ones, _ := network.Mask.Size()
+			ranges = append(ranges, valueRange{first, first | uint32(math.MaxUint32>>uint(ones))})
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			first, last := ParseIPv4(bounds[0]), ParseIPv4(bounds[1])
+			if first == nil || last == nil || ipToUint(first) > ipToUint(last) {
+				return nil, invalidDestinationError(part)
+			}
+			ranges = append(ranges, valueRange{ipToUint(first), ipToUint(last)})
+		default:
+			address := ParseIPv4(part)
+			if address == nil {
+				return nil, invalidDestinationError(part)
+			}
+			ranges = append(ranges, valueRange{ipToUint(address), ipToUint(address)})
+		}
+	}
+
+	return ranges, nil
+}
+
+// ParseIPv4 returns nil when the address is not an IPv4 address.
+func ParseIPv4(address string) net.IP {
+	return net.ParseIP(strings.TrimSpace(address)).To4()
+}
+
+func ipToUint(ip net.IP) uint32 {
+	return binary.BigEndian.Uint32(ip.To4())
+}
+
+func invalidDestinationError(destination string) error {
+	return errors.New(T("invalid destination {{.Destination}}", map[string]interface{}{"Destination": destination}))
+}
+
+// parsePorts accepts a single port, a range of ports, or a comma separated
+// list of those.
+func parsePorts(ports string) ([]valueRange, error) {
+	ranges := []valueRange{}
+
+	for _, part := range strings.Split(ports, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		if len(bounds) == 1 {
+			bounds = append(bounds, bounds[0])
+		}
+
+		first, err := parsePort(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last, err := parsePort(bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		if first > last {
+			return nil, errors.New(T("invalid port range {{.Ports}}", map[string]interface{}{"Ports": part}))
+		}
+
+		ranges = append(ranges, valueRange{uint32(first), uint32(last)})
+	}
+
+	return ranges, nil
+}
+
+func parsePort(port string) (int, error) {
+	parsed, err := strconv.Atoi(strings.TrimSpace(port))
+	if err != nil || parsed < 1 || parsed > 65535 {
+		return 0, errors.New(T("invalid port {{.Port}}", map[string]interface{}{"Port": port}))
+	}
+	return parsed, nil
+}
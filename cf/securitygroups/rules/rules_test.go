@@ -0,0 +1,79 @@
+package rules_test
+
+import (
+	"net"
+
+	"github.com/cloudfoundry/cli/cf/securitygroups/rules"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rules", func() {
+	parse := func(rule map[string]interface{}) rules.Rule {
+		parsed, err := rules.Parse(rule)
+		Expect(err).NotTo(HaveOccurred())
+		return parsed
+	}
+
+	Describe("Parse", func() {
+		It("reads tcp and udp rules", func() {
+			rule := parse(map[string]interface{}{"protocol": "tcp", "destination": "10.0.0.0/24", "ports": "80,443"})
+			Expect(rule.String()).To(Equal("tcp 10.0.0.0/24:80,443"))
+		})
+
+		It("treats missing icmp type and code as any", func() {
+			rule := parse(map[string]interface{}{"protocol": "icmp", "destination": "10.0.0.1"})
+			Expect(rule.Type).To(Equal(rules.AnyICMP))
+			Expect(rule.Code).To(Equal(rules.AnyICMP))
+		})
+
+		It("fails on invalid destinations and ports", func() {
+			_, err := rules.Parse(map[string]interface{}{"protocol": "all", "destination": "10.0.0.5-10.0.0.1"})
+			Expect(err).To(MatchError("invalid destination 10.0.0.5-10.0.0.1"))
+
+			_, err = rules.Parse(map[string]interface{}{"protocol": "udp", "destination": "10.0.0.1", "ports": "70000"})
+			Expect(err).To(MatchError("invalid port 70000"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("rejects fields that CC does not know", func() {
+			err := rules.Validate(map[string]interface{}{"protocol": "tcp", "destination": "10.0.0.1", "port": "80"})
+			Expect(err).To(MatchError("unknown field port"))
+		})
+
+		It("rejects ports in icmp rules", func() {
+			err := rules.Validate(map[string]interface{}{"protocol": "icmp", "destination": "10.0.0.1", "ports": "80", "type": 0, "code": 0})
+			Expect(err).To(MatchError("ports are only allowed in tcp and udp rules"))
+		})
+	})
+
+	Describe("Allows", func() {
+		It("matches the protocol, address and port of the connection", func() {
+			rule := parse(map[string]interface{}{"protocol": "tcp", "destination": "10.0.0.0-10.0.0.9", "ports": "8000-8080"})
+
+			Expect(rule.Allows(rules.Connection{Protocol: "tcp", IP: net.ParseIP("10.0.0.3"), Port: 8080})).To(BeTrue())
+			Expect(rule.Allows(rules.Connection{Protocol: "udp", IP: net.ParseIP("10.0.0.3"), Port: 8080})).To(BeFalse())
+			Expect(rule.Allows(rules.Connection{Protocol: "tcp", IP: net.ParseIP("10.0.0.10"), Port: 8080})).To(BeFalse())
+			Expect(rule.Allows(rules.Connection{Protocol: "tcp", IP: net.ParseIP("10.0.0.3"), Port: 8081})).To(BeFalse())
+		})
+	})
+
+	Describe("Covers", func() {
+		It("is true when the rule allows all the traffic of the other one", func() {
+			broad := parse(map[string]interface{}{"protocol": "all", "destination": "10.0.0.0/8"})
+			narrow := parse(map[string]interface{}{"protocol": "tcp", "destination": "10.1.0.0/16", "ports": "443"})
+
+			Expect(broad.Covers(narrow)).To(BeTrue())
+			Expect(narrow.Covers(broad)).To(BeFalse())
+		})
+	})
+
+	Describe("IsOverlyBroad", func() {
+		It("is true for rules that open every port of every address", func() {
+			Expect(parse(map[string]interface{}{"protocol": "tcp", "destination": "0.0.0.0/0", "ports": "1-65535"}).IsOverlyBroad()).To(BeTrue())
+			Expect(parse(map[string]interface{}{"protocol": "tcp", "destination": "0.0.0.0/0", "ports": "443"}).IsOverlyBroad()).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,135 @@
+// Package spacebundle reads and writes the description of a space that is
+// exported by export-space and recreated by import-space.
+package spacebundle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ManifestFile is the name of the app manifest in a bundle.
+	ManifestFile = "manifest.yml"
+	// SpaceFile is the name of the description of the services, routes,
+	// space quota and security groups in a bundle.
+	SpaceFile = "space.yml"
+)
+
+type Bundle struct {
+	Space                string                `yaml:"space,omitempty"`
+	SpaceQuota           *SpaceQuota           `yaml:"space_quota,omitempty"`
+	SecurityGroups       []string              `yaml:"security_groups,omitempty"`
+	Services             []Service             `yaml:"services,omitempty"`
+	UserProvidedServices []UserProvidedService `yaml:"user_provided_services,omitempty"`
+	Routes               []Route               `yaml:"routes,omitempty"`
+}
+
+type SpaceQuota struct {
+	Name                    string `yaml:"name"`
+	MemoryLimit             int64  `yaml:"memory_limit"`
+	InstanceMemoryLimit     int64  `yaml:"instance_memory_limit"`
+	RoutesLimit             int    `yaml:"total_routes"`
+	ServicesLimit           int    `yaml:"total_services"`
+	NonBasicServicesAllowed bool   `yaml:"non_basic_services_allowed"`
+	AppInstanceLimit        int    `yaml:"app_instance_limit"`
+	ReservedRoutePortsLimit string `yaml:"total_reserved_route_ports,omitempty"`
+}
+
+type Service struct {
+	Name       string                 `yaml:"name"`
+	Service    string                 `yaml:"service"`
+	Plan       string                 `yaml:"plan"`
+	Tags       []string               `yaml:"tags,omitempty"`
+	Parameters map[string]interface{} `yaml:"parameters,omitempty"`
+}
+
+type UserProvidedService struct {
+	Name            string                 `yaml:"name"`
+	Credentials     map[string]interface{} `yaml:"credentials,omitempty"`
+	SyslogDrainURL  string                 `yaml:"syslog_drain_url,omitempty"`
+	RouteServiceURL string                 `yaml:"route_service_url,omitempty"`
+	Tags            []string               `yaml:"tags,omitempty"`
+}
+
+type Route struct {
+	Host   string   `yaml:"host,omitempty"`
+	Domain string   `yaml:"domain"`
+	Path   string   `yaml:"path,omitempty"`
+	Port   int      `yaml:"port,omitempty"`
+	Apps   []string `yaml:"apps,omitempty"`
+}
+
+// Read reads the space description of the bundle in dir.
+func Read(dir string) (Bundle, error) {
+	var bundle Bundle
+
+	contents, err := ioutil.ReadFile(filepath.Join(dir, SpaceFile))
+	if err != nil {
+		return bundle, err
+	}
+
+	err = yaml.Unmarshal(contents, &bundle)
+	if err != nil {
+		return bundle, fmt.Errorf("%s: %s", SpaceFile, err.Error())
+	}
+
+	for i, service := range bundle.Services {
+		bundle.Services[i].Parameters = stringKeys(service.Parameters).(map[string]interface{})
+	}
+	for i, service := range bundle.UserProvidedServices {
+		bundle.UserProvidedServices[i].Credentials = stringKeys(service.Credentials).(map[string]interface{})
+	}
+
+	return bundle, nil
+}
+
+// Write writes the space description of the bundle to dir, creating the
+// directory if needed. The file is only readable by the current user since
+// it holds the credentials of the user-provided services.
+func Write(dir string, bundle Bundle) error {
+	contents, err := yaml.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, SpaceFile), contents, 0600)
+}
+
+// stringKeys converts the maps decoded from YAML, which have interface{}
+// keys, to maps with string keys so that they can be sent as JSON.
+func stringKeys(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			converted[fmt.Sprintf("%v", k)] = stringKeys(v)
+		}
+		return converted
+	case map[string]interface{}:
+		if value == nil {
+			return value
+		}
+		converted := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			converted[k] = stringKeys(v)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, v := range value {
+			converted[i] = stringKeys(v)
+		}
+		return converted
+	default:
+		return value
+	}
+}
@@ -0,0 +1,13 @@
+package spacebundle_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSpacebundle(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Spacebundle Suite")
+}
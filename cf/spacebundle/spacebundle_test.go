@@ -0,0 +1,70 @@
+package spacebundle_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/spacebundle"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("spacebundle", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "spacebundle")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("reads back the bundle that it wrote", func() {
+		bundle := spacebundle.Bundle{
+			Space:          "my-space",
+			SpaceQuota:     &spacebundle.SpaceQuota{Name: "my-quota", MemoryLimit: 1024, AppInstanceLimit: -1},
+			SecurityGroups: []string{"public-networks"},
+			Services: []spacebundle.Service{
+				{
+					Name:       "my-db",
+					Service:    "p-mysql",
+					Plan:       "100mb",
+					Parameters: map[string]interface{}{"backups": map[string]interface{}{"enabled": true}},
+				},
+			},
+			UserProvidedServices: []spacebundle.UserProvidedService{
+				{Name: "my-logs", SyslogDrainURL: "syslog://logs.example.com", Tags: []string{"logs"}},
+				{Name: "my-api", Credentials: map[string]interface{}{"uri": "https://api.example.com", "ports": []interface{}{map[string]interface{}{"http": 80}}}},
+			},
+			Routes: []spacebundle.Route{
+				{Host: "my-app", Domain: "example.com", Path: "/api", Apps: []string{"my-app"}},
+			},
+		}
+
+		subdir := filepath.Join(dir, "bundle")
+		Expect(spacebundle.Write(subdir, bundle)).To(Succeed())
+
+		info, err := os.Stat(filepath.Join(subdir, spacebundle.SpaceFile))
+		Expect(err).NotTo(HaveOccurred())
+		if os.PathSeparator == '/' {
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		}
+
+		read, err := spacebundle.Read(subdir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read).To(Equal(bundle))
+	})
+
+	It("returns an error when the description is not valid YAML", func() {
+		err := ioutil.WriteFile(filepath.Join(dir, spacebundle.SpaceFile), []byte("services: {"), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = spacebundle.Read(dir)
+		Expect(err).To(MatchError(ContainSubstring("space.yml")))
+	})
+})
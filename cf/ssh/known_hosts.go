@@ -0,0 +1,135 @@
+package sshCmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudfoundry/cli/cf/configuration/confighelpers"
+	"golang.org/x/crypto/ssh"
+)
+
+//go:generate counterfeiter . KnownHosts
+
+// KnownHosts pins the host key of each SSH endpoint the first time it is
+// seen, so that a later change can be detected independently of the
+// fingerprint advertised by the API.
+type KnownHosts interface {
+	Lookup(endpoint string) (key ssh.PublicKey, found bool, err error)
+	Pin(endpoint string, key ssh.PublicKey) error
+}
+
+// knownHostsFile keeps the pinned keys in the OpenSSH known_hosts format, so
+// that the file can also be given to ssh as UserKnownHostsFile.
+type knownHostsFile struct {
+	path string
+	lock sync.Mutex
+}
+
+func NewKnownHosts(path string) KnownHosts {
+	return &knownHostsFile{path: path}
+}
+
+// DefaultKnownHostsPath returns the location of the pinned host keys, next
+// to the CLI config in the CF home directory.
+func DefaultKnownHostsPath() (string, error) {
+	configPath, err := confighelpers.DefaultFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(configPath), "ssh_known_hosts"), nil
+}
+
+// KnownHostsName returns the name under which the key of the endpoint is
+// pinned, which is the host for port 22 and [host]:port otherwise.
+func KnownHostsName(endpoint string) string {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return endpoint
+	}
+
+	if port == "22" {
+		return host
+	}
+	return fmt.Sprintf("[%s]:%s", host, port)
+}
+
+func (k *knownHostsFile) Lookup(endpoint string) (ssh.PublicKey, bool, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	hosts, err := k.read()
+	if err != nil {
+		return nil, false, err
+	}
+
+	key, found := hosts[KnownHostsName(endpoint)]
+	return key, found, nil
+}
+
+func (k *knownHostsFile) Pin(endpoint string, key ssh.PublicKey) error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	hosts, err := k.read()
+	if err != nil {
+		return err
+	}
+
+	hosts[KnownHostsName(endpoint)] = key
+
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	contents := ""
+	for _, name := range names {
+		contents += fmt.Sprintf("%s %s\n", name, bytes.TrimSpace(ssh.MarshalAuthorizedKey(hosts[name])))
+	}
+
+	err = os.MkdirAll(filepath.Dir(k.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(k.path, []byte(contents), 0600)
+}
+
+func (k *knownHostsFile) read() (map[string]ssh.PublicKey, error) {
+	hosts := map[string]ssh.PublicKey{}
+
+	file, err := os.Open(k.path)
+	if os.IsNotExist(err) {
+		return hosts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(fields[1] + " " + fields[2]))
+		if err != nil {
+			continue
+		}
+		hosts[fields[0]] = key
+	}
+
+	return hosts, scanner.Err()
+}
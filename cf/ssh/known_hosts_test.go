@@ -0,0 +1,105 @@
+package sshCmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/ssh"
+	"golang.org/x/crypto/ssh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KnownHosts", func() {
+	var (
+		tmpDir     string
+		path       string
+		knownHosts sshCmd.KnownHosts
+	)
+
+	authorizedKey := func(key ssh.PublicKey) string {
+		return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "known-hosts")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(tmpDir, ".cf", "ssh_known_hosts")
+		knownHosts = sshCmd.NewKnownHosts(path)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("KnownHostsName", func() {
+		It("uses the host alone for port 22", func() {
+			Expect(sshCmd.KnownHostsName("ssh.example.com:22")).To(Equal("ssh.example.com"))
+		})
+
+		It("puts the host in brackets for other ports", func() {
+			Expect(sshCmd.KnownHostsName("ssh.example.com:2222")).To(Equal("[ssh.example.com]:2222"))
+		})
+	})
+
+	Describe("Lookup", func() {
+		Context("when nothing has been pinned yet", func() {
+			It("does not find the endpoint", func() {
+				_, found, err := knownHosts.Lookup("ssh.example.com:2222")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("when the file contains pinned endpoints", func() {
+			BeforeEach(func() {
+				Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+				contents := "[ssh.example.com]:2222 " + authorizedKey(TestHostKey.PublicKey()) + "\n" +
+					"broken line here\n" +
+					"[ssh.other.com]:2222 " + authorizedKey(TestPrivateKey.PublicKey()) + "\n"
+				Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+			})
+
+			It("returns the pinned key", func() {
+				key, found, err := knownHosts.Lookup("ssh.other.com:2222")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(key.Marshal()).To(Equal(TestPrivateKey.PublicKey().Marshal()))
+			})
+		})
+	})
+
+	Describe("Pin", func() {
+		It("creates the file and stores the key", func() {
+			err := knownHosts.Pin("ssh.example.com:2222", TestHostKey.PublicKey())
+			Expect(err).NotTo(HaveOccurred())
+
+			info, err := os.Stat(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			key, found, err := knownHosts.Lookup("ssh.example.com:2222")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(key.Marshal()).To(Equal(TestHostKey.PublicKey().Marshal()))
+		})
+
+		It("replaces the key of an endpoint and keeps the others, in the known_hosts format", func() {
+			Expect(knownHosts.Pin("ssh.example.com:2222", TestPrivateKey.PublicKey())).To(Succeed())
+			Expect(knownHosts.Pin("ssh.other.com:22", TestPrivateKey.PublicKey())).To(Succeed())
+			Expect(knownHosts.Pin("ssh.example.com:2222", TestHostKey.PublicKey())).To(Succeed())
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal(
+				"[ssh.example.com]:2222 " + authorizedKey(TestHostKey.PublicKey()) + "\n" +
+					"ssh.other.com " + authorizedKey(TestPrivateKey.PublicKey()) + "\n",
+			))
+		})
+	})
+})
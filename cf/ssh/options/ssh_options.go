@@ -1,6 +1,7 @@
 package options
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -29,6 +30,11 @@ type SSHOptions struct {
 	SkipRemoteExecution bool
 	TerminalRequest     TTYRequest
 	ForwardSpecs        []ForwardSpec
+	DynamicForwardSpecs []ForwardSpec
+	RemoteForwardSpecs  []ForwardSpec
+	AllInstances        bool
+	ResetHostKey        bool
+	RecordPath          string
 }
 
 func NewSSHOptions(fc flags.FlagContext) (*SSHOptions, error) {
@@ -37,8 +43,11 @@ func NewSSHOptions(fc flags.FlagContext) (*SSHOptions, error) {
 	sshOptions.AppName = fc.Args()[0]
 	sshOptions.Index = uint(fc.Int("i"))
 	sshOptions.SkipHostValidation = fc.Bool("k")
+	sshOptions.ResetHostKey = fc.Bool("reset-host-key")
 	sshOptions.SkipRemoteExecution = fc.Bool("N")
 	sshOptions.Command = fc.StringSlice("c")
+	sshOptions.AllInstances = fc.Bool("all-instances")
+	sshOptions.RecordPath = fc.String("record")
 
 	if fc.IsSet("L") {
 		for _, arg := range fc.StringSlice("L") {
@@ -50,6 +59,26 @@ func NewSSHOptions(fc flags.FlagContext) (*SSHOptions, error) {
 		}
 	}
 
+	if fc.IsSet("D") {
+		for _, arg := range fc.StringSlice("D") {
+			forwardSpec, err := sshOptions.parseDynamicForwardingSpec(arg)
+			if err != nil {
+				return sshOptions, err
+			}
+			sshOptions.DynamicForwardSpecs = append(sshOptions.DynamicForwardSpecs, *forwardSpec)
+		}
+	}
+
+	if fc.IsSet("R") {
+		for _, arg := range fc.StringSlice("R") {
+			forwardSpec, err := sshOptions.parseRemoteForwardingSpec(arg)
+			if err != nil {
+				return sshOptions, err
+			}
+			sshOptions.RemoteForwardSpecs = append(sshOptions.RemoteForwardSpecs, *forwardSpec)
+		}
+	}
+
 	if fc.IsSet("t") && fc.Bool("t") {
 		sshOptions.TerminalRequest = RequestTTYYes
 	}
@@ -62,21 +91,94 @@ func NewSSHOptions(fc flags.FlagContext) (*SSHOptions, error) {
 		sshOptions.TerminalRequest = RequestTTYNo
 	}
 
+	if sshOptions.AllInstances {
+		err := sshOptions.validateAllInstances(fc)
+		if err != nil {
+			return sshOptions, err
+		}
+	}
+
+	if sshOptions.RecordPath != "" && sshOptions.SkipRemoteExecution {
+		return sshOptions, errors.New("--record cannot be used with --skip-remote-execution")
+	}
+
 	return sshOptions, nil
 }
 
+// validateAllInstances rejects the options that only make sense for a single
+// interactive connection when a command is run on every instance.
+func (o *SSHOptions) validateAllInstances(fc flags.FlagContext) error {
+	if len(o.Command) == 0 {
+		return errors.New("--all-instances requires a command to be given with -c")
+	}
+
+	if fc.IsSet("i") {
+		return errors.New("--all-instances cannot be used with --app-instance-index")
+	}
+
+	if len(o.ForwardSpecs) > 0 || len(o.DynamicForwardSpecs) > 0 || len(o.RemoteForwardSpecs) > 0 {
+		return errors.New("--all-instances cannot be used with port forwarding")
+	}
+
+	if o.SkipRemoteExecution {
+		return errors.New("--all-instances cannot be used with --skip-remote-execution")
+	}
+
+	if o.TerminalRequest == RequestTTYYes || o.TerminalRequest == RequestTTYForce {
+		return errors.New("--all-instances cannot be used with pseudo-tty allocation")
+	}
+
+	if o.RecordPath != "" {
+		return errors.New("--all-instances cannot be used with --record")
+	}
+
+	return nil
+}
+
 func (o *SSHOptions) parseLocalForwardingSpec(arg string) (*ForwardSpec, error) {
+	return parseForwardingSpec(arg, "local")
+}
+
+// parseRemoteForwardingSpec parses [bind_address:]port:host:hostport where
+// the listen address is on the application container and the connect
+// address is reached from the local machine.
+func (o *SSHOptions) parseRemoteForwardingSpec(arg string) (*ForwardSpec, error) {
+	return parseForwardingSpec(arg, "remote")
+}
+
+// parseDynamicForwardingSpec parses [bind_address:]port. Connections made to
+// the listen address are forwarded to the destination requested by the
+// SOCKS client, so the connect address is left empty.
+func (o *SSHOptions) parseDynamicForwardingSpec(arg string) (*ForwardSpec, error) {
 	arg = strings.TrimSpace(arg)
 
-	parts := []string{}
-	for remainder := arg; remainder != ""; {
-		part, r, err := tokenizeForward(remainder)
-		if err != nil {
-			return nil, err
+	parts, err := tokenizeForwardingSpec(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	forwardSpec := &ForwardSpec{}
+	switch len(parts) {
+	case 2:
+		if parts[0] == "*" {
+			parts[0] = ""
 		}
+		forwardSpec.ListenAddress = fmt.Sprintf("%s:%s", parts[0], parts[1])
+	case 1:
+		forwardSpec.ListenAddress = fmt.Sprintf("localhost:%s", parts[0])
+	default:
+		return nil, fmt.Errorf("Unable to parse dynamic forwarding argument: %q", arg)
+	}
 
-		parts = append(parts, part)
-		remainder = r
+	return forwardSpec, nil
+}
+
+func parseForwardingSpec(arg string, direction string) (*ForwardSpec, error) {
+	arg = strings.TrimSpace(arg)
+
+	parts, err := tokenizeForwardingSpec(arg)
+	if err != nil {
+		return nil, err
 	}
 
 	forwardSpec := &ForwardSpec{}
@@ -91,12 +193,27 @@ func (o *SSHOptions) parseLocalForwardingSpec(arg string) (*ForwardSpec, error)
 		forwardSpec.ListenAddress = fmt.Sprintf("localhost:%s", parts[0])
 		forwardSpec.ConnectAddress = fmt.Sprintf("%s:%s", parts[1], parts[2])
 	default:
-		return nil, fmt.Errorf("Unable to parse local forwarding argument: %q", arg)
+		return nil, fmt.Errorf("Unable to parse %s forwarding argument: %q", direction, arg)
 	}
 
 	return forwardSpec, nil
 }
 
+func tokenizeForwardingSpec(arg string) ([]string, error) {
+	parts := []string{}
+	for remainder := arg; remainder != ""; {
+		part, r, err := tokenizeForward(remainder)
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, part)
+		remainder = r
+	}
+
+	return parts, nil
+}
+
 func tokenizeForward(arg string) (string, string, error) {
 	switch arg[0] {
 	case ':':
@@ -20,6 +20,8 @@ var _ = Describe("SSHOptions", func() {
 		BeforeEach(func() {
 			fc = flags.New()
 			fc.NewStringSliceFlag("L", "", "")
+			fc.NewStringSliceFlag("D", "", "")
+			fc.NewStringSliceFlag("R", "", "")
 			fc.NewStringSliceFlag("command", "c", "")
 			fc.NewIntFlag("app-instance-index", "i", "")
 			fc.NewBoolFlag("skip-host-validation", "k", "")
@@ -27,6 +29,9 @@ var _ = Describe("SSHOptions", func() {
 			fc.NewBoolFlag("request-pseudo-tty", "t", "")
 			fc.NewBoolFlag("force-pseudo-tty", "tt", "")
 			fc.NewBoolFlag("disable-pseudo-tty", "T", "")
+			fc.NewBoolFlag("all-instances", "", "")
+			fc.NewBoolFlag("reset-host-key", "", "")
+			fc.NewStringFlag("record", "", "")
 
 			args = []string{}
 			parseError = nil
@@ -75,6 +80,18 @@ var _ = Describe("SSHOptions", func() {
 			})
 		})
 
+		Context("when --reset-host-key is set", func() {
+			BeforeEach(func() {
+				args = append(args, "app-name", "--reset-host-key")
+			})
+
+			It("requests the pinned host key to be replaced", func() {
+				Expect(parseError).NotTo(HaveOccurred())
+				Expect(opts.ResetHostKey).To(BeTrue())
+				Expect(opts.SkipHostValidation).To(BeFalse())
+			})
+		})
+
 		Context("when -k is set", func() {
 			BeforeEach(func() {
 				args = append(args, "app-name", "-k")
@@ -281,6 +298,237 @@ var _ = Describe("SSHOptions", func() {
 			})
 		})
 
+		Context("when dynamic port forwarding is requested", func() {
+			BeforeEach(func() {
+				args = append(args, "app-name")
+			})
+
+			Context("without an explicit bind address", func() {
+				BeforeEach(func() {
+					args = append(args, "-D", "1080")
+				})
+
+				It("sets the forward spec", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.DynamicForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "localhost:1080"}))
+				})
+			})
+
+			Context("with an explicit bind address", func() {
+				BeforeEach(func() {
+					args = append(args, "-D", "explicit:1080")
+				})
+
+				It("sets the forward spec", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.DynamicForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "explicit:1080"}))
+				})
+			})
+
+			Context("with an explicit ipv6 bind address", func() {
+				BeforeEach(func() {
+					args = append(args, "-D", "[::1]:1080")
+				})
+
+				It("sets the forward spec", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.DynamicForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "[::1]:1080"}))
+				})
+			})
+
+			Context("with * as the bind address", func() {
+				BeforeEach(func() {
+					args = append(args, "-D", "*:1080")
+				})
+
+				It("sets the forward spec", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.DynamicForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: ":1080"}))
+				})
+			})
+
+			Context("when a connect address is provided", func() {
+				BeforeEach(func() {
+					args = append(args, "-D", "1080:remote:8888")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError(`Unable to parse dynamic forwarding argument: "1080:remote:8888"`))
+				})
+			})
+
+			Context("when multiple dynamic port forward options are specified", func() {
+				BeforeEach(func() {
+					args = append(args, "-D", "1080")
+					args = append(args, "-D", "1081")
+				})
+
+				It("sets the forward specs", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.DynamicForwardSpecs).To(ConsistOf(
+						options.ForwardSpec{ListenAddress: "localhost:1080"},
+						options.ForwardSpec{ListenAddress: "localhost:1081"},
+					))
+				})
+			})
+		})
+
+		Context("when remote port forwarding is requested", func() {
+			BeforeEach(func() {
+				args = append(args, "app-name")
+			})
+
+			Context("without an explicit bind address", func() {
+				BeforeEach(func() {
+					args = append(args, "-R", "9999:localhost:5432")
+				})
+
+				It("sets the forward spec", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.RemoteForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "localhost:9999", ConnectAddress: "localhost:5432"}))
+				})
+			})
+
+			Context("with an explicit bind address", func() {
+				BeforeEach(func() {
+					args = append(args, "-R", "0.0.0.0:9999:localhost:5432")
+				})
+
+				It("sets the forward spec", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.RemoteForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "0.0.0.0:9999", ConnectAddress: "localhost:5432"}))
+				})
+			})
+
+			Context("with an explicit ipv6 connect address", func() {
+				BeforeEach(func() {
+					args = append(args, "-R", "9999:[::1]:5432")
+				})
+
+				It("sets the forward spec", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.RemoteForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "localhost:9999", ConnectAddress: "[::1]:5432"}))
+				})
+			})
+
+			Context("when the connect address is missing", func() {
+				BeforeEach(func() {
+					args = append(args, "-R", "9999")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError(`Unable to parse remote forwarding argument: "9999"`))
+				})
+			})
+
+			Context("when it is combined with local port forwarding", func() {
+				BeforeEach(func() {
+					args = append(args, "-R", "9999:localhost:5432", "-L", "8080:remote:80")
+				})
+
+				It("keeps the forward specs separate", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.ForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "localhost:8080", ConnectAddress: "remote:80"}))
+					Expect(opts.RemoteForwardSpecs).To(ConsistOf(options.ForwardSpec{ListenAddress: "localhost:9999", ConnectAddress: "localhost:5432"}))
+				})
+			})
+		})
+
+		Context("when --all-instances is specified", func() {
+			Context("with a command", func() {
+				BeforeEach(func() {
+					args = append(args, "app-name", "--all-instances", "-c", "df", "-c", "-h")
+				})
+
+				It("requests the command on all instances", func() {
+					Expect(parseError).NotTo(HaveOccurred())
+					Expect(opts.AllInstances).To(BeTrue())
+					Expect(opts.Command).To(Equal([]string{"df", "-h"}))
+				})
+			})
+
+			Context("without a command", func() {
+				BeforeEach(func() {
+					args = append(args, "app-name", "--all-instances")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError("--all-instances requires a command to be given with -c"))
+				})
+			})
+
+			Context("with an instance index", func() {
+				BeforeEach(func() {
+					args = append(args, "app-name", "--all-instances", "-c", "df", "-i", "1")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError("--all-instances cannot be used with --app-instance-index"))
+				})
+			})
+
+			Context("with port forwarding", func() {
+				BeforeEach(func() {
+					args = append(args, "app-name", "--all-instances", "-c", "df", "-L", "9999:remote:8888")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError("--all-instances cannot be used with port forwarding"))
+				})
+			})
+
+			Context("with -N", func() {
+				BeforeEach(func() {
+					args = append(args, "app-name", "--all-instances", "-c", "df", "-N")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError("--all-instances cannot be used with --skip-remote-execution"))
+				})
+			})
+
+			Context("with a pseudo-tty request", func() {
+				BeforeEach(func() {
+					args = append(args, "app-name", "--all-instances", "-c", "top", "-t")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError("--all-instances cannot be used with pseudo-tty allocation"))
+				})
+			})
+
+			Context("with --record", func() {
+				BeforeEach(func() {
+					args = append(args, "app-name", "--all-instances", "-c", "df", "--record", "session.cast")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError("--all-instances cannot be used with --record"))
+				})
+			})
+		})
+
+		Context("when --record is specified", func() {
+			BeforeEach(func() {
+				args = append(args, "app-name", "--record", "session.cast")
+			})
+
+			It("populates the RecordPath field", func() {
+				Expect(parseError).NotTo(HaveOccurred())
+				Expect(opts.RecordPath).To(Equal("session.cast"))
+			})
+
+			Context("with -N", func() {
+				BeforeEach(func() {
+					args = append(args, "-N")
+				})
+
+				It("returns an error", func() {
+					Expect(parseError).To(MatchError("--record cannot be used with --skip-remote-execution"))
+				})
+			})
+		})
+
 		Context("when -N is specified", func() {
 			BeforeEach(func() {
 				args = append(args, "app-name", "-N")
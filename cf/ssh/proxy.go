@@ -0,0 +1,233 @@
+package sshCmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/configuration/confighelpers"
+	"golang.org/x/crypto/ssh"
+)
+
+// ProxyHostKeyAlias is the name under which the host key of the local proxy
+// is pinned, so that OpenSSH can check it with HostKeyAlias.
+const ProxyHostKeyAlias = "cf-ssh-proxy"
+
+// Proxy serves an SSH server with the given host key over the downstream
+// connection and relays the channels and requests of whichever client
+// connects to the application container. The container is reached over the
+// already authenticated secure client, so the downstream client is not asked
+// to authenticate; it is expected to be a local process such as an OpenSSH
+// ProxyCommand.
+func (c *secureShell) Proxy(downstream net.Conn, hostKey ssh.Signer) error {
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(hostKey)
+
+	serverConn, newChannels, globalRequests, err := ssh.NewServerConn(downstream, config)
+	if err != nil {
+		return err
+	}
+	defer serverConn.Close()
+
+	upstream := c.secureClient.Conn()
+
+	keepaliveStopCh := make(chan struct{})
+	defer close(keepaliveStopCh)
+
+	go keepalive(upstream, time.NewTicker(c.keepAliveInterval), keepaliveStopCh)
+	go relayGlobalRequests(upstream, globalRequests)
+
+	wg := &sync.WaitGroup{}
+	for newChannel := range newChannels {
+		wg.Add(1)
+		go func(newChannel ssh.NewChannel) {
+			defer wg.Done()
+			relayChannel(upstream, newChannel)
+		}(newChannel)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// DefaultProxyHostKeyPath returns the location of the host key of the local
+// proxy, next to the pinned host keys in the CF home directory.
+func DefaultProxyHostKeyPath() (string, error) {
+	configPath, err := confighelpers.DefaultFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(configPath), "ssh_proxy_host_key"), nil
+}
+
+// LoadProxyHostKey reads the host key of the local proxy, generating it the
+// first time. The key stays the same across connections, so that the client
+// can keep checking it strictly.
+func LoadProxyHostKey(path string) (ssh.Signer, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err == nil {
+		return ssh.ParsePrivateKey(contents)
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return ssh.NewSignerFromKey(key)
+}
+
+func relayGlobalRequests(upstream ssh.Conn, requests <-chan *ssh.Request) {
+	for req := range requests {
+		// forwarded-tcpip channels opened by the server are not relayed, so
+		// remote forwarding cannot work through the proxy
+		if req.Type == "tcpip-forward" || req.Type == "cancel-tcpip-forward" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+
+		ok, payload, err := upstream.SendRequest(req.Type, req.WantReply, req.Payload)
+		if err != nil {
+			ok = false
+		}
+
+		if req.WantReply {
+			_ = req.Reply(ok, payload)
+		}
+	}
+}
+
+func relayChannel(upstream ssh.Conn, newChannel ssh.NewChannel) {
+	upstreamChannel, upstreamRequests, err := upstream.OpenChannel(newChannel.ChannelType(), newChannel.ExtraData())
+	if err != nil {
+		if openErr, ok := err.(*ssh.OpenChannelError); ok {
+			_ = newChannel.Reject(openErr.Reason, openErr.Message)
+		} else {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		}
+		return
+	}
+
+	downstreamChannel, downstreamRequests, err := newChannel.Accept()
+	if err != nil {
+		_ = upstreamChannel.Close()
+		return
+	}
+
+	// EOF, exit-status and exit-signal must only be relayed once all of the
+	// output has been, otherwise the client may drop the rest of it
+	outputWG := &sync.WaitGroup{}
+	outputWG.Add(2)
+	go copyAndDone(outputWG, downstreamChannel, upstreamChannel)
+	go copyAndDone(outputWG, downstreamChannel.Stderr(), upstreamChannel.Stderr())
+	go func() {
+		outputWG.Wait()
+		_ = downstreamChannel.CloseWrite()
+	}()
+
+	go func() {
+		_, _ = io.Copy(upstreamChannel, downstreamChannel)
+		_ = upstreamChannel.CloseWrite()
+	}()
+
+	// the container may close the channel as soon as it has answered a
+	// request, so the downstream channel is only closed once that answer has
+	// been relayed
+	replyLock := &sync.Mutex{}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		relayChannelRequests(upstreamChannel, downstreamRequests, nil, replyLock)
+		_ = upstreamChannel.Close()
+		wg.Done()
+	}()
+	go func() {
+		relayChannelRequests(downstreamChannel, upstreamRequests, outputWG, nil)
+		outputWG.Wait()
+		replyLock.Lock()
+		_ = downstreamChannel.Close()
+		replyLock.Unlock()
+		wg.Done()
+	}()
+	wg.Wait()
+}
+
+// relayChannelRequests sends each request to dest and relays the reply. The
+// upstream exit-status and exit-signal requests are held back until all of
+// the output has been relayed, as the client may drop the rest of it.
+func relayChannelRequests(dest ssh.Channel, requests <-chan *ssh.Request, outputWG *sync.WaitGroup, replyLock *sync.Mutex) {
+	for req := range requests {
+		if outputWG != nil && (req.Type == "exit-status" || req.Type == "exit-signal") {
+			outputWG.Wait()
+		}
+
+		relayChannelRequest(dest, req, replyLock)
+	}
+}
+
+func relayChannelRequest(dest ssh.Channel, req *ssh.Request, replyLock *sync.Mutex) {
+	if replyLock != nil {
+		replyLock.Lock()
+		defer replyLock.Unlock()
+	}
+
+	ok, err := dest.SendRequest(req.Type, req.WantReply, req.Payload)
+	if err != nil {
+		ok = false
+	}
+
+	if req.WantReply {
+		_ = req.Reply(ok, nil)
+	}
+}
+
+type stdioConn struct {
+	io.Reader
+	io.WriteCloser
+}
+
+// NewStdioConn wraps a pair of streams, such as the standard input and output
+// of a ProxyCommand, as a net.Conn that can be handed to Proxy.
+func NewStdioConn(in io.Reader, out io.WriteCloser) net.Conn {
+	return &stdioConn{Reader: in, WriteCloser: out}
+}
+
+func (c *stdioConn) LocalAddr() net.Addr                { return stdioAddr{} }
+func (c *stdioConn) RemoteAddr() net.Addr               { return stdioAddr{} }
+func (c *stdioConn) SetDeadline(t time.Time) error      { return nil }
+func (c *stdioConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *stdioConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type stdioAddr struct{}
+
+func (stdioAddr) Network() string { return "stdio" }
+func (stdioAddr) String() string  { return "stdio" }
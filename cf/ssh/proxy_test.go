@@ -0,0 +1,231 @@
+package sshCmd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/ssh"
+	"github.com/cloudfoundry/cli/cf/ssh/options"
+	"github.com/cloudfoundry/cli/cf/ssh/sshfakes"
+	"golang.org/x/crypto/ssh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Proxy", func() {
+	var (
+		fakeSecureDialer *sshfakes.FakeSecureDialer
+		fakeSecureClient *sshfakes.FakeSecureClient
+		secureShell      sshCmd.SecureShell
+
+		upstreamListener net.Listener
+		upstreamClient   *ssh.Client
+
+		downstreamListener net.Listener
+		downstreamClient   *ssh.Client
+		proxyErrCh         chan error
+	)
+
+	// stands in for the SSH server of the application container
+	serveUpstream := func(conn net.Conn) {
+		config := &ssh.ServerConfig{NoClientAuth: true}
+		config.AddHostKey(TestHostKey)
+
+		serverConn, newChannels, requests, err := ssh.NewServerConn(conn, config)
+		if err != nil {
+			return
+		}
+		defer serverConn.Close()
+		go ssh.DiscardRequests(requests)
+
+		for newChannel := range newChannels {
+			if newChannel.ChannelType() != "session" {
+				newChannel.Reject(ssh.Prohibited, "no forwarding here")
+				continue
+			}
+
+			channel, channelRequests, err := newChannel.Accept()
+			Expect(err).NotTo(HaveOccurred())
+
+			go func() {
+				for req := range channelRequests {
+					if req.Type != "exec" {
+						req.Reply(false, nil)
+						continue
+					}
+					req.Reply(true, nil)
+
+					command := string(req.Payload[4:])
+					channel.Write([]byte("ran: " + command + "\n"))
+					channel.Stderr().Write([]byte("warning\n"))
+					channel.CloseWrite()
+					channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{3}))
+					channel.Close()
+				}
+			}()
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		upstreamListener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		go func(listener net.Listener) {
+			conn, err := listener.Accept()
+			if err == nil {
+				serveUpstream(conn)
+			}
+		}(upstreamListener)
+
+		upstreamConn, err := net.Dial("tcp", upstreamListener.Addr().String())
+		Expect(err).NotTo(HaveOccurred())
+
+		clientConn, chans, reqs, err := ssh.NewClientConn(upstreamConn, "", &ssh.ClientConfig{User: "cf:app-guid/0"})
+		Expect(err).NotTo(HaveOccurred())
+		upstreamClient = ssh.NewClient(clientConn, chans, reqs)
+
+		fakeSecureClient = new(sshfakes.FakeSecureClient)
+		fakeSecureClient.ConnReturns(upstreamClient)
+		fakeSecureDialer = new(sshfakes.FakeSecureDialer)
+		fakeSecureDialer.DialReturns(fakeSecureClient, nil)
+
+		app := models.Application{}
+		app.State = "STARTED"
+		app.Diego = true
+
+		secureShell = sshCmd.NewSecureShell(
+			fakeSecureDialer,
+			nil,
+			new(sshfakes.FakeListenerFactory),
+			new(sshfakes.FakeKnownHosts),
+			30*time.Second,
+			app,
+			"",
+			"",
+			"",
+		)
+
+		err = secureShell.Connect(&options.SSHOptions{AppName: "app-1", SkipHostValidation: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		downstreamListener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		proxyErrCh = make(chan error, 1)
+		go func(listener net.Listener, secureShell sshCmd.SecureShell, errCh chan<- error) {
+			conn, err := listener.Accept()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			errCh <- secureShell.Proxy(conn, TestHostKey)
+		}(downstreamListener, secureShell, proxyErrCh)
+
+		downstreamConn, err := net.Dial("tcp", downstreamListener.Addr().String())
+		Expect(err).NotTo(HaveOccurred())
+
+		clientConn, chans, reqs, err = ssh.NewClientConn(downstreamConn, "", &ssh.ClientConfig{
+			User: "cf:app-guid/0",
+			HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+				Expect(key.Marshal()).To(Equal(TestHostKey.PublicKey().Marshal()))
+				return nil
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		downstreamClient = ssh.NewClient(clientConn, chans, reqs)
+	})
+
+	AfterEach(func() {
+		downstreamClient.Close()
+		upstreamClient.Close()
+		downstreamListener.Close()
+		upstreamListener.Close()
+	})
+
+	It("relays sessions, their output and their exit status", func() {
+		session, err := downstreamClient.NewSession()
+		Expect(err).NotTo(HaveOccurred())
+
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+		session.Stdout = stdout
+		session.Stderr = stderr
+
+		err = session.Run("uptime")
+		Expect(err).To(BeAssignableToTypeOf(&ssh.ExitError{}))
+		Expect(err.(*ssh.ExitError).ExitStatus()).To(Equal(3))
+
+		Expect(stdout.String()).To(Equal("ran: uptime\n"))
+		Expect(stderr.String()).To(Equal("warning\n"))
+	})
+
+	It("relays rejected requests", func() {
+		session, err := downstreamClient.NewSession()
+		Expect(err).NotTo(HaveOccurred())
+		defer session.Close()
+
+		err = session.Shell()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("relays rejected channels", func() {
+		_, err := downstreamClient.Dial("tcp", "10.0.0.1:8080")
+		Expect(err).To(MatchError(ContainSubstring("no forwarding here")))
+	})
+
+	It("refuses remote port forwarding", func() {
+		_, err := downstreamClient.Listen("tcp", "127.0.0.1:9999")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns when the downstream client disconnects", func() {
+		Expect(downstreamClient.Close()).To(Succeed())
+		Eventually(proxyErrCh).Should(Receive(BeNil()))
+	})
+})
+
+var _ = Describe("LoadProxyHostKey", func() {
+	var (
+		dir     string
+		keyPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "proxy-host-key")
+		Expect(err).NotTo(HaveOccurred())
+		keyPath = filepath.Join(dir, "cf", "ssh_proxy_host_key")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("generates the key the first time and returns the same key afterwards", func() {
+		key, err := sshCmd.LoadProxyHostKey(keyPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		info, err := os.Stat(keyPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		loadedKey, err := sshCmd.LoadProxyHostKey(keyPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loadedKey.PublicKey().Marshal()).To(Equal(key.PublicKey().Marshal()))
+	})
+
+	It("returns an error when the key cannot be parsed", func() {
+		Expect(os.MkdirAll(filepath.Dir(keyPath), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(keyPath, []byte("garbage"), 0600)).To(Succeed())
+
+		_, err := sshCmd.LoadProxyHostKey(keyPath)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,247 @@
+package sshCmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudfoundry/cli/cf/configuration/confighelpers"
+)
+
+// Session recordings are written in the asciicast v2 format: a header line
+// followed by one [elapsed seconds, event type, data] line per event.
+const (
+	asciicastVersion = 2
+
+	recordingEventOutput = "o"
+	recordingEventInput  = "i"
+)
+
+// RecordingMetadata identifies who opened a recorded session and to which
+// application instance.
+type RecordingMetadata struct {
+	User     string `json:"user"`
+	Org      string `json:"org"`
+	Space    string `json:"space"`
+	AppName  string `json:"app_name"`
+	AppGUID  string `json:"app_guid"`
+	Instance uint   `json:"instance"`
+}
+
+type RecordingHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	CF        RecordingMetadata `json:"cf"`
+}
+
+// DefaultRecordingDir returns the directory that sessions are recorded to
+// when recording is required by the config but no file was given.
+func DefaultRecordingDir() (string, error) {
+	configPath, err := confighelpers.DefaultFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(configPath), "ssh_recordings"), nil
+}
+
+// Recording captures the input and output of an interactive session along
+// with the time at which each of them happened.
+type Recording struct {
+	writer   io.WriteCloser
+	metadata RecordingMetadata
+	start    time.Time
+	lock     sync.Mutex
+	err      error
+}
+
+func NewRecording(writer io.WriteCloser, metadata RecordingMetadata) *Recording {
+	return &Recording{
+		writer:   writer,
+		metadata: metadata,
+	}
+}
+
+// Start writes the header of the recording. Events are timed relative to
+// when it was called.
+func (r *Recording) Start(width, height int) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.start = time.Now()
+
+	header := RecordingHeader{
+		Version:   asciicastVersion,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Title:     fmt.Sprintf("%s/%d", r.metadata.AppName, r.metadata.Instance),
+		Env:       map[string]string{"TERM": os.Getenv("TERM")},
+		CF:        r.metadata,
+	}
+
+	return r.writeLine(header)
+}
+
+// Input returns a writer that records everything written to it as input.
+func (r *Recording) Input() io.Writer {
+	return &recordingStream{recording: r, eventType: recordingEventInput}
+}
+
+// Output returns a writer that records everything written to it as output.
+func (r *Recording) Output() io.Writer {
+	return &recordingStream{recording: r, eventType: recordingEventOutput}
+}
+
+// Close closes the underlying writer and returns the first error that
+// happened while recording, if any.
+func (r *Recording) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	err := r.writer.Close()
+	if r.err != nil {
+		return r.err
+	}
+	return err
+}
+
+func (r *Recording) record(eventType string, data string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err != nil {
+		return
+	}
+
+	elapsed := time.Since(r.start).Seconds()
+	r.err = r.writeLine([]interface{}{elapsed, eventType, data})
+}
+
+func (r *Recording) writeLine(v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.writer.Write(append(line, '\n'))
+	return err
+}
+
+// recordingStream never fails a write, so that a broken recording cannot
+// interrupt the session itself; errors are reported by Recording.Close.
+type recordingStream struct {
+	recording *Recording
+	eventType string
+	partial   []byte
+}
+
+func (s *recordingStream) Write(p []byte) (int, error) {
+	data := append(s.partial, p...)
+
+	// hold back a rune split across writes so it is not recorded as invalid
+	end := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				end = i
+			}
+			break
+		}
+	}
+
+	s.partial = append([]byte{}, data[end:]...)
+	if end > 0 {
+		s.recording.record(s.eventType, string(data[:end]))
+	}
+
+	return len(p), nil
+}
+
+// RecordingPlayer plays back the output of a recording.
+type RecordingPlayer struct {
+	Header  RecordingHeader
+	scanner *bufio.Scanner
+}
+
+// NewRecordingPlayer reads the header of a recording, so it can be shown
+// before the recording is played.
+func NewRecordingPlayer(reader io.Reader) (*RecordingPlayer, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	if !scanner.Scan() {
+		if scanner.Err() != nil {
+			return nil, scanner.Err()
+		}
+		return nil, errors.New("Recording is empty")
+	}
+
+	header := RecordingHeader{}
+	err := json.Unmarshal(scanner.Bytes(), &header)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid recording header: %s", err.Error())
+	}
+
+	if header.Version != asciicastVersion {
+		return nil, fmt.Errorf("Unsupported recording version %d", header.Version)
+	}
+
+	return &RecordingPlayer{Header: header, scanner: scanner}, nil
+}
+
+// Play writes the output of the recording to writer. Events are delayed to
+// reproduce the original timing divided by speed, unless speed is 0. Pauses
+// are never longer than maxIdle, if it is set.
+func (p *RecordingPlayer) Play(writer io.Writer, speed float64, maxIdle time.Duration) error {
+	previous := 0.0
+	for line := 2; p.scanner.Scan(); line++ {
+		if len(p.scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var event []interface{}
+		err := json.Unmarshal(p.scanner.Bytes(), &event)
+		if err != nil || len(event) != 3 {
+			return fmt.Errorf("Invalid recording event on line %d", line)
+		}
+
+		elapsed, okElapsed := event[0].(float64)
+		eventType, okType := event[1].(string)
+		data, okData := event[2].(string)
+		if !okElapsed || !okType || !okData {
+			return fmt.Errorf("Invalid recording event on line %d", line)
+		}
+
+		if eventType != recordingEventOutput {
+			continue
+		}
+
+		if speed > 0 && elapsed > previous {
+			delay := time.Duration((elapsed - previous) / speed * float64(time.Second))
+			if maxIdle > 0 && delay > maxIdle {
+				delay = maxIdle
+			}
+			time.Sleep(delay)
+		}
+		previous = elapsed
+
+		_, err = io.WriteString(writer, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.scanner.Err()
+}
@@ -0,0 +1,187 @@
+package sshCmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/ssh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type closeBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closeBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+type failingWriteCloser struct{}
+
+func (failingWriteCloser) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+func (failingWriteCloser) Close() error                { return nil }
+
+var _ = Describe("Recording", func() {
+	var (
+		buffer    *closeBuffer
+		recording *sshCmd.Recording
+	)
+
+	BeforeEach(func() {
+		buffer = &closeBuffer{}
+		recording = sshCmd.NewRecording(buffer, sshCmd.RecordingMetadata{
+			User:     "my-user",
+			Org:      "my-org",
+			Space:    "my-space",
+			AppName:  "my-app",
+			AppGUID:  "my-app-guid",
+			Instance: 2,
+		})
+	})
+
+	lines := func() []string {
+		return strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	}
+
+	event := func(line string) []interface{} {
+		var e []interface{}
+		Expect(json.Unmarshal([]byte(line), &e)).To(Succeed())
+		return e
+	}
+
+	It("writes an asciicast header identifying the session", func() {
+		Expect(recording.Start(120, 40)).To(Succeed())
+
+		header := sshCmd.RecordingHeader{}
+		Expect(json.Unmarshal([]byte(lines()[0]), &header)).To(Succeed())
+		Expect(header.Version).To(Equal(2))
+		Expect(header.Width).To(Equal(120))
+		Expect(header.Height).To(Equal(40))
+		Expect(header.Timestamp).To(BeNumerically("~", time.Now().Unix(), 5))
+		Expect(header.Title).To(Equal("my-app/2"))
+		Expect(header.CF).To(Equal(sshCmd.RecordingMetadata{
+			User:     "my-user",
+			Org:      "my-org",
+			Space:    "my-space",
+			AppName:  "my-app",
+			AppGUID:  "my-app-guid",
+			Instance: 2,
+		}))
+	})
+
+	It("records input and output events", func() {
+		Expect(recording.Start(80, 24)).To(Succeed())
+
+		_, err := recording.Input().Write([]byte("ls\r"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = recording.Output().Write([]byte("app\r\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(lines()).To(HaveLen(3))
+
+		input := event(lines()[1])
+		Expect(input[0]).To(BeNumerically(">=", 0))
+		Expect(input[1:]).To(Equal([]interface{}{"i", "ls\r"}))
+
+		output := event(lines()[2])
+		Expect(output[0]).To(BeNumerically(">=", input[0]))
+		Expect(output[1:]).To(Equal([]interface{}{"o", "app\r\n"}))
+	})
+
+	It("does not split a character across events", func() {
+		Expect(recording.Start(80, 24)).To(Succeed())
+
+		output := recording.Output()
+		_, err := output.Write([]byte("caf\xc3"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = output.Write([]byte("\xa9!"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(lines()).To(HaveLen(3))
+		Expect(event(lines()[1])[2]).To(Equal("caf"))
+		Expect(event(lines()[2])[2]).To(Equal("é!"))
+	})
+
+	Describe("Close", func() {
+		It("closes the underlying writer", func() {
+			Expect(recording.Close()).To(Succeed())
+			Expect(buffer.closed).To(BeTrue())
+		})
+
+		It("reports errors that happened while recording", func() {
+			recording = sshCmd.NewRecording(failingWriteCloser{}, sshCmd.RecordingMetadata{})
+
+			_, err := recording.Output().Write([]byte("output"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(recording.Close()).To(MatchError("disk full"))
+		})
+	})
+})
+
+var _ = Describe("RecordingPlayer", func() {
+	var output *bytes.Buffer
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+	})
+
+	It("reads the header and writes the output events", func() {
+		cast := `{"version":2,"width":80,"height":24,"timestamp":1476000000,"cf":{"user":"my-user","app_name":"my-app","app_guid":"my-app-guid","instance":1}}
+[0.1,"o","$ "]
+[0.5,"i","ls\r"]
+[0.6,"o","app\r\n"]
+`
+		player, err := sshCmd.NewRecordingPlayer(strings.NewReader(cast))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(player.Header.Timestamp).To(Equal(int64(1476000000)))
+		Expect(player.Header.CF.User).To(Equal("my-user"))
+		Expect(player.Header.CF.AppGUID).To(Equal("my-app-guid"))
+		Expect(player.Header.CF.Instance).To(Equal(uint(1)))
+
+		Expect(player.Play(output, 0, 0)).To(Succeed())
+		Expect(output.String()).To(Equal("$ app\r\n"))
+	})
+
+	It("limits pauses to the maximum idle time", func() {
+		cast := `{"version":2,"width":80,"height":24}
+[3600,"o","done"]
+`
+		player, err := sshCmd.NewRecordingPlayer(strings.NewReader(cast))
+		Expect(err).NotTo(HaveOccurred())
+
+		start := time.Now()
+		Expect(player.Play(output, 1, time.Millisecond)).To(Succeed())
+
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(output.String()).To(Equal("done"))
+	})
+
+	It("fails on an empty recording", func() {
+		_, err := sshCmd.NewRecordingPlayer(strings.NewReader(""))
+		Expect(err).To(MatchError("Recording is empty"))
+	})
+
+	It("fails on an unsupported version", func() {
+		_, err := sshCmd.NewRecordingPlayer(strings.NewReader(`{"version":1}`))
+		Expect(err).To(MatchError("Unsupported recording version 1"))
+	})
+
+	It("fails on a malformed event", func() {
+		cast := `{"version":2,"width":80,"height":24}
+[0.1,"o"]
+`
+		player, err := sshCmd.NewRecordingPlayer(strings.NewReader(cast))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(player.Play(output, 0, 0)).To(MatchError("Invalid recording event on line 2"))
+	})
+})
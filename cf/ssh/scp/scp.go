@@ -0,0 +1,347 @@
+package scp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/formatters"
+)
+
+const (
+	statusOK      = 0
+	statusWarning = 1
+	statusError   = 2
+
+	progressInterval = 250 * time.Millisecond
+)
+
+// Copier speaks the source and sink sides of the scp protocol with a remote
+// "scp -t" or "scp -f" process attached to the given streams.
+type Copier struct {
+	in       io.Writer
+	out      *bufio.Reader
+	progress io.Writer
+}
+
+// NewCopier returns a Copier that writes to the remote process's stdin and
+// reads from its stdout. Progress is reported to the progress writer unless
+// it is nil.
+func NewCopier(in io.Writer, out io.Reader, progress io.Writer) *Copier {
+	return &Copier{
+		in:       in,
+		out:      bufio.NewReader(out),
+		progress: progress,
+	}
+}
+
+// Send acts as the source side, copying the local files (and directories
+// when recursive is set) to a remote "scp -t" process.
+func (c *Copier) Send(localPaths []string, recursive bool) error {
+	err := c.readStatus()
+	if err != nil {
+		return err
+	}
+
+	for _, localPath := range localPaths {
+		info, err := os.Stat(localPath)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if !recursive {
+				return fmt.Errorf("%s: not a regular file", localPath)
+			}
+			err = c.sendDir(localPath, info)
+		} else {
+			err = c.sendFile(localPath, info)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Copier) sendDir(localPath string, info os.FileInfo) error {
+	_, err := fmt.Fprintf(c.in, "D%04o 0 %s\n", info.Mode().Perm(), info.Name())
+	if err != nil {
+		return err
+	}
+
+	err = c.readStatus()
+	if err != nil {
+		return err
+	}
+
+	entries, err := ioutil.ReadDir(localPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(localPath, entry.Name())
+		switch {
+		case entry.IsDir():
+			err = c.sendDir(entryPath, entry)
+		case entry.Mode().IsRegular():
+			err = c.sendFile(entryPath, entry)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprint(c.in, "E\n")
+	if err != nil {
+		return err
+	}
+
+	return c.readStatus()
+}
+
+func (c *Copier) sendFile(localPath string, info os.FileInfo) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(c.in, "C%04o %d %s\n", info.Mode().Perm(), info.Size(), info.Name())
+	if err != nil {
+		return err
+	}
+
+	err = c.readStatus()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.copyWithProgress(c.in, file, info.Name(), info.Size())
+	if err != nil {
+		return err
+	}
+
+	_, err = c.in.Write([]byte{statusOK})
+	if err != nil {
+		return err
+	}
+
+	return c.readStatus()
+}
+
+// Receive acts as the sink side, writing whatever a remote "scp -f" process
+// sends to localPath. When localPath is an existing directory the received
+// files are created inside it, otherwise the only received entry is created
+// as localPath and receiving more than one entry fails.
+func (c *Copier) Receive(localPath string) error {
+	targetIsDir := false
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		targetIsDir = true
+	}
+
+	dirs := []string{}
+	received := false
+
+	err := c.writeStatus()
+	if err != nil {
+		return err
+	}
+
+	for {
+		line, err := c.out.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if line[0] == statusWarning || line[0] == statusError {
+			return errors.New(strings.TrimSpace(line[1:]))
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+
+		switch line[0] {
+		case 'C', 'D':
+			mode, size, name, err := parseEntry(line)
+			if err != nil {
+				return err
+			}
+
+			var entryPath string
+			switch {
+			case len(dirs) > 0:
+				entryPath = filepath.Join(dirs[len(dirs)-1], name)
+			case targetIsDir:
+				entryPath = filepath.Join(localPath, name)
+			case received:
+				return fmt.Errorf("%s: not a directory, cannot receive more than one file", localPath)
+			default:
+				entryPath = localPath
+			}
+			if len(dirs) == 0 {
+				received = true
+			}
+
+			if line[0] == 'D' {
+				// keep the directory writable so its contents can be received
+				err = os.MkdirAll(entryPath, mode|0700)
+				dirs = append(dirs, entryPath)
+			} else {
+				err = c.receiveFile(entryPath, mode, size, name)
+			}
+			if err != nil {
+				return err
+			}
+		case 'E':
+			if len(dirs) == 0 {
+				return fmt.Errorf("unexpected end of directory")
+			}
+			dirs = dirs[:len(dirs)-1]
+		case 'T':
+			// modification times are not preserved
+		default:
+			return fmt.Errorf("unexpected message from remote: %q", line)
+		}
+
+		err = c.writeStatus()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Copier) receiveFile(localPath string, mode os.FileMode, size int64, name string) error {
+	file, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = c.writeStatus()
+	if err != nil {
+		return err
+	}
+
+	copied, err := c.copyWithProgress(file, io.LimitReader(c.out, size), name, size)
+	if err == nil && copied != size {
+		err = fmt.Errorf("%s: connection closed after %d of %d bytes", name, copied, size)
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(localPath)
+		return err
+	}
+
+	err = c.readStatus()
+	if err != nil {
+		return err
+	}
+
+	return os.Chmod(localPath, mode)
+}
+
+func parseEntry(line string) (os.FileMode, int64, string, error) {
+	parts := strings.SplitN(line[1:], " ", 3)
+	if len(parts) != 3 {
+		return 0, 0, "", fmt.Errorf("unexpected message from remote: %q", line)
+	}
+
+	mode, err := strconv.ParseUint(parts[0], 8, 32)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("invalid file mode %q", parts[0])
+	}
+
+	size, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("invalid file size %q", parts[1])
+	}
+
+	name := parts[2]
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return 0, 0, "", fmt.Errorf("invalid file name %q", name)
+	}
+
+	return os.FileMode(mode).Perm(), size, name, nil
+}
+
+func (c *Copier) readStatus() error {
+	status, err := c.out.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	if status == statusOK {
+		return nil
+	}
+
+	message, err := c.out.ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	return errors.New(strings.TrimSpace(message))
+}
+
+func (c *Copier) writeStatus() error {
+	_, err := c.in.Write([]byte{statusOK})
+	return err
+}
+
+func (c *Copier) copyWithProgress(dest io.Writer, src io.Reader, name string, size int64) (int64, error) {
+	if c.progress == nil {
+		return io.Copy(dest, src)
+	}
+
+	reporter := &progressReporter{
+		writer: c.progress,
+		name:   name,
+		total:  size,
+	}
+
+	copied, err := io.Copy(dest, io.TeeReader(src, reporter))
+	reporter.finish()
+	return copied, err
+}
+
+type progressReporter struct {
+	writer     io.Writer
+	name       string
+	total      int64
+	copied     int64
+	lastReport time.Time
+}
+
+func (r *progressReporter) Write(p []byte) (int, error) {
+	r.copied += int64(len(p))
+	if time.Since(r.lastReport) >= progressInterval {
+		r.report("\r")
+		r.lastReport = time.Now()
+	}
+	return len(p), nil
+}
+
+func (r *progressReporter) finish() {
+	r.report("\r")
+	fmt.Fprintln(r.writer)
+}
+
+func (r *progressReporter) report(prefix string) {
+	percent := int64(100)
+	if r.total > 0 {
+		percent = r.copied * 100 / r.total
+	}
+
+	fmt.Fprintf(r.writer, "%s%-40s %3d%% %8s", prefix, r.name, percent, formatters.ByteSize(r.copied))
+}
@@ -0,0 +1,197 @@
+package scp_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/ssh/scp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Copier", func() {
+	var (
+		sourceDir string
+		targetDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		sourceDir, err = ioutil.TempDir("", "scp-source")
+		Expect(err).NotTo(HaveOccurred())
+		targetDir, err = ioutil.TempDir("", "scp-target")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(sourceDir)
+		os.RemoveAll(targetDir)
+	})
+
+	writeFile := func(path string, contents string, mode os.FileMode) {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		Expect(err).NotTo(HaveOccurred())
+		err = ioutil.WriteFile(path, []byte(contents), mode)
+		Expect(err).NotTo(HaveOccurred())
+		err = os.Chmod(path, mode)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	Describe("Send", func() {
+		It("sends a file using the scp protocol", func() {
+			filePath := filepath.Join(sourceDir, "app.log")
+			writeFile(filePath, "hello", 0640)
+
+			in := &bytes.Buffer{}
+			out := strings.NewReader("\x00\x00\x00\x00")
+
+			err := scp.NewCopier(in, out, nil).Send([]string{filePath}, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(in.String()).To(Equal("C0640 5 app.log\nhello\x00"))
+		})
+
+		It("refuses to send a directory without recursive", func() {
+			in := &bytes.Buffer{}
+			out := strings.NewReader("\x00")
+
+			err := scp.NewCopier(in, out, nil).Send([]string{sourceDir}, false)
+			Expect(err).To(MatchError(ContainSubstring("not a regular file")))
+		})
+
+		It("returns the error reported by the remote", func() {
+			filePath := filepath.Join(sourceDir, "app.log")
+			writeFile(filePath, "hello", 0640)
+
+			in := &bytes.Buffer{}
+			out := strings.NewReader("\x00\x01scp: /app: Permission denied\n")
+
+			err := scp.NewCopier(in, out, nil).Send([]string{filePath}, false)
+			Expect(err).To(MatchError("scp: /app: Permission denied"))
+		})
+	})
+
+	Describe("Receive", func() {
+		It("receives a file into an existing directory", func() {
+			in := &bytes.Buffer{}
+			out := strings.NewReader("C0600 5 app.log\nhello\x00")
+
+			err := scp.NewCopier(in, out, nil).Receive(targetDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(filepath.Join(targetDir, "app.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("hello"))
+			Expect(in.String()).To(Equal("\x00\x00\x00"))
+		})
+
+		It("renames a single file to the target path", func() {
+			targetPath := filepath.Join(targetDir, "renamed.log")
+			out := strings.NewReader("C0600 5 app.log\nhello\x00")
+
+			err := scp.NewCopier(&bytes.Buffer{}, out, nil).Receive(targetPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(targetPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("hello"))
+		})
+
+		It("rejects more than one file when the target is not a directory", func() {
+			targetPath := filepath.Join(targetDir, "renamed.log")
+			out := strings.NewReader("C0600 5 app.log\nhello\x00C0600 3 other.log\nbye\x00")
+
+			err := scp.NewCopier(&bytes.Buffer{}, out, nil).Receive(targetPath)
+			Expect(err).To(MatchError(ContainSubstring("not a directory")))
+
+			contents, err := ioutil.ReadFile(targetPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("hello"))
+		})
+
+		It("rejects file names that escape the target directory", func() {
+			out := strings.NewReader("C0600 5 ../evil\nhello\x00")
+
+			err := scp.NewCopier(&bytes.Buffer{}, out, nil).Receive(targetDir)
+			Expect(err).To(MatchError(ContainSubstring("invalid file name")))
+		})
+
+		It("fails and removes the partial file when the remote stops sending early", func() {
+			out := strings.NewReader("C0600 10 app.log\nhello")
+
+			err := scp.NewCopier(&bytes.Buffer{}, out, nil).Receive(targetDir)
+			Expect(err).To(MatchError("app.log: connection closed after 5 of 10 bytes"))
+
+			_, err = os.Stat(filepath.Join(targetDir, "app.log"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("returns the error reported by the remote", func() {
+			out := strings.NewReader("\x01scp: /app/missing: No such file or directory\n")
+
+			err := scp.NewCopier(&bytes.Buffer{}, out, nil).Receive(targetDir)
+			Expect(err).To(MatchError("scp: /app/missing: No such file or directory"))
+		})
+	})
+
+	Context("when a source is connected to a sink", func() {
+		copyBetween := func(localPaths []string, recursive bool, target string, progress io.Writer) {
+			sourceOut, sinkIn := io.Pipe()
+			sinkOut, sourceIn := io.Pipe()
+
+			receiveErr := make(chan error, 1)
+			go func() {
+				defer GinkgoRecover()
+				receiveErr <- scp.NewCopier(sinkIn, sinkOut, nil).Receive(target)
+				sinkIn.Close()
+			}()
+
+			err := scp.NewCopier(sourceIn, sourceOut, progress).Send(localPaths, recursive)
+			Expect(err).NotTo(HaveOccurred())
+			sourceIn.Close()
+
+			Expect(<-receiveErr).NotTo(HaveOccurred())
+		}
+
+		It("copies directories recursively and preserves file modes", func() {
+			writeFile(filepath.Join(sourceDir, "dumps", "heap.hprof"), "heap", 0600)
+			writeFile(filepath.Join(sourceDir, "dumps", "threads", "1.txt"), "thread", 0755)
+
+			copyBetween([]string{filepath.Join(sourceDir, "dumps")}, true, targetDir, nil)
+
+			contents, err := ioutil.ReadFile(filepath.Join(targetDir, "dumps", "heap.hprof"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("heap"))
+
+			contents, err = ioutil.ReadFile(filepath.Join(targetDir, "dumps", "threads", "1.txt"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("thread"))
+
+			if runtime.GOOS != "windows" {
+				info, err := os.Stat(filepath.Join(targetDir, "dumps", "threads", "1.txt"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+
+				info, err = os.Stat(filepath.Join(targetDir, "dumps", "heap.hprof"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			}
+		})
+
+		It("reports progress for every file", func() {
+			writeFile(filepath.Join(sourceDir, "a.txt"), "aaaa", 0644)
+			writeFile(filepath.Join(sourceDir, "b.txt"), "bb", 0644)
+
+			progress := &bytes.Buffer{}
+			copyBetween([]string{filepath.Join(sourceDir, "a.txt"), filepath.Join(sourceDir, "b.txt")}, false, targetDir, progress)
+
+			Expect(progress.String()).To(MatchRegexp(`a\.txt\s+100%\s+4B\n`))
+			Expect(progress.String()).To(MatchRegexp(`b\.txt\s+100%\s+2B\n`))
+		})
+	})
+})
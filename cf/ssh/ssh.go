@@ -1,8 +1,11 @@
 package sshCmd
 
 import (
+	"bytes"
 	"crypto/md5"
 	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -19,6 +22,7 @@ import (
 
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/ssh/options"
+	"github.com/cloudfoundry/cli/cf/ssh/scp"
 	"github.com/cloudfoundry/cli/cf/ssh/sigwinch"
 	"github.com/cloudfoundry/cli/cf/ssh/terminal"
 	"github.com/docker/docker/pkg/term"
@@ -34,7 +38,13 @@ const (
 type SecureShell interface {
 	Connect(opts *options.SSHOptions) error
 	InteractiveSession() error
+	CommandSession(stdout io.Writer, stderr io.Writer) error
+	Record(recording *Recording)
 	LocalPortForward() error
+	RemotePortForward() error
+	Proxy(downstream net.Conn, hostKey ssh.Signer) error
+	CopyToRemote(localPaths []string, remotePath string, recursive bool) error
+	CopyFromRemote(remotePath string, localPath string, recursive bool) error
 	Wait() error
 	Close() error
 }
@@ -51,6 +61,7 @@ type SecureClient interface {
 	NewSession() (SecureSession, error)
 	Conn() ssh.Conn
 	Dial(network, address string) (net.Conn, error)
+	Listen(network, address string) (net.Listener, error)
 	Wait() error
 	Close() error
 }
@@ -79,6 +90,7 @@ type secureShell struct {
 	secureDialer           SecureDialer
 	terminalHelper         sshTerminal.TerminalHelper
 	listenerFactory        ListenerFactory
+	knownHosts             KnownHosts
 	keepAliveInterval      time.Duration
 	app                    models.Application
 	sshEndpointFingerprint string
@@ -86,14 +98,17 @@ type secureShell struct {
 	token                  string
 	secureClient           SecureClient
 	opts                   *options.SSHOptions
+	recording              *Recording
 
-	localListeners []net.Listener
+	localListeners  []net.Listener
+	remoteListeners []net.Listener
 }
 
 func NewSecureShell(
 	secureDialer SecureDialer,
 	terminalHelper sshTerminal.TerminalHelper,
 	listenerFactory ListenerFactory,
+	knownHosts KnownHosts,
 	keepAliveInterval time.Duration,
 	app models.Application,
 	sshEndpointFingerprint string,
@@ -101,15 +116,17 @@ func NewSecureShell(
 	token string,
 ) SecureShell {
 	return &secureShell{
-		secureDialer:      secureDialer,
-		terminalHelper:    terminalHelper,
-		listenerFactory:   listenerFactory,
-		keepAliveInterval: keepAliveInterval,
-		app:               app,
+		secureDialer:           secureDialer,
+		terminalHelper:         terminalHelper,
+		listenerFactory:        listenerFactory,
+		knownHosts:             knownHosts,
+		keepAliveInterval:      keepAliveInterval,
+		app:                    app,
 		sshEndpointFingerprint: sshEndpointFingerprint,
 		sshEndpoint:            sshEndpoint,
 		token:                  token,
 		localListeners:         []net.Listener{},
+		remoteListeners:        []net.Listener{},
 	}
 }
 
@@ -124,7 +141,7 @@ func (c *secureShell) Connect(opts *options.SSHOptions) error {
 		Auth: []ssh.AuthMethod{
 			ssh.Password(c.token),
 		},
-		HostKeyCallback: fingerprintCallback(opts, c.sshEndpointFingerprint),
+		HostKeyCallback: fingerprintCallback(opts, c.sshEndpointFingerprint, c.sshEndpoint, c.knownHosts),
 	}
 
 	secureClient, err := c.secureDialer.Dial("tcp", c.sshEndpoint, clientConfig)
@@ -141,9 +158,15 @@ func (c *secureShell) Close() error {
 	for _, listener := range c.localListeners {
 		_ = listener.Close()
 	}
+	for _, listener := range c.remoteListeners {
+		_ = listener.Close()
+	}
 	return c.secureClient.Close()
 }
 
+// LocalPortForward listens locally for both the static (-L) and the dynamic
+// SOCKS (-D) forward specs and tunnels accepted connections into the
+// application container.
 func (c *secureShell) LocalPortForward() error {
 	for _, forwardSpec := range c.opts.ForwardSpecs {
 		listener, err := c.listenerFactory.Listen("tcp", forwardSpec.ListenAddress)
@@ -152,13 +175,46 @@ func (c *secureShell) LocalPortForward() error {
 		}
 		c.localListeners = append(c.localListeners, listener)
 
-		go c.localForwardAcceptLoop(listener, forwardSpec.ConnectAddress)
+		connectAddress := forwardSpec.ConnectAddress
+		go c.acceptLoop(listener, func(conn net.Conn) {
+			c.handleForwardConnection(conn, connectAddress)
+		})
+	}
+
+	for _, forwardSpec := range c.opts.DynamicForwardSpecs {
+		listener, err := c.listenerFactory.Listen("tcp", forwardSpec.ListenAddress)
+		if err != nil {
+			return err
+		}
+		c.localListeners = append(c.localListeners, listener)
+
+		go c.acceptLoop(listener, c.handleDynamicForwardConnection)
 	}
 
 	return nil
 }
 
-func (c *secureShell) localForwardAcceptLoop(listener net.Listener, addr string) {
+// RemotePortForward asks the SSH server to listen on the container side of
+// each -R forward spec and connects accepted connections to the local
+// connect address.
+func (c *secureShell) RemotePortForward() error {
+	for _, forwardSpec := range c.opts.RemoteForwardSpecs {
+		listener, err := c.secureClient.Listen("tcp", forwardSpec.ListenAddress)
+		if err != nil {
+			return err
+		}
+		c.remoteListeners = append(c.remoteListeners, listener)
+
+		connectAddress := forwardSpec.ConnectAddress
+		go c.acceptLoop(listener, func(conn net.Conn) {
+			c.handleRemoteForwardConnection(conn, connectAddress)
+		})
+	}
+
+	return nil
+}
+
+func (c *secureShell) acceptLoop(listener net.Listener, handle func(net.Conn)) {
 	defer listener.Close()
 
 	for {
@@ -171,7 +227,7 @@ func (c *secureShell) localForwardAcceptLoop(listener net.Listener, addr string)
 			return
 		}
 
-		go c.handleForwardConnection(conn, addr)
+		go handle(conn)
 	}
 }
 
@@ -185,6 +241,48 @@ func (c *secureShell) handleForwardConnection(conn net.Conn, targetAddr string)
 	}
 	defer target.Close()
 
+	pipeConnections(conn, target)
+}
+
+func (c *secureShell) handleDynamicForwardConnection(conn net.Conn) {
+	defer conn.Close()
+
+	targetAddr, err := socksHandshake(conn)
+	if err != nil {
+		fmt.Printf("SOCKS handshake failed: %s\n", err.Error())
+		return
+	}
+
+	target, err := c.secureClient.Dial("tcp", targetAddr)
+	if err != nil {
+		_ = socksReply(conn, socksReplyHostUnreachable)
+		fmt.Printf("connect to %s failed: %s\n", targetAddr, err.Error())
+		return
+	}
+	defer target.Close()
+
+	err = socksReply(conn, socksReplySucceeded)
+	if err != nil {
+		return
+	}
+
+	pipeConnections(conn, target)
+}
+
+func (c *secureShell) handleRemoteForwardConnection(conn net.Conn, targetAddr string) {
+	defer conn.Close()
+
+	target, err := net.Dial("tcp", targetAddr)
+	if err != nil {
+		fmt.Printf("connect to %s failed: %s\n", targetAddr, err.Error())
+		return
+	}
+	defer target.Close()
+
+	pipeConnections(conn, target)
+}
+
+func pipeConnections(conn net.Conn, target net.Conn) {
 	wg := &sync.WaitGroup{}
 	wg.Add(2)
 
@@ -206,6 +304,11 @@ func copyAndDone(wg *sync.WaitGroup, dest io.Writer, src io.Reader) {
 	wg.Done()
 }
 
+// Record captures the input and output of the next interactive session.
+func (c *secureShell) Record(recording *Recording) {
+	c.recording = recording
+}
+
 func (c *secureShell) InteractiveSession() error {
 	var err error
 
@@ -259,6 +362,19 @@ func (c *secureShell) InteractiveSession() error {
 		}
 	}
 
+	var input io.Reader = stdin
+	if c.recording != nil {
+		width, height := c.getWindowDimensions(stdoutFd)
+		err = c.recording.Start(width, height)
+		if err != nil {
+			return fmt.Errorf("Unable to start recording: %s", err.Error())
+		}
+
+		input = io.TeeReader(stdin, c.recording.Input())
+		stdout = io.MultiWriter(stdout, c.recording.Output())
+		stderr = io.MultiWriter(stderr, c.recording.Output())
+	}
+
 	if len(opts.Command) != 0 {
 		cmd := strings.Join(opts.Command, " ")
 		err = session.Start(cmd)
@@ -275,7 +391,7 @@ func (c *secureShell) InteractiveSession() error {
 	wg := &sync.WaitGroup{}
 	wg.Add(2)
 
-	go copyAndClose(nil, inPipe, stdin)
+	go copyAndClose(nil, inPipe, input)
 	go copyAndDone(wg, stdout, outPipe)
 	go copyAndDone(wg, stderr, errPipe)
 
@@ -310,6 +426,110 @@ func (c *secureShell) InteractiveSession() error {
 	return result
 }
 
+// CommandSession runs the requested command without a terminal or stdin,
+// copying its output to the given writers. A non-zero exit status is
+// returned as an *ssh.ExitError.
+func (c *secureShell) CommandSession(stdout io.Writer, stderr io.Writer) error {
+	session, err := c.secureClient.NewSession()
+	if err != nil {
+		return fmt.Errorf("SSH session allocation failed: %s", err.Error())
+	}
+	defer session.Close()
+
+	outPipe, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	errPipe, err := session.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	err = session.Start(strings.Join(c.opts.Command, " "))
+	if err != nil {
+		return err
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	go copyAndDone(wg, stdout, outPipe)
+	go copyAndDone(wg, stderr, errPipe)
+
+	keepaliveStopCh := make(chan struct{})
+	defer close(keepaliveStopCh)
+
+	go keepalive(c.secureClient.Conn(), time.NewTicker(c.keepAliveInterval), keepaliveStopCh)
+
+	result := session.Wait()
+	wg.Wait()
+	return result
+}
+
+func (c *secureShell) CopyToRemote(localPaths []string, remotePath string, recursive bool) error {
+	command := "scp -t"
+	if recursive {
+		command += " -r"
+	}
+	if len(localPaths) > 1 {
+		command += " -d"
+	}
+
+	return c.runSCP(command+" "+shellQuote(remotePath), func(copier *scp.Copier) error {
+		return copier.Send(localPaths, recursive)
+	})
+}
+
+func (c *secureShell) CopyFromRemote(remotePath string, localPath string, recursive bool) error {
+	command := "scp -f"
+	if recursive {
+		command += " -r"
+	}
+
+	return c.runSCP(command+" "+shellQuote(remotePath), func(copier *scp.Copier) error {
+		return copier.Receive(localPath)
+	})
+}
+
+func (c *secureShell) runSCP(command string, copy func(*scp.Copier) error) error {
+	session, err := c.secureClient.NewSession()
+	if err != nil {
+		return fmt.Errorf("SSH session allocation failed: %s", err.Error())
+	}
+	defer session.Close()
+
+	inPipe, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	outPipe, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	err = session.Start(command)
+	if err != nil {
+		return err
+	}
+
+	_, _, stderr := c.terminalHelper.StdStreams()
+
+	copyErr := copy(scp.NewCopier(inPipe, outPipe, stderr))
+	_ = inPipe.Close()
+
+	waitErr := session.Wait()
+	if copyErr != nil {
+		return copyErr
+	}
+	return waitErr
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
 func (c *secureShell) Wait() error {
 	keepaliveStopCh := make(chan struct{})
 	defer close(keepaliveStopCh)
@@ -341,9 +561,14 @@ func sha1Fingerprint(key ssh.PublicKey) string {
 	return strings.Replace(fmt.Sprintf("% x", sum), " ", ":", -1)
 }
 
+func sha256Fingerprint(key ssh.PublicKey) string {
+	sum := sha256.Sum256(key.Marshal())
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
 type hostKeyCallback func(hostname string, remote net.Addr, key ssh.PublicKey) error
 
-func fingerprintCallback(opts *options.SSHOptions, expectedFingerprint string) hostKeyCallback {
+func fingerprintCallback(opts *options.SSHOptions, expectedFingerprint string, endpoint string, knownHosts KnownHosts) hostKeyCallback {
 	if opts.SkipHostValidation {
 		return nil
 	}
@@ -366,8 +591,37 @@ func fingerprintCallback(opts *options.SSHOptions, expectedFingerprint string) h
 		default:
 			return errors.New("Unsupported host key fingerprint format")
 		}
+		return verifyPinnedHostKey(knownHosts, endpoint, key, opts.ResetHostKey)
+	}
+}
+
+// verifyPinnedHostKey compares the host key with the one pinned for the
+// endpoint, pinning it when the endpoint has not been seen before or when
+// the user asked for the pinned key to be replaced.
+func verifyPinnedHostKey(knownHosts KnownHosts, endpoint string, key ssh.PublicKey, reset bool) error {
+	if knownHosts == nil {
+		return nil
+	}
+
+	pinnedKey, found, err := knownHosts.Lookup(endpoint)
+	if err != nil {
+		return fmt.Errorf("Unable to read pinned host keys: %s", err.Error())
+	}
+
+	if found && bytes.Equal(pinnedKey.Marshal(), key.Marshal()) {
 		return nil
 	}
+
+	if found && !reset {
+		return fmt.Errorf("WARNING: THE HOST KEY OF %s HAS CHANGED!\n\nSomeone could be intercepting the connection. The fingerprint of the received key was %q, but %q was pinned the first time this endpoint was used.\nIf the change is expected, run 'cf ssh --reset-host-key' to pin the new key.", endpoint, sha256Fingerprint(key), sha256Fingerprint(pinnedKey))
+	}
+
+	err = knownHosts.Pin(endpoint, key)
+	if err != nil {
+		return fmt.Errorf("Unable to pin host key: %s", err.Error())
+	}
+
+	return nil
 }
 
 func (c *secureShell) shouldAllocateTerminal(opts *options.SSHOptions, stdinIsTerminal bool) bool {
@@ -469,6 +723,9 @@ func (sc *secureClient) Wait() error    { return sc.client.Wait() }
 func (sc *secureClient) Dial(n, addr string) (net.Conn, error) {
 	return sc.client.Dial(n, addr)
 }
+func (sc *secureClient) Listen(n, addr string) (net.Listener, error) {
+	return sc.client.Listen(n, addr)
+}
 func (sc *secureClient) NewSession() (SecureSession, error) {
 	return sc.client.NewSession()
 }
@@ -7,11 +7,18 @@
 package sshCmd_test
 
 import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"os"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
@@ -29,6 +36,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/ssh/terminal/terminalhelperfakes"
 	"github.com/docker/docker/pkg/term"
 	"github.com/kr/pty"
+	"github.com/onsi/gomega/gbytes"
 	"github.com/pivotal-golang/lager/lagertest"
 	"golang.org/x/crypto/ssh"
 
@@ -40,6 +48,7 @@ var _ = Describe("SSH", func() {
 	var (
 		fakeTerminalHelper  *terminalhelperfakes.FakeTerminalHelper
 		fakeListenerFactory *sshfakes.FakeListenerFactory
+		fakeKnownHosts      *sshfakes.FakeKnownHosts
 
 		fakeConnection    *fake_ssh.FakeConn
 		fakeSecureClient  *sshfakes.FakeSecureClient
@@ -65,6 +74,8 @@ var _ = Describe("SSH", func() {
 		fakeListenerFactory = new(sshfakes.FakeListenerFactory)
 		fakeListenerFactory.ListenStub = net.Listen
 
+		fakeKnownHosts = new(sshfakes.FakeKnownHosts)
+
 		keepAliveDuration = 30 * time.Second
 
 		currentApp = models.Application{}
@@ -106,6 +117,7 @@ var _ = Describe("SSH", func() {
 			fakeSecureDialer,
 			terminalHelper,
 			fakeListenerFactory,
+			fakeKnownHosts,
 			keepAliveDuration,
 			currentApp,
 			sshEndpointFingerprint,
@@ -265,6 +277,112 @@ var _ = Describe("SSH", func() {
 				})
 			})
 
+			Context("when the fingerprint matches the one advertised by the API", func() {
+				var fingerprint string
+
+				BeforeEach(func() {
+					sum := sha1.Sum(TestHostKey.PublicKey().Marshal())
+					fingerprint = strings.Replace(fmt.Sprintf("% x", sum), " ", ":", -1)
+					sshEndpointFingerprint = fingerprint
+				})
+
+				Context("when the endpoint has not been seen before", func() {
+					It("pins the host key", func() {
+						err := callback("", addr, TestHostKey.PublicKey())
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(fakeKnownHosts.LookupCallCount()).To(Equal(1))
+						Expect(fakeKnownHosts.LookupArgsForCall(0)).To(Equal("ssh.example.com:22"))
+
+						Expect(fakeKnownHosts.PinCallCount()).To(Equal(1))
+						endpoint, pinnedKey := fakeKnownHosts.PinArgsForCall(0)
+						Expect(endpoint).To(Equal("ssh.example.com:22"))
+						Expect(pinnedKey).To(Equal(TestHostKey.PublicKey()))
+					})
+
+					Context("when pinning fails", func() {
+						BeforeEach(func() {
+							fakeKnownHosts.PinReturns(errors.New("read-only file system"))
+						})
+
+						It("returns an error", func() {
+							err := callback("", addr, TestHostKey.PublicKey())
+							Expect(err).To(MatchError("Unable to pin host key: read-only file system"))
+						})
+					})
+				})
+
+				Context("when the pinned host key matches", func() {
+					BeforeEach(func() {
+						fakeKnownHosts.LookupReturns(TestHostKey.PublicKey(), true, nil)
+					})
+
+					It("accepts the key without pinning it again", func() {
+						err := callback("", addr, TestHostKey.PublicKey())
+						Expect(err).NotTo(HaveOccurred())
+						Expect(fakeKnownHosts.PinCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the pinned host key is different", func() {
+					BeforeEach(func() {
+						fakeKnownHosts.LookupReturns(TestPrivateKey.PublicKey(), true, nil)
+					})
+
+					It("returns an error showing the SHA256 fingerprints of both keys", func() {
+						sha256Fingerprint := func(key ssh.PublicKey) string {
+							sum := sha256.Sum256(key.Marshal())
+							return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+						}
+
+						err := callback("", addr, TestHostKey.PublicKey())
+						Expect(err).To(MatchError(MatchRegexp("THE HOST KEY OF ssh\\.example\\.com:22 HAS CHANGED")))
+						Expect(err).To(MatchError(ContainSubstring(sha256Fingerprint(TestHostKey.PublicKey()))))
+						Expect(err).To(MatchError(ContainSubstring(sha256Fingerprint(TestPrivateKey.PublicKey()))))
+						Expect(err).To(MatchError(ContainSubstring("--reset-host-key")))
+						Expect(fakeKnownHosts.PinCallCount()).To(Equal(0))
+					})
+
+					Context("when the pinned key is being reset", func() {
+						BeforeEach(func() {
+							opts.ResetHostKey = true
+						})
+
+						It("pins the new key", func() {
+							err := callback("", addr, TestHostKey.PublicKey())
+							Expect(err).NotTo(HaveOccurred())
+
+							Expect(fakeKnownHosts.PinCallCount()).To(Equal(1))
+							_, pinnedKey := fakeKnownHosts.PinArgsForCall(0)
+							Expect(pinnedKey).To(Equal(TestHostKey.PublicKey()))
+						})
+					})
+				})
+
+				Context("when the pinned host keys cannot be read", func() {
+					BeforeEach(func() {
+						fakeKnownHosts.LookupReturns(nil, false, errors.New("permission denied"))
+					})
+
+					It("returns an error", func() {
+						err := callback("", addr, TestHostKey.PublicKey())
+						Expect(err).To(MatchError("Unable to read pinned host keys: permission denied"))
+						Expect(fakeKnownHosts.PinCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when the API fingerprint does not match", func() {
+				BeforeEach(func() {
+					sshEndpointFingerprint = "00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00"
+				})
+
+				It("does not pin the host key", func() {
+					callback("", addr, TestHostKey.PublicKey())
+					Expect(fakeKnownHosts.PinCallCount()).To(Equal(0))
+				})
+			})
+
 			Context("when the fingerprint length doesn't make sense", func() {
 				BeforeEach(func() {
 					sshEndpointFingerprint = "garbage"
@@ -701,6 +819,36 @@ var _ = Describe("SSH", func() {
 					Eventually(stdinPipe.CloseCallCount).Should(Equal(1))
 				})
 			})
+
+			Context("when the session is recorded", func() {
+				var recordingBuffer *gbytes.Buffer
+
+				BeforeEach(func() {
+					recordingBuffer = gbytes.NewBuffer()
+					fakeTerminalHelper.GetWinsizeReturns(&term.Winsize{Width: 100, Height: 30}, nil)
+
+					interactiveSessionInvoker = func(secureShell sshCmd.SecureShell) {
+						secureShell.Record(sshCmd.NewRecording(recordingBuffer, sshCmd.RecordingMetadata{AppName: "app-name", Instance: 2}))
+						sessionError = secureShell.InteractiveSession()
+					}
+				})
+
+				It("records the window size", func() {
+					Expect(recordingBuffer).To(gbytes.Say(`"version":2,"width":100,"height":30`))
+				})
+
+				It("records the stdin, stdout and stderr streams", func() {
+					Eventually(recordingBuffer.Contents).Should(ContainSubstring(`"i","\u0000"]`))
+					Expect(recordingBuffer.Contents()).To(ContainSubstring(`"o","\u0001"]`))
+					Expect(recordingBuffer.Contents()).To(ContainSubstring(`"o","\u0002"]`))
+				})
+
+				It("still copies the streams", func() {
+					Eventually(stdinPipe.WriteCallCount).Should(Equal(1))
+					Expect(stdout.WriteCallCount()).To(Equal(1))
+					Expect(stderr.WriteCallCount()).To(Equal(1))
+				})
+			})
 		})
 
 		Context("when stdout is a terminal and a window size change occurs", func() {
@@ -1123,6 +1271,497 @@ var _ = Describe("SSH", func() {
 		})
 	})
 
+	Describe("LocalPortForward with dynamic forward specs", func() {
+		var (
+			opts              *options.SSHOptions
+			localForwardError error
+
+			echoAddress  string
+			echoListener net.Listener
+
+			socksAddress      string
+			realSocksListener net.Listener
+		)
+
+		BeforeEach(func() {
+			var err error
+			echoListener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			echoAddress = echoListener.Addr().String()
+
+			go func(listener net.Listener) {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					go func() {
+						io.Copy(conn, conn)
+						conn.Close()
+					}()
+				}
+			}(echoListener)
+
+			realSocksListener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			socksAddress = realSocksListener.Addr().String()
+			fakeListenerFactory.ListenReturns(realSocksListener, nil)
+
+			opts = &options.SSHOptions{
+				AppName: "app-1",
+				DynamicForwardSpecs: []options.ForwardSpec{{
+					ListenAddress: socksAddress,
+				}},
+			}
+
+			currentApp.State = "STARTED"
+			currentApp.Diego = true
+
+			fakeSecureClient.DialStub = net.Dial
+		})
+
+		JustBeforeEach(func() {
+			connectErr := secureShell.Connect(opts)
+			Expect(connectErr).NotTo(HaveOccurred())
+
+			localForwardError = secureShell.LocalPortForward()
+		})
+
+		AfterEach(func() {
+			err := secureShell.Close()
+			Expect(err).NotTo(HaveOccurred())
+			echoListener.Close()
+			realSocksListener.Close()
+		})
+
+		socksConnect := func(request []byte) (net.Conn, []byte) {
+			conn, err := net.Dial("tcp", socksAddress)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = conn.Write([]byte{0x05, 0x01, 0x00})
+			Expect(err).NotTo(HaveOccurred())
+
+			method := make([]byte, 2)
+			_, err = io.ReadFull(conn, method)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(method).To(Equal([]byte{0x05, 0x00}))
+
+			_, err = conn.Write(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			reply := make([]byte, 10)
+			_, err = io.ReadFull(conn, reply)
+			Expect(err).NotTo(HaveOccurred())
+
+			return conn, reply
+		}
+
+		echoPort := func() []byte {
+			_, port, err := net.SplitHostPort(echoAddress)
+			Expect(err).NotTo(HaveOccurred())
+
+			var portNumber int
+			fmt.Sscanf(port, "%d", &portNumber)
+			return []byte{byte(portNumber >> 8), byte(portNumber)}
+		}
+
+		It("listens on the dynamic forward address", func() {
+			Expect(localForwardError).NotTo(HaveOccurred())
+			Expect(fakeListenerFactory.ListenCallCount()).To(Equal(1))
+
+			network, addr := fakeListenerFactory.ListenArgsForCall(0)
+			Expect(network).To(Equal("tcp"))
+			Expect(addr).To(Equal(socksAddress))
+		})
+
+		It("dials the address requested by the SOCKS client through the secure client", func() {
+			request := append([]byte{0x05, 0x01, 0x00, 0x01, 127, 0, 0, 1}, echoPort()...)
+			conn, reply := socksConnect(request)
+			defer conn.Close()
+
+			Expect(reply[:2]).To(Equal([]byte{0x05, 0x00}))
+
+			Eventually(fakeSecureClient.DialCallCount).Should(Equal(1))
+			network, addr := fakeSecureClient.DialArgsForCall(0)
+			Expect(network).To(Equal("tcp"))
+			Expect(addr).To(Equal(echoAddress))
+
+			msg := "Hello through SOCKS\n"
+			_, err := conn.Write([]byte(msg))
+			Expect(err).NotTo(HaveOccurred())
+
+			response := make([]byte, len(msg))
+			_, err = io.ReadFull(conn, response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(response)).To(Equal(msg))
+		})
+
+		It("resolves domain names on the remote side", func() {
+			request := append([]byte{0x05, 0x01, 0x00, 0x03, byte(len("localhost"))}, []byte("localhost")...)
+			request = append(request, echoPort()...)
+			conn, _ := socksConnect(request)
+			defer conn.Close()
+
+			Eventually(fakeSecureClient.DialCallCount).Should(Equal(1))
+			_, addr := fakeSecureClient.DialArgsForCall(0)
+			_, port, _ := net.SplitHostPort(echoAddress)
+			Expect(addr).To(Equal("localhost:" + port))
+		})
+
+		Context("when dialing the requested address fails", func() {
+			BeforeEach(func() {
+				fakeSecureClient.DialStub = nil
+				fakeSecureClient.DialReturns(nil, errors.New("boom"))
+			})
+
+			It("replies with a failure", func() {
+				request := append([]byte{0x05, 0x01, 0x00, 0x01, 10, 0, 0, 1}, echoPort()...)
+				conn, reply := socksConnect(request)
+				defer conn.Close()
+
+				Expect(reply[:2]).To(Equal([]byte{0x05, 0x04}))
+			})
+		})
+
+		Context("when the SOCKS client requests an unsupported command", func() {
+			It("replies that the command is not supported", func() {
+				request := append([]byte{0x05, 0x02, 0x00, 0x01, 127, 0, 0, 1}, echoPort()...)
+				conn, reply := socksConnect(request)
+				defer conn.Close()
+
+				Expect(reply[:2]).To(Equal([]byte{0x05, 0x07}))
+				Consistently(fakeSecureClient.DialCallCount).Should(Equal(0))
+			})
+		})
+
+		Context("when listen fails", func() {
+			BeforeEach(func() {
+				fakeListenerFactory.ListenReturns(nil, errors.New("failure is an option"))
+			})
+
+			It("returns the error", func() {
+				Expect(localForwardError).To(MatchError("failure is an option"))
+			})
+		})
+	})
+
+	Describe("RemotePortForward", func() {
+		var (
+			opts               *options.SSHOptions
+			remoteForwardError error
+
+			echoAddress  string
+			echoListener net.Listener
+
+			remoteAddress      string
+			realRemoteListener net.Listener
+		)
+
+		BeforeEach(func() {
+			var err error
+			echoListener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			echoAddress = echoListener.Addr().String()
+
+			go func(listener net.Listener) {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					go func() {
+						io.Copy(conn, conn)
+						conn.Close()
+					}()
+				}
+			}(echoListener)
+
+			// stands in for the listener the SSH server opens in the container
+			realRemoteListener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			remoteAddress = realRemoteListener.Addr().String()
+			fakeSecureClient.ListenReturns(realRemoteListener, nil)
+
+			opts = &options.SSHOptions{
+				AppName: "app-1",
+				RemoteForwardSpecs: []options.ForwardSpec{{
+					ListenAddress:  "localhost:9999",
+					ConnectAddress: echoAddress,
+				}},
+			}
+
+			currentApp.State = "STARTED"
+			currentApp.Diego = true
+		})
+
+		JustBeforeEach(func() {
+			connectErr := secureShell.Connect(opts)
+			Expect(connectErr).NotTo(HaveOccurred())
+
+			remoteForwardError = secureShell.RemotePortForward()
+		})
+
+		AfterEach(func() {
+			err := secureShell.Close()
+			Expect(err).NotTo(HaveOccurred())
+			echoListener.Close()
+			realRemoteListener.Close()
+		})
+
+		It("asks the server to listen on the remote address", func() {
+			Expect(remoteForwardError).NotTo(HaveOccurred())
+			Expect(fakeSecureClient.ListenCallCount()).To(Equal(1))
+
+			network, addr := fakeSecureClient.ListenArgsForCall(0)
+			Expect(network).To(Equal("tcp"))
+			Expect(addr).To(Equal("localhost:9999"))
+		})
+
+		It("copies data between the remote connection and the local target", func() {
+			conn, err := net.Dial("tcp", remoteAddress)
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			msg := "Hello from the container\n"
+			_, err = conn.Write([]byte(msg))
+			Expect(err).NotTo(HaveOccurred())
+
+			response := make([]byte, len(msg))
+			_, err = io.ReadFull(conn, response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(response)).To(Equal(msg))
+
+			Expect(fakeSecureClient.DialCallCount()).To(Equal(0))
+		})
+
+		Context("when the server refuses to listen", func() {
+			BeforeEach(func() {
+				fakeSecureClient.ListenReturns(nil, errors.New("tcpip-forward request denied by peer"))
+			})
+
+			It("returns the error", func() {
+				Expect(remoteForwardError).To(MatchError("tcpip-forward request denied by peer"))
+			})
+		})
+
+		Context("when the secure client is closed", func() {
+			var fakeRemoteListener *fake_net.FakeListener
+
+			BeforeEach(func() {
+				fakeRemoteListener = &fake_net.FakeListener{}
+				fakeRemoteListener.AcceptReturns(nil, errors.New("not accepting connections"))
+				fakeSecureClient.ListenReturns(fakeRemoteListener, nil)
+			})
+
+			It("closes the remote listeners", func() {
+				Eventually(fakeRemoteListener.AcceptCallCount).Should(Equal(1))
+
+				originalCloseCount := fakeRemoteListener.CloseCallCount()
+				err := secureShell.Close()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeRemoteListener.CloseCallCount()).Should(Equal(originalCloseCount + 1))
+			})
+		})
+	})
+
+	Describe("CommandSession", func() {
+		var (
+			opts           *options.SSHOptions
+			stdout, stderr *bytes.Buffer
+			sessionError   error
+		)
+
+		BeforeEach(func() {
+			opts = &options.SSHOptions{
+				AppName: "app-1",
+				Command: []string{"df", "-h"},
+			}
+
+			currentApp.State = "STARTED"
+			currentApp.Diego = true
+
+			stdout = &bytes.Buffer{}
+			stderr = &bytes.Buffer{}
+
+			fakeSecureSession.StdoutPipeReturns(strings.NewReader("Filesystem Size\n"), nil)
+			fakeSecureSession.StderrPipeReturns(strings.NewReader("df: warning\n"), nil)
+		})
+
+		JustBeforeEach(func() {
+			connectErr := secureShell.Connect(opts)
+			Expect(connectErr).NotTo(HaveOccurred())
+
+			sessionError = secureShell.CommandSession(stdout, stderr)
+		})
+
+		It("starts the command without requesting a pty", func() {
+			Expect(sessionError).NotTo(HaveOccurred())
+			Expect(fakeSecureSession.StartCallCount()).To(Equal(1))
+			Expect(fakeSecureSession.StartArgsForCall(0)).To(Equal("df -h"))
+			Expect(fakeSecureSession.RequestPtyCallCount()).To(Equal(0))
+			Expect(fakeSecureSession.StdinPipeCallCount()).To(Equal(0))
+		})
+
+		It("copies the command output to the given writers", func() {
+			Expect(stdout.String()).To(Equal("Filesystem Size\n"))
+			Expect(stderr.String()).To(Equal("df: warning\n"))
+		})
+
+		It("closes the session", func() {
+			Expect(fakeSecureSession.CloseCallCount()).To(Equal(1))
+		})
+
+		Context("when the command fails to start", func() {
+			BeforeEach(func() {
+				fakeSecureSession.StartReturns(errors.New("oops"))
+			})
+
+			It("returns the error", func() {
+				Expect(sessionError).To(MatchError("oops"))
+			})
+		})
+
+		Context("when the command exits with an error", func() {
+			BeforeEach(func() {
+				fakeSecureSession.WaitReturns(errors.New("exit status 1"))
+			})
+
+			It("returns the result from wait", func() {
+				Expect(sessionError).To(MatchError("exit status 1"))
+			})
+		})
+
+		Context("when the session allocation fails", func() {
+			BeforeEach(func() {
+				fakeSecureClient.NewSessionReturns(nil, errors.New("no session"))
+			})
+
+			It("returns the error", func() {
+				Expect(sessionError).To(MatchError("SSH session allocation failed: no session"))
+			})
+		})
+	})
+
+	Describe("CopyToRemote and CopyFromRemote", func() {
+		var (
+			opts       *options.SSHOptions
+			localDir   string
+			stdinBytes *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			opts = &options.SSHOptions{
+				AppName: "app-1",
+			}
+
+			currentApp.State = "STARTED"
+			currentApp.Diego = true
+
+			var err error
+			localDir, err = ioutil.TempDir("", "scp")
+			Expect(err).NotTo(HaveOccurred())
+
+			stdinBytes = &bytes.Buffer{}
+			stdinPipe.WriteStub = stdinBytes.Write
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(localDir)
+		})
+
+		JustBeforeEach(func() {
+			connectErr := secureShell.Connect(opts)
+			Expect(connectErr).NotTo(HaveOccurred())
+		})
+
+		Describe("CopyToRemote", func() {
+			var localFile string
+
+			BeforeEach(func() {
+				localFile = filepath.Join(localDir, "config.yml")
+				err := ioutil.WriteFile(localFile, []byte("key: value"), 0644)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeSecureSession.StdoutPipeReturns(strings.NewReader("\x00\x00\x00\x00"), nil)
+			})
+
+			It("runs scp in sink mode on the remote and sends the files", func() {
+				err := secureShell.CopyToRemote([]string{localFile}, "/tmp/it's here", false)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSecureSession.StartCallCount()).To(Equal(1))
+				Expect(fakeSecureSession.StartArgsForCall(0)).To(Equal(`scp -t '/tmp/it'\''s here'`))
+				Expect(stdinBytes.String()).To(HavePrefix("C0644 10 config.yml\nkey: value"))
+				Expect(stdinPipe.CloseCallCount()).To(Equal(1))
+				Expect(fakeSecureSession.WaitCallCount()).To(Equal(1))
+			})
+
+			It("copies recursively into a target directory when requested", func() {
+				fakeSecureSession.StdoutPipeReturns(strings.NewReader(strings.Repeat("\x00", 7)), nil)
+
+				err := secureShell.CopyToRemote([]string{localFile, localFile}, "/tmp", true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeSecureSession.StartArgsForCall(0)).To(Equal(`scp -t -r -d '/tmp'`))
+			})
+
+			Context("when the remote command fails to start", func() {
+				BeforeEach(func() {
+					fakeSecureSession.StartReturns(errors.New("start-error"))
+				})
+
+				It("returns the error", func() {
+					err := secureShell.CopyToRemote([]string{localFile}, "/tmp", false)
+					Expect(err).To(MatchError("start-error"))
+				})
+			})
+
+			Context("when the session allocation fails", func() {
+				BeforeEach(func() {
+					fakeSecureClient.NewSessionReturns(nil, errors.New("session-error"))
+				})
+
+				It("returns the error", func() {
+					err := secureShell.CopyToRemote([]string{localFile}, "/tmp", false)
+					Expect(err).To(MatchError(ContainSubstring("session-error")))
+				})
+			})
+		})
+
+		Describe("CopyFromRemote", func() {
+			BeforeEach(func() {
+				fakeSecureSession.StdoutPipeReturns(strings.NewReader("C0600 5 app.log\nhello\x00"), nil)
+			})
+
+			It("runs scp in source mode on the remote and receives the files", func() {
+				err := secureShell.CopyFromRemote("/home/vcap/logs/app.log", localDir, false)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSecureSession.StartArgsForCall(0)).To(Equal(`scp -f '/home/vcap/logs/app.log'`))
+
+				contents, err := ioutil.ReadFile(filepath.Join(localDir, "app.log"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("hello"))
+			})
+
+			It("requests a recursive copy when requested", func() {
+				err := secureShell.CopyFromRemote("/home/vcap/logs", localDir, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeSecureSession.StartArgsForCall(0)).To(Equal(`scp -f -r '/home/vcap/logs'`))
+			})
+
+			Context("when the remote command exits with an error", func() {
+				BeforeEach(func() {
+					fakeSecureSession.WaitReturns(errors.New("exit-error"))
+				})
+
+				It("returns the error", func() {
+					err := secureShell.CopyFromRemote("/home/vcap/logs/app.log", localDir, false)
+					Expect(err).To(MatchError("exit-error"))
+				})
+			})
+		})
+	})
+
 	Describe("Wait", func() {
 		var opts *options.SSHOptions
 		var waitErr error
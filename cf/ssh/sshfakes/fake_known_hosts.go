@@ -0,0 +1,100 @@
+// This file was generated by counterfeiter
+package sshfakes
+
+import (
+	"sync"
+
+	sshCmd "github.com/cloudfoundry/cli/cf/ssh"
+	"golang.org/x/crypto/ssh"
+)
+
+type FakeKnownHosts struct {
+	LookupStub        func(endpoint string) (key ssh.PublicKey, found bool, err error)
+	lookupMutex       sync.RWMutex
+	lookupArgsForCall []struct {
+		endpoint string
+	}
+	lookupReturns struct {
+		result1 ssh.PublicKey
+		result2 bool
+		result3 error
+	}
+	PinStub        func(endpoint string, key ssh.PublicKey) error
+	pinMutex       sync.RWMutex
+	pinArgsForCall []struct {
+		endpoint string
+		key      ssh.PublicKey
+	}
+	pinReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeKnownHosts) Lookup(endpoint string) (key ssh.PublicKey, found bool, err error) {
+	fake.lookupMutex.Lock()
+	fake.lookupArgsForCall = append(fake.lookupArgsForCall, struct {
+		endpoint string
+	}{endpoint})
+	fake.lookupMutex.Unlock()
+	if fake.LookupStub != nil {
+		return fake.LookupStub(endpoint)
+	} else {
+		return fake.lookupReturns.result1, fake.lookupReturns.result2, fake.lookupReturns.result3
+	}
+}
+
+func (fake *FakeKnownHosts) LookupCallCount() int {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return len(fake.lookupArgsForCall)
+}
+
+func (fake *FakeKnownHosts) LookupArgsForCall(i int) string {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return fake.lookupArgsForCall[i].endpoint
+}
+
+func (fake *FakeKnownHosts) LookupReturns(result1 ssh.PublicKey, result2 bool, result3 error) {
+	fake.LookupStub = nil
+	fake.lookupReturns = struct {
+		result1 ssh.PublicKey
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeKnownHosts) Pin(endpoint string, key ssh.PublicKey) error {
+	fake.pinMutex.Lock()
+	fake.pinArgsForCall = append(fake.pinArgsForCall, struct {
+		endpoint string
+		key      ssh.PublicKey
+	}{endpoint, key})
+	fake.pinMutex.Unlock()
+	if fake.PinStub != nil {
+		return fake.PinStub(endpoint, key)
+	} else {
+		return fake.pinReturns.result1
+	}
+}
+
+func (fake *FakeKnownHosts) PinCallCount() int {
+	fake.pinMutex.RLock()
+	defer fake.pinMutex.RUnlock()
+	return len(fake.pinArgsForCall)
+}
+
+func (fake *FakeKnownHosts) PinArgsForCall(i int) (string, ssh.PublicKey) {
+	fake.pinMutex.RLock()
+	defer fake.pinMutex.RUnlock()
+	return fake.pinArgsForCall[i].endpoint, fake.pinArgsForCall[i].key
+}
+
+func (fake *FakeKnownHosts) PinReturns(result1 error) {
+	fake.PinStub = nil
+	fake.pinReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ sshCmd.KnownHosts = new(FakeKnownHosts)
@@ -2,10 +2,13 @@
 package sshfakes
 
 import (
+	"io"
+	"net"
 	"sync"
 
 	"github.com/cloudfoundry/cli/cf/ssh"
 	"github.com/cloudfoundry/cli/cf/ssh/options"
+	"golang.org/x/crypto/ssh"
 )
 
 type FakeSecureShell struct {
@@ -23,12 +26,61 @@ type FakeSecureShell struct {
 	interactiveSessionReturns     struct {
 		result1 error
 	}
+	CommandSessionStub        func(stdout io.Writer, stderr io.Writer) error
+	commandSessionMutex       sync.RWMutex
+	commandSessionArgsForCall []struct {
+		stdout io.Writer
+		stderr io.Writer
+	}
+	commandSessionReturns struct {
+		result1 error
+	}
+	RecordStub        func(recording *sshCmd.Recording)
+	recordMutex       sync.RWMutex
+	recordArgsForCall []struct {
+		recording *sshCmd.Recording
+	}
 	LocalPortForwardStub        func() error
 	localPortForwardMutex       sync.RWMutex
 	localPortForwardArgsForCall []struct{}
 	localPortForwardReturns     struct {
 		result1 error
 	}
+	RemotePortForwardStub        func() error
+	remotePortForwardMutex       sync.RWMutex
+	remotePortForwardArgsForCall []struct{}
+	remotePortForwardReturns     struct {
+		result1 error
+	}
+	ProxyStub        func(downstream net.Conn, hostKey ssh.Signer) error
+	proxyMutex       sync.RWMutex
+	proxyArgsForCall []struct {
+		downstream net.Conn
+		hostKey    ssh.Signer
+	}
+	proxyReturns struct {
+		result1 error
+	}
+	CopyToRemoteStub        func(localPaths []string, remotePath string, recursive bool) error
+	copyToRemoteMutex       sync.RWMutex
+	copyToRemoteArgsForCall []struct {
+		localPaths []string
+		remotePath string
+		recursive  bool
+	}
+	copyToRemoteReturns struct {
+		result1 error
+	}
+	CopyFromRemoteStub        func(remotePath string, localPath string, recursive bool) error
+	copyFromRemoteMutex       sync.RWMutex
+	copyFromRemoteArgsForCall []struct {
+		remotePath string
+		localPath  string
+		recursive  bool
+	}
+	copyFromRemoteReturns struct {
+		result1 error
+	}
 	WaitStub        func() error
 	waitMutex       sync.RWMutex
 	waitArgsForCall []struct{}
@@ -99,6 +151,62 @@ func (fake *FakeSecureShell) InteractiveSessionReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeSecureShell) CommandSession(stdout io.Writer, stderr io.Writer) error {
+	fake.commandSessionMutex.Lock()
+	fake.commandSessionArgsForCall = append(fake.commandSessionArgsForCall, struct {
+		stdout io.Writer
+		stderr io.Writer
+	}{stdout, stderr})
+	fake.commandSessionMutex.Unlock()
+	if fake.CommandSessionStub != nil {
+		return fake.CommandSessionStub(stdout, stderr)
+	} else {
+		return fake.commandSessionReturns.result1
+	}
+}
+
+func (fake *FakeSecureShell) CommandSessionCallCount() int {
+	fake.commandSessionMutex.RLock()
+	defer fake.commandSessionMutex.RUnlock()
+	return len(fake.commandSessionArgsForCall)
+}
+
+func (fake *FakeSecureShell) CommandSessionArgsForCall(i int) (io.Writer, io.Writer) {
+	fake.commandSessionMutex.RLock()
+	defer fake.commandSessionMutex.RUnlock()
+	return fake.commandSessionArgsForCall[i].stdout, fake.commandSessionArgsForCall[i].stderr
+}
+
+func (fake *FakeSecureShell) CommandSessionReturns(result1 error) {
+	fake.CommandSessionStub = nil
+	fake.commandSessionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSecureShell) Record(recording *sshCmd.Recording) {
+	fake.recordMutex.Lock()
+	fake.recordArgsForCall = append(fake.recordArgsForCall, struct {
+		recording *sshCmd.Recording
+	}{recording})
+	fake.recordMutex.Unlock()
+	if fake.RecordStub != nil {
+		fake.RecordStub(recording)
+	}
+}
+
+func (fake *FakeSecureShell) RecordCallCount() int {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return len(fake.recordArgsForCall)
+}
+
+func (fake *FakeSecureShell) RecordArgsForCall(i int) *sshCmd.Recording {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return fake.recordArgsForCall[i].recording
+}
+
 func (fake *FakeSecureShell) LocalPortForward() error {
 	fake.localPortForwardMutex.Lock()
 	fake.localPortForwardArgsForCall = append(fake.localPortForwardArgsForCall, struct{}{})
@@ -123,6 +231,136 @@ func (fake *FakeSecureShell) LocalPortForwardReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeSecureShell) RemotePortForward() error {
+	fake.remotePortForwardMutex.Lock()
+	fake.remotePortForwardArgsForCall = append(fake.remotePortForwardArgsForCall, struct{}{})
+	fake.remotePortForwardMutex.Unlock()
+	if fake.RemotePortForwardStub != nil {
+		return fake.RemotePortForwardStub()
+	} else {
+		return fake.remotePortForwardReturns.result1
+	}
+}
+
+func (fake *FakeSecureShell) RemotePortForwardCallCount() int {
+	fake.remotePortForwardMutex.RLock()
+	defer fake.remotePortForwardMutex.RUnlock()
+	return len(fake.remotePortForwardArgsForCall)
+}
+
+func (fake *FakeSecureShell) RemotePortForwardReturns(result1 error) {
+	fake.RemotePortForwardStub = nil
+	fake.remotePortForwardReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSecureShell) Proxy(downstream net.Conn, hostKey ssh.Signer) error {
+	fake.proxyMutex.Lock()
+	fake.proxyArgsForCall = append(fake.proxyArgsForCall, struct {
+		downstream net.Conn
+		hostKey    ssh.Signer
+	}{downstream, hostKey})
+	fake.proxyMutex.Unlock()
+	if fake.ProxyStub != nil {
+		return fake.ProxyStub(downstream, hostKey)
+	} else {
+		return fake.proxyReturns.result1
+	}
+}
+
+func (fake *FakeSecureShell) ProxyCallCount() int {
+	fake.proxyMutex.RLock()
+	defer fake.proxyMutex.RUnlock()
+	return len(fake.proxyArgsForCall)
+}
+
+func (fake *FakeSecureShell) ProxyArgsForCall(i int) (net.Conn, ssh.Signer) {
+	fake.proxyMutex.RLock()
+	defer fake.proxyMutex.RUnlock()
+	return fake.proxyArgsForCall[i].downstream, fake.proxyArgsForCall[i].hostKey
+}
+
+func (fake *FakeSecureShell) ProxyReturns(result1 error) {
+	fake.ProxyStub = nil
+	fake.proxyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSecureShell) CopyToRemote(localPaths []string, remotePath string, recursive bool) error {
+	var localPathsCopy []string
+	if localPaths != nil {
+		localPathsCopy = make([]string, len(localPaths))
+		copy(localPathsCopy, localPaths)
+	}
+	fake.copyToRemoteMutex.Lock()
+	fake.copyToRemoteArgsForCall = append(fake.copyToRemoteArgsForCall, struct {
+		localPaths []string
+		remotePath string
+		recursive  bool
+	}{localPathsCopy, remotePath, recursive})
+	fake.copyToRemoteMutex.Unlock()
+	if fake.CopyToRemoteStub != nil {
+		return fake.CopyToRemoteStub(localPaths, remotePath, recursive)
+	} else {
+		return fake.copyToRemoteReturns.result1
+	}
+}
+
+func (fake *FakeSecureShell) CopyToRemoteCallCount() int {
+	fake.copyToRemoteMutex.RLock()
+	defer fake.copyToRemoteMutex.RUnlock()
+	return len(fake.copyToRemoteArgsForCall)
+}
+
+func (fake *FakeSecureShell) CopyToRemoteArgsForCall(i int) ([]string, string, bool) {
+	fake.copyToRemoteMutex.RLock()
+	defer fake.copyToRemoteMutex.RUnlock()
+	return fake.copyToRemoteArgsForCall[i].localPaths, fake.copyToRemoteArgsForCall[i].remotePath, fake.copyToRemoteArgsForCall[i].recursive
+}
+
+func (fake *FakeSecureShell) CopyToRemoteReturns(result1 error) {
+	fake.CopyToRemoteStub = nil
+	fake.copyToRemoteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSecureShell) CopyFromRemote(remotePath string, localPath string, recursive bool) error {
+	fake.copyFromRemoteMutex.Lock()
+	fake.copyFromRemoteArgsForCall = append(fake.copyFromRemoteArgsForCall, struct {
+		remotePath string
+		localPath  string
+		recursive  bool
+	}{remotePath, localPath, recursive})
+	fake.copyFromRemoteMutex.Unlock()
+	if fake.CopyFromRemoteStub != nil {
+		return fake.CopyFromRemoteStub(remotePath, localPath, recursive)
+	} else {
+		return fake.copyFromRemoteReturns.result1
+	}
+}
+
+func (fake *FakeSecureShell) CopyFromRemoteCallCount() int {
+	fake.copyFromRemoteMutex.RLock()
+	defer fake.copyFromRemoteMutex.RUnlock()
+	return len(fake.copyFromRemoteArgsForCall)
+}
+
+func (fake *FakeSecureShell) CopyFromRemoteArgsForCall(i int) (string, string, bool) {
+	fake.copyFromRemoteMutex.RLock()
+	defer fake.copyFromRemoteMutex.RUnlock()
+	return fake.copyFromRemoteArgsForCall[i].remotePath, fake.copyFromRemoteArgsForCall[i].localPath, fake.copyFromRemoteArgsForCall[i].recursive
+}
+
+func (fake *FakeSecureShell) CopyFromRemoteReturns(result1 error) {
+	fake.CopyFromRemoteStub = nil
+	fake.copyFromRemoteReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeSecureShell) Wait() error {
 	fake.waitMutex.Lock()
 	fake.waitArgsForCall = append(fake.waitArgsForCall, struct{}{})
@@ -0,0 +1,127 @@
+package trace
+
+import "regexp"
+
+// DefaultSecretPattern matches the names of the keys and variables whose
+// values are always treated as secrets. The name must end in one of the
+// words, either as a whole segment after _, - or . or as a camel case word,
+// so that keys such as token_endpoint are left alone.
+const DefaultSecretPattern = `(^|[_.-])(?i:passw(or)?d|pwd|secret|secret_?key|token|private_?key|api_?key|access_?key)$` +
+	`|[a-z0-9](Passw(or)?d|Pwd|Secret|SecretKey|Token|PrivateKey|ApiKey|AccessKey)$`
+
+var (
+	jsonStringOrNumberPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)("(?:[^"\\]|\\.)*"|-?[0-9][0-9.eE+-]*)`)
+	urlCredentialsPattern     = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^:/@\s"]+):([^@/\s"]+)@`)
+
+	defaultSecretMasker = &SecretMasker{
+		patterns: []*regexp.Regexp{regexp.MustCompile(DefaultSecretPattern)},
+	}
+)
+
+// SecretMasker hides credentials in output that is meant to be read by
+// people, such as environment variables and service credentials.
+type SecretMasker struct {
+	patterns []*regexp.Regexp
+}
+
+// NewSecretMasker returns a SecretMasker which treats the keys that match
+// the default pattern, or any of the given extra patterns, as secrets.
+func NewSecretMasker(extraPatterns []string) (*SecretMasker, error) {
+	masker := &SecretMasker{
+		patterns: append([]*regexp.Regexp{}, defaultSecretMasker.patterns...),
+	}
+
+	for _, pattern := range extraPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		masker.patterns = append(masker.patterns, re)
+	}
+
+	return masker, nil
+}
+
+// IsSecret reports whether the value of the key with the given name is a
+// secret.
+func (m *SecretMasker) IsSecret(name string) bool {
+	for _, pattern := range m.patterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskValue hides the value of a single variable when its name is a secret,
+// and otherwise hides the passwords of any URLs in it.
+func (m *SecretMasker) MaskValue(name string, value string) string {
+	if m.IsSecret(name) {
+		return PrivateDataPlaceholder()
+	}
+	return sanitizeURLCredentials(value)
+}
+
+// MaskVariables returns a copy of the variables in which the values of the
+// secret keys, at any depth, and the passwords of any URLs are hidden.
+func (m *SecretMasker) MaskVariables(vars map[string]interface{}) map[string]interface{} {
+	if vars == nil {
+		return nil
+	}
+
+	masked := make(map[string]interface{}, len(vars))
+	for name, value := range vars {
+		masked[name] = m.maskVariable(name, value)
+	}
+	return masked
+}
+
+func (m *SecretMasker) maskVariable(name string, value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return m.MaskVariables(value)
+	case []interface{}:
+		masked := make([]interface{}, len(value))
+		for i, element := range value {
+			masked[i] = m.maskVariable(name, element)
+		}
+		return masked
+	case string:
+		return m.MaskValue(name, value)
+	case bool, nil:
+		return value
+	default:
+		if m.IsSecret(name) {
+			return PrivateDataPlaceholder()
+		}
+		return value
+	}
+}
+
+// Mask hides the string and number values of the secret keys in JSON, and
+// the passwords of any URLs in the text.
+func (m *SecretMasker) Mask(input string) string {
+	return sanitizeURLCredentials(m.maskJSON(input, true))
+}
+
+// maskJSON hides the string and number values of the secret keys in JSON.
+// Unless keepSeparator is set, the colon after a hidden key is written
+// without the surrounding whitespace, as the trace output always has been.
+func (m *SecretMasker) maskJSON(input string, keepSeparator bool) string {
+	return jsonStringOrNumberPattern.ReplaceAllStringFunc(input, func(pair string) string {
+		match := jsonStringOrNumberPattern.FindStringSubmatch(pair)
+		if !m.IsSecret(match[1]) {
+			return pair
+		}
+
+		separator := ":"
+		if keepSeparator {
+			separator = match[2]
+		}
+		return `"` + match[1] + `"` + separator + `"` + PrivateDataPlaceholder() + `"`
+	})
+}
+
+func sanitizeURLCredentials(input string) string {
+	return urlCredentialsPattern.ReplaceAllString(input, "${1}:"+PrivateDataPlaceholder()+"@")
+}
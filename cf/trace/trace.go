@@ -1,7 +1,6 @@
 package trace
 
 import (
-	"fmt"
 	"regexp"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
@@ -16,20 +15,12 @@ func Sanitize(input string) string {
 	re = regexp.MustCompile(`password=[^&]*&`)
 	sanitized = re.ReplaceAllString(sanitized, "password="+PrivateDataPlaceholder()+"&")
 
-	sanitized = sanitizeJSON("access_token", sanitized)
-	sanitized = sanitizeJSON("refresh_token", sanitized)
-	sanitized = sanitizeJSON("token", sanitized)
-	sanitized = sanitizeJSON("password", sanitized)
-	sanitized = sanitizeJSON("oldPassword", sanitized)
+	sanitized = defaultSecretMasker.maskJSON(sanitized, false)
+	sanitized = sanitizeURLCredentials(sanitized)
 
 	return sanitized
 }
 
-func sanitizeJSON(propertyName string, json string) string {
-	regex := regexp.MustCompile(fmt.Sprintf(`"%s":\s*"[^\,]*"`, propertyName))
-	return regex.ReplaceAllString(json, fmt.Sprintf(`"%s":"%s"`, propertyName, PrivateDataPlaceholder()))
-}
-
 func PrivateDataPlaceholder() string {
 	return T("[PRIVATE DATA HIDDEN]")
 }
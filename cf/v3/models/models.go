@@ -17,12 +17,45 @@ type Link struct {
 }
 
 type V3Process struct {
+	GUID       string `json:"guid"`
 	Type       string `json:"type"`
 	Instances  int    `json:"instances"`
 	MemoryInMB int64  `json:"memory_in_mb"`
 	DiskInMB   int64  `json:"disk_in_mb"`
 }
 
+type V3ProcessScale struct {
+	Instances  *int   `json:"instances,omitempty"`
+	MemoryInMB *int64 `json:"memory_in_mb,omitempty"`
+	DiskInMB   *int64 `json:"disk_in_mb,omitempty"`
+}
+
+type V3ProcessUpdate struct {
+	Command     *string        `json:"command,omitempty"`
+	HealthCheck *V3HealthCheck `json:"health_check,omitempty"`
+}
+
+type V3HealthCheck struct {
+	Type string `json:"type"`
+}
+
+type V3ProcessStats struct {
+	Type      string       `json:"type"`
+	Index     int          `json:"index"`
+	State     string       `json:"state"`
+	Usage     V3StatsUsage `json:"usage"`
+	Uptime    int64        `json:"uptime"`
+	MemQuota  int64        `json:"mem_quota"`
+	DiskQuota int64        `json:"disk_quota"`
+	Details   string       `json:"details"`
+}
+
+type V3StatsUsage struct {
+	CPU  float64 `json:"cpu"`
+	Mem  int64   `json:"mem"`
+	Disk int64   `json:"disk"`
+}
+
 type V3Route struct {
 	Host string `json:"host"`
 	Path string `json:"path"`
@@ -1,10 +1,12 @@
 package repository
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/url"
 
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/net"
 	"github.com/cloudfoundry/cli/cf/v3/models"
 	"github.com/cloudfoundry/go-ccapi/v3/client"
 )
@@ -15,17 +17,24 @@ type Repository interface {
 	GetApplications() ([]models.V3Application, error)
 	GetProcesses(path string) ([]models.V3Process, error)
 	GetRoutes(path string) ([]models.V3Route, error)
+	GetProcessStats(path string) ([]models.V3ProcessStats, error)
+	ScaleProcess(path string, scale models.V3ProcessScale) error
+	UpdateProcess(path string, update models.V3ProcessUpdate) error
 }
 
+// The v3 client only reads resources, so changes go through the Cloud
+// Controller gateway.
 type repository struct {
-	client client.Client
-	config coreconfig.ReadWriter
+	client  client.Client
+	gateway net.Gateway
+	config  coreconfig.ReadWriter
 }
 
-func NewRepository(config coreconfig.ReadWriter, client client.Client) Repository {
+func NewRepository(config coreconfig.ReadWriter, client client.Client, gateway net.Gateway) Repository {
 	return &repository{
-		client: client,
-		config: config,
+		client:  client,
+		gateway: gateway,
+		config:  config,
 	}
 }
 
@@ -87,3 +96,45 @@ func (r *repository) GetRoutes(path string) ([]models.V3Route, error) {
 
 	return routes, nil
 }
+
+func (r *repository) GetProcessStats(path string) ([]models.V3ProcessStats, error) {
+	jsonResponse, err := r.client.GetResources(path, 0)
+	if err != nil {
+		return []models.V3ProcessStats{}, err
+	}
+
+	r.handleUpdatedTokens()
+
+	stats := []models.V3ProcessStats{}
+	err = json.Unmarshal(jsonResponse, &stats)
+	if err != nil {
+		return []models.V3ProcessStats{}, err
+	}
+
+	return stats, nil
+}
+
+func (r *repository) ScaleProcess(path string, scale models.V3ProcessScale) error {
+	body, err := json.Marshal(scale)
+	if err != nil {
+		return err
+	}
+
+	return r.gateway.UpdateResourceSync(r.config.APIEndpoint(), path, bytes.NewReader(body))
+}
+
+func (r *repository) UpdateProcess(path string, update models.V3ProcessUpdate) error {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	request, err := r.gateway.NewRequest("PATCH", r.config.APIEndpoint()+path, r.config.AccessToken(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	process := models.V3Process{}
+	_, err = r.gateway.PerformRequestForJSONResponse(request, &process)
+	return err
+}
@@ -2,11 +2,14 @@ package repository_test
 
 import (
 	"errors"
+	"net/http"
 
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/v3/models"
 	"github.com/cloudfoundry/cli/cf/v3/repository"
+	"github.com/cloudfoundry/cli/testhelpers/cloudcontrollergateway"
 	"github.com/cloudfoundry/cli/testhelpers/configuration"
+	"github.com/onsi/gomega/ghttp"
 
 	ccClientFakes "github.com/cloudfoundry/go-ccapi/v3/client/fakes"
 
@@ -24,7 +27,8 @@ var _ = Describe("Repository", func() {
 	BeforeEach(func() {
 		ccClient = &ccClientFakes.FakeClient{}
 		config = configuration.NewRepositoryWithDefaults()
-		r = repository.NewRepository(config, ccClient)
+		gateway := cloudcontrollergateway.NewTestCloudControllerGateway(config)
+		r = repository.NewRepository(config, ccClient, gateway)
 	})
 
 	Describe("GetApplications", func() {
@@ -139,12 +143,14 @@ var _ = Describe("Repository", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(processes).To(Equal([]models.V3Process{
 					{
+						GUID:       "process-1-guid",
 						Type:       "web",
 						Instances:  1,
 						MemoryInMB: 1024,
 						DiskInMB:   1024,
 					},
 					{
+						GUID:       "process-2-guid",
 						Type:       "web",
 						Instances:  2,
 						MemoryInMB: 512,
@@ -208,8 +214,146 @@ var _ = Describe("Repository", func() {
 			})
 		})
 	})
+
+	Describe("GetProcessStats", func() {
+		It("tries to get the process stats from CC with a token handler", func() {
+			r.GetProcessStats("/the-path")
+			Expect(ccClient.GetResourcesCallCount()).To(Equal(1))
+			Expect(ccClient.GetResourcesArgsForCall(0)).To(Equal("/the-path"))
+		})
+
+		Context("when getting the process stats fails", func() {
+			BeforeEach(func() {
+				ccClient.GetResourcesReturns([]byte{}, errors.New("get-stats-err"))
+			})
+
+			It("returns an error", func() {
+				_, err := r.GetProcessStats("/the-path")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("get-stats-err"))
+			})
+		})
+
+		Context("when getting the process stats succeeds", func() {
+			BeforeEach(func() {
+				ccClient.GetResourcesReturns(getProcessStatsJSON, nil)
+			})
+
+			It("returns a slice of process stats model objects", func() {
+				stats, err := r.GetProcessStats("/the-path")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stats).To(Equal([]models.V3ProcessStats{
+					{
+						Type:  "worker",
+						Index: 1,
+						State: "CRASHED",
+					},
+					{
+						Type:  "worker",
+						Index: 0,
+						State: "RUNNING",
+						Usage: models.V3StatsUsage{
+							CPU:  0.25,
+							Mem:  1048576,
+							Disk: 2097152,
+						},
+						Uptime:    60,
+						MemQuota:  134217728,
+						DiskQuota: 536870912,
+					},
+				}))
+			})
+		})
+	})
+
+	Describe("changing processes", func() {
+		var ccServer *ghttp.Server
+
+		BeforeEach(func() {
+			ccServer = ghttp.NewServer()
+			config.SetAPIEndpoint(ccServer.URL())
+		})
+
+		AfterEach(func() {
+			ccServer.Close()
+		})
+
+		Describe("ScaleProcess", func() {
+			BeforeEach(func() {
+				ccServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/v3/processes/worker-guid/scale"),
+						ghttp.VerifyJSON(`{"instances": 3, "memory_in_mb": 512}`),
+						ghttp.RespondWith(http.StatusAccepted, `{}`),
+					),
+				)
+			})
+
+			It("scales only the given attributes of the process", func() {
+				instances := 3
+				memory := int64(512)
+				err := r.ScaleProcess("/v3/processes/worker-guid/scale", models.V3ProcessScale{
+					Instances:  &instances,
+					MemoryInMB: &memory,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ccServer.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		Describe("UpdateProcess", func() {
+			It("updates the command and health check of the process", func() {
+				ccServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PATCH", "/v3/processes/worker-guid"),
+						ghttp.VerifyJSON(`{"command": "rake work", "health_check": {"type": "process"}}`),
+						ghttp.RespondWith(http.StatusOK, `{"guid": "worker-guid", "type": "worker"}`),
+					),
+				)
+
+				command := "rake work"
+				err := r.UpdateProcess("/v3/processes/worker-guid", models.V3ProcessUpdate{
+					Command:     &command,
+					HealthCheck: &models.V3HealthCheck{Type: "process"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ccServer.ReceivedRequests()).To(HaveLen(1))
+			})
+
+			It("returns an error when the process cannot be updated", func() {
+				ccServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PATCH", "/v3/processes/worker-guid"),
+						ghttp.RespondWith(http.StatusUnprocessableEntity, `{"errors": [{"detail": "invalid health check"}]}`),
+					),
+				)
+
+				command := "rake work"
+				err := r.UpdateProcess("/v3/processes/worker-guid", models.V3ProcessUpdate{Command: &command})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
 })
 
+var getProcessStatsJSON = []byte(`[
+	{
+		"type": "worker",
+		"index": 1,
+		"state": "CRASHED",
+		"uptime": 0
+	},
+	{
+		"type": "worker",
+		"index": 0,
+		"state": "RUNNING",
+		"usage": { "cpu": 0.25, "mem": 1048576, "disk": 2097152 },
+		"uptime": 60,
+		"mem_quota": 134217728,
+		"disk_quota": 536870912
+	}
+]`)
+
 var getApplicationsJSON = []byte(`[
 {
 	"guid": "app-1-guid",
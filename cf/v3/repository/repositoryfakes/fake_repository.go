@@ -34,6 +34,33 @@ type FakeRepository struct {
 		result1 []models.V3Route
 		result2 error
 	}
+	GetProcessStatsStub        func(path string) ([]models.V3ProcessStats, error)
+	getProcessStatsMutex       sync.RWMutex
+	getProcessStatsArgsForCall []struct {
+		path string
+	}
+	getProcessStatsReturns struct {
+		result1 []models.V3ProcessStats
+		result2 error
+	}
+	ScaleProcessStub        func(path string, scale models.V3ProcessScale) error
+	scaleProcessMutex       sync.RWMutex
+	scaleProcessArgsForCall []struct {
+		path  string
+		scale models.V3ProcessScale
+	}
+	scaleProcessReturns struct {
+		result1 error
+	}
+	UpdateProcessStub        func(path string, update models.V3ProcessUpdate) error
+	updateProcessMutex       sync.RWMutex
+	updateProcessArgsForCall []struct {
+		path   string
+		update models.V3ProcessUpdate
+	}
+	updateProcessReturns struct {
+		result1 error
+	}
 }
 
 func (fake *FakeRepository) GetApplications() ([]models.V3Application, error) {
@@ -127,4 +154,103 @@ func (fake *FakeRepository) GetRoutesReturns(result1 []models.V3Route, result2 e
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) GetProcessStats(path string) ([]models.V3ProcessStats, error) {
+	fake.getProcessStatsMutex.Lock()
+	fake.getProcessStatsArgsForCall = append(fake.getProcessStatsArgsForCall, struct {
+		path string
+	}{path})
+	fake.getProcessStatsMutex.Unlock()
+	if fake.GetProcessStatsStub != nil {
+		return fake.GetProcessStatsStub(path)
+	} else {
+		return fake.getProcessStatsReturns.result1, fake.getProcessStatsReturns.result2
+	}
+}
+
+func (fake *FakeRepository) GetProcessStatsCallCount() int {
+	fake.getProcessStatsMutex.RLock()
+	defer fake.getProcessStatsMutex.RUnlock()
+	return len(fake.getProcessStatsArgsForCall)
+}
+
+func (fake *FakeRepository) GetProcessStatsArgsForCall(i int) string {
+	fake.getProcessStatsMutex.RLock()
+	defer fake.getProcessStatsMutex.RUnlock()
+	return fake.getProcessStatsArgsForCall[i].path
+}
+
+func (fake *FakeRepository) GetProcessStatsReturns(result1 []models.V3ProcessStats, result2 error) {
+	fake.GetProcessStatsStub = nil
+	fake.getProcessStatsReturns = struct {
+		result1 []models.V3ProcessStats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ScaleProcess(path string, scale models.V3ProcessScale) error {
+	fake.scaleProcessMutex.Lock()
+	fake.scaleProcessArgsForCall = append(fake.scaleProcessArgsForCall, struct {
+		path  string
+		scale models.V3ProcessScale
+	}{path, scale})
+	fake.scaleProcessMutex.Unlock()
+	if fake.ScaleProcessStub != nil {
+		return fake.ScaleProcessStub(path, scale)
+	} else {
+		return fake.scaleProcessReturns.result1
+	}
+}
+
+func (fake *FakeRepository) ScaleProcessCallCount() int {
+	fake.scaleProcessMutex.RLock()
+	defer fake.scaleProcessMutex.RUnlock()
+	return len(fake.scaleProcessArgsForCall)
+}
+
+func (fake *FakeRepository) ScaleProcessArgsForCall(i int) (string, models.V3ProcessScale) {
+	fake.scaleProcessMutex.RLock()
+	defer fake.scaleProcessMutex.RUnlock()
+	return fake.scaleProcessArgsForCall[i].path, fake.scaleProcessArgsForCall[i].scale
+}
+
+func (fake *FakeRepository) ScaleProcessReturns(result1 error) {
+	fake.ScaleProcessStub = nil
+	fake.scaleProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) UpdateProcess(path string, update models.V3ProcessUpdate) error {
+	fake.updateProcessMutex.Lock()
+	fake.updateProcessArgsForCall = append(fake.updateProcessArgsForCall, struct {
+		path   string
+		update models.V3ProcessUpdate
+	}{path, update})
+	fake.updateProcessMutex.Unlock()
+	if fake.UpdateProcessStub != nil {
+		return fake.UpdateProcessStub(path, update)
+	} else {
+		return fake.updateProcessReturns.result1
+	}
+}
+
+func (fake *FakeRepository) UpdateProcessCallCount() int {
+	fake.updateProcessMutex.RLock()
+	defer fake.updateProcessMutex.RUnlock()
+	return len(fake.updateProcessArgsForCall)
+}
+
+func (fake *FakeRepository) UpdateProcessArgsForCall(i int) (string, models.V3ProcessUpdate) {
+	fake.updateProcessMutex.RLock()
+	defer fake.updateProcessMutex.RUnlock()
+	return fake.updateProcessArgsForCall[i].path, fake.updateProcessArgsForCall[i].update
+}
+
+func (fake *FakeRepository) UpdateProcessReturns(result1 error) {
+	fake.UpdateProcessStub = nil
+	fake.updateProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
 var _ repository.Repository = new(FakeRepository)
@@ -3,6 +3,7 @@ package commandsloader
 import (
 	"github.com/cloudfoundry/cli/cf/commands"
 	"github.com/cloudfoundry/cli/cf/commands/application"
+	"github.com/cloudfoundry/cli/cf/commands/apply"
 	"github.com/cloudfoundry/cli/cf/commands/buildpack"
 	"github.com/cloudfoundry/cli/cf/commands/domain"
 	"github.com/cloudfoundry/cli/cf/commands/environmentvariablegroup"
@@ -35,6 +36,7 @@ get initialized
 func Load() {
 	_ = commands.API{}
 	_ = application.ListApps{}
+	_ = apply.Apply{}
 	_ = buildpack.ListBuildpacks{}
 	_ = domain.CreateDomain{}
 	_ = environmentvariablegroup.RunningEnvironmentVariableGroup{}
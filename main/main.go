@@ -176,6 +176,11 @@ func handleHelp(args []string) []string {
 	hIndex := -1
 
 	for i, v := range args {
+		// arguments after '--' belong to the command being run, not to cf
+		if v == "--" {
+			break
+		}
+
 		if v == "-h" || v == "--help" || v == "--h" {
 			hIndex = i
 			break
@@ -199,6 +204,10 @@ func handleVerbose(args []string) ([]string, bool) {
 	idx := -1
 
 	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+
 		if arg == "-v" {
 			idx = i
 			break
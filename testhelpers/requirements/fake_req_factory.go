@@ -45,6 +45,7 @@ type FakeReqFactory struct {
 	Domain     models.DomainFields
 
 	UserUsername string
+	UserOrigin   string
 	UserFields   models.UserFields
 
 	Buildpack models.Buildpack
@@ -118,6 +119,12 @@ func (f *FakeReqFactory) NewUserRequirement(username string, wantGUID bool) requ
 	return FakeRequirement{f, !f.UserRequirementFails}
 }
 
+func (f *FakeReqFactory) NewUserRequirementWithOrigin(username string, origin string) requirements.UserRequirement {
+	f.UserUsername = username
+	f.UserOrigin = origin
+	return FakeRequirement{f, !f.UserRequirementFails}
+}
+
 func (f *FakeReqFactory) NewBuildpackRequirement(buildpack string) requirements.BuildpackRequirement {
 	f.Buildpack.Name = buildpack
 	return FakeRequirement{f, f.BuildpackSuccess}